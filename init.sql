@@ -21,3 +21,181 @@ CREATE TABLE IF NOT EXISTS tasks (
 -- Create indexes
 CREATE INDEX IF NOT EXISTS idx_tasks_user_id ON tasks(user_id);
 CREATE INDEX IF NOT EXISTS idx_users_email ON users(email);
+
+-- Create attachments table (blob bytes live in the configured BlobStore)
+CREATE TABLE IF NOT EXISTS attachments (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    filename VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    storage_key VARCHAR(255) UNIQUE NOT NULL,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_attachments_task_id ON attachments(task_id);
+
+-- Create task_series table (template for recurring tasks, one row per RRULE)
+CREATE TABLE IF NOT EXISTS task_series (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    title VARCHAR(100) NOT NULL,
+    content TEXT,
+    rrule TEXT NOT NULL,
+    timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    dtstart TIMESTAMPTZ NOT NULL,
+    mode VARCHAR(20) NOT NULL DEFAULT 'on_complete' CHECK (mode IN ('on_complete', 'scheduled')),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Task status, due date and recurrence
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'todo'
+    CHECK (status IN ('todo', 'in_progress', 'done'));
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS due_at TIMESTAMPTZ;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS series_id UUID REFERENCES task_series(id) ON DELETE SET NULL;
+
+-- One occurrence per due date, so concurrent generators can't duplicate
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tasks_series_due ON tasks(series_id, due_at) WHERE series_id IS NOT NULL;
+CREATE INDEX IF NOT EXISTS idx_task_series_mode ON task_series(mode);
+
+-- Create task_reminders table (fire time = task due_at - offset_seconds)
+CREATE TABLE IF NOT EXISTS task_reminders (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    offset_seconds BIGINT NOT NULL CHECK (offset_seconds >= 0),
+    channel VARCHAR(20) NOT NULL,
+    target TEXT,
+    fired_for TIMESTAMPTZ,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (task_id, offset_seconds, channel)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_reminders_task_id ON task_reminders(task_id);
+CREATE INDEX IF NOT EXISTS idx_task_reminders_user_id ON task_reminders(user_id);
+CREATE INDEX IF NOT EXISTS idx_tasks_due_at ON tasks(due_at) WHERE due_at IS NOT NULL;
+
+-- Per-user notification preferences (no row = defaults, account emails are always sent)
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    email_enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    reminders BOOLEAN NOT NULL DEFAULT TRUE,
+    invitations BOOLEAN NOT NULL DEFAULT TRUE,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Task dependencies: task_id is blocked by blocked_by_id
+CREATE TABLE IF NOT EXISTS task_dependencies (
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    blocked_by_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (task_id, blocked_by_id),
+    CHECK (task_id <> blocked_by_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_task_dependencies_blocked_by ON task_dependencies(blocked_by_id);
+
+-- Checklist items inside a task, positions are dense (0..n-1) per task
+CREATE TABLE IF NOT EXISTS checklist_items (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    text VARCHAR(200) NOT NULL,
+    checked BOOLEAN NOT NULL DEFAULT FALSE,
+    position INT NOT NULL CHECK (position >= 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    -- deferred so a reorder can move every item inside one transaction
+    CONSTRAINT checklist_items_task_position UNIQUE (task_id, position) DEFERRABLE INITIALLY DEFERRED
+);
+
+-- Checklist completion counts, kept in sync by the checklist repository
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS checklist_total INT NOT NULL DEFAULT 0;
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS checklist_done INT NOT NULL DEFAULT 0;
+
+-- Full-text search over title (weight A) and content (weight B)
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', coalesce(title, '')), 'A') ||
+    setweight(to_tsvector('english', coalesce(content, '')), 'B')
+) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_tasks_search_vector ON tasks USING GIN (search_vector);
+
+-- Id of the task in the tool it was imported from, used to upsert on re-import
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS external_id VARCHAR(255);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tasks_user_external_id ON tasks(user_id, external_id) WHERE external_id IS NOT NULL;
+
+-- Calendar feed per user, only the sha256 of the secret feed token is stored
+CREATE TABLE IF NOT EXISTS calendar_feeds (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    token_hash CHAR(64) NOT NULL UNIQUE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Applied schema versions, readiness fails while the database is behind
+-- service.SchemaVersion. Changes added below record the next version.
+CREATE TABLE IF NOT EXISTS schema_migrations (
+    version INT PRIMARY KEY,
+    applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO schema_migrations (version) VALUES (1) ON CONFLICT DO NOTHING;
+
+-- Email verification, NULL until the user opens the link from the verification
+-- email. Accounts that existed before verification was introduced are trusted.
+ALTER TABLE users ADD COLUMN IF NOT EXISTS email_verified_at TIMESTAMP;
+
+UPDATE users SET email_verified_at = created_at
+WHERE email_verified_at IS NULL
+  AND NOT EXISTS (SELECT 1 FROM schema_migrations WHERE version = 2);
+
+INSERT INTO schema_migrations (version) VALUES (2) ON CONFLICT DO NOTHING;
+
+-- TOTP two-factor authentication, the secret is encrypted with MFA_ENCRYPTION_KEY.
+-- enabled_at stays NULL until the first code is confirmed.
+CREATE TABLE IF NOT EXISTS user_mfa (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    secret TEXT NOT NULL,
+    enabled_at TIMESTAMP,
+    last_used_step BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Single-use recovery codes, only their sha256 is stored
+CREATE TABLE IF NOT EXISTS mfa_recovery_codes (
+    user_id UUID NOT NULL REFERENCES user_mfa(user_id) ON DELETE CASCADE,
+    code_hash CHAR(64) NOT NULL,
+    used_at TIMESTAMP,
+    PRIMARY KEY (user_id, code_hash)
+);
+
+INSERT INTO schema_migrations (version) VALUES (3) ON CONFLICT DO NOTHING;
+
+-- Free-form labels on tasks, stored lowercased; search filters on them
+ALTER TABLE tasks ADD COLUMN IF NOT EXISTS labels TEXT[] NOT NULL DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS idx_tasks_labels ON tasks USING GIN (labels);
+
+INSERT INTO schema_migrations (version) VALUES (4) ON CONFLICT DO NOTHING;
+
+-- Storage keys of deleted attachments, filled by a trigger so task and user
+-- cascades are covered too. The blob sweeper deletes the blobs and the rows.
+CREATE TABLE IF NOT EXISTS attachment_blob_deletions (
+    storage_key VARCHAR(255) PRIMARY KEY,
+    deleted_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE OR REPLACE FUNCTION queue_attachment_blob_deletion() RETURNS TRIGGER AS $$
+BEGIN
+    INSERT INTO attachment_blob_deletions (storage_key) VALUES (OLD.storage_key) ON CONFLICT DO NOTHING;
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE OR REPLACE TRIGGER trg_attachment_blob_deletion
+    AFTER DELETE ON attachments
+    FOR EACH ROW EXECUTE FUNCTION queue_attachment_blob_deletion();
+
+INSERT INTO schema_migrations (version) VALUES (5) ON CONFLICT DO NOTHING;
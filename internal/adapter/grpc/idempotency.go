@@ -0,0 +1,110 @@
+package grpc
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/suryansh74/task-management-api-project/internal/models"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+)
+
+// idempotencyKeyMetadata gRPC counterpart of the Idempotency-Key header
+const idempotencyKeyMetadata = "idempotency-key"
+
+// IdempotencyInterceptor replays the stored response of a call retried with
+// the same idempotency-key metadata. Only successful responses are stored,
+// a failed call can be retried with the same key.
+func IdempotencyInterceptor(store ports.IdempotencyStore, ttl, lockTTL time.Duration) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		md, _ := metadata.FromIncomingContext(ctx)
+		keys := md.Get(idempotencyKeyMetadata)
+		if len(keys) == 0 || keys[0] == "" {
+			return handler(ctx, req)
+		}
+		if len(keys[0]) > 255 {
+			return nil, status.Error(codes.InvalidArgument, "idempotency-key must be at most 255 characters")
+		}
+
+		msg, ok := req.(proto.Message)
+		if !ok {
+			return handler(ctx, req)
+		}
+		payload, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+		if err != nil {
+			return nil, status.Error(codes.Internal, "failed to hash request")
+		}
+		sum := sha256.Sum256(append([]byte(info.FullMethod+"\n"), payload...))
+		fingerprint := hex.EncodeToString(sum[:])
+
+		// every request carries the acting user, keys are scoped to it
+		storeKey := "grpc:" + keys[0]
+		if r, ok := req.(interface{ GetUserId() string }); ok {
+			storeKey = "grpc:" + r.GetUserId() + ":" + keys[0]
+		}
+
+		record, claimed, err := store.Begin(ctx, storeKey, fingerprint, lockTTL)
+		if err != nil {
+			return nil, status.Error(codes.Internal, "failed to check idempotency key")
+		}
+		if !claimed {
+			switch {
+			case record.Fingerprint != fingerprint:
+				return nil, status.Error(codes.InvalidArgument, "idempotency-key was already used with a different request")
+			case record.State == models.IdempotencyStateInFlight:
+				return nil, status.Error(codes.Aborted, "a request with this idempotency-key is still in progress")
+			}
+
+			var stored anypb.Any
+			if err := proto.Unmarshal(record.Body, &stored); err != nil {
+				return nil, status.Error(codes.Internal, "failed to replay response")
+			}
+			resp, err := stored.UnmarshalNew()
+			if err != nil {
+				return nil, status.Error(codes.Internal, "failed to replay response")
+			}
+			grpc.SetHeader(ctx, metadata.Pairs("idempotent-replayed", "true"))
+			return resp, nil
+		}
+
+		resp, err := handler(ctx, req)
+		// a cancelled call must still release or complete its claim
+		ctx = context.WithoutCancel(ctx)
+		if err != nil {
+			if releaseErr := store.Release(ctx, storeKey); releaseErr != nil {
+				zerolog.Ctx(ctx).Error().Err(releaseErr).Msg("failed to release idempotency key")
+			}
+			return resp, err
+		}
+
+		if out, ok := resp.(proto.Message); ok {
+			stored, err := anypb.New(out)
+			if err == nil {
+				var body []byte
+				body, err = proto.Marshal(stored)
+				if err == nil {
+					err = store.Complete(ctx, storeKey, &models.IdempotencyRecord{
+						Fingerprint: fingerprint,
+						Body:        body,
+					}, ttl)
+				}
+			}
+			if err != nil {
+				zerolog.Ctx(ctx).Error().
+					Err(err).
+					Str("method", info.FullMethod).
+					Msg("failed to store idempotent response")
+			}
+		}
+		return resp, nil
+	}
+}
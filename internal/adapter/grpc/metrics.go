@@ -0,0 +1,24 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"github.com/suryansh74/task-management-api-project/internal/metrics"
+)
+
+// MetricsInterceptor counts every unary call and records its latency by method
+// and status code. Placed before the rate limiter so rejected calls are counted.
+func MetricsInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		code := status.Code(err).String()
+		metrics.GRPCRequestsTotal.WithLabelValues(info.FullMethod, code).Inc()
+		metrics.GRPCRequestDuration.WithLabelValues(info.FullMethod, code).Observe(time.Since(start).Seconds())
+		return resp, err
+	}
+}
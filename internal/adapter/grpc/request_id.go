@@ -0,0 +1,47 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/suryansh74/task-management-api-project/internal/logger"
+	"github.com/suryansh74/task-management-api-project/internal/utils"
+)
+
+// requestIDMetadata gRPC counterpart of the X-Request-ID header
+const requestIDMetadata = "x-request-id"
+
+// RequestIDInterceptor accepts the caller's x-request-id metadata or generates
+// one, echoes it as a response header and scopes a logger carrying it, the
+// method and the acting user to the call. Must run first.
+func RequestIDInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		var incoming string
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if ids := md.Get(requestIDMetadata); len(ids) > 0 {
+				incoming = ids[0]
+			}
+		}
+		requestID := utils.RequestID(incoming)
+		grpc.SetHeader(ctx, metadata.Pairs(requestIDMetadata, requestID))
+
+		ctx = logger.NewRequestContext(ctx, requestID)
+		ctx = logger.WithField(ctx, "route", info.FullMethod)
+		if r, ok := req.(interface{ GetUserId() string }); ok && r.GetUserId() != "" {
+			ctx = logger.WithField(ctx, "user_id", r.GetUserId())
+		}
+
+		start := time.Now()
+		resp, err := handler(ctx, req)
+		zerolog.Ctx(ctx).Info().
+			Str("code", status.Code(err).String()).
+			Dur("duration", time.Since(start)).
+			Msg("grpc request")
+		return resp, err
+	}
+}
@@ -1,10 +1,14 @@
 package grpc
 
 import (
+	"context"
 	"fmt"
 	"net"
 
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 
 	taskv1 "github.com/suryansh74/task-management-api-project/api/gen/task/v1"
@@ -14,24 +18,37 @@ import (
 
 // Server wraps the gRPC server.
 type Server struct {
-	grpcServer *grpc.Server
-	addr       string
+	grpcServer   *grpc.Server
+	healthServer *health.Server
+	addr         string
 }
 
 // NewServer creates a gRPC server with the Task service registered.
 // Both REST and gRPC share the same ports.TaskService instance.
-func NewServer(addr string, taskService ports.TaskService) *Server {
-	s := grpc.NewServer()
+// Interceptors run in the given order around every unary call.
+func NewServer(addr string, taskService ports.TaskService, batchService ports.TaskBatchService, interceptors ...grpc.UnaryServerInterceptor) *Server {
+	// otelgrpc continues the caller's trace from the traceparent metadata
+	s := grpc.NewServer(
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(interceptors...),
+	)
 
-	taskServer := NewTaskServer(taskService)
+	taskServer := NewTaskServer(taskService, batchService)
 	taskv1.RegisterTaskServiceServer(s, taskServer)
 
+	// Standard grpc.health.v1 service, NOT_SERVING until the first readiness check
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(s, healthServer)
+	healthServer.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	healthServer.SetServingStatus(taskv1.TaskService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_NOT_SERVING)
+
 	// Register reflection for tools like grpcurl
 	reflection.Register(s)
 
 	return &Server{
-		grpcServer: s,
-		addr:       addr,
+		grpcServer:   s,
+		healthServer: healthServer,
+		addr:         addr,
 	}
 }
 
@@ -47,8 +64,43 @@ func (s *Server) Start() error {
 	return s.grpcServer.Serve(lis)
 }
 
+// SetServing reports the server and the Task service as SERVING or NOT_SERVING
+// to health checkers.
+func (s *Server) SetServing(serving bool) {
+	status := healthpb.HealthCheckResponse_NOT_SERVING
+	if serving {
+		status = healthpb.HealthCheckResponse_SERVING
+	}
+	s.healthServer.SetServingStatus("", status)
+	s.healthServer.SetServingStatus(taskv1.TaskService_ServiceDesc.ServiceName, status)
+}
+
+// StopServing marks every service NOT_SERVING for good, called when shutdown
+// begins so clients move away before the listener closes.
+func (s *Server) StopServing() {
+	s.healthServer.Shutdown()
+}
+
 // Stop gracefully stops the gRPC server.
 func (s *Server) Stop() {
 	logger.Log.Info().Msg("stopping gRPC server")
 	s.grpcServer.GracefulStop()
 }
+
+// Shutdown gracefully stops the gRPC server, waiting for in-flight calls
+// until ctx is done and cancelling whatever is still running after that.
+func (s *Server) Shutdown(ctx context.Context) {
+	done := make(chan struct{})
+	go func() {
+		s.Stop()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		logger.Log.Warn().Msg("gRPC drain timed out, closing remaining calls")
+		s.grpcServer.Stop()
+		<-done
+	}
+}
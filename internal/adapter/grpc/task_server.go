@@ -3,7 +3,9 @@ package grpc
 import (
 	"context"
 	"errors"
+	"time"
 
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
@@ -18,12 +20,13 @@ import (
 // It depends only on the application port (ports.TaskService).
 type TaskServer struct {
 	taskv1.UnimplementedTaskServiceServer
-	taskService ports.TaskService
+	taskService  ports.TaskService
+	batchService ports.TaskBatchService
 }
 
 // NewTaskServer creates a new gRPC task server adapter.
-func NewTaskServer(taskService ports.TaskService) *TaskServer {
-	return &TaskServer{taskService: taskService}
+func NewTaskServer(taskService ports.TaskService, batchService ports.TaskBatchService) *TaskServer {
+	return &TaskServer{taskService: taskService, batchService: batchService}
 }
 
 func (s *TaskServer) GetTasks(ctx context.Context, req *taskv1.GetTasksRequest) (*taskv1.GetTasksResponse, error) {
@@ -67,6 +70,7 @@ func (s *TaskServer) CreateTask(ctx context.Context, req *taskv1.CreateTaskReque
 		UserID:  req.UserId,
 		Title:   req.Title,
 		Content: req.Content,
+		Labels:  req.Labels,
 	}
 
 	id, err := s.taskService.CreateTask(ctx, task)
@@ -95,6 +99,7 @@ func (s *TaskServer) UpdateTask(ctx context.Context, req *taskv1.UpdateTaskReque
 	task := &models.Task{
 		Title:   req.Title,
 		Content: req.Content,
+		Labels:  req.Labels,
 	}
 
 	if err := s.taskService.UpdateTaskByID(ctx, req.Id, req.UserId, task); err != nil {
@@ -121,6 +126,98 @@ func (s *TaskServer) DeleteTask(ctx context.Context, req *taskv1.DeleteTaskReque
 	return &taskv1.DeleteTaskResponse{}, nil
 }
 
+func (s *TaskServer) SearchTasks(ctx context.Context, req *taskv1.SearchTasksRequest) (*taskv1.SearchTasksResponse, error) {
+	if req.UserId == "" || req.Query == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id and query are required")
+	}
+
+	query := &models.TaskSearchQuery{
+		UserID:      req.UserId,
+		Query:       req.Query,
+		Statuses:    req.Statuses,
+		Labels:      req.Labels,
+		DueFrom:     fromProtoTime(req.DueFrom),
+		DueTo:       fromProtoTime(req.DueTo),
+		CreatedFrom: fromProtoTime(req.CreatedFrom),
+		CreatedTo:   fromProtoTime(req.CreatedTo),
+		Limit:       int(req.Limit),
+		Offset:      int(req.Offset),
+	}
+	for _, st := range query.Statuses {
+		if st != models.TaskStatusTodo && st != models.TaskStatusInProgress && st != models.TaskStatusDone {
+			return nil, status.Error(codes.InvalidArgument, "statuses must be todo, in_progress or done")
+		}
+	}
+
+	result, err := s.taskService.SearchTasks(ctx, query)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	resp := &taskv1.SearchTasksResponse{
+		Hits:  make([]*taskv1.SearchHit, 0, len(result.Hits)),
+		Total: int32(result.Total),
+	}
+	for _, hit := range result.Hits {
+		resp.Hits = append(resp.Hits, &taskv1.SearchHit{
+			Task:           toProtoTask(hit.Task),
+			Rank:           hit.Rank,
+			TitleHighlight: hit.TitleHighlight,
+			Snippet:        hit.Snippet,
+		})
+	}
+	return resp, nil
+}
+
+func (s *TaskServer) BatchTasks(ctx context.Context, req *taskv1.BatchTasksRequest) (*taskv1.BatchTasksResponse, error) {
+	if req.UserId == "" {
+		return nil, status.Error(codes.InvalidArgument, "user_id is required")
+	}
+
+	ops := make([]*models.TaskBatchOp, 0, len(req.Operations))
+	for _, op := range req.Operations {
+		ops = append(ops, &models.TaskBatchOp{
+			Op:      op.Op,
+			ID:      op.Id,
+			Title:   op.Title,
+			Content: op.Content,
+			Status:  op.Status,
+			DueAt:   fromProtoTime(op.DueAt),
+		})
+	}
+
+	result, err := s.batchService.BatchTasks(ctx, req.UserId, ops, req.Atomic)
+	if err != nil {
+		return nil, mapError(err)
+	}
+
+	resp := &taskv1.BatchTasksResponse{
+		Committed: result.Committed,
+		Succeeded: int32(result.Succeeded),
+		Failed:    int32(result.Failed),
+		Results:   make([]*taskv1.BatchItemResult, 0, len(result.Results)),
+	}
+	for _, item := range result.Results {
+		resp.Results = append(resp.Results, &taskv1.BatchItemResult{
+			Index: int32(item.Index),
+			Op:    item.Op,
+			Id:    item.ID,
+			Ok:    item.OK,
+			Code:  item.Code,
+			Error: item.Error,
+		})
+	}
+	return resp, nil
+}
+
+func fromProtoTime(ts *timestamppb.Timestamp) *time.Time {
+	if ts == nil {
+		return nil
+	}
+	t := ts.AsTime()
+	return &t
+}
+
 func toProtoTask(t *models.Task) *taskv1.Task {
 	if t == nil {
 		return nil
@@ -130,8 +227,12 @@ func toProtoTask(t *models.Task) *taskv1.Task {
 		UserId:    t.UserID,
 		Title:     t.Title,
 		Content:   t.Content,
+		Labels:    t.Labels,
 		CreatedAt: timestamppb.New(t.CreatedAt),
 		UpdatedAt: timestamppb.New(t.UpdatedAt),
+
+		ChecklistTotal: int32(t.ChecklistTotal),
+		ChecklistDone:  int32(t.ChecklistDone),
 	}
 }
 
@@ -152,6 +253,14 @@ func mapError(err error) error {
 			return status.Error(codes.InvalidArgument, appErr.Message)
 		case "CONFLICT":
 			return status.Error(codes.AlreadyExists, appErr.Message)
+		case "PAYLOAD_TOO_LARGE", "TOO_MANY_REQUESTS":
+			return status.Error(codes.ResourceExhausted, appErr.Message)
+		case "QUOTA_EXCEEDED":
+			return quotaExceeded(appErr.Message)
+		case "SERVICE_UNAVAILABLE":
+			return status.Error(codes.Unavailable, appErr.Message)
+		case "UNSUPPORTED_MEDIA_TYPE", "UNPROCESSABLE_ENTITY":
+			return status.Error(codes.InvalidArgument, appErr.Message)
 		default:
 			return status.Error(codes.Internal, appErr.Message)
 		}
@@ -159,3 +268,14 @@ func mapError(err error) error {
 
 	return status.Error(codes.Internal, err.Error())
 }
+
+// quotaExceeded RESOURCE_EXHAUSTED naming the storage quota that was hit
+func quotaExceeded(message string) error {
+	st, err := status.New(codes.ResourceExhausted, message).WithDetails(&errdetails.QuotaFailure{
+		Violations: []*errdetails.QuotaFailure_Violation{{Subject: "storage", Description: message}},
+	})
+	if err != nil {
+		return status.Error(codes.ResourceExhausted, message)
+	}
+	return st.Err()
+}
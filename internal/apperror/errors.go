@@ -3,6 +3,7 @@ package apperror
 import (
 	"errors"
 	"net/http"
+	"time"
 )
 
 // AppError represents application-level errors with HTTP context
@@ -11,6 +12,8 @@ type AppError struct {
 	Message    string `json:"message"`
 	StatusCode int    `json:"-"`
 	Err        error  `json:"-"`
+	// RetryAfter sent as the Retry-After header when set
+	RetryAfter time.Duration `json:"-"`
 }
 
 func (e *AppError) Error() string {
@@ -34,6 +37,12 @@ var (
 	ErrInternalServer     = errors.New("internal server error")
 	ErrBadRequest         = errors.New("bad request")
 	ErrServiceUnavailable = errors.New("service unavailable")
+	ErrPayloadTooLarge    = errors.New("payload too large")
+	ErrUnsupportedMedia   = errors.New("unsupported media type")
+	ErrUnprocessable      = errors.New("unprocessable entity")
+	ErrTooManyRequests    = errors.New("too many requests")
+	ErrQuotaExceeded      = errors.New("quota exceeded")
+	ErrEmailNotVerified   = errors.New("email not verified")
 )
 
 // Error constructors
@@ -99,3 +108,76 @@ func NewBadRequestError(message string) *AppError {
 		Err:        ErrBadRequest,
 	}
 }
+
+func NewPayloadTooLargeError(message string) *AppError {
+	return &AppError{
+		Code:       "PAYLOAD_TOO_LARGE",
+		Message:    message,
+		StatusCode: http.StatusRequestEntityTooLarge,
+		Err:        ErrPayloadTooLarge,
+	}
+}
+
+func NewUnsupportedMediaTypeError(message string) *AppError {
+	return &AppError{
+		Code:       "UNSUPPORTED_MEDIA_TYPE",
+		Message:    message,
+		StatusCode: http.StatusUnsupportedMediaType,
+		Err:        ErrUnsupportedMedia,
+	}
+}
+
+func NewUnprocessableEntityError(message string) *AppError {
+	return &AppError{
+		Code:       "UNPROCESSABLE_ENTITY",
+		Message:    message,
+		StatusCode: http.StatusUnprocessableEntity,
+		Err:        ErrUnprocessable,
+	}
+}
+
+func NewTooManyRequestsError(message string) *AppError {
+	return &AppError{
+		Code:       "TOO_MANY_REQUESTS",
+		Message:    message,
+		StatusCode: http.StatusTooManyRequests,
+		Err:        ErrTooManyRequests,
+	}
+}
+
+func NewServiceUnavailableError(message string) *AppError {
+	return &AppError{
+		Code:       "SERVICE_UNAVAILABLE",
+		Message:    message,
+		StatusCode: http.StatusServiceUnavailable,
+		Err:        ErrServiceUnavailable,
+	}
+}
+
+func NewQuotaExceededError(message string) *AppError {
+	return &AppError{
+		Code:       "QUOTA_EXCEEDED",
+		Message:    message,
+		StatusCode: http.StatusForbidden,
+		Err:        ErrQuotaExceeded,
+	}
+}
+
+func NewEmailNotVerifiedError(message string) *AppError {
+	return &AppError{
+		Code:       "EMAIL_NOT_VERIFIED",
+		Message:    message,
+		StatusCode: http.StatusForbidden,
+		Err:        ErrEmailNotVerified,
+	}
+}
+
+func NewLoginThrottledError(message string, retryAfter time.Duration) *AppError {
+	return &AppError{
+		Code:       "LOGIN_THROTTLED",
+		Message:    message,
+		StatusCode: http.StatusTooManyRequests,
+		Err:        ErrTooManyRequests,
+		RetryAfter: retryAfter,
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"net/http"
 	"testing"
+	"time"
 )
 
 func TestNewUnauthorizedError(t *testing.T) {
@@ -50,6 +51,20 @@ func TestNewBadRequestError(t *testing.T) {
 	}
 }
 
+func TestNewPayloadTooLargeError(t *testing.T) {
+	err := NewPayloadTooLargeError("file too large")
+	if err.Code != "PAYLOAD_TOO_LARGE" || err.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Errorf("unexpected payload too large error: %+v", err)
+	}
+}
+
+func TestNewUnsupportedMediaTypeError(t *testing.T) {
+	err := NewUnsupportedMediaTypeError("type not allowed")
+	if err.Code != "UNSUPPORTED_MEDIA_TYPE" || err.StatusCode != http.StatusUnsupportedMediaType {
+		t.Errorf("unexpected unsupported media type error: %+v", err)
+	}
+}
+
 func TestNewInternalError(t *testing.T) {
 	inner := errors.New("db down")
 	err := NewInternalError("something failed", inner)
@@ -61,6 +76,16 @@ func TestNewInternalError(t *testing.T) {
 	}
 }
 
+func TestNewLoginThrottledError(t *testing.T) {
+	err := NewLoginThrottledError("slow down", 90*time.Second)
+	if err.Code != "LOGIN_THROTTLED" || err.StatusCode != http.StatusTooManyRequests || err.RetryAfter != 90*time.Second {
+		t.Errorf("unexpected login throttled error: %+v", err)
+	}
+	if !errors.Is(err, ErrTooManyRequests) {
+		t.Error("expected login throttling to be a too many requests error")
+	}
+}
+
 func TestAppError_Error(t *testing.T) {
 	err := NewUnauthorizedError("auth failed")
 	if err.Error() == "" {
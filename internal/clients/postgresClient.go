@@ -6,10 +6,17 @@ import (
 	"os"
 	"time"
 
-	"github.com/jackc/pgx/v5"
+	"github.com/exaring/otelpgx"
+	"github.com/jackc/pgx/v5/multitracer"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/suryansh74/task-management-api-project/internal/metrics"
 )
 
-func PostgresClient(user, password, host, port, dbName string) *pgx.Conn {
+// PostgresClient connection pool shared by handlers and background jobs; a
+// *pgx.Conn is not safe for concurrent use and dies with a cancelled query,
+// the pool hands each query or transaction its own connection and replaces
+// broken ones
+func PostgresClient(user, password, host, port, dbName string, maxConns int32) *pgxpool.Pool {
 	dbPath := fmt.Sprintf(
 		"postgres://%s:%s@%s:%s/%s",
 		user,
@@ -19,17 +26,33 @@ func PostgresClient(user, password, host, port, dbName string) *pgx.Conn {
 		dbName,
 	)
 
-	var conn *pgx.Conn
-	var err error
+	poolConfig, err := pgxpool.ParseConfig(dbPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid database config: %v\n", err)
+		os.Exit(1)
+	}
+	if maxConns > 0 {
+		poolConfig.MaxConns = maxConns
+	}
+	// one span per query, child of the request span in ctx, plus a latency histogram
+	poolConfig.ConnConfig.Tracer = multitracer.New(otelpgx.NewTracer(), metrics.QueryTracer{})
+
+	pool, err := pgxpool.NewWithConfig(context.Background(), poolConfig)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid database config: %v\n", err)
+		os.Exit(1)
+	}
+	// the pool connects lazily, ping so startup still waits for the database
 	for attempt := 1; attempt <= 20; attempt++ {
-		conn, err = pgx.Connect(context.Background(), dbPath)
+		err = pool.Ping(context.Background())
 		if err == nil {
 			fmt.Fprintf(os.Stderr, "PostgreSQL connected (attempt %d)\n", attempt)
-			return conn
+			return pool
 		}
 		fmt.Fprintf(os.Stderr, "PostgreSQL dial attempt %d/20 failed: %v (retry in 2s)\n", attempt, err)
 		time.Sleep(2 * time.Second)
 	}
+	pool.Close()
 	fmt.Fprintf(os.Stderr, "Unable to connect to database after retries: %v\n", err)
 	os.Exit(1)
 	return nil
@@ -6,7 +6,9 @@ import (
 	"os"
 	"time"
 
+	"github.com/redis/go-redis/extra/redisotel/v9"
 	"github.com/redis/go-redis/v9"
+	"github.com/suryansh74/task-management-api-project/internal/metrics"
 )
 
 func RedisClient(addr string, password string, db int) *redis.Client {
@@ -28,6 +30,11 @@ func RedisClient(addr string, password string, db int) *redis.Client {
 		err = rdb.Ping(context.Background()).Err()
 		if err == nil {
 			fmt.Fprintf(os.Stderr, "Redis connected (attempt %d)\n", attempt)
+			// one span per command (or pipeline), child of the request span in ctx
+			if err := redisotel.InstrumentTracing(rdb); err != nil {
+				fmt.Fprintf(os.Stderr, "Redis tracing disabled: %v\n", err)
+			}
+			metrics.RegisterRedisPoolStats(rdb)
 			return rdb
 		}
 		fmt.Fprintf(os.Stderr, "Redis ping attempt %d/20 failed: %v (retry in 2s)\n", attempt, err)
@@ -11,12 +11,18 @@ type Config struct {
 	ServerHost string `mapstructure:"SERVER_HOST"`
 	ServerPort string `mapstructure:"SERVER_PORT"`
 	GRPCPort   string `mapstructure:"GRPC_PORT"`
+	// client IPs come from ProxyHeader only on requests from TrustedProxies
+	// (IPs or CIDRs); otherwise, and by default, the connection's address is used
+	ProxyHeader    string   `mapstructure:"PROXY_HEADER"`
+	TrustedProxies []string `mapstructure:"TRUSTED_PROXIES"`
 
 	DBPort     string `mapstructure:"DB_PORT"`
 	DBHost     string `mapstructure:"DB_HOST"`
 	DBUser     string `mapstructure:"DB_USER"`
 	DBName     string `mapstructure:"DB_NAME"`
 	DBPassword string `mapstructure:"DB_PASSWORD"`
+	// connections in the pool shared by handlers and background jobs
+	DBMaxConns int32 `mapstructure:"DB_MAX_CONNS"`
 
 	RedisAddr     string `mapstructure:"REDIS_ADDR"`
 	RedisDB       string `mapstructure:"REDIS_DB"`
@@ -25,6 +31,119 @@ type Config struct {
 	SessionExpiration time.Duration `mapstructure:"SESSION_EXPIRATION"`
 	RedisAppName      string        `mapstructure:"REDIS_APP_NAME"`
 	CacheExpiration   time.Duration `mapstructure:"CACHE_EXPIRATION"`
+
+	// task cache stampede protection, see models.TaskCacheOptions
+	CacheNegativeExpiration time.Duration `mapstructure:"CACHE_NEGATIVE_EXPIRATION"`
+	CacheTTLJitter          float64       `mapstructure:"CACHE_TTL_JITTER"`
+	CacheEarlyRefreshBeta   float64       `mapstructure:"CACHE_EARLY_REFRESH_BETA"`
+
+	// in-process tier in front of Redis, 0 disables it
+	CacheLocalSize int           `mapstructure:"CACHE_LOCAL_SIZE"`
+	CacheLocalTTL  time.Duration `mapstructure:"CACHE_LOCAL_TTL"`
+
+	PublicBaseURL string `mapstructure:"PUBLIC_BASE_URL"`
+
+	// page the reset email links to with ?token=, defaults to <PUBLIC_BASE_URL>/password/reset
+	PasswordResetURL string        `mapstructure:"PASSWORD_RESET_URL"`
+	PasswordResetTTL time.Duration `mapstructure:"PASSWORD_RESET_TTL"`
+
+	// page the verification email links to with ?token=, defaults to <PUBLIC_BASE_URL>/email/verify
+	EmailVerificationURL        string        `mapstructure:"EMAIL_VERIFICATION_URL"`
+	EmailVerificationTTL        time.Duration `mapstructure:"EMAIL_VERIFICATION_TTL"`
+	EmailVerificationSigningKey string        `mapstructure:"EMAIL_VERIFICATION_SIGNING_KEY"`
+	// what unverified accounts may do: off, read_only or block
+	EmailVerificationPolicy string `mapstructure:"EMAIL_VERIFICATION_POLICY"`
+
+	// TOTP two-factor authentication; secrets are encrypted with MFAEncryptionKey,
+	// a login waits MFAChallengeTTL for its code and allows MFAMaxAttempts wrong ones
+	MFAIssuer        string        `mapstructure:"MFA_ISSUER"`
+	MFAEncryptionKey string        `mapstructure:"MFA_ENCRYPTION_KEY"`
+	MFAChallengeTTL  time.Duration `mapstructure:"MFA_CHALLENGE_TTL"`
+	MFAMaxAttempts   int           `mapstructure:"MFA_MAX_ATTEMPTS"`
+
+	// brute-force protection; LoginMaxFailures failures within LoginFailureWindow
+	// lock the account, earlier ones add a delay doubling from LoginBaseDelay, an IP
+	// failing on LoginStuffingAccounts accounts is blocked for LoginStuffingBlock, an
+	// account failing from LoginDistributedIPs IPs is reported as a distributed attack
+	LoginMaxFailures      int           `mapstructure:"LOGIN_MAX_FAILURES"`
+	LoginFailureWindow    time.Duration `mapstructure:"LOGIN_FAILURE_WINDOW"`
+	LoginLockoutDuration  time.Duration `mapstructure:"LOGIN_LOCKOUT_DURATION"`
+	LoginBaseDelay        time.Duration `mapstructure:"LOGIN_BASE_DELAY"`
+	LoginMaxDelay         time.Duration `mapstructure:"LOGIN_MAX_DELAY"`
+	LoginStuffingAccounts int           `mapstructure:"LOGIN_STUFFING_ACCOUNTS"`
+	LoginStuffingBlock    time.Duration `mapstructure:"LOGIN_STUFFING_BLOCK"`
+	LoginDistributedIPs   int           `mapstructure:"LOGIN_DISTRIBUTED_IPS"`
+
+	BlobStore         string        `mapstructure:"BLOB_STORE"`
+	BlobLocalDir      string        `mapstructure:"BLOB_LOCAL_DIR"`
+	BlobSigningKey    string        `mapstructure:"BLOB_SIGNING_KEY"`
+	BlobURLExpiration time.Duration `mapstructure:"BLOB_URL_EXPIRATION"`
+
+	S3Endpoint  string `mapstructure:"S3_ENDPOINT"`
+	S3Region    string `mapstructure:"S3_REGION"`
+	S3Bucket    string `mapstructure:"S3_BUCKET"`
+	S3AccessKey string `mapstructure:"S3_ACCESS_KEY"`
+	S3SecretKey string `mapstructure:"S3_SECRET_KEY"`
+	S3UseSSL    bool   `mapstructure:"S3_USE_SSL"`
+
+	AttachmentMaxSize       int64         `mapstructure:"ATTACHMENT_MAX_SIZE"`
+	AttachmentAllowedTypes  []string      `mapstructure:"ATTACHMENT_ALLOWED_TYPES"`
+	AttachmentSweepInterval time.Duration `mapstructure:"ATTACHMENT_SWEEP_INTERVAL"`
+	TransferTimeout         time.Duration `mapstructure:"TRANSFER_TIMEOUT"`
+
+	RecurrenceInterval time.Duration `mapstructure:"RECURRENCE_INTERVAL"`
+
+	ReminderInterval       time.Duration `mapstructure:"REMINDER_INTERVAL"`
+	ReminderLookahead      time.Duration `mapstructure:"REMINDER_LOOKAHEAD"`
+	ReminderBatchSize      int           `mapstructure:"REMINDER_BATCH_SIZE"`
+	ReminderWebhookSecret  string        `mapstructure:"REMINDER_WEBHOOK_SECRET"`
+	ReminderWebhookTimeout time.Duration `mapstructure:"REMINDER_WEBHOOK_TIMEOUT"`
+
+	SMTPHost     string        `mapstructure:"SMTP_HOST"`
+	SMTPPort     string        `mapstructure:"SMTP_PORT"`
+	SMTPUsername string        `mapstructure:"SMTP_USERNAME"`
+	SMTPPassword string        `mapstructure:"SMTP_PASSWORD"`
+	SMTPFrom     string        `mapstructure:"SMTP_FROM"`
+	SMTPTimeout  time.Duration `mapstructure:"SMTP_TIMEOUT"`
+
+	MailWorkerInterval time.Duration `mapstructure:"MAIL_WORKER_INTERVAL"`
+	MailMaxAttempts    int           `mapstructure:"MAIL_MAX_ATTEMPTS"`
+	MailRetryBackoff   time.Duration `mapstructure:"MAIL_RETRY_BACKOFF"`
+	MailClaimTimeout   time.Duration `mapstructure:"MAIL_CLAIM_TIMEOUT"`
+
+	IdempotencyTTL     time.Duration `mapstructure:"IDEMPOTENCY_TTL"`
+	IdempotencyLockTTL time.Duration `mapstructure:"IDEMPOTENCY_LOCK_TTL"`
+
+	// rate limits are "<requests>/<window>", e.g. "100/1m"
+	RateLimitPublic       string `mapstructure:"RATE_LIMIT_PUBLIC"`
+	RateLimitTask         string `mapstructure:"RATE_LIMIT_TASK"`
+	RateLimitRoutes       string `mapstructure:"RATE_LIMIT_ROUTES"`
+	RateLimitFailOpen     bool   `mapstructure:"RATE_LIMIT_FAIL_OPEN"`
+	RateLimitPassword     string `mapstructure:"RATE_LIMIT_PASSWORD"`
+	RateLimitVerification string `mapstructure:"RATE_LIMIT_VERIFICATION"`
+	// gRPC limits, methods are "<Method>=<limit>/<window>" overrides
+	RateLimitGRPC        string `mapstructure:"RATE_LIMIT_GRPC"`
+	RateLimitGRPCMethods string `mapstructure:"RATE_LIMIT_GRPC_METHODS"`
+
+	// per-user storage quotas, 0 disables a quota
+	QuotaMaxTasks        int   `mapstructure:"QUOTA_MAX_TASKS"`
+	QuotaMaxContentBytes int64 `mapstructure:"QUOTA_MAX_CONTENT_BYTES"`
+
+	// readiness fails for ShutdownDelay before draining, drain is capped by ShutdownTimeout
+	ShutdownDelay   time.Duration `mapstructure:"SHUTDOWN_DELAY"`
+	ShutdownTimeout time.Duration `mapstructure:"SHUTDOWN_TIMEOUT"`
+
+	HealthCheckTimeout  time.Duration `mapstructure:"HEALTH_CHECK_TIMEOUT"`
+	HealthCheckInterval time.Duration `mapstructure:"HEALTH_CHECK_INTERVAL"`
+
+	// tracing is off (no-op) unless OTLP_ENDPOINT is set, e.g. "otel-collector:4317"
+	OTLPEndpoint     string  `mapstructure:"OTLP_ENDPOINT"`
+	OTLPInsecure     bool    `mapstructure:"OTLP_INSECURE"`
+	OTelServiceName  string  `mapstructure:"OTEL_SERVICE_NAME"`
+	TraceSampleRatio float64 `mapstructure:"TRACE_SAMPLE_RATIO"`
+
+	// refresh interval of gauges that need a query, e.g. active sessions
+	MetricsInterval time.Duration `mapstructure:"METRICS_INTERVAL"`
 }
 
 func LoadConfig(path string) (config Config, err error) {
@@ -37,10 +156,27 @@ func LoadConfig(path string) (config Config, err error) {
 
 	// Explicitly bind so Unmarshal sees Docker/K8s environment variables
 	for _, key := range []string{
-		"SERVER_HOST", "SERVER_PORT", "GRPC_PORT",
-		"DB_HOST", "DB_PORT", "DB_USER", "DB_NAME", "DB_PASSWORD",
+		"SERVER_HOST", "SERVER_PORT", "GRPC_PORT", "PROXY_HEADER", "TRUSTED_PROXIES",
+		"DB_HOST", "DB_PORT", "DB_USER", "DB_NAME", "DB_PASSWORD", "DB_MAX_CONNS",
 		"REDIS_ADDR", "REDIS_DB", "REDIS_PASSWORD", "REDIS_APP_NAME",
-		"SESSION_EXPIRATION", "CACHE_EXPIRATION",
+		"SESSION_EXPIRATION", "CACHE_EXPIRATION", "PUBLIC_BASE_URL", "PASSWORD_RESET_URL", "PASSWORD_RESET_TTL",
+		"EMAIL_VERIFICATION_URL", "EMAIL_VERIFICATION_TTL", "EMAIL_VERIFICATION_SIGNING_KEY", "EMAIL_VERIFICATION_POLICY",
+		"MFA_ISSUER", "MFA_ENCRYPTION_KEY", "MFA_CHALLENGE_TTL", "MFA_MAX_ATTEMPTS",
+		"LOGIN_MAX_FAILURES", "LOGIN_FAILURE_WINDOW", "LOGIN_LOCKOUT_DURATION", "LOGIN_BASE_DELAY", "LOGIN_MAX_DELAY",
+		"LOGIN_STUFFING_ACCOUNTS", "LOGIN_STUFFING_BLOCK", "LOGIN_DISTRIBUTED_IPS",
+		"CACHE_NEGATIVE_EXPIRATION", "CACHE_TTL_JITTER", "CACHE_EARLY_REFRESH_BETA", "CACHE_LOCAL_SIZE", "CACHE_LOCAL_TTL",
+		"BLOB_STORE", "BLOB_LOCAL_DIR", "BLOB_SIGNING_KEY", "BLOB_URL_EXPIRATION",
+		"S3_ENDPOINT", "S3_REGION", "S3_BUCKET", "S3_ACCESS_KEY", "S3_SECRET_KEY", "S3_USE_SSL",
+		"ATTACHMENT_MAX_SIZE", "ATTACHMENT_ALLOWED_TYPES", "ATTACHMENT_SWEEP_INTERVAL", "TRANSFER_TIMEOUT", "RECURRENCE_INTERVAL",
+		"REMINDER_INTERVAL", "REMINDER_LOOKAHEAD", "REMINDER_BATCH_SIZE", "REMINDER_WEBHOOK_SECRET", "REMINDER_WEBHOOK_TIMEOUT",
+		"SMTP_HOST", "SMTP_PORT", "SMTP_USERNAME", "SMTP_PASSWORD", "SMTP_FROM", "SMTP_TIMEOUT",
+		"MAIL_WORKER_INTERVAL", "MAIL_MAX_ATTEMPTS", "MAIL_RETRY_BACKOFF", "MAIL_CLAIM_TIMEOUT",
+		"IDEMPOTENCY_TTL", "IDEMPOTENCY_LOCK_TTL",
+		"RATE_LIMIT_PUBLIC", "RATE_LIMIT_TASK", "RATE_LIMIT_ROUTES", "RATE_LIMIT_FAIL_OPEN", "RATE_LIMIT_PASSWORD",
+		"RATE_LIMIT_VERIFICATION",
+		"RATE_LIMIT_GRPC", "RATE_LIMIT_GRPC_METHODS", "QUOTA_MAX_TASKS", "QUOTA_MAX_CONTENT_BYTES",
+		"SHUTDOWN_DELAY", "SHUTDOWN_TIMEOUT", "HEALTH_CHECK_TIMEOUT", "HEALTH_CHECK_INTERVAL",
+		"OTLP_ENDPOINT", "OTLP_INSECURE", "OTEL_SERVICE_NAME", "TRACE_SAMPLE_RATIO", "METRICS_INTERVAL",
 	} {
 		_ = viper.BindEnv(key)
 	}
@@ -49,9 +185,69 @@ func LoadConfig(path string) (config Config, err error) {
 	viper.SetDefault("SERVER_PORT", "8000")
 	viper.SetDefault("GRPC_PORT", "50051")
 	viper.SetDefault("REDIS_DB", "0")
+	viper.SetDefault("DB_MAX_CONNS", 10)
 	viper.SetDefault("SESSION_EXPIRATION", "30m")
 	viper.SetDefault("CACHE_EXPIRATION", "10m")
+	viper.SetDefault("CACHE_NEGATIVE_EXPIRATION", "30s")
+	viper.SetDefault("CACHE_TTL_JITTER", 0.1)
+	viper.SetDefault("CACHE_EARLY_REFRESH_BETA", 1.0)
+	viper.SetDefault("CACHE_LOCAL_SIZE", 10000)
+	viper.SetDefault("CACHE_LOCAL_TTL", "5s") // bounds staleness if an invalidation is lost
 	viper.SetDefault("REDIS_APP_NAME", "task-management-api")
+	viper.SetDefault("PUBLIC_BASE_URL", "http://localhost:8000")
+	viper.SetDefault("PASSWORD_RESET_TTL", "30m")
+	viper.SetDefault("EMAIL_VERIFICATION_TTL", "24h")
+	viper.SetDefault("EMAIL_VERIFICATION_POLICY", "read_only")
+	viper.SetDefault("MFA_ISSUER", "Task Management API")
+	viper.SetDefault("MFA_CHALLENGE_TTL", "5m")
+	viper.SetDefault("MFA_MAX_ATTEMPTS", 5)
+	viper.SetDefault("LOGIN_MAX_FAILURES", 10)
+	viper.SetDefault("LOGIN_FAILURE_WINDOW", "15m")
+	viper.SetDefault("LOGIN_LOCKOUT_DURATION", "15m")
+	viper.SetDefault("LOGIN_BASE_DELAY", "1s")
+	viper.SetDefault("LOGIN_MAX_DELAY", "30s")
+	viper.SetDefault("LOGIN_STUFFING_ACCOUNTS", 20)
+	viper.SetDefault("LOGIN_STUFFING_BLOCK", "1h")
+	viper.SetDefault("LOGIN_DISTRIBUTED_IPS", 5)
+	viper.SetDefault("BLOB_STORE", "local")
+	viper.SetDefault("BLOB_LOCAL_DIR", "./data/blobs")
+	viper.SetDefault("BLOB_URL_EXPIRATION", "15m")
+	viper.SetDefault("S3_REGION", "us-east-1")
+	viper.SetDefault("S3_BUCKET", "task-attachments")
+	viper.SetDefault("ATTACHMENT_MAX_SIZE", 10<<20) // 10 MiB
+	viper.SetDefault("ATTACHMENT_ALLOWED_TYPES", "image/png,image/jpeg,image/gif,image/webp,text/plain,application/pdf,application/zip")
+	viper.SetDefault("ATTACHMENT_SWEEP_INTERVAL", "5m")
+	viper.SetDefault("TRANSFER_TIMEOUT", "10m")
+	viper.SetDefault("RECURRENCE_INTERVAL", "1m")
+	viper.SetDefault("REMINDER_INTERVAL", "30s")
+	viper.SetDefault("REMINDER_LOOKAHEAD", "2m") // keep above REMINDER_INTERVAL
+	viper.SetDefault("REMINDER_BATCH_SIZE", 100)
+	viper.SetDefault("REMINDER_WEBHOOK_TIMEOUT", "10s")
+	viper.SetDefault("SMTP_PORT", "1025")
+	viper.SetDefault("SMTP_FROM", "tasks@localhost")
+	viper.SetDefault("SMTP_TIMEOUT", "30s")
+	viper.SetDefault("MAIL_WORKER_INTERVAL", "2s")
+	viper.SetDefault("MAIL_MAX_ATTEMPTS", 5)
+	viper.SetDefault("MAIL_RETRY_BACKOFF", "30s")
+	viper.SetDefault("MAIL_CLAIM_TIMEOUT", "5m") // keep above the slowest worker batch
+	viper.SetDefault("IDEMPOTENCY_TTL", "24h")
+	viper.SetDefault("IDEMPOTENCY_LOCK_TTL", "1m") // keep above the slowest request
+	viper.SetDefault("RATE_LIMIT_PUBLIC", "10/1m")
+	viper.SetDefault("RATE_LIMIT_TASK", "100/1m")
+	viper.SetDefault("RATE_LIMIT_FAIL_OPEN", true)
+	viper.SetDefault("RATE_LIMIT_PASSWORD", "5/15m")
+	viper.SetDefault("RATE_LIMIT_VERIFICATION", "3/15m")
+	viper.SetDefault("RATE_LIMIT_GRPC", "100/1m")
+	viper.SetDefault("QUOTA_MAX_TASKS", 10000)
+	viper.SetDefault("QUOTA_MAX_CONTENT_BYTES", 10<<20) // 10 MiB of titles and contents
+	viper.SetDefault("SHUTDOWN_DELAY", "5s")
+	viper.SetDefault("SHUTDOWN_TIMEOUT", "20s")      // keep delay + timeout below terminationGracePeriodSeconds
+	viper.SetDefault("HEALTH_CHECK_TIMEOUT", "2s")   // per check, keep the sum below the probe timeout
+	viper.SetDefault("HEALTH_CHECK_INTERVAL", "10s") // gRPC health status refresh
+	viper.SetDefault("OTLP_INSECURE", true)
+	viper.SetDefault("OTEL_SERVICE_NAME", "task-management-api")
+	viper.SetDefault("TRACE_SAMPLE_RATIO", 1.0)
+	viper.SetDefault("METRICS_INTERVAL", "15s")
 
 	// Optional .env file (ignore if missing)
 	_ = viper.ReadInConfig()
@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"mime"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog"
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/http/response"
+	"github.com/suryansh74/task-management-api-project/internal/logger"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+)
+
+type AttachmentHandler struct {
+	attachmentService ports.AttachmentService
+}
+
+// NewAttachmentHandler Constructor for AttachmentHandler
+// =========================================================================
+func NewAttachmentHandler(attachmentService ports.AttachmentService) *AttachmentHandler {
+	logger.Log.Info().Msg("initializing attachment handler")
+	return &AttachmentHandler{
+		attachmentService: attachmentService,
+	}
+}
+
+// UploadAttachment accepts multipart field "file"
+// =========================================================================
+func (h *AttachmentHandler) UploadAttachment(c *fiber.Ctx) error {
+	taskID := c.Params("id")
+
+	zerolog.Ctx(c.UserContext()).Info().
+		Str("method", c.Method()).
+		Str("path", c.Path()).
+		Str("task_id", taskID).
+		Str("ip", c.IP()).
+		Msg("received request to upload attachment")
+
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return apperror.NewUnauthorizedError("invalid auth context")
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		zerolog.Ctx(c.UserContext()).Warn().
+			Err(err).
+			Str("task_id", taskID).
+			Msg("missing multipart file field")
+		return apperror.NewBadRequestError("multipart field 'file' is required")
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return apperror.NewBadRequestError("unable to read uploaded file")
+	}
+	defer file.Close()
+
+	attachment, err := h.attachmentService.UploadAttachment(c.UserContext(), taskID, userID, &ports.AttachmentUpload{
+		Filename: fileHeader.Filename,
+		Size:     fileHeader.Size,
+		Body:     file,
+	})
+	if err != nil {
+		zerolog.Ctx(c.UserContext()).Error().
+			Err(err).
+			Str("task_id", taskID).
+			Msg("failed to upload attachment")
+		return err
+	}
+
+	zerolog.Ctx(c.UserContext()).Info().
+		Str("attachment_id", attachment.ID).
+		Str("task_id", taskID).
+		Int("status", fiber.StatusCreated).
+		Msg("attachment uploaded successfully")
+
+	return response.Success(c, fiber.StatusCreated, "Attachment Uploaded", attachment)
+}
+
+// GetAttachments list task attachments
+// =========================================================================
+func (h *AttachmentHandler) GetAttachments(c *fiber.Ctx) error {
+	taskID := c.Params("id")
+
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return apperror.NewUnauthorizedError("invalid auth context")
+	}
+
+	attachments, err := h.attachmentService.GetAttachments(c.UserContext(), taskID, userID)
+	if err != nil {
+		zerolog.Ctx(c.UserContext()).Error().
+			Err(err).
+			Str("task_id", taskID).
+			Msg("failed to fetch attachments")
+		return err
+	}
+
+	return response.Success(c, fiber.StatusOK, "All Returned Attachments", attachments)
+}
+
+// GetDownloadURL returns signed download link
+// =========================================================================
+func (h *AttachmentHandler) GetDownloadURL(c *fiber.Ctx) error {
+	taskID := c.Params("id")
+	attachmentID := c.Params("attachmentID")
+
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return apperror.NewUnauthorizedError("invalid auth context")
+	}
+
+	download, err := h.attachmentService.GetDownloadURL(c.UserContext(), taskID, attachmentID, userID)
+	if err != nil {
+		zerolog.Ctx(c.UserContext()).Error().
+			Err(err).
+			Str("task_id", taskID).
+			Str("attachment_id", attachmentID).
+			Msg("failed to create download url")
+		return err
+	}
+
+	return response.Success(c, fiber.StatusOK, "Download URL Created", download)
+}
+
+// DeleteAttachmentByID delete attachment
+// =========================================================================
+func (h *AttachmentHandler) DeleteAttachmentByID(c *fiber.Ctx) error {
+	taskID := c.Params("id")
+	attachmentID := c.Params("attachmentID")
+
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return apperror.NewUnauthorizedError("invalid auth context")
+	}
+
+	if err := h.attachmentService.DeleteAttachmentByID(c.UserContext(), taskID, attachmentID, userID); err != nil {
+		zerolog.Ctx(c.UserContext()).Error().
+			Err(err).
+			Str("task_id", taskID).
+			Str("attachment_id", attachmentID).
+			Msg("failed to delete attachment")
+		return err
+	}
+
+	return response.Success(c, fiber.StatusOK, "Attachment Deleted", nil)
+}
+
+// DownloadSignedFile streams a blob for a valid signed link (no session needed)
+// =========================================================================
+func (h *AttachmentHandler) DownloadSignedFile(c *fiber.Ctx) error {
+	key := c.Params("*")
+	filename := c.Query("filename")
+
+	expires, err := strconv.ParseInt(c.Query("expires"), 10, 64)
+	if err != nil {
+		return apperror.NewBadRequestError("invalid expires")
+	}
+
+	body, err := h.attachmentService.OpenSignedFile(c.UserContext(), key, filename, expires, c.Query("signature"))
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	c.Set(fiber.HeaderContentType, fiber.MIMEOctetStream)
+	// fasthttp closes the stream once it has been fully written
+	return c.Status(fiber.StatusOK).SendStream(body)
+}
@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog"
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/http/response"
+	"github.com/suryansh74/task-management-api-project/internal/logger"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+)
+
+type CalendarFeedHandler struct {
+	feedService ports.CalendarFeedService
+}
+
+// NewCalendarFeedHandler Constructor for CalendarFeedHandler
+// =========================================================================
+func NewCalendarFeedHandler(feedService ports.CalendarFeedService) *CalendarFeedHandler {
+	logger.Log.Info().Msg("initializing calendar feed handler")
+	return &CalendarFeedHandler{
+		feedService: feedService,
+	}
+}
+
+// GetFeed whether the user has a calendar feed
+// =========================================================================
+func (h *CalendarFeedHandler) GetFeed(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return apperror.NewUnauthorizedError("invalid auth context")
+	}
+
+	feed, err := h.feedService.GetFeed(c.UserContext(), userID)
+	if err != nil {
+		zerolog.Ctx(c.UserContext()).Error().
+			Err(err).
+			Msg("failed to fetch calendar feed")
+		return err
+	}
+
+	return response.Success(c, fiber.StatusOK, "Calendar Feed", feed)
+}
+
+// RotateFeedToken create the feed or issue a new URL for it
+// =========================================================================
+func (h *CalendarFeedHandler) RotateFeedToken(c *fiber.Ctx) error {
+	zerolog.Ctx(c.UserContext()).Info().
+		Str("method", c.Method()).
+		Str("path", c.Path()).
+		Str("ip", c.IP()).
+		Msg("received request to rotate calendar feed token")
+
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return apperror.NewUnauthorizedError("invalid auth context")
+	}
+
+	feed, err := h.feedService.RotateFeedToken(c.UserContext(), userID)
+	if err != nil {
+		zerolog.Ctx(c.UserContext()).Error().
+			Err(err).
+			Msg("failed to rotate calendar feed token")
+		return err
+	}
+
+	return response.Success(c, fiber.StatusOK, "Calendar Feed URL Issued", feed)
+}
+
+// DisableFeed remove the calendar feed
+// =========================================================================
+func (h *CalendarFeedHandler) DisableFeed(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return apperror.NewUnauthorizedError("invalid auth context")
+	}
+
+	if err := h.feedService.DisableFeed(c.UserContext(), userID); err != nil {
+		zerolog.Ctx(c.UserContext()).Error().
+			Err(err).
+			Msg("failed to disable calendar feed")
+		return err
+	}
+
+	return response.Success(c, fiber.StatusOK, "Calendar Feed Disabled", nil)
+}
+
+// ServeFeed public .ics download, the token in the path is the credential
+// =========================================================================
+func (h *CalendarFeedHandler) ServeFeed(c *fiber.Ctx) error {
+	// the path is a secret, don't log it
+	zerolog.Ctx(c.UserContext()).Info().
+		Str("method", c.Method()).
+		Str("ip", c.IP()).
+		Msg("received request for calendar feed")
+
+	body, etag, err := h.feedService.RenderFeed(c.UserContext(), c.Params("token"), c.Query("type"))
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderETag, etag)
+	c.Set(fiber.HeaderCacheControl, "private, no-cache")
+	if etagMatches(c.Get(fiber.HeaderIfNoneMatch), etag) {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
+
+	c.Set(fiber.HeaderContentType, "text/calendar; charset=utf-8")
+	return c.Status(fiber.StatusOK).Send(body)
+}
+
+// etagMatches weak comparison against an If-None-Match list
+func etagMatches(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+	return false
+}
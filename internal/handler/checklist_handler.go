@@ -0,0 +1,211 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog"
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/http/response"
+	"github.com/suryansh74/task-management-api-project/internal/logger"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+	"github.com/suryansh74/task-management-api-project/internal/validator"
+)
+
+type ChecklistHandler struct {
+	checklistService ports.ChecklistService
+}
+
+// NewChecklistHandler Constructor for ChecklistHandler
+// =========================================================================
+func NewChecklistHandler(checklistService ports.ChecklistService) *ChecklistHandler {
+	logger.Log.Info().Msg("initializing checklist handler")
+	return &ChecklistHandler{
+		checklistService: checklistService,
+	}
+}
+
+// AddChecklistItemRequest dto for incoming req
+// =========================================================================
+type AddChecklistItemRequest struct {
+	Text string `json:"text" validate:"required,max=200"`
+}
+
+// UpdateChecklistItemRequest dto for incoming req, omitted fields keep their value
+// =========================================================================
+type UpdateChecklistItemRequest struct {
+	Text    *string `json:"text" validate:"omitempty,min=1,max=200"`
+	Checked *bool   `json:"checked"`
+}
+
+// ReorderChecklistRequest dto for incoming req, every item id in the new order
+// =========================================================================
+type ReorderChecklistRequest struct {
+	ItemIDs []string `json:"item_ids" validate:"required,dive,uuid"`
+}
+
+// AddItem append item to the checklist of a task
+// =========================================================================
+func (h *ChecklistHandler) AddItem(c *fiber.Ctx) error {
+	taskID := c.Params("id")
+
+	zerolog.Ctx(c.UserContext()).Info().
+		Str("method", c.Method()).
+		Str("path", c.Path()).
+		Str("task_id", taskID).
+		Str("ip", c.IP()).
+		Msg("received request to add checklist item")
+
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return apperror.NewUnauthorizedError("invalid auth context")
+	}
+
+	var req AddChecklistItemRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.NewBadRequestError("Invalid request body")
+	}
+	if fieldErrors := validator.ValidateStruct(req); len(fieldErrors) > 0 {
+		return response.ValidationError(c, fieldErrors)
+	}
+
+	item, err := h.checklistService.AddItem(c.UserContext(), taskID, userID, req.Text)
+	if err != nil {
+		zerolog.Ctx(c.UserContext()).Error().
+			Err(err).
+			Str("task_id", taskID).
+			Msg("failed to add checklist item")
+		return err
+	}
+
+	return response.Success(c, fiber.StatusCreated, "Checklist Item Added", item)
+}
+
+// GetItems list checklist of a task
+// =========================================================================
+func (h *ChecklistHandler) GetItems(c *fiber.Ctx) error {
+	taskID := c.Params("id")
+
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return apperror.NewUnauthorizedError("invalid auth context")
+	}
+
+	items, err := h.checklistService.GetItems(c.UserContext(), taskID, userID)
+	if err != nil {
+		zerolog.Ctx(c.UserContext()).Error().
+			Err(err).
+			Str("task_id", taskID).
+			Msg("failed to fetch checklist")
+		return err
+	}
+
+	return response.Success(c, fiber.StatusOK, "All Returned Checklist Items", items)
+}
+
+// UpdateItem change text or checked state of an item
+// =========================================================================
+func (h *ChecklistHandler) UpdateItem(c *fiber.Ctx) error {
+	taskID := c.Params("id")
+	itemID := c.Params("itemID")
+
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return apperror.NewUnauthorizedError("invalid auth context")
+	}
+
+	var req UpdateChecklistItemRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.NewBadRequestError("Invalid request body")
+	}
+	if fieldErrors := validator.ValidateStruct(req); len(fieldErrors) > 0 {
+		return response.ValidationError(c, fieldErrors)
+	}
+
+	item, err := h.checklistService.UpdateItem(c.UserContext(), taskID, itemID, userID, req.Text, req.Checked)
+	if err != nil {
+		zerolog.Ctx(c.UserContext()).Error().
+			Err(err).
+			Str("task_id", taskID).
+			Str("item_id", itemID).
+			Msg("failed to update checklist item")
+		return err
+	}
+
+	return response.Success(c, fiber.StatusOK, "Checklist Item Updated", item)
+}
+
+// ToggleItem flip checked state of an item
+// =========================================================================
+func (h *ChecklistHandler) ToggleItem(c *fiber.Ctx) error {
+	taskID := c.Params("id")
+	itemID := c.Params("itemID")
+
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return apperror.NewUnauthorizedError("invalid auth context")
+	}
+
+	item, err := h.checklistService.ToggleItem(c.UserContext(), taskID, itemID, userID)
+	if err != nil {
+		zerolog.Ctx(c.UserContext()).Error().
+			Err(err).
+			Str("task_id", taskID).
+			Str("item_id", itemID).
+			Msg("failed to toggle checklist item")
+		return err
+	}
+
+	return response.Success(c, fiber.StatusOK, "Checklist Item Toggled", item)
+}
+
+// ReorderItems replace the order of the whole checklist
+// =========================================================================
+func (h *ChecklistHandler) ReorderItems(c *fiber.Ctx) error {
+	taskID := c.Params("id")
+
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return apperror.NewUnauthorizedError("invalid auth context")
+	}
+
+	var req ReorderChecklistRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.NewBadRequestError("Invalid request body")
+	}
+	if fieldErrors := validator.ValidateStruct(req); len(fieldErrors) > 0 {
+		return response.ValidationError(c, fieldErrors)
+	}
+
+	items, err := h.checklistService.ReorderItems(c.UserContext(), taskID, userID, req.ItemIDs)
+	if err != nil {
+		zerolog.Ctx(c.UserContext()).Error().
+			Err(err).
+			Str("task_id", taskID).
+			Msg("failed to reorder checklist")
+		return err
+	}
+
+	return response.Success(c, fiber.StatusOK, "Checklist Reordered", items)
+}
+
+// DeleteItem remove item from the checklist
+// =========================================================================
+func (h *ChecklistHandler) DeleteItem(c *fiber.Ctx) error {
+	taskID := c.Params("id")
+	itemID := c.Params("itemID")
+
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return apperror.NewUnauthorizedError("invalid auth context")
+	}
+
+	if err := h.checklistService.DeleteItem(c.UserContext(), taskID, itemID, userID); err != nil {
+		zerolog.Ctx(c.UserContext()).Error().
+			Err(err).
+			Str("task_id", taskID).
+			Str("item_id", itemID).
+			Msg("failed to delete checklist item")
+		return err
+	}
+
+	return response.Success(c, fiber.StatusOK, "Checklist Item Deleted", nil)
+}
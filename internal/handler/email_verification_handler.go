@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog"
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/http/response"
+	"github.com/suryansh74/task-management-api-project/internal/logger"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+	"github.com/suryansh74/task-management-api-project/internal/validator"
+)
+
+type EmailVerificationHandler struct {
+	verificationService ports.EmailVerificationService
+}
+
+// NewEmailVerificationHandler Constructor for EmailVerificationHandler
+// =========================================================================
+func NewEmailVerificationHandler(verificationService ports.EmailVerificationService) *EmailVerificationHandler {
+	logger.Log.Info().Msg("initializing email verification handler")
+	return &EmailVerificationHandler{
+		verificationService: verificationService,
+	}
+}
+
+// VerifyEmailRequest dto for incoming req
+// =========================================================================
+type VerifyEmailRequest struct {
+	Token string `json:"token" validate:"required"`
+}
+
+// VerifyEmail verify the address with a token from the verification email,
+// no session needed so the link works on any device
+// =========================================================================
+func (h *EmailVerificationHandler) VerifyEmail(c *fiber.Ctx) error {
+	zerolog.Ctx(c.UserContext()).Info().
+		Str("method", c.Method()).
+		Str("path", c.Path()).
+		Str("ip", c.IP()).
+		Msg("received verify email request")
+
+	var req VerifyEmailRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.NewBadRequestError("Invalid request body")
+	}
+	if fieldErrors := validator.ValidateStruct(req); len(fieldErrors) > 0 {
+		return response.ValidationError(c, fieldErrors)
+	}
+
+	if err := h.verificationService.Verify(c.UserContext(), req.Token); err != nil {
+		zerolog.Ctx(c.UserContext()).Warn().
+			Err(err).
+			Str("ip", c.IP()).
+			Msg("email verification failed")
+		return err
+	}
+
+	return response.Success(c, fiber.StatusOK, "Email verified successfully", nil)
+}
+
+// ResendVerification email the logged in user a new verification link
+// =========================================================================
+func (h *EmailVerificationHandler) ResendVerification(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return apperror.NewUnauthorizedError("invalid auth context")
+	}
+
+	if err := h.verificationService.SendVerification(c.UserContext(), userID); err != nil {
+		zerolog.Ctx(c.UserContext()).Warn().
+			Err(err).
+			Msg("failed to resend verification email")
+		return err
+	}
+
+	return response.Success(c, fiber.StatusAccepted, "Verification email sent", nil)
+}
@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog"
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/http/response"
+	"github.com/suryansh74/task-management-api-project/internal/logger"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+	"github.com/suryansh74/task-management-api-project/internal/validator"
+)
+
+type MFAHandler struct {
+	mfaService ports.MFAService
+}
+
+// NewMFAHandler Constructor for MFAHandler
+// =========================================================================
+func NewMFAHandler(mfaService ports.MFAService) *MFAHandler {
+	logger.Log.Info().Msg("initializing mfa handler")
+	return &MFAHandler{
+		mfaService: mfaService,
+	}
+}
+
+// MFACodeRequest dto for incoming req
+// =========================================================================
+type MFACodeRequest struct {
+	Code string `json:"code" validate:"required,max=32"`
+}
+
+// Enroll start 2FA enrollment, the secret and QR URI are only shown here
+// =========================================================================
+func (h *MFAHandler) Enroll(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return apperror.NewUnauthorizedError("invalid auth context")
+	}
+
+	enrollment, err := h.mfaService.Enroll(c.UserContext(), userID)
+	if err != nil {
+		zerolog.Ctx(c.UserContext()).Warn().
+			Err(err).
+			Msg("failed to start mfa enrollment")
+		return err
+	}
+
+	return response.Success(c, fiber.StatusOK, "Scan the QR code, then confirm with a code from your app", enrollment)
+}
+
+// Confirm enable 2FA with a first code, answers with the recovery codes
+// =========================================================================
+func (h *MFAHandler) Confirm(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return apperror.NewUnauthorizedError("invalid auth context")
+	}
+
+	var req MFACodeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.NewBadRequestError("Invalid request body")
+	}
+	if fieldErrors := validator.ValidateStruct(req); len(fieldErrors) > 0 {
+		return response.ValidationError(c, fieldErrors)
+	}
+
+	codes, err := h.mfaService.Confirm(c.UserContext(), userID, req.Code)
+	if err != nil {
+		zerolog.Ctx(c.UserContext()).Warn().
+			Err(err).
+			Msg("failed to confirm mfa enrollment")
+		return err
+	}
+
+	return response.Success(c, fiber.StatusOK, "Two-factor authentication enabled, store the recovery codes safely", fiber.Map{
+		"recovery_codes": codes,
+	})
+}
+
+// Disable turn 2FA off with a current or recovery code
+// =========================================================================
+func (h *MFAHandler) Disable(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return apperror.NewUnauthorizedError("invalid auth context")
+	}
+
+	var req MFACodeRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.NewBadRequestError("Invalid request body")
+	}
+	if fieldErrors := validator.ValidateStruct(req); len(fieldErrors) > 0 {
+		return response.ValidationError(c, fieldErrors)
+	}
+
+	if err := h.mfaService.Disable(c.UserContext(), userID, req.Code, c.IP()); err != nil {
+		zerolog.Ctx(c.UserContext()).Warn().
+			Err(err).
+			Msg("failed to disable mfa")
+		return err
+	}
+
+	return response.Success(c, fiber.StatusOK, "Two-factor authentication disabled", nil)
+}
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog"
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/http/response"
+	"github.com/suryansh74/task-management-api-project/internal/logger"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+)
+
+type NotificationHandler struct {
+	preferencesService ports.NotificationPreferencesService
+}
+
+// NewNotificationHandler Constructor for NotificationHandler
+// =========================================================================
+func NewNotificationHandler(preferencesService ports.NotificationPreferencesService) *NotificationHandler {
+	logger.Log.Info().Msg("initializing notification handler")
+	return &NotificationHandler{
+		preferencesService: preferencesService,
+	}
+}
+
+// UpdatePreferencesRequest dto for incoming req, omitted fields keep their value
+// =========================================================================
+type UpdatePreferencesRequest struct {
+	EmailEnabled *bool `json:"email_enabled"`
+	Reminders    *bool `json:"reminders"`
+	Invitations  *bool `json:"invitations"`
+}
+
+// GetPreferences return notification preferences of the user
+// =========================================================================
+func (h *NotificationHandler) GetPreferences(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return apperror.NewUnauthorizedError("invalid auth context")
+	}
+
+	prefs, err := h.preferencesService.GetPreferences(c.UserContext(), userID)
+	if err != nil {
+		zerolog.Ctx(c.UserContext()).Error().
+			Err(err).
+			Msg("failed to fetch notification preferences")
+		return err
+	}
+
+	return response.Success(c, fiber.StatusOK, "Notification Preferences", prefs)
+}
+
+// UpdatePreferences change notification preferences of the user
+// =========================================================================
+func (h *NotificationHandler) UpdatePreferences(c *fiber.Ctx) error {
+	zerolog.Ctx(c.UserContext()).Info().
+		Str("method", c.Method()).
+		Str("path", c.Path()).
+		Str("ip", c.IP()).
+		Msg("received request to update notification preferences")
+
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return apperror.NewUnauthorizedError("invalid auth context")
+	}
+
+	var req UpdatePreferencesRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.NewBadRequestError("Invalid request body")
+	}
+
+	prefs, err := h.preferencesService.GetPreferences(c.UserContext(), userID)
+	if err != nil {
+		return err
+	}
+	if req.EmailEnabled != nil {
+		prefs.EmailEnabled = *req.EmailEnabled
+	}
+	if req.Reminders != nil {
+		prefs.Reminders = *req.Reminders
+	}
+	if req.Invitations != nil {
+		prefs.Invitations = *req.Invitations
+	}
+
+	prefs, err = h.preferencesService.UpdatePreferences(c.UserContext(), prefs)
+	if err != nil {
+		zerolog.Ctx(c.UserContext()).Error().
+			Err(err).
+			Msg("failed to update notification preferences")
+		return err
+	}
+
+	return response.Success(c, fiber.StatusOK, "Notification Preferences Updated", prefs)
+}
@@ -0,0 +1,101 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog"
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/http/response"
+	"github.com/suryansh74/task-management-api-project/internal/logger"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+	"github.com/suryansh74/task-management-api-project/internal/validator"
+)
+
+type PasswordHandler struct {
+	resetService ports.PasswordResetService
+}
+
+// NewPasswordHandler Constructor for PasswordHandler
+// =========================================================================
+func NewPasswordHandler(resetService ports.PasswordResetService) *PasswordHandler {
+	logger.Log.Info().Msg("initializing password handler")
+	return &PasswordHandler{
+		resetService: resetService,
+	}
+}
+
+// ForgotPasswordRequest dto for incoming req
+// =========================================================================
+type ForgotPasswordRequest struct {
+	Email string `json:"email" validate:"required,email"`
+}
+
+// ResetPasswordRequest dto for incoming req
+// =========================================================================
+type ResetPasswordRequest struct {
+	Token    string `json:"token" validate:"required"`
+	Password string `json:"password" validate:"required,min=8,max=72"`
+}
+
+// ForgotPassword email a reset link, the answer does not tell whether the account exists
+// =========================================================================
+func (h *PasswordHandler) ForgotPassword(c *fiber.Ctx) error {
+	zerolog.Ctx(c.UserContext()).Info().
+		Str("method", c.Method()).
+		Str("path", c.Path()).
+		Str("ip", c.IP()).
+		Msg("received forgot password request")
+
+	var req ForgotPasswordRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.NewBadRequestError("Invalid request body")
+	}
+	if fieldErrors := validator.ValidateStruct(req); len(fieldErrors) > 0 {
+		return response.ValidationError(c, fieldErrors)
+	}
+
+	if err := h.resetService.ForgotPassword(c.UserContext(), req.Email); err != nil {
+		zerolog.Ctx(c.UserContext()).Error().
+			Err(err).
+			Msg("failed to start password reset")
+		return err
+	}
+
+	return response.Success(c, fiber.StatusAccepted, "If an account exists for this email, a reset link has been sent.", nil)
+}
+
+// ResetPassword set a new password with a token from the reset email
+// =========================================================================
+func (h *PasswordHandler) ResetPassword(c *fiber.Ctx) error {
+	zerolog.Ctx(c.UserContext()).Info().
+		Str("method", c.Method()).
+		Str("path", c.Path()).
+		Str("ip", c.IP()).
+		Msg("received reset password request")
+
+	var req ResetPasswordRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.NewBadRequestError("Invalid request body")
+	}
+	if fieldErrors := validator.ValidateStruct(req); len(fieldErrors) > 0 {
+		return response.ValidationError(c, fieldErrors)
+	}
+
+	if err := h.resetService.ResetPassword(c.UserContext(), req.Token, req.Password); err != nil {
+		zerolog.Ctx(c.UserContext()).Warn().
+			Err(err).
+			Str("ip", c.IP()).
+			Msg("password reset failed")
+		return err
+	}
+
+	// the caller's own session, if any, was revoked with the rest
+	c.Cookie(&fiber.Cookie{
+		Name:     "session_id",
+		Value:    "",
+		HTTPOnly: true,
+		Secure:   false,
+		SameSite: "Lax",
+	})
+
+	return response.Success(c, fiber.StatusOK, "Password reset successfully, please log in again", nil)
+}
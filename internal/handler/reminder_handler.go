@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog"
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/http/response"
+	"github.com/suryansh74/task-management-api-project/internal/logger"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+	"github.com/suryansh74/task-management-api-project/internal/validator"
+)
+
+type ReminderHandler struct {
+	reminderService ports.ReminderService
+}
+
+// NewReminderHandler Constructor for ReminderHandler
+// =========================================================================
+func NewReminderHandler(reminderService ports.ReminderService) *ReminderHandler {
+	logger.Log.Info().Msg("initializing reminder handler")
+	return &ReminderHandler{
+		reminderService: reminderService,
+	}
+}
+
+// CreateReminderRequest dto for incoming req, before is a Go duration like "1h" or "30m"
+// =========================================================================
+type CreateReminderRequest struct {
+	Before  string `json:"before" validate:"required"`
+	Channel string `json:"channel" validate:"required,oneof=log webhook email"`
+	Target  string `json:"target" validate:"omitempty,max=2048"`
+}
+
+// CreateReminder add reminder rule to task
+// =========================================================================
+func (h *ReminderHandler) CreateReminder(c *fiber.Ctx) error {
+	taskID := c.Params("id")
+
+	zerolog.Ctx(c.UserContext()).Info().
+		Str("method", c.Method()).
+		Str("path", c.Path()).
+		Str("task_id", taskID).
+		Str("ip", c.IP()).
+		Msg("received request to create reminder")
+
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return apperror.NewUnauthorizedError("invalid auth context")
+	}
+
+	var req CreateReminderRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.NewBadRequestError("Invalid request body")
+	}
+	if fieldErrors := validator.ValidateStruct(req); len(fieldErrors) > 0 {
+		zerolog.Ctx(c.UserContext()).Warn().
+			Interface("validation_errors", fieldErrors).
+			Msg("validation failed for reminder creation")
+		return response.ValidationError(c, fieldErrors)
+	}
+
+	before, err := time.ParseDuration(req.Before)
+	if err != nil || before < 0 {
+		return apperror.NewBadRequestError("before must be a positive duration like 1h or 30m")
+	}
+	if req.Channel == models.ReminderChannelEmail && req.Target != "" {
+		if err := validator.GetValidator().Var(req.Target, "email"); err != nil {
+			return apperror.NewBadRequestError("email reminders need a valid target address")
+		}
+	}
+
+	reminder, err := h.reminderService.CreateReminder(c.UserContext(), taskID, userID, &models.Reminder{
+		OffsetSeconds: int64(before / time.Second),
+		Channel:       req.Channel,
+		Target:        req.Target,
+	})
+	if err != nil {
+		zerolog.Ctx(c.UserContext()).Error().
+			Err(err).
+			Str("task_id", taskID).
+			Msg("failed to create reminder")
+		return err
+	}
+
+	return response.Success(c, fiber.StatusCreated, "Reminder Created", reminder)
+}
+
+// GetReminders list reminder rules of task
+// =========================================================================
+func (h *ReminderHandler) GetReminders(c *fiber.Ctx) error {
+	taskID := c.Params("id")
+
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return apperror.NewUnauthorizedError("invalid auth context")
+	}
+
+	reminders, err := h.reminderService.GetReminders(c.UserContext(), taskID, userID)
+	if err != nil {
+		zerolog.Ctx(c.UserContext()).Error().
+			Err(err).
+			Str("task_id", taskID).
+			Msg("failed to fetch reminders")
+		return err
+	}
+
+	return response.Success(c, fiber.StatusOK, "All Returned Reminders", reminders)
+}
+
+// DeleteReminderByID delete reminder rule
+// =========================================================================
+func (h *ReminderHandler) DeleteReminderByID(c *fiber.Ctx) error {
+	taskID := c.Params("id")
+	reminderID := c.Params("reminderID")
+
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return apperror.NewUnauthorizedError("invalid auth context")
+	}
+
+	if err := h.reminderService.DeleteReminderByID(c.UserContext(), taskID, reminderID, userID); err != nil {
+		zerolog.Ctx(c.UserContext()).Error().
+			Err(err).
+			Str("task_id", taskID).
+			Str("reminder_id", reminderID).
+			Msg("failed to delete reminder")
+		return err
+	}
+
+	return response.Success(c, fiber.StatusOK, "Reminder Deleted", nil)
+}
+
+// GetUpcomingReminders next reminders of the user, ?limit= (default 20, max 100)
+// =========================================================================
+func (h *ReminderHandler) GetUpcomingReminders(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return apperror.NewUnauthorizedError("invalid auth context")
+	}
+
+	reminders, err := h.reminderService.GetUpcomingReminders(c.UserContext(), userID, c.QueryInt("limit", 20))
+	if err != nil {
+		zerolog.Ctx(c.UserContext()).Error().
+			Err(err).
+			Msg("failed to fetch upcoming reminders")
+		return err
+	}
+
+	return response.Success(c, fiber.StatusOK, "Upcoming Reminders", reminders)
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog"
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/http/response"
+	"github.com/suryansh74/task-management-api-project/internal/logger"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+	"github.com/suryansh74/task-management-api-project/internal/validator"
+)
+
+type TaskBatchHandler struct {
+	batchService ports.TaskBatchService
+}
+
+// NewTaskBatchHandler Constructor for TaskBatchHandler
+// =========================================================================
+func NewTaskBatchHandler(batchService ports.TaskBatchService) *TaskBatchHandler {
+	logger.Log.Info().Msg("initializing task batch handler")
+	return &TaskBatchHandler{
+		batchService: batchService,
+	}
+}
+
+// BatchTasksRequest dto for incoming req
+// =========================================================================
+type BatchTasksRequest struct {
+	Atomic     bool              `json:"atomic"`
+	Operations []*BatchOpRequest `json:"operations" validate:"required,min=1,max=100"`
+}
+
+// BatchOpRequest one operation, fields needed depend on op
+// =========================================================================
+type BatchOpRequest struct {
+	Op      string     `json:"op" validate:"required,oneof=create update delete set_status"`
+	ID      string     `json:"id" validate:"required_unless=Op create,omitempty,uuid"`
+	Title   string     `json:"title" validate:"required_if=Op create,required_if=Op update,omitempty,min=2,max=100"`
+	Content string     `json:"content" validate:"max=500"`
+	Status  string     `json:"status" validate:"required_if=Op set_status,omitempty,oneof=todo in_progress done"`
+	DueAt   *time.Time `json:"due_at"`
+}
+
+// BatchTasks apply many create/update/delete/status ops in one request
+// =========================================================================
+func (h *TaskBatchHandler) BatchTasks(c *fiber.Ctx) error {
+	zerolog.Ctx(c.UserContext()).Info().
+		Str("method", c.Method()).
+		Str("path", c.Path()).
+		Str("ip", c.IP()).
+		Msg("received request to batch tasks")
+
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return apperror.NewUnauthorizedError("invalid auth context")
+	}
+
+	var req BatchTasksRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.NewBadRequestError("Invalid request body")
+	}
+	if fieldErrors := validator.ValidateStruct(req); len(fieldErrors) > 0 {
+		return response.ValidationError(c, fieldErrors)
+	}
+
+	// malformed ops are rejected up front, keyed by their position
+	fieldErrors := map[string]string{}
+	ops := make([]*models.TaskBatchOp, 0, len(req.Operations))
+	for i, op := range req.Operations {
+		if op == nil {
+			fieldErrors[fmt.Sprintf("operations[%d]", i)] = "operation is required"
+			continue
+		}
+		for field, msg := range validator.ValidateStruct(op) {
+			fieldErrors[fmt.Sprintf("operations[%d].%s", i, field)] = msg
+		}
+		ops = append(ops, &models.TaskBatchOp{
+			Op:      op.Op,
+			ID:      op.ID,
+			Title:   op.Title,
+			Content: op.Content,
+			Status:  op.Status,
+			DueAt:   op.DueAt,
+		})
+	}
+	if len(fieldErrors) > 0 {
+		return response.ValidationError(c, fieldErrors)
+	}
+
+	result, err := h.batchService.BatchTasks(c.UserContext(), userID, ops, req.Atomic)
+	if err != nil {
+		zerolog.Ctx(c.UserContext()).Error().
+			Err(err).
+			Msg("failed to process task batch")
+		return err
+	}
+
+	return response.Success(c, fiber.StatusOK, "Batch Processed", result)
+}
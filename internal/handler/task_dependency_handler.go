@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog"
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/http/response"
+	"github.com/suryansh74/task-management-api-project/internal/logger"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+	"github.com/suryansh74/task-management-api-project/internal/validator"
+)
+
+type TaskDependencyHandler struct {
+	dependencyService ports.TaskDependencyService
+}
+
+// NewTaskDependencyHandler Constructor for TaskDependencyHandler
+// =========================================================================
+func NewTaskDependencyHandler(dependencyService ports.TaskDependencyService) *TaskDependencyHandler {
+	logger.Log.Info().Msg("initializing task dependency handler")
+	return &TaskDependencyHandler{
+		dependencyService: dependencyService,
+	}
+}
+
+// AddBlockerRequest dto for incoming req
+// =========================================================================
+type AddBlockerRequest struct {
+	BlockerID string `json:"blocker_id" validate:"required,uuid"`
+}
+
+// AddBlocker mark task as blocked by another task
+// =========================================================================
+func (h *TaskDependencyHandler) AddBlocker(c *fiber.Ctx) error {
+	taskID := c.Params("id")
+
+	zerolog.Ctx(c.UserContext()).Info().
+		Str("method", c.Method()).
+		Str("path", c.Path()).
+		Str("task_id", taskID).
+		Str("ip", c.IP()).
+		Msg("received request to add blocker")
+
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return apperror.NewUnauthorizedError("invalid auth context")
+	}
+
+	var req AddBlockerRequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.NewBadRequestError("Invalid request body")
+	}
+	if fieldErrors := validator.ValidateStruct(req); len(fieldErrors) > 0 {
+		return response.ValidationError(c, fieldErrors)
+	}
+
+	if err := h.dependencyService.AddBlocker(c.UserContext(), taskID, req.BlockerID, userID); err != nil {
+		zerolog.Ctx(c.UserContext()).Error().
+			Err(err).
+			Str("task_id", taskID).
+			Str("blocker_id", req.BlockerID).
+			Msg("failed to add blocker")
+		return err
+	}
+
+	return response.Success(c, fiber.StatusCreated, "Blocker Added", nil)
+}
+
+// RemoveBlocker remove blocked-by edge
+// =========================================================================
+func (h *TaskDependencyHandler) RemoveBlocker(c *fiber.Ctx) error {
+	taskID := c.Params("id")
+	blockerID := c.Params("blockerID")
+
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return apperror.NewUnauthorizedError("invalid auth context")
+	}
+
+	if err := h.dependencyService.RemoveBlocker(c.UserContext(), taskID, blockerID, userID); err != nil {
+		zerolog.Ctx(c.UserContext()).Error().
+			Err(err).
+			Str("task_id", taskID).
+			Str("blocker_id", blockerID).
+			Msg("failed to remove blocker")
+		return err
+	}
+
+	return response.Success(c, fiber.StatusOK, "Blocker Removed", nil)
+}
+
+// GetBlockers list tasks blocking the task
+// =========================================================================
+func (h *TaskDependencyHandler) GetBlockers(c *fiber.Ctx) error {
+	taskID := c.Params("id")
+
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return apperror.NewUnauthorizedError("invalid auth context")
+	}
+
+	tasks, err := h.dependencyService.GetBlockers(c.UserContext(), taskID, userID)
+	if err != nil {
+		zerolog.Ctx(c.UserContext()).Error().
+			Err(err).
+			Str("task_id", taskID).
+			Msg("failed to fetch blockers")
+		return err
+	}
+
+	return response.Success(c, fiber.StatusOK, "All Returned Blockers", tasks)
+}
+
+// GetDependents list tasks blocked by the task
+// =========================================================================
+func (h *TaskDependencyHandler) GetDependents(c *fiber.Ctx) error {
+	taskID := c.Params("id")
+
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return apperror.NewUnauthorizedError("invalid auth context")
+	}
+
+	tasks, err := h.dependencyService.GetDependents(c.UserContext(), taskID, userID)
+	if err != nil {
+		zerolog.Ctx(c.UserContext()).Error().
+			Err(err).
+			Str("task_id", taskID).
+			Msg("failed to fetch dependents")
+		return err
+	}
+
+	return response.Success(c, fiber.StatusOK, "All Returned Dependents", tasks)
+}
+
+// GetTopologicalOrder all tasks of the user, blockers first
+// =========================================================================
+func (h *TaskDependencyHandler) GetTopologicalOrder(c *fiber.Ctx) error {
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return apperror.NewUnauthorizedError("invalid auth context")
+	}
+
+	tasks, err := h.dependencyService.GetTopologicalOrder(c.UserContext(), userID)
+	if err != nil {
+		zerolog.Ctx(c.UserContext()).Error().
+			Err(err).
+			Msg("failed to compute task order")
+		return err
+	}
+
+	return response.Success(c, fiber.StatusOK, "Tasks In Dependency Order", tasks)
+}
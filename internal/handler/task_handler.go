@@ -1,9 +1,11 @@
 package handler
 
 import (
+	"strings"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog"
 	"github.com/suryansh74/task-management-api-project/internal/apperror"
 	"github.com/suryansh74/task-management-api-project/internal/http/response"
 	"github.com/suryansh74/task-management-api-project/internal/logger"
@@ -13,22 +15,24 @@ import (
 )
 
 type TaskHandler struct {
-	taskService     ports.TaskService
-	redisAppName    string
-	cacheExpiration time.Duration
+	taskService       ports.TaskService
+	recurrenceService ports.RecurrenceService
+	redisAppName      string
+	cacheExpiration   time.Duration
 }
 
 // NewTaskHandler Constructor for TaskHandler
 // =========================================================================
-func NewTaskHandler(taskService ports.TaskService, redisAppName string, cacheExpiration time.Duration) *TaskHandler {
+func NewTaskHandler(taskService ports.TaskService, recurrenceService ports.RecurrenceService, redisAppName string, cacheExpiration time.Duration) *TaskHandler {
 	logger.Log.Info().
 		Str("redis_app_name", redisAppName).
 		Dur("cache_expiration", cacheExpiration).
 		Msg("initializing task handler")
 	return &TaskHandler{
-		taskService:     taskService,
-		redisAppName:    redisAppName,
-		cacheExpiration: cacheExpiration,
+		taskService:       taskService,
+		recurrenceService: recurrenceService,
+		redisAppName:      redisAppName,
+		cacheExpiration:   cacheExpiration,
 	}
 }
 
@@ -40,8 +44,18 @@ type CreateTaskRequest struct {
 }
 
 type UpdateTaskRequest struct {
-	Title   string `json:"title" validate:"min=2,max=100"`
-	Content string `json:"content" validate:"max=500"`
+	Title      string             `json:"title" validate:"min=2,max=100"`
+	Content    string             `json:"content" validate:"max=500"`
+	Status     string             `json:"status" validate:"omitempty,oneof=todo in_progress done"`
+	Labels     []string           `json:"labels"`
+	DueAt      *time.Time         `json:"due_at"`
+	Recurrence *models.Recurrence `json:"recurrence"`
+}
+
+// RecurrenceRequest optional recurrence block of a create request
+// =========================================================================
+type RecurrenceRequest struct {
+	Recurrence *models.Recurrence `json:"recurrence"`
 }
 
 // GetTaskResponse dto for incoming req
@@ -57,7 +71,7 @@ type GetTaskResponse struct {
 // GetTasks return all tasks
 // =========================================================================
 func (h *TaskHandler) GetTasks(c *fiber.Ctx) error {
-	logger.Log.Info().
+	zerolog.Ctx(c.UserContext()).Info().
 		Str("method", c.Method()).
 		Str("path", c.Path()).
 		Str("ip", c.IP()).
@@ -66,7 +80,7 @@ func (h *TaskHandler) GetTasks(c *fiber.Ctx) error {
 	// policy
 	userID, ok := c.Locals("user_id").(string)
 	if !ok || userID == "" {
-		logger.Log.Warn().
+		zerolog.Ctx(c.UserContext()).Warn().
 			Str("method", c.Method()).
 			Str("path", c.Path()).
 			Str("ip", c.IP()).
@@ -74,23 +88,25 @@ func (h *TaskHandler) GetTasks(c *fiber.Ctx) error {
 		return apperror.NewUnauthorizedError("invalid auth context")
 	}
 
-	logger.Log.Debug().
-		Str("user_id", userID).
+	zerolog.Ctx(c.UserContext()).Debug().
 		Msg("authenticated user fetching tasks")
 
+	// paged when asked for, the plain array stays for existing clients
+	if c.Query("limit") != "" || c.Query("cursor") != "" {
+		return h.listTasks(c, userID)
+	}
+
 	// Call service
-	tasks, err := h.taskService.GetTasks(c.Context(), userID)
+	tasks, err := h.taskService.GetTasks(c.UserContext(), userID)
 	if err != nil {
-		logger.Log.Error().
+		zerolog.Ctx(c.UserContext()).Error().
 			Err(err).
-			Str("user_id", userID).
 			Str("path", c.Path()).
 			Msg("failed to fetch tasks")
 		return err // Global error handler will catch this
 	}
 
-	logger.Log.Info().
-		Str("user_id", userID).
+	zerolog.Ctx(c.UserContext()).Info().
 		Int("task_count", len(tasks)).
 		Int("status", fiber.StatusOK).
 		Msg("successfully returned all tasks")
@@ -98,10 +114,43 @@ func (h *TaskHandler) GetTasks(c *fiber.Ctx) error {
 	return response.Success(c, fiber.StatusOK, "All Returned Tasks", tasks)
 }
 
+// ListTasksRequest dto for the paging query string
+// =========================================================================
+type ListTasksRequest struct {
+	Limit  int    `query:"limit" validate:"omitempty,min=1,max=100"`
+	Cursor string `query:"cursor" validate:"omitempty,max=512"`
+}
+
+// listTasks one page of the user's tasks, next_cursor fetches the following page
+func (h *TaskHandler) listTasks(c *fiber.Ctx, userID string) error {
+	var req ListTasksRequest
+	if err := c.QueryParser(&req); err != nil {
+		return apperror.NewBadRequestError("invalid query parameters")
+	}
+	if fieldErrors := validator.ValidateStruct(req); len(fieldErrors) > 0 {
+		return response.ValidationError(c, fieldErrors)
+	}
+
+	page, err := h.taskService.ListTasks(c.UserContext(), &models.TaskListQuery{
+		UserID: userID,
+		Limit:  req.Limit,
+		Cursor: req.Cursor,
+	})
+	if err != nil {
+		zerolog.Ctx(c.UserContext()).Error().
+			Err(err).
+			Str("path", c.Path()).
+			Msg("failed to list tasks")
+		return err
+	}
+
+	return response.Success(c, fiber.StatusOK, "All Returned Tasks", page)
+}
+
 // CreateTask create task
 // =========================================================================
 func (h *TaskHandler) CreateTask(c *fiber.Ctx) error {
-	logger.Log.Info().
+	zerolog.Ctx(c.UserContext()).Info().
 		Str("method", c.Method()).
 		Str("path", c.Path()).
 		Str("ip", c.IP()).
@@ -111,7 +160,7 @@ func (h *TaskHandler) CreateTask(c *fiber.Ctx) error {
 
 	// Parse body
 	if err := c.BodyParser(&req); err != nil {
-		logger.Log.Warn().
+		zerolog.Ctx(c.UserContext()).Warn().
 			Err(err).
 			Str("method", c.Method()).
 			Str("path", c.Path()).
@@ -120,49 +169,70 @@ func (h *TaskHandler) CreateTask(c *fiber.Ctx) error {
 		return apperror.NewBadRequestError("Invalid request body")
 	}
 
-	logger.Log.Debug().
+	zerolog.Ctx(c.UserContext()).Debug().
 		Str("title", req.Title).
 		Msg("parsed task creation request")
 
 	// Validate
 	if fieldErrors := validator.ValidateStruct(req); len(fieldErrors) > 0 {
-		logger.Log.Warn().
+		zerolog.Ctx(c.UserContext()).Warn().
 			Interface("validation_errors", fieldErrors).
 			Str("title", req.Title).
 			Msg("validation failed for task creation")
 		return response.ValidationError(c, fieldErrors)
 	}
 
+	var recurrenceReq RecurrenceRequest
+	if err := c.BodyParser(&recurrenceReq); err != nil {
+		return apperror.NewBadRequestError("Invalid request body")
+	}
+	if recurrenceReq.Recurrence != nil {
+		if fieldErrors := validator.ValidateStruct(recurrenceReq.Recurrence); len(fieldErrors) > 0 {
+			zerolog.Ctx(c.UserContext()).Warn().
+				Interface("validation_errors", fieldErrors).
+				Msg("validation failed for task recurrence")
+			return response.ValidationError(c, fieldErrors)
+		}
+	}
+
 	userID, ok := c.Locals("user_id").(string)
 	if !ok || userID == "" {
-		logger.Log.Warn().
+		zerolog.Ctx(c.UserContext()).Warn().
 			Str("method", c.Method()).
 			Str("path", c.Path()).
 			Msg("unauthorized request: invalid auth context")
 		return apperror.NewUnauthorizedError("invalid auth context")
 	}
 
+	// server-owned fields, a client must not attach its task to someone else's series
+	req.ID = ""
+	req.SeriesID = nil
+	req.ExternalID = nil
+	req.ChecklistTotal, req.ChecklistDone = 0, 0
 	req.UserID = userID
 
-	logger.Log.Debug().
-		Str("user_id", userID).
+	zerolog.Ctx(c.UserContext()).Debug().
 		Str("title", req.Title).
 		Msg("creating task for user")
 
 	// Call service
-	id, err := h.taskService.CreateTask(c.Context(), &req)
+	var id string
+	var err error
+	if recurrenceReq.Recurrence != nil {
+		id, err = h.recurrenceService.CreateRecurringTask(c.UserContext(), &req, recurrenceReq.Recurrence)
+	} else {
+		id, err = h.taskService.CreateTask(c.UserContext(), &req)
+	}
 	if err != nil {
-		logger.Log.Error().
+		zerolog.Ctx(c.UserContext()).Error().
 			Err(err).
-			Str("user_id", userID).
 			Str("title", req.Title).
 			Msg("failed to create task")
 		return err // Global error handler will catch this
 	}
 
-	logger.Log.Info().
+	zerolog.Ctx(c.UserContext()).Info().
 		Str("task_id", id).
-		Str("user_id", userID).
 		Str("title", req.Title).
 		Int("status", fiber.StatusOK).
 		Msg("task created successfully")
@@ -175,7 +245,7 @@ func (h *TaskHandler) CreateTask(c *fiber.Ctx) error {
 func (h *TaskHandler) GetTaskByID(c *fiber.Ctx) error {
 	id := c.Params("id")
 
-	logger.Log.Info().
+	zerolog.Ctx(c.UserContext()).Info().
 		Str("method", c.Method()).
 		Str("path", c.Path()).
 		Str("task_id", id).
@@ -183,7 +253,7 @@ func (h *TaskHandler) GetTaskByID(c *fiber.Ctx) error {
 		Msg("received request to get task by id")
 
 	if id == "" {
-		logger.Log.Warn().
+		zerolog.Ctx(c.UserContext()).Warn().
 			Str("method", c.Method()).
 			Str("path", c.Path()).
 			Msg("missing task id in request")
@@ -192,7 +262,7 @@ func (h *TaskHandler) GetTaskByID(c *fiber.Ctx) error {
 
 	userID, ok := c.Locals("user_id").(string)
 	if !ok || userID == "" {
-		logger.Log.Warn().
+		zerolog.Ctx(c.UserContext()).Warn().
 			Str("task_id", id).
 			Str("method", c.Method()).
 			Str("path", c.Path()).
@@ -200,24 +270,21 @@ func (h *TaskHandler) GetTaskByID(c *fiber.Ctx) error {
 		return apperror.NewUnauthorizedError("invalid auth context")
 	}
 
-	logger.Log.Debug().
+	zerolog.Ctx(c.UserContext()).Debug().
 		Str("task_id", id).
-		Str("user_id", userID).
 		Msg("fetching task for user")
 
-	task, err := h.taskService.GetTaskByID(c.Context(), id, userID)
+	task, err := h.taskService.GetTaskByID(c.UserContext(), id, userID)
 	if err != nil {
-		logger.Log.Error().
+		zerolog.Ctx(c.UserContext()).Error().
 			Err(err).
 			Str("task_id", id).
-			Str("user_id", userID).
 			Msg("failed to get task")
 		return err
 	}
 
-	logger.Log.Info().
+	zerolog.Ctx(c.UserContext()).Info().
 		Str("task_id", id).
-		Str("user_id", userID).
 		Int("status", fiber.StatusOK).
 		Msg("task retrieved successfully")
 
@@ -229,7 +296,7 @@ func (h *TaskHandler) GetTaskByID(c *fiber.Ctx) error {
 func (h *TaskHandler) UpdateTaskByID(c *fiber.Ctx) error {
 	id := c.Params("id")
 
-	logger.Log.Info().
+	zerolog.Ctx(c.UserContext()).Info().
 		Str("method", c.Method()).
 		Str("path", c.Path()).
 		Str("task_id", id).
@@ -237,7 +304,7 @@ func (h *TaskHandler) UpdateTaskByID(c *fiber.Ctx) error {
 		Msg("received request to update task")
 
 	if id == "" {
-		logger.Log.Warn().
+		zerolog.Ctx(c.UserContext()).Warn().
 			Str("method", c.Method()).
 			Str("path", c.Path()).
 			Msg("missing task id in request")
@@ -246,7 +313,7 @@ func (h *TaskHandler) UpdateTaskByID(c *fiber.Ctx) error {
 
 	var req UpdateTaskRequest
 	if err := c.BodyParser(&req); err != nil {
-		logger.Log.Warn().
+		zerolog.Ctx(c.UserContext()).Warn().
 			Err(err).
 			Str("task_id", id).
 			Str("method", c.Method()).
@@ -254,13 +321,13 @@ func (h *TaskHandler) UpdateTaskByID(c *fiber.Ctx) error {
 		return apperror.NewBadRequestError("invalid request body")
 	}
 
-	logger.Log.Debug().
+	zerolog.Ctx(c.UserContext()).Debug().
 		Str("task_id", id).
 		Str("title", req.Title).
 		Msg("parsed task update request")
 
 	if fieldErrors := validator.ValidateStruct(req); len(fieldErrors) > 0 {
-		logger.Log.Warn().
+		zerolog.Ctx(c.UserContext()).Warn().
 			Interface("validation_errors", fieldErrors).
 			Str("task_id", id).
 			Str("title", req.Title).
@@ -268,38 +335,59 @@ func (h *TaskHandler) UpdateTaskByID(c *fiber.Ctx) error {
 		return response.ValidationError(c, fieldErrors)
 	}
 
+	if req.Recurrence != nil {
+		if fieldErrors := validator.ValidateStruct(req.Recurrence); len(fieldErrors) > 0 {
+			return response.ValidationError(c, fieldErrors)
+		}
+	}
+
+	// scope=this (default) edits one occurrence, scope=future the rest of the series
+	scope := c.Query("scope", "this")
+	if scope != "this" && scope != "future" {
+		return apperror.NewBadRequestError("scope must be 'this' or 'future'")
+	}
+	if scope == "this" && req.Recurrence != nil {
+		return apperror.NewBadRequestError("recurrence can only be changed with scope=future")
+	}
+
 	task := &models.Task{
 		Title:   req.Title,
 		Content: req.Content,
+		Status:  req.Status,
+		Labels:  req.Labels,
+		DueAt:   req.DueAt,
 	}
 
 	userID, ok := c.Locals("user_id").(string)
 	if !ok || userID == "" {
-		logger.Log.Warn().
+		zerolog.Ctx(c.UserContext()).Warn().
 			Str("task_id", id).
 			Str("method", c.Method()).
 			Msg("unauthorized request: invalid auth context")
 		return apperror.NewUnauthorizedError("invalid auth context")
 	}
 
-	logger.Log.Debug().
+	zerolog.Ctx(c.UserContext()).Debug().
 		Str("task_id", id).
-		Str("user_id", userID).
 		Str("title", req.Title).
 		Msg("updating task for user")
 
-	if err := h.taskService.UpdateTaskByID(c.Context(), id, userID, task); err != nil {
-		logger.Log.Error().
+	var err error
+	if scope == "future" {
+		err = h.recurrenceService.UpdateFutureOccurrences(c.UserContext(), id, userID, task, req.Recurrence)
+	} else {
+		err = h.taskService.UpdateTaskByID(c.UserContext(), id, userID, task)
+	}
+	if err != nil {
+		zerolog.Ctx(c.UserContext()).Error().
 			Err(err).
 			Str("task_id", id).
-			Str("user_id", userID).
 			Msg("failed to update task")
 		return err
 	}
 
-	logger.Log.Info().
+	zerolog.Ctx(c.UserContext()).Info().
 		Str("task_id", id).
-		Str("user_id", userID).
 		Str("title", req.Title).
 		Int("status", fiber.StatusOK).
 		Msg("task updated successfully")
@@ -312,7 +400,7 @@ func (h *TaskHandler) UpdateTaskByID(c *fiber.Ctx) error {
 func (h *TaskHandler) DeleteTaskByID(c *fiber.Ctx) error {
 	id := c.Params("id")
 
-	logger.Log.Info().
+	zerolog.Ctx(c.UserContext()).Info().
 		Str("method", c.Method()).
 		Str("path", c.Path()).
 		Str("task_id", id).
@@ -320,7 +408,7 @@ func (h *TaskHandler) DeleteTaskByID(c *fiber.Ctx) error {
 		Msg("received request to delete task")
 
 	if id == "" {
-		logger.Log.Warn().
+		zerolog.Ctx(c.UserContext()).Warn().
 			Str("method", c.Method()).
 			Str("path", c.Path()).
 			Msg("missing task id in request")
@@ -329,32 +417,115 @@ func (h *TaskHandler) DeleteTaskByID(c *fiber.Ctx) error {
 
 	userID, ok := c.Locals("user_id").(string)
 	if !ok || userID == "" {
-		logger.Log.Warn().
+		zerolog.Ctx(c.UserContext()).Warn().
 			Str("task_id", id).
 			Str("method", c.Method()).
 			Msg("unauthorized request: invalid auth context")
 		return apperror.NewUnauthorizedError("invalid auth context")
 	}
 
-	logger.Log.Debug().
+	zerolog.Ctx(c.UserContext()).Debug().
 		Str("task_id", id).
-		Str("user_id", userID).
 		Msg("deleting task for user")
 
-	if err := h.taskService.DeleteTaskByID(c.Context(), id, userID); err != nil {
-		logger.Log.Error().
+	if err := h.taskService.DeleteTaskByID(c.UserContext(), id, userID); err != nil {
+		zerolog.Ctx(c.UserContext()).Error().
 			Err(err).
 			Str("task_id", id).
-			Str("user_id", userID).
 			Msg("failed to delete task")
 		return err
 	}
 
-	logger.Log.Info().
+	zerolog.Ctx(c.UserContext()).Info().
 		Str("task_id", id).
-		Str("user_id", userID).
 		Int("status", fiber.StatusOK).
 		Msg("task deleted successfully")
 
 	return response.Success(c, fiber.StatusOK, "Task Deleted", nil)
 }
+
+// SearchTasksRequest dto for the search query string
+// =========================================================================
+type SearchTasksRequest struct {
+	Q      string `query:"q" validate:"required,max=200"`
+	Status string `query:"status"`
+	Labels string `query:"labels"`
+	Limit  int    `query:"limit" validate:"omitempty,min=1,max=100"`
+	Offset int    `query:"offset" validate:"omitempty,min=0"`
+}
+
+// SearchTasks full-text search over the user's tasks
+// =========================================================================
+func (h *TaskHandler) SearchTasks(c *fiber.Ctx) error {
+	zerolog.Ctx(c.UserContext()).Info().
+		Str("method", c.Method()).
+		Str("path", c.Path()).
+		Str("ip", c.IP()).
+		Msg("received request to search tasks")
+
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return apperror.NewUnauthorizedError("invalid auth context")
+	}
+
+	var req SearchTasksRequest
+	if err := c.QueryParser(&req); err != nil {
+		return apperror.NewBadRequestError("invalid query parameters")
+	}
+	if fieldErrors := validator.ValidateStruct(req); len(fieldErrors) > 0 {
+		return response.ValidationError(c, fieldErrors)
+	}
+
+	query := &models.TaskSearchQuery{
+		UserID: userID,
+		Query:  req.Q,
+		Limit:  req.Limit,
+		Offset: req.Offset,
+	}
+	// status=todo,in_progress
+	for _, status := range strings.Split(req.Status, ",") {
+		switch status = strings.TrimSpace(status); status {
+		case "":
+		case models.TaskStatusTodo, models.TaskStatusInProgress, models.TaskStatusDone:
+			query.Statuses = append(query.Statuses, status)
+		default:
+			return apperror.NewBadRequestError("status must be todo, in_progress or done")
+		}
+	}
+	// labels=work,urgent, a hit needs all of them
+	if req.Labels != "" {
+		query.Labels = strings.Split(req.Labels, ",")
+	}
+
+	// date ranges are RFC3339, from is inclusive and to is exclusive
+	ranges := []struct {
+		name string
+		dst  **time.Time
+	}{
+		{"due_from", &query.DueFrom},
+		{"due_to", &query.DueTo},
+		{"created_from", &query.CreatedFrom},
+		{"created_to", &query.CreatedTo},
+	}
+	for _, r := range ranges {
+		raw := c.Query(r.name)
+		if raw == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return apperror.NewBadRequestError(r.name + " must be an RFC3339 timestamp")
+		}
+		*r.dst = &t
+	}
+
+	result, err := h.taskService.SearchTasks(c.UserContext(), query)
+	if err != nil {
+		zerolog.Ctx(c.UserContext()).Error().
+			Err(err).
+			Msg("failed to search tasks")
+		return err
+	}
+
+	return response.Success(c, fiber.StatusOK, "Search Results", result)
+}
@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"mime"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog"
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/http/response"
+	"github.com/suryansh74/task-management-api-project/internal/logger"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+	"github.com/suryansh74/task-management-api-project/internal/validator"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// exportContentTypes response content type per export format
+var exportContentTypes = map[string]string{
+	models.TransferFormatCSV:    "text/csv; charset=utf-8",
+	models.TransferFormatJSON:   fiber.MIMEApplicationJSONCharsetUTF8,
+	models.TransferFormatNDJSON: "application/x-ndjson",
+}
+
+type TaskTransferHandler struct {
+	transferService ports.TaskTransferService
+}
+
+// NewTaskTransferHandler Constructor for TaskTransferHandler
+// =========================================================================
+func NewTaskTransferHandler(transferService ports.TaskTransferService) *TaskTransferHandler {
+	logger.Log.Info().Msg("initializing task transfer handler")
+	return &TaskTransferHandler{
+		transferService: transferService,
+	}
+}
+
+// ExportTasksRequest dto for the export query string
+// =========================================================================
+type ExportTasksRequest struct {
+	Format string `query:"format" validate:"omitempty,oneof=csv json ndjson"`
+}
+
+// ExportTasks stream all tasks of the user as a file download
+// =========================================================================
+func (h *TaskTransferHandler) ExportTasks(c *fiber.Ctx) error {
+	zerolog.Ctx(c.UserContext()).Info().
+		Str("method", c.Method()).
+		Str("path", c.Path()).
+		Str("ip", c.IP()).
+		Msg("received request to export tasks")
+
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return apperror.NewUnauthorizedError("invalid auth context")
+	}
+
+	var req ExportTasksRequest
+	if err := c.QueryParser(&req); err != nil {
+		return apperror.NewBadRequestError("invalid query parameters")
+	}
+	if fieldErrors := validator.ValidateStruct(req); len(fieldErrors) > 0 {
+		return response.ValidationError(c, fieldErrors)
+	}
+	format := req.Format
+	if format == "" {
+		format = models.TransferFormatJSON
+	}
+
+	filename := "tasks-" + time.Now().UTC().Format("20060102") + "." + format
+	c.Set(fiber.HeaderContentDisposition, mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	c.Set(fiber.HeaderContentType, exportContentTypes[format])
+
+	// the body is written after the handler returns, so the request context
+	// can't be used, only its trace and logger are carried over; once
+	// streaming started a failure can only truncate the file
+	exportCtx := trace.ContextWithSpanContext(context.Background(), trace.SpanContextFromContext(c.UserContext()))
+	exportCtx = zerolog.Ctx(c.UserContext()).WithContext(exportCtx)
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := h.transferService.ExportTasks(exportCtx, userID, format, w); err != nil {
+			zerolog.Ctx(exportCtx).Error().
+				Err(err).
+				Str("format", format).
+				Msg("task export aborted")
+		}
+		w.Flush()
+	})
+	return nil
+}
+
+// ImportTasksRequest dto for the import query string, the file is the raw body
+// =========================================================================
+type ImportTasksRequest struct {
+	Format string `query:"format" validate:"omitempty,oneof=csv json ndjson"`
+	Mode   string `query:"mode" validate:"omitempty,oneof=create upsert"`
+	DryRun bool   `query:"dry_run"`
+}
+
+// ImportTasks create or upsert tasks from an uploaded file
+// =========================================================================
+func (h *TaskTransferHandler) ImportTasks(c *fiber.Ctx) error {
+	zerolog.Ctx(c.UserContext()).Info().
+		Str("method", c.Method()).
+		Str("path", c.Path()).
+		Str("ip", c.IP()).
+		Msg("received request to import tasks")
+
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return apperror.NewUnauthorizedError("invalid auth context")
+	}
+
+	var req ImportTasksRequest
+	if err := c.QueryParser(&req); err != nil {
+		return apperror.NewBadRequestError("invalid query parameters")
+	}
+	if fieldErrors := validator.ValidateStruct(req); len(fieldErrors) > 0 {
+		return response.ValidationError(c, fieldErrors)
+	}
+
+	opts := models.TaskImportOptions{
+		Format: req.Format,
+		Mode:   req.Mode,
+		DryRun: req.DryRun,
+	}
+	if opts.Format == "" {
+		opts.Format = models.TransferFormatJSON
+	}
+
+	result, err := h.transferService.ImportTasks(c.UserContext(), userID, bytes.NewReader(c.Body()), opts)
+	if err != nil {
+		zerolog.Ctx(c.UserContext()).Error().
+			Err(err).
+			Str("format", opts.Format).
+			Msg("failed to import tasks")
+		return err
+	}
+
+	message := "Tasks Imported"
+	if result.DryRun {
+		message = "Import Validated"
+	}
+	return response.Success(c, fiber.StatusOK, message, result)
+}
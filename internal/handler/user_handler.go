@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog"
 	"github.com/suryansh74/task-management-api-project/internal/apperror"
 	"github.com/suryansh74/task-management-api-project/internal/http/response"
 	"github.com/suryansh74/task-management-api-project/internal/logger"
@@ -12,24 +13,31 @@ import (
 )
 
 type UserHandler struct {
-	userService       ports.UserService
-	sessionService    ports.SessionService
-	sessionExpiration time.Duration
-	redisAppName      string
+	userService         ports.UserService
+	sessionService      ports.SessionService
+	verificationService ports.EmailVerificationService
+	mfaService          ports.MFAService
+	sessionExpiration   time.Duration
+	mfaChallengeTTL     time.Duration
+	redisAppName        string
 }
 
 // NewUserHandler Constructor for UserHandler
 // =========================================================================
-func NewUserHandler(userService ports.UserService, sessionService ports.SessionService, sessionExpiration time.Duration, redisAppName string) *UserHandler {
+func NewUserHandler(userService ports.UserService, sessionService ports.SessionService, verificationService ports.EmailVerificationService, mfaService ports.MFAService, sessionExpiration time.Duration, mfaChallengeTTL time.Duration, redisAppName string) *UserHandler {
 	logger.Log.Info().
 		Dur("session_expiration", sessionExpiration).
+		Dur("mfa_challenge_ttl", mfaChallengeTTL).
 		Str("redis_app_name", redisAppName).
 		Msg("initializing user handler")
 	return &UserHandler{
-		userService:       userService,
-		sessionService:    sessionService,
-		sessionExpiration: sessionExpiration,
-		redisAppName:      redisAppName,
+		userService:         userService,
+		sessionService:      sessionService,
+		verificationService: verificationService,
+		mfaService:          mfaService,
+		sessionExpiration:   sessionExpiration,
+		mfaChallengeTTL:     mfaChallengeTTL,
+		redisAppName:        redisAppName,
 	}
 }
 
@@ -48,10 +56,16 @@ type LoginRequest struct {
 	Password string `json:"password" validate:"required,min=8"`
 }
 
+// LoginMFARequest dto for incoming req, a TOTP code or a recovery code
+// =========================================================================
+type LoginMFARequest struct {
+	Code string `json:"code" validate:"required,max=32"`
+}
+
 // Register handles user registration
 // =========================================================================
 func (h *UserHandler) Register(c *fiber.Ctx) error {
-	logger.Log.Info().
+	zerolog.Ctx(c.UserContext()).Info().
 		Str("method", c.Method()).
 		Str("path", c.Path()).
 		Str("ip", c.IP()).
@@ -61,7 +75,7 @@ func (h *UserHandler) Register(c *fiber.Ctx) error {
 
 	// Parse body
 	if err := c.BodyParser(&req); err != nil {
-		logger.Log.Warn().
+		zerolog.Ctx(c.UserContext()).Warn().
 			Err(err).
 			Str("method", c.Method()).
 			Str("path", c.Path()).
@@ -70,14 +84,14 @@ func (h *UserHandler) Register(c *fiber.Ctx) error {
 		return apperror.NewBadRequestError("Invalid request body")
 	}
 
-	logger.Log.Debug().
+	zerolog.Ctx(c.UserContext()).Debug().
 		Str("email", req.Email).
 		Str("name", req.Name).
 		Msg("parsed registration request")
 
 	// Validate
 	if fieldErrors := validator.ValidateStruct(req); len(fieldErrors) > 0 {
-		logger.Log.Warn().
+		zerolog.Ctx(c.UserContext()).Warn().
 			Interface("validation_errors", fieldErrors).
 			Str("email", req.Email).
 			Str("name", req.Name).
@@ -85,15 +99,15 @@ func (h *UserHandler) Register(c *fiber.Ctx) error {
 		return response.ValidationError(c, fieldErrors)
 	}
 
-	logger.Log.Debug().
+	zerolog.Ctx(c.UserContext()).Debug().
 		Str("email", req.Email).
 		Str("name", req.Name).
 		Msg("registering new user")
 
 	// Call service
-	user, err := h.userService.Register(c.Context(), req.Name, req.Email, req.Password)
+	user, err := h.userService.Register(c.UserContext(), req.Name, req.Email, req.Password)
 	if err != nil {
-		logger.Log.Error().
+		zerolog.Ctx(c.UserContext()).Error().
 			Err(err).
 			Str("email", req.Email).
 			Str("name", req.Name).
@@ -101,20 +115,28 @@ func (h *UserHandler) Register(c *fiber.Ctx) error {
 		return err // Global error handler will catch this
 	}
 
-	logger.Log.Info().
+	zerolog.Ctx(c.UserContext()).Info().
 		Str("user_id", user.ID).
 		Str("email", user.Email).
 		Str("name", user.Name).
 		Int("status", fiber.StatusCreated).
 		Msg("user registered successfully")
 
+	// the account exists either way, the user can ask for a new link
+	if err := h.verificationService.SendVerification(c.UserContext(), user.ID); err != nil {
+		zerolog.Ctx(c.UserContext()).Error().
+			Err(err).
+			Str("user_id", user.ID).
+			Msg("failed to send verification email, continuing with registration")
+	}
+
 	return response.Success(c, fiber.StatusCreated, "User registered successfully.", user)
 }
 
 // Login handles retrieving user by email
 // =========================================================================
 func (h *UserHandler) Login(c *fiber.Ctx) error {
-	logger.Log.Info().
+	zerolog.Ctx(c.UserContext()).Info().
 		Str("method", c.Method()).
 		Str("path", c.Path()).
 		Str("ip", c.IP()).
@@ -124,7 +146,7 @@ func (h *UserHandler) Login(c *fiber.Ctx) error {
 
 	// Parse body
 	if err := c.BodyParser(&req); err != nil {
-		logger.Log.Warn().
+		zerolog.Ctx(c.UserContext()).Warn().
 			Err(err).
 			Str("method", c.Method()).
 			Str("path", c.Path()).
@@ -133,27 +155,27 @@ func (h *UserHandler) Login(c *fiber.Ctx) error {
 		return apperror.NewBadRequestError("Invalid request body")
 	}
 
-	logger.Log.Debug().
+	zerolog.Ctx(c.UserContext()).Debug().
 		Str("email", req.Email).
 		Msg("parsed login request")
 
 	// Validate
 	if fieldErrors := validator.ValidateStruct(req); len(fieldErrors) > 0 {
-		logger.Log.Warn().
+		zerolog.Ctx(c.UserContext()).Warn().
 			Interface("validation_errors", fieldErrors).
 			Str("email", req.Email).
 			Msg("validation failed for login")
 		return response.ValidationError(c, fieldErrors)
 	}
 
-	logger.Log.Debug().
+	zerolog.Ctx(c.UserContext()).Debug().
 		Str("email", req.Email).
 		Msg("attempting user login")
 
 	// Call service
-	user, err := h.userService.Login(c.Context(), req.Email, req.Password)
+	user, err := h.userService.Login(c.UserContext(), req.Email, req.Password, c.IP())
 	if err != nil {
-		logger.Log.Warn().
+		zerolog.Ctx(c.UserContext()).Warn().
 			Err(err).
 			Str("email", req.Email).
 			Str("ip", c.IP()).
@@ -161,15 +183,99 @@ func (h *UserHandler) Login(c *fiber.Ctx) error {
 		return err
 	}
 
-	logger.Log.Info().
+	if user.MFAEnabled {
+		return h.startMFALogin(c, user)
+	}
+
+	zerolog.Ctx(c.UserContext()).Info().
 		Str("user_id", user.ID).
 		Str("email", user.Email).
 		Msg("user authenticated successfully, creating session")
 
-	// set user session
-	sessionID, err := h.sessionService.CreateSession(c.Context(), user.ID)
+	if err := h.startSession(c, user); err != nil {
+		return err
+	}
+	return response.Success(c, fiber.StatusOK, "User logged in successfully", user)
+}
+
+// LoginMFA second login step, trades the mfa_challenge cookie and a code for a session
+// =========================================================================
+func (h *UserHandler) LoginMFA(c *fiber.Ctx) error {
+	zerolog.Ctx(c.UserContext()).Info().
+		Str("method", c.Method()).
+		Str("path", c.Path()).
+		Str("ip", c.IP()).
+		Msg("received two-factor login request")
+
+	challengeID := c.Cookies("mfa_challenge")
+	if challengeID == "" {
+		return apperror.NewUnauthorizedError("no two-factor login in progress, log in first")
+	}
+
+	var req LoginMFARequest
+	if err := c.BodyParser(&req); err != nil {
+		return apperror.NewBadRequestError("Invalid request body")
+	}
+	if fieldErrors := validator.ValidateStruct(req); len(fieldErrors) > 0 {
+		return response.ValidationError(c, fieldErrors)
+	}
+
+	user, err := h.mfaService.CompleteLogin(c.UserContext(), challengeID, req.Code, c.IP())
+	if err != nil {
+		zerolog.Ctx(c.UserContext()).Warn().
+			Err(err).
+			Str("ip", c.IP()).
+			Msg("two-factor login failed")
+		return err
+	}
+
+	c.Cookie(&fiber.Cookie{
+		Name:     "mfa_challenge",
+		Value:    "",
+		HTTPOnly: true,
+		Secure:   false,
+		SameSite: "Lax",
+	})
+	if err := h.startSession(c, user); err != nil {
+		return err
+	}
+	return response.Success(c, fiber.StatusOK, "User logged in successfully", user)
+}
+
+// startMFALogin password was right, hand out a short-lived challenge instead of a session
+func (h *UserHandler) startMFALogin(c *fiber.Ctx, user *ports.UserResponse) error {
+	challengeID, err := h.mfaService.StartLogin(c.UserContext(), user)
+	if err != nil {
+		zerolog.Ctx(c.UserContext()).Error().
+			Err(err).
+			Str("user_id", user.ID).
+			Msg("failed to start two-factor login")
+		return err
+	}
+
+	c.Cookie(&fiber.Cookie{
+		Name:     "mfa_challenge",
+		Value:    challengeID,
+		Path:     "/",
+		HTTPOnly: true,
+		Secure:   false,
+		SameSite: "Lax",
+		Expires:  time.Now().Add(h.mfaChallengeTTL),
+	})
+
+	zerolog.Ctx(c.UserContext()).Info().
+		Str("user_id", user.ID).
+		Int("status", fiber.StatusOK).
+		Msg("password accepted, two-factor code required")
+
+	return response.Success(c, fiber.StatusOK, "Two-factor code required", fiber.Map{"mfa_required": true})
+}
+
+// startSession create the session and set its cookie
+func (h *UserHandler) startSession(c *fiber.Ctx, user *ports.UserResponse) error {
+	sessionID, err := h.sessionService.CreateSession(c.UserContext(), user.ID, user.EmailVerified)
 	if err != nil {
-		logger.Log.Error().
+		zerolog.Ctx(c.UserContext()).Error().
 			Err(err).
 			Str("user_id", user.ID).
 			Str("email", user.Email).
@@ -177,7 +283,7 @@ func (h *UserHandler) Login(c *fiber.Ctx) error {
 		return apperror.NewInternalError("user session not created", err)
 	}
 
-	logger.Log.Debug().
+	zerolog.Ctx(c.UserContext()).Debug().
 		Str("user_id", user.ID).
 		Str("session_id", sessionID).
 		Dur("expiration", h.sessionExpiration).
@@ -194,14 +300,13 @@ func (h *UserHandler) Login(c *fiber.Ctx) error {
 		Expires:  time.Now().Add(h.sessionExpiration),
 	})
 
-	logger.Log.Info().
+	zerolog.Ctx(c.UserContext()).Info().
 		Str("user_id", user.ID).
 		Str("email", user.Email).
 		Str("session_id", sessionID).
 		Int("status", fiber.StatusOK).
 		Msg("user logged in successfully with session")
-
-	return response.Success(c, fiber.StatusOK, "User logged in successfully", user)
+	return nil
 }
 
 // Logout delete session
@@ -209,7 +314,7 @@ func (h *UserHandler) Login(c *fiber.Ctx) error {
 func (h *UserHandler) Logout(c *fiber.Ctx) error {
 	sessionID := c.Cookies("session_id")
 
-	logger.Log.Info().
+	zerolog.Ctx(c.UserContext()).Info().
 		Str("method", c.Method()).
 		Str("path", c.Path()).
 		Str("session_id", sessionID).
@@ -217,29 +322,29 @@ func (h *UserHandler) Logout(c *fiber.Ctx) error {
 		Msg("received user logout request")
 
 	if sessionID == "" {
-		logger.Log.Debug().
+		zerolog.Ctx(c.UserContext()).Debug().
 			Str("ip", c.IP()).
 			Msg("logout request with no session cookie")
 	} else {
-		logger.Log.Debug().
+		zerolog.Ctx(c.UserContext()).Debug().
 			Str("session_id", sessionID).
 			Msg("deleting user session")
 
 		// Call service
-		err := h.sessionService.Logout(c.Context(), sessionID)
+		err := h.sessionService.Logout(c.UserContext(), sessionID)
 		if err != nil {
-			logger.Log.Warn().
+			zerolog.Ctx(c.UserContext()).Warn().
 				Err(err).
 				Str("session_id", sessionID).
 				Msg("failed to delete session, continuing with logout")
 		} else {
-			logger.Log.Info().
+			zerolog.Ctx(c.UserContext()).Info().
 				Str("session_id", sessionID).
 				Msg("session deleted successfully")
 		}
 	}
 
-	logger.Log.Debug().
+	zerolog.Ctx(c.UserContext()).Debug().
 		Str("session_id", sessionID).
 		Msg("clearing session cookie")
 
@@ -252,7 +357,7 @@ func (h *UserHandler) Logout(c *fiber.Ctx) error {
 		SameSite: "Lax",
 	})
 
-	logger.Log.Info().
+	zerolog.Ctx(c.UserContext()).Info().
 		Str("session_id", sessionID).
 		Int("status", fiber.StatusOK).
 		Msg("user logged out successfully")
@@ -2,10 +2,12 @@ package response
 
 import (
 	"errors"
+	"math"
+	"strconv"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog"
 	"github.com/suryansh74/task-management-api-project/internal/apperror"
-	"github.com/suryansh74/task-management-api-project/internal/logger"
 )
 
 // Response wraps all API responses
@@ -57,18 +59,21 @@ func HandleError(c *fiber.Ctx, err error) error {
 
 	// Check if it's an AppError
 	if errors.As(err, &appErr) {
-		logger.Log.Error().
+		zerolog.Ctx(c.UserContext()).Error().
 			Str("code", appErr.Code).
 			Str("path", c.Path()).
 			Str("method", c.Method()).
 			Err(appErr.Err).
 			Msg(appErr.Message)
 
+		if appErr.RetryAfter > 0 {
+			c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(appErr.RetryAfter.Seconds()))))
+		}
 		return Error(c, appErr.StatusCode, appErr.Code, appErr.Message, nil)
 	}
 
 	// Unknown error - log and return generic error
-	logger.Log.Error().
+	zerolog.Ctx(c.UserContext()).Error().
 		Str("path", c.Path()).
 		Str("method", c.Method()).
 		Err(err).
@@ -0,0 +1,20 @@
+package logger
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+)
+
+// NewRequestContext stores a logger carrying requestID in ctx; services log
+// through zerolog.Ctx(ctx), and the trace of ctx (if any) is attached too
+func NewRequestContext(ctx context.Context, requestID string) context.Context {
+	l := Log.With().Ctx(ctx).Str("request_id", requestID).Logger()
+	return l.WithContext(ctx)
+}
+
+// WithField returns ctx whose logger also carries key=value
+func WithField(ctx context.Context, key, value string) context.Context {
+	l := zerolog.Ctx(ctx).With().Str(key, value).Logger()
+	return l.WithContext(ctx)
+}
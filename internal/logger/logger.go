@@ -6,6 +6,7 @@ import (
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/pkgerrors"
+	"go.opentelemetry.io/otel/trace"
 )
 
 var Log zerolog.Logger
@@ -39,5 +40,19 @@ func Init() {
 		}
 	}
 
-	Log = Log.Level(logLevel)
+	Log = Log.Level(logLevel).Hook(traceHook{})
+	// zerolog.Ctx falls back to the global logger outside a request
+	zerolog.DefaultContextLogger = &Log
+}
+
+// traceHook adds trace_id and span_id to events logged with .Ctx(ctx) inside a span
+type traceHook struct{}
+
+func (traceHook) Run(e *zerolog.Event, level zerolog.Level, msg string) {
+	spanCtx := trace.SpanContextFromContext(e.GetCtx())
+	if !spanCtx.IsValid() {
+		return
+	}
+	e.Str("trace_id", spanCtx.TraceID().String()).
+		Str("span_id", spanCtx.SpanID().String())
 }
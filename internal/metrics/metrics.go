@@ -27,20 +27,37 @@ var (
 		[]string{"method", "path"},
 	)
 
-	CacheHits = promauto.NewCounter(prometheus.CounterOpts{
-		Name: "cache_hits_total",
-		Help: "Total cache hits",
-	})
+	CacheHits = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cache_hits_total",
+			Help: "Total cache hits",
+		},
+		[]string{"cache"},
+	)
 
-	CacheMisses = promauto.NewCounter(prometheus.CounterOpts{
-		Name: "cache_misses_total",
-		Help: "Total cache misses",
-	})
+	CacheMisses = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cache_misses_total",
+			Help: "Total cache misses",
+		},
+		[]string{"cache"},
+	)
 
-	TasksCreated = promauto.NewCounter(prometheus.CounterOpts{
-		Name: "tasks_created_total",
-		Help: "Total tasks created",
-	})
+	CacheErrors = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "cache_errors_total",
+			Help: "Total cache operations that failed",
+		},
+		[]string{"cache", "operation"},
+	)
+
+	TasksCreated = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "tasks_created_total",
+			Help: "Total tasks created",
+		},
+		[]string{"source"},
+	)
 
 	GRPCRequestsTotal = promauto.NewCounterVec(
 		prometheus.CounterOpts{
@@ -49,8 +66,58 @@ var (
 		},
 		[]string{"method", "code"},
 	)
+
+	GRPCRequestDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "grpc_request_duration_seconds",
+			Help:    "gRPC request latency in seconds",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"method", "code"},
+	)
+
+	DBQueryDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "db_query_duration_seconds",
+			Help:    "PostgreSQL query latency in seconds",
+			Buckets: []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		},
+		[]string{"operation", "status"},
+	)
+
+	ActiveSessions = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "active_sessions",
+		Help: "Sessions that have not expired or been logged out",
+	})
+
+	AuthEvents = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "auth_events_total",
+			Help: "Total authentication audit events",
+		},
+		[]string{"event"},
+	)
 )
 
+// ObserveCacheLookup counts a cache read as a hit, a miss or an error.
+func ObserveCacheLookup(cache string, found bool, err error) {
+	switch {
+	case err != nil:
+		CacheErrors.WithLabelValues(cache, "get").Inc()
+	case found:
+		CacheHits.WithLabelValues(cache).Inc()
+	default:
+		CacheMisses.WithLabelValues(cache).Inc()
+	}
+}
+
+// ObserveCacheWrite counts a failed cache set or delete.
+func ObserveCacheWrite(cache string, operation string, err error) {
+	if err != nil {
+		CacheErrors.WithLabelValues(cache, operation).Inc()
+	}
+}
+
 // Middleware records request count and duration for Fiber.
 func Middleware() fiber.Handler {
 	return func(c *fiber.Ctx) error {
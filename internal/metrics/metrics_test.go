@@ -0,0 +1,45 @@
+package metrics
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestObserveCacheLookup(t *testing.T) {
+	hits := testutil.ToFloat64(CacheHits.WithLabelValues("test"))
+	misses := testutil.ToFloat64(CacheMisses.WithLabelValues("test"))
+	errs := testutil.ToFloat64(CacheErrors.WithLabelValues("test", "get"))
+
+	ObserveCacheLookup("test", true, nil)
+	ObserveCacheLookup("test", false, nil)
+	ObserveCacheLookup("test", false, nil)
+	ObserveCacheLookup("test", false, errors.New("redis down"))
+
+	if got := testutil.ToFloat64(CacheHits.WithLabelValues("test")) - hits; got != 1 {
+		t.Errorf("expected 1 hit, got %v", got)
+	}
+	if got := testutil.ToFloat64(CacheMisses.WithLabelValues("test")) - misses; got != 2 {
+		t.Errorf("expected 2 misses, got %v", got)
+	}
+	// an error is neither a hit nor a miss
+	if got := testutil.ToFloat64(CacheErrors.WithLabelValues("test", "get")) - errs; got != 1 {
+		t.Errorf("expected 1 error, got %v", got)
+	}
+}
+
+func TestQueryOperation(t *testing.T) {
+	for sql, want := range map[string]string{
+		"SELECT id FROM tasks":                        "select",
+		"\n\t\tINSERT INTO tasks (title) VALUES ($1)": "insert",
+		"WITH RECURSIVE chain(id) AS (...)":           "with",
+		"SELECT\n\t\tid FROM tasks":                   "select",
+		"commit;":                                     "commit",
+		"VACUUM tasks":                                "other",
+	} {
+		if got := queryOperation(sql); got != want {
+			t.Errorf("queryOperation(%q) = %q, want %q", sql, got, want)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package metrics
+
+import (
+	"context"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/jackc/pgx/v5"
+)
+
+type queryStartKey struct{}
+
+// QueryTracer records every query in DBQueryDuration, labelled by its SQL verb.
+type QueryTracer struct{}
+
+func (QueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, queryStartKey{}, queryStart{at: time.Now(), operation: queryOperation(data.SQL)})
+}
+
+func (QueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	start, ok := ctx.Value(queryStartKey{}).(queryStart)
+	if !ok {
+		return
+	}
+	status := "ok"
+	if data.Err != nil {
+		status = "error"
+	}
+	DBQueryDuration.WithLabelValues(start.operation, status).Observe(time.Since(start.at).Seconds())
+}
+
+type queryStart struct {
+	at        time.Time
+	operation string
+}
+
+// queryOperation first keyword of the statement, anything unexpected is "other"
+// so the label stays bounded
+func queryOperation(sql string) string {
+	verb := strings.TrimSpace(sql)
+	if end := strings.IndexFunc(verb, unicode.IsSpace); end >= 0 {
+		verb = verb[:end]
+	}
+	verb = strings.ToLower(strings.TrimRight(verb, ";"))
+	switch verb {
+	case "select", "insert", "update", "delete", "with", "begin", "commit", "rollback", "savepoint", "release":
+		return verb
+	}
+	return "other"
+}
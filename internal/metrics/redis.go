@@ -0,0 +1,34 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/redis/go-redis/v9"
+)
+
+// RegisterRedisPoolStats exposes the client's connection pool counters, read at scrape time.
+func RegisterRedisPoolStats(client *redis.Client) {
+	gauge := func(name, help string, value func(*redis.PoolStats) uint32) {
+		promauto.NewGaugeFunc(prometheus.GaugeOpts{Name: name, Help: help}, func() float64 {
+			return float64(value(client.PoolStats()))
+		})
+	}
+	counter := func(name, help string, value func(*redis.PoolStats) uint32) {
+		promauto.NewCounterFunc(prometheus.CounterOpts{Name: name, Help: help}, func() float64 {
+			return float64(value(client.PoolStats()))
+		})
+	}
+
+	gauge("redis_pool_total_connections", "Connections in the Redis pool",
+		func(s *redis.PoolStats) uint32 { return s.TotalConns })
+	gauge("redis_pool_idle_connections", "Idle connections in the Redis pool",
+		func(s *redis.PoolStats) uint32 { return s.IdleConns })
+	counter("redis_pool_hits_total", "Times a free connection was found in the pool",
+		func(s *redis.PoolStats) uint32 { return s.Hits })
+	counter("redis_pool_misses_total", "Times no free connection was found in the pool",
+		func(s *redis.PoolStats) uint32 { return s.Misses })
+	counter("redis_pool_timeouts_total", "Times waiting for a connection timed out",
+		func(s *redis.PoolStats) uint32 { return s.Timeouts })
+	counter("redis_pool_stale_connections_total", "Stale connections removed from the pool",
+		func(s *redis.PoolStats) uint32 { return s.StaleConns })
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Audit event types for authentication
+const (
+	AuditLoginSucceeded     = "login_succeeded"
+	AuditLoginFailed        = "login_failed"
+	AuditLoginThrottled     = "login_throttled"
+	AuditAccountLocked      = "account_locked"
+	AuditCredentialStuffing = "credential_stuffing"
+	AuditDistributedAttack  = "distributed_attack"
+)
+
+// AuditEvent a security relevant event, UserID is empty for unknown accounts;
+// Distinct counts accounts failing from the IP for credential_stuffing and IPs
+// failing on the account for distributed_attack
+type AuditEvent struct {
+	Type       string
+	UserID     string
+	Email      string
+	IP         string
+	Reason     string
+	Failures   int64
+	Distinct   int64
+	RetryAfter time.Duration
+}
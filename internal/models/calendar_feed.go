@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+const (
+	CalendarFeedTypeEvent = "event"
+	CalendarFeedTypeTodo  = "todo"
+)
+
+// CalendarFeed per-user iCalendar subscription, the URL carries the secret
+// token and is only known right after a rotation
+type CalendarFeed struct {
+	UserID    string     `json:"user_id"`
+	Enabled   bool       `json:"enabled"`
+	URL       string     `json:"url,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
@@ -0,0 +1,37 @@
+package models
+
+// Email categories, users can opt out of every category except account
+const (
+	EmailCategoryAccount     = "account"
+	EmailCategoryReminders   = "reminders"
+	EmailCategoryInvitations = "invitations"
+)
+
+// Email is a fully rendered message ready for a Mailer
+type Email struct {
+	To      []string `json:"to"`
+	Subject string   `json:"subject"`
+	Text    string   `json:"text"`
+	HTML    string   `json:"html,omitempty"`
+}
+
+// EmailMessage asks for a templated email to be rendered and queued.
+// UserID and Category decide whether the recipient's preferences allow it.
+type EmailMessage struct {
+	Template string
+	To       string
+	UserID   string
+	Category string
+	Data     map[string]any
+}
+
+// MailJob is one queued email and its delivery attempts
+type MailJob struct {
+	ID        string `json:"id"`
+	Email     Email  `json:"email"`
+	Attempts  int    `json:"attempts"`
+	LastError string `json:"last_error,omitempty"`
+
+	// Receipt queue entry the job was claimed from, set by MailQueue.ClaimDue
+	Receipt string `json:"-"`
+}
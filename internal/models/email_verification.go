@@ -0,0 +1,26 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Email verification policies, what an account may do before its email is verified
+const (
+	// EmailVerificationOff unverified accounts are not restricted
+	EmailVerificationOff = "off"
+	// EmailVerificationReadOnly unverified accounts may only read
+	EmailVerificationReadOnly = "read_only"
+	// EmailVerificationBlock unverified accounts can't use protected routes
+	EmailVerificationBlock = "block"
+)
+
+// ParseEmailVerificationPolicy parse one of off, read_only or block
+func ParseEmailVerificationPolicy(spec string) (string, error) {
+	policy := strings.ToLower(strings.TrimSpace(spec))
+	switch policy {
+	case EmailVerificationOff, EmailVerificationReadOnly, EmailVerificationBlock:
+		return policy, nil
+	}
+	return "", fmt.Errorf("email verification policy %q: want off, read_only or block", spec)
+}
@@ -0,0 +1,22 @@
+package models
+
+import "testing"
+
+func TestParseEmailVerificationPolicy(t *testing.T) {
+	for spec, want := range map[string]string{
+		"off":       EmailVerificationOff,
+		"read_only": EmailVerificationReadOnly,
+		" BLOCK ":   EmailVerificationBlock,
+	} {
+		got, err := ParseEmailVerificationPolicy(spec)
+		if err != nil || got != want {
+			t.Errorf("ParseEmailVerificationPolicy(%q) = %q, %v; want %q", spec, got, err, want)
+		}
+	}
+
+	for _, spec := range []string{"", "readonly", "strict"} {
+		if _, err := ParseEmailVerificationPolicy(spec); err == nil {
+			t.Errorf("ParseEmailVerificationPolicy(%q) expected an error", spec)
+		}
+	}
+}
@@ -0,0 +1,25 @@
+package models
+
+const (
+	HealthStatusOK      = "ok"
+	HealthStatusFailing = "failing"
+)
+
+// HealthCheck outcome of one readiness check
+type HealthCheck struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	DurationMs int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+}
+
+// HealthReport overall readiness, failing when any check fails
+type HealthReport struct {
+	Status string         `json:"status"`
+	Checks []*HealthCheck `json:"checks,omitempty"`
+}
+
+// OK true when every check passed
+func (r *HealthReport) OK() bool {
+	return r.Status == HealthStatusOK
+}
@@ -0,0 +1,16 @@
+package models
+
+const (
+	IdempotencyStateInFlight  = "in_flight"
+	IdempotencyStateCompleted = "completed"
+)
+
+// IdempotencyRecord outcome of the first request made with an idempotency key
+type IdempotencyRecord struct {
+	State string `json:"state"`
+	// Fingerprint hash of the request, a retry must send the same payload
+	Fingerprint string `json:"fingerprint"`
+	StatusCode  int    `json:"status_code,omitempty"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        []byte `json:"body,omitempty"`
+}
@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// LoginProtection limits on failed logins, per account and per client IP
+type LoginProtection struct {
+	// MaxFailures failures within Window that lock the account for Lockout
+	MaxFailures int
+	Window      time.Duration
+	Lockout     time.Duration
+	// from the second failure on, the next attempt waits BaseDelay, doubling up to MaxDelay
+	BaseDelay time.Duration
+	MaxDelay  time.Duration
+	// StuffingAccounts distinct accounts failing from one IP within Window that
+	// block the IP for StuffingBlock, 0 disables
+	StuffingAccounts int
+	StuffingBlock    time.Duration
+	// DistributedIPs distinct IPs failing on one account within Window that flag
+	// a distributed attack on it, the account lock still applies, 0 disables
+	DistributedIPs int
+}
+
+// Why a login was refused before the password was checked
+const (
+	LoginThrottleDelay     = "delay"
+	LoginThrottleLocked    = "locked"
+	LoginThrottleIPBlocked = "ip_blocked"
+)
+
+// LoginThrottle a refused login attempt and when to retry
+type LoginThrottle struct {
+	Reason     string
+	RetryAfter time.Duration
+}
+
+// LoginFailure state after recording a failed login
+type LoginFailure struct {
+	Failures int64
+	// Locked the account was locked by this failure
+	Locked     bool
+	RetryAfter time.Duration
+	// DistinctAccounts accounts the IP failed on within the window
+	DistinctAccounts int64
+	// DistinctIPs IPs the account failed from within the window
+	DistinctIPs int64
+	// IPBlocked the IP was blocked for credential stuffing by this failure
+	IPBlocked bool
+	// Distributed this failure's IP brought the account to DistributedIPs
+	Distributed bool
+}
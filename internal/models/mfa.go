@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// UserMFA the user's TOTP enrollment, pending until confirmed with a first code
+type UserMFA struct {
+	UserID string
+	// Secret the TOTP secret encrypted with MFA_ENCRYPTION_KEY, empty when never enrolled
+	Secret    string
+	Enabled   bool
+	EnabledAt *time.Time
+	// LastUsedStep the newest time step a code was accepted for, older codes are replays
+	LastUsedStep int64
+}
+
+// MFAEnrollment what an authenticator app needs, shown once when enrolling
+type MFAEnrollment struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+// MFAChallenge a login that passed the password check and waits for the second factor
+type MFAChallenge struct {
+	ID            string `redis:"id"`
+	UserID        string `redis:"user_id"`
+	EmailVerified bool   `redis:"email_verified"`
+}
@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// NotificationPreferences what a user wants to be emailed about
+type NotificationPreferences struct {
+	UserID       string    `json:"user_id"`
+	EmailEnabled bool      `json:"email_enabled"`
+	Reminders    bool      `json:"reminders"`
+	Invitations  bool      `json:"invitations"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// DefaultNotificationPreferences applies to users who never changed anything
+func DefaultNotificationPreferences(userID string) *NotificationPreferences {
+	return &NotificationPreferences{
+		UserID:       userID,
+		EmailEnabled: true,
+		Reminders:    true,
+		Invitations:  true,
+	}
+}
+
+// Allows reports whether an email of category may be sent
+func (p *NotificationPreferences) Allows(category string) bool {
+	switch category {
+	case "", EmailCategoryAccount:
+		return true
+	case EmailCategoryReminders:
+		return p.EmailEnabled && p.Reminders
+	case EmailCategoryInvitations:
+		return p.EmailEnabled && p.Invitations
+	default:
+		return p.EmailEnabled
+	}
+}
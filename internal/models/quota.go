@@ -0,0 +1,37 @@
+package models
+
+import "fmt"
+
+// TaskQuota per-user storage limits, zero means unlimited
+type TaskQuota struct {
+	MaxTasks        int
+	MaxContentBytes int64
+}
+
+// Limited whether any quota is set
+func (q TaskQuota) Limited() bool {
+	return q.MaxTasks > 0 || q.MaxContentBytes > 0
+}
+
+// Exceeded why adding tasks and bytes to usage breaks a quota, empty when it
+// fits; removals (zero or negative amounts) always fit
+func (q TaskQuota) Exceeded(usage *TaskUsage, addTasks int, addBytes int64) string {
+	if q.MaxTasks > 0 && addTasks > 0 && usage.Tasks+addTasks > q.MaxTasks {
+		return fmt.Sprintf("task quota exceeded: at most %d tasks", q.MaxTasks)
+	}
+	if q.MaxContentBytes > 0 && addBytes > 0 && usage.ContentBytes+addBytes > q.MaxContentBytes {
+		return fmt.Sprintf("content quota exceeded: at most %d bytes of task text", q.MaxContentBytes)
+	}
+	return ""
+}
+
+// TaskUsage what a user currently stores, content is title plus content bytes
+type TaskUsage struct {
+	Tasks        int   `json:"tasks"`
+	ContentBytes int64 `json:"content_bytes"`
+}
+
+// TaskContentBytes size of a task counted against the content quota
+func TaskContentBytes(title, content string) int64 {
+	return int64(len(title) + len(content))
+}
@@ -0,0 +1,84 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RateLimitRule at most Limit requests in any Window
+type RateLimitRule struct {
+	Limit  int
+	Window time.Duration
+}
+
+// RateLimitResult outcome of one rate limit check
+type RateLimitResult struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	// Reset until a slot frees up, the wait before retrying when not allowed
+	Reset time.Duration
+}
+
+// ParseRateLimitRule parse "<limit>/<window>", e.g. "100/1m"
+func ParseRateLimitRule(spec string) (RateLimitRule, error) {
+	limit, window, ok := strings.Cut(strings.TrimSpace(spec), "/")
+	if !ok {
+		return RateLimitRule{}, fmt.Errorf("rate limit %q: want <limit>/<window>", spec)
+	}
+	n, err := strconv.Atoi(strings.TrimSpace(limit))
+	if err != nil || n <= 0 {
+		return RateLimitRule{}, fmt.Errorf("rate limit %q: limit must be a positive number", spec)
+	}
+	d, err := time.ParseDuration(strings.TrimSpace(window))
+	if err != nil || d < time.Millisecond {
+		return RateLimitRule{}, fmt.Errorf("rate limit %q: window must be a duration like 30s or 1m", spec)
+	}
+	return RateLimitRule{Limit: n, Window: d}, nil
+}
+
+// ParseRouteRateLimits parse comma separated "<METHOD> <route>=<limit>/<window>"
+// overrides, keyed by "<METHOD> <route>"
+func ParseRouteRateLimits(spec string) (map[string]RateLimitRule, error) {
+	rules := make(map[string]RateLimitRule)
+	for _, entry := range strings.Split(spec, ",") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		route, limit, ok := strings.Cut(entry, "=")
+		method, path, hasPath := strings.Cut(strings.TrimSpace(route), " ")
+		if !ok || !hasPath {
+			return nil, fmt.Errorf("route rate limit %q: want METHOD /path=<limit>/<window>", entry)
+		}
+		rule, err := ParseRateLimitRule(limit)
+		if err != nil {
+			return nil, err
+		}
+		rules[strings.ToUpper(method)+" "+strings.TrimSpace(path)] = rule
+	}
+	return rules, nil
+}
+
+// ParseMethodRateLimits parse comma separated "<Method>=<limit>/<window>"
+// overrides, keyed by the bare gRPC method name
+func ParseMethodRateLimits(spec string) (map[string]RateLimitRule, error) {
+	rules := make(map[string]RateLimitRule)
+	for _, entry := range strings.Split(spec, ",") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		method, limit, ok := strings.Cut(entry, "=")
+		method = strings.TrimSpace(method)
+		if !ok || method == "" || strings.ContainsAny(method, " /") {
+			return nil, fmt.Errorf("method rate limit %q: want Method=<limit>/<window>", entry)
+		}
+		rule, err := ParseRateLimitRule(limit)
+		if err != nil {
+			return nil, err
+		}
+		rules[method] = rule
+	}
+	return rules, nil
+}
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseRateLimitRule(t *testing.T) {
+	rule, err := ParseRateLimitRule(" 100 / 1m ")
+	if err != nil {
+		t.Fatalf("ParseRateLimitRule failed: %v", err)
+	}
+	if rule.Limit != 100 || rule.Window != time.Minute {
+		t.Errorf("unexpected rule %+v", rule)
+	}
+
+	for _, spec := range []string{"", "100", "0/1m", "-1/1m", "ten/1m", "10/", "10/minute"} {
+		if _, err := ParseRateLimitRule(spec); err == nil {
+			t.Errorf("%q: expected an error", spec)
+		}
+	}
+}
+
+func TestParseRouteRateLimits(t *testing.T) {
+	rules, err := ParseRouteRateLimits("post /tasks/import=5/1m, GET /tasks/:id=30/10s,")
+	if err != nil {
+		t.Fatalf("ParseRouteRateLimits failed: %v", err)
+	}
+	if len(rules) != 2 {
+		t.Fatalf("expected 2 rules, got %d", len(rules))
+	}
+	if rules["POST /tasks/import"] != (RateLimitRule{Limit: 5, Window: time.Minute}) {
+		t.Errorf("unexpected import rule %+v", rules["POST /tasks/import"])
+	}
+	if rules["GET /tasks/:id"] != (RateLimitRule{Limit: 30, Window: 10 * time.Second}) {
+		t.Errorf("unexpected get rule %+v", rules["GET /tasks/:id"])
+	}
+
+	if rules, err := ParseRouteRateLimits(""); err != nil || len(rules) != 0 {
+		t.Errorf("empty spec must give no rules, got %v, %v", rules, err)
+	}
+	for _, spec := range []string{"/tasks=5/1m", "POST /tasks", "POST /tasks=5"} {
+		if _, err := ParseRouteRateLimits(spec); err == nil {
+			t.Errorf("%q: expected an error", spec)
+		}
+	}
+}
+
+func TestParseMethodRateLimits(t *testing.T) {
+	rules, err := ParseMethodRateLimits("BatchTasks=10/1m, SearchTasks=60/30s")
+	if err != nil {
+		t.Fatalf("ParseMethodRateLimits failed: %v", err)
+	}
+	if rules["BatchTasks"] != (RateLimitRule{Limit: 10, Window: time.Minute}) {
+		t.Errorf("unexpected batch rule %+v", rules["BatchTasks"])
+	}
+	if rules["SearchTasks"] != (RateLimitRule{Limit: 60, Window: 30 * time.Second}) {
+		t.Errorf("unexpected search rule %+v", rules["SearchTasks"])
+	}
+
+	for _, spec := range []string{"=10/1m", "/task.v1.TaskService/GetTasks=10/1m", "GetTasks", "GetTasks=10"} {
+		if _, err := ParseMethodRateLimits(spec); err == nil {
+			t.Errorf("%q: expected an error", spec)
+		}
+	}
+}
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// Reminder delivery channels
+const (
+	ReminderChannelLog     = "log"
+	ReminderChannelWebhook = "webhook"
+	ReminderChannelEmail   = "email"
+)
+
+// Reminder is a rule that fires OffsetSeconds before its task is due
+type Reminder struct {
+	ID            string    `json:"id"`
+	TaskID        string    `json:"task_id"`
+	UserID        string    `json:"user_id"`
+	OffsetSeconds int64     `json:"offset_seconds"`
+	Channel       string    `json:"channel"`
+	Target        string    `json:"target,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// DueReminder is a reminder resolved against its task's current due date
+type DueReminder struct {
+	ReminderID string    `json:"reminder_id"`
+	TaskID     string    `json:"task_id"`
+	UserID     string    `json:"user_id"`
+	TaskTitle  string    `json:"task_title"`
+	Channel    string    `json:"channel"`
+	Target     string    `json:"target,omitempty"`
+	UserEmail  string    `json:"-"`
+	DueAt      time.Time `json:"due_at"`
+	FireAt     time.Time `json:"fire_at"`
+}
@@ -1,6 +1,7 @@
 package models
 
 type Session struct {
-	ID     string `redis:"id"`
-	UserID string `redis:"user_id"`
+	ID            string `redis:"id"`
+	UserID        string `redis:"user_id"`
+	EmailVerified bool   `redis:"email_verified"`
 }
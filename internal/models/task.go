@@ -2,11 +2,29 @@ package models
 
 import "time"
 
+// Task statuses
+const (
+	TaskStatusTodo       = "todo"
+	TaskStatusInProgress = "in_progress"
+	TaskStatusDone       = "done"
+)
+
 type Task struct {
-	ID        string    `json:"id"`
-	UserID    string    `json:"user_id"`
-	Title     string    `json:"title" validate:"required,min=3,max=50"`
-	Content   string    `json:"content" validate:"max=500"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID        string     `json:"id"`
+	UserID    string     `json:"user_id"`
+	Title     string     `json:"title" validate:"required,min=3,max=50"`
+	Content   string     `json:"content" validate:"max=500"`
+	Status    string     `json:"status" validate:"omitempty,oneof=todo in_progress done"`
+	Labels    []string   `json:"labels"`
+	DueAt     *time.Time `json:"due_at,omitempty"`
+	SeriesID  *string    `json:"series_id,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+
+	// checklist completion, maintained by the checklist repository
+	ChecklistTotal int `json:"checklist_total"`
+	ChecklistDone  int `json:"checklist_done"`
+
+	// id in the tool the task was imported from, only loaded by export
+	ExternalID *string `json:"external_id,omitempty"`
 }
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// Batch operation kinds
+const (
+	BatchOpCreate    = "create"
+	BatchOpUpdate    = "update"
+	BatchOpDelete    = "delete"
+	BatchOpSetStatus = "set_status"
+)
+
+// TaskBatchOp one operation of a batch, ID is filled in for created tasks
+type TaskBatchOp struct {
+	Op      string
+	ID      string
+	UserID  string
+	Title   string
+	Content string
+	Status  string
+	DueAt   *time.Time
+}
+
+// TaskBatchItemResult outcome of one operation, in request order
+type TaskBatchItemResult struct {
+	Index int    `json:"index"`
+	Op    string `json:"op"`
+	ID    string `json:"id,omitempty"`
+	OK    bool   `json:"ok"`
+	Code  string `json:"code,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// TaskBatchResult outcome of a batch; in atomic mode Committed is false as
+// soon as one operation failed and none of them were applied
+type TaskBatchResult struct {
+	Atomic    bool                   `json:"atomic"`
+	Committed bool                   `json:"committed"`
+	Succeeded int                    `json:"succeeded"`
+	Failed    int                    `json:"failed"`
+	Results   []*TaskBatchItemResult `json:"results"`
+}
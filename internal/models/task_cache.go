@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// TaskCacheOptions how the task read-through cache behaves, zero disables a feature
+type TaskCacheOptions struct {
+	Expiration time.Duration
+	// NegativeExpiration how long a missing task is remembered as missing
+	NegativeExpiration time.Duration
+	// Jitter spreads Expiration by ±Jitter (0.1 = ±10%) so keys cached together expire apart
+	Jitter float64
+	// EarlyRefreshBeta probabilistic early refresh (XFetch), higher refreshes earlier
+	EarlyRefreshBeta float64
+}
+
+// TaskCacheEntry one cached task lookup
+type TaskCacheEntry struct {
+	Task *Task // nil when NotFound
+	// NotFound the task is cached as missing
+	NotFound bool
+	// TTL time left before the entry expires, negative when it has none
+	TTL time.Duration
+}
@@ -0,0 +1,10 @@
+package models
+
+import "time"
+
+// TaskDependency is an edge "TaskID is blocked by BlockedByID"
+type TaskDependency struct {
+	TaskID      string    `json:"task_id"`
+	BlockedByID string    `json:"blocked_by_id"`
+	CreatedAt   time.Time `json:"created_at"`
+}
@@ -0,0 +1,16 @@
+package models
+
+// TaskListQuery one page of a user's tasks, oldest first. Cursor is the
+// NextCursor of the previous page, empty for the first page.
+type TaskListQuery struct {
+	UserID string
+	Limit  int
+	Cursor string
+}
+
+// TaskPage NextCursor is empty on the last page
+type TaskPage struct {
+	Tasks      []*Task `json:"tasks"`
+	NextCursor string  `json:"next_cursor,omitempty"`
+	Limit      int     `json:"limit"`
+}
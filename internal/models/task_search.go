@@ -0,0 +1,36 @@
+package models
+
+import "time"
+
+// TaskSearchQuery full-text search over the tasks of one user
+type TaskSearchQuery struct {
+	UserID string
+	// Query is the raw search text, the service splits it into Terms which
+	// are matched as prefixes and must all be present; a hit must also carry
+	// every one of Labels
+	Query       string
+	Terms       []string
+	Statuses    []string
+	Labels      []string
+	DueFrom     *time.Time
+	DueTo       *time.Time
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	Limit       int
+	Offset      int
+}
+
+// TaskSearchHit one ranked result, highlights wrap matches in <mark></mark>
+type TaskSearchHit struct {
+	Task           *Task   `json:"task"`
+	Rank           float32 `json:"rank"`
+	TitleHighlight string  `json:"title_highlight"`
+	Snippet        string  `json:"snippet"`
+}
+
+type TaskSearchResult struct {
+	Hits   []*TaskSearchHit `json:"hits"`
+	Total  int              `json:"total"`
+	Limit  int              `json:"limit"`
+	Offset int              `json:"offset"`
+}
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// Series generation modes
+const (
+	// SeriesModeOnComplete creates the next occurrence when the current one is done
+	SeriesModeOnComplete = "on_complete"
+	// SeriesModeScheduled creates the next occurrence once the current one is due
+	SeriesModeScheduled = "scheduled"
+)
+
+// TaskSeries is the template every occurrence of a recurring task is copied from
+type TaskSeries struct {
+	ID        string    `json:"id"`
+	UserID    string    `json:"user_id"`
+	Title     string    `json:"title"`
+	Content   string    `json:"content"`
+	RRule     string    `json:"rrule"`
+	Timezone  string    `json:"timezone"`
+	DTStart   time.Time `json:"dtstart"`
+	Mode      string    `json:"mode"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Recurrence is the client supplied schedule of a recurring task
+type Recurrence struct {
+	RRule    string `json:"rrule" validate:"required"`
+	Timezone string `json:"timezone" validate:"omitempty,timezone"`
+	Mode     string `json:"mode" validate:"omitempty,oneof=on_complete scheduled"`
+}
@@ -0,0 +1,52 @@
+package models
+
+// Import/export formats
+const (
+	TransferFormatCSV    = "csv"
+	TransferFormatJSON   = "json"
+	TransferFormatNDJSON = "ndjson"
+)
+
+// Import modes: create always inserts, upsert matches rows on external_id
+const (
+	ImportModeCreate = "create"
+	ImportModeUpsert = "upsert"
+)
+
+// TaskRecord flat task representation used by export and import, timestamps
+// are RFC3339 strings so CSV and JSON rows share the same shape; nil Labels
+// keep the stored labels on upsert, an empty list clears them
+type TaskRecord struct {
+	ID         string   `json:"id,omitempty"`
+	ExternalID string   `json:"external_id,omitempty" validate:"omitempty,max=255"`
+	Title      string   `json:"title" validate:"required,min=2,max=100"`
+	Content    string   `json:"content" validate:"max=500"`
+	Status     string   `json:"status,omitempty" validate:"omitempty,oneof=todo in_progress done"`
+	DueAt      string   `json:"due_at,omitempty" validate:"omitempty,datetime=2006-01-02T15:04:05Z07:00"`
+	Labels     []string `json:"labels"`
+	CreatedAt  string   `json:"created_at,omitempty"`
+	UpdatedAt  string   `json:"updated_at,omitempty"`
+}
+
+// TaskImportOptions how an import is applied
+type TaskImportOptions struct {
+	Format string
+	Mode   string
+	DryRun bool
+}
+
+// TaskImportRowError field errors of one input row, rows count from 1
+type TaskImportRowError struct {
+	Row    int               `json:"row"`
+	Errors map[string]string `json:"errors"`
+}
+
+type TaskImportResult struct {
+	DryRun  bool                  `json:"dry_run"`
+	Mode    string                `json:"mode"`
+	Total   int                   `json:"total"`
+	Created int                   `json:"created"`
+	Updated int                   `json:"updated"`
+	Failed  int                   `json:"failed"`
+	Errors  []*TaskImportRowError `json:"errors"`
+}
@@ -3,6 +3,8 @@ package policy
 import (
 	"context"
 
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/metrics"
 	"github.com/suryansh74/task-management-api-project/internal/ports"
 )
 
@@ -17,9 +19,13 @@ func NewTaskQuery(cache ports.TaskCacheRepository, repo ports.TaskRepository) *T
 
 func (q *TaskQuery) GetOwnerIDByTaskID(ctx context.Context, taskID string, key string) (string, error) {
 	// 1️⃣ Try cache
-	task, _ := q.cache.GetTaskByID(ctx, key)
-	if task != nil {
-		return task.UserID, nil
+	entry, err := q.cache.GetTaskEntry(ctx, key)
+	metrics.ObserveCacheLookup("task_owner", entry != nil, err)
+	if entry != nil {
+		if entry.NotFound {
+			return "", apperror.NewNotFoundError("task not found")
+		}
+		return entry.Task.UserID, nil
 	}
 
 	// 2️⃣ Fallback to DB
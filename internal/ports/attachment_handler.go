@@ -0,0 +1,12 @@
+package ports
+
+import "github.com/gofiber/fiber/v2"
+
+// AttachmentHandler defines the HTTP adapter contract for task attachments.
+type AttachmentHandler interface {
+	UploadAttachment(c *fiber.Ctx) error
+	GetAttachments(c *fiber.Ctx) error
+	GetDownloadURL(c *fiber.Ctx) error
+	DeleteAttachmentByID(c *fiber.Ctx) error
+	DownloadSignedFile(c *fiber.Ctx) error
+}
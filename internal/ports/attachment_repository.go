@@ -0,0 +1,19 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/suryansh74/task-management-api-project/internal/models"
+)
+
+type AttachmentRepository interface {
+	CreateAttachment(ctx context.Context, attachment *models.Attachment) (string, error)
+	GetAttachmentByID(ctx context.Context, id string) (*models.Attachment, error)
+	GetAttachmentsByTaskID(ctx context.Context, taskID string) ([]*models.Attachment, error)
+	DeleteAttachmentByID(ctx context.Context, id string) error
+	// GetDeletedBlobKeys returns up to limit storage keys of deleted
+	// attachments (including task and user cascades) whose blobs may remain.
+	GetDeletedBlobKeys(ctx context.Context, limit int) ([]string, error)
+	// ForgetDeletedBlobKeys drops keys whose blobs are gone.
+	ForgetDeletedBlobKeys(ctx context.Context, keys []string) error
+}
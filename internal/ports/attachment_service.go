@@ -0,0 +1,35 @@
+package ports
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/suryansh74/task-management-api-project/internal/models"
+)
+
+// AttachmentService defines business logic operations for task attachments
+type AttachmentService interface {
+	UploadAttachment(ctx context.Context, taskID string, userID string, upload *AttachmentUpload) (*models.Attachment, error)
+	GetAttachments(ctx context.Context, taskID string, userID string) ([]*models.Attachment, error)
+	GetDownloadURL(ctx context.Context, taskID string, attachmentID string, userID string) (*AttachmentDownload, error)
+	DeleteAttachmentByID(ctx context.Context, taskID string, attachmentID string, userID string) error
+	// OpenSignedFile verifies a signed download link and opens the blob behind it.
+	OpenSignedFile(ctx context.Context, key string, filename string, expires int64, signature string) (io.ReadCloser, error)
+	// SweepDeletedBlobs deletes blobs left behind by deleted attachments,
+	// tasks and users and returns how many were removed.
+	SweepDeletedBlobs(ctx context.Context) (int, error)
+}
+
+// AttachmentUpload is the service layer input for a new attachment
+type AttachmentUpload struct {
+	Filename string
+	Size     int64
+	Body     io.Reader
+}
+
+// AttachmentDownload is the service layer response for a signed download link
+type AttachmentDownload struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
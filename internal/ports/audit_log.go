@@ -0,0 +1,12 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/suryansh74/task-management-api-project/internal/models"
+)
+
+// AuditLog records security events, best effort so it never fails the caller
+type AuditLog interface {
+	Record(ctx context.Context, event *models.AuditEvent)
+}
@@ -0,0 +1,18 @@
+package ports
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// BlobStore abstracts where attachment bytes live (local disk, S3-compatible, ...).
+// Keys are opaque, slash-separated paths chosen by the caller.
+type BlobStore interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	// SignedURL returns a time-limited download URL for key.
+	// filename is suggested to the client via Content-Disposition.
+	SignedURL(ctx context.Context, key string, filename string, expiry time.Duration) (string, error)
+}
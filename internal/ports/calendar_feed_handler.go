@@ -0,0 +1,11 @@
+package ports
+
+import "github.com/gofiber/fiber/v2"
+
+// CalendarFeedHandler defines the HTTP adapter contract for calendar feeds.
+type CalendarFeedHandler interface {
+	GetFeed(c *fiber.Ctx) error
+	RotateFeedToken(c *fiber.Ctx) error
+	DisableFeed(c *fiber.Ctx) error
+	ServeFeed(c *fiber.Ctx) error
+}
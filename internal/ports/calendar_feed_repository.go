@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/suryansh74/task-management-api-project/internal/models"
+)
+
+type CalendarFeedRepository interface {
+	// SaveFeedToken replaces the token hash of the user, invalidating the old URL
+	SaveFeedToken(ctx context.Context, userID string, tokenHash string) (*models.CalendarFeed, error)
+	// GetFeed returns a disabled feed when the user has none
+	GetFeed(ctx context.Context, userID string) (*models.CalendarFeed, error)
+	GetUserIDByTokenHash(ctx context.Context, tokenHash string) (string, error)
+	DeleteFeed(ctx context.Context, userID string) error
+}
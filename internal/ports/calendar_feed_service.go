@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/suryansh74/task-management-api-project/internal/models"
+)
+
+// CalendarFeedService token protected iCalendar feed of tasks with a due date
+type CalendarFeedService interface {
+	GetFeed(ctx context.Context, userID string) (*models.CalendarFeed, error)
+	// RotateFeedToken issues a new feed URL, the previous one stops working
+	RotateFeedToken(ctx context.Context, userID string) (*models.CalendarFeed, error)
+	DisableFeed(ctx context.Context, userID string) error
+	// RenderFeed returns the .ics body for a feed token and its ETag
+	RenderFeed(ctx context.Context, token string, feedType string) ([]byte, string, error)
+}
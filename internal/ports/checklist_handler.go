@@ -0,0 +1,13 @@
+package ports
+
+import "github.com/gofiber/fiber/v2"
+
+// ChecklistHandler defines the HTTP adapter contract for task checklists.
+type ChecklistHandler interface {
+	AddItem(c *fiber.Ctx) error
+	GetItems(c *fiber.Ctx) error
+	UpdateItem(c *fiber.Ctx) error
+	ToggleItem(c *fiber.Ctx) error
+	ReorderItems(c *fiber.Ctx) error
+	DeleteItem(c *fiber.Ctx) error
+}
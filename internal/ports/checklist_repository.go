@@ -0,0 +1,24 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/suryansh74/task-management-api-project/internal/models"
+)
+
+// ChecklistRepository stores checklist items. Every write also refreshes the
+// checklist_total/checklist_done counts of the owning task.
+type ChecklistRepository interface {
+	// CreateChecklistItem appends the item to the end of the task's checklist.
+	CreateChecklistItem(ctx context.Context, item *models.ChecklistItem) (string, error)
+	GetChecklistItemByID(ctx context.Context, id string) (*models.ChecklistItem, error)
+	GetChecklistItemsByTaskID(ctx context.Context, taskID string) ([]*models.ChecklistItem, error)
+	// UpdateChecklistItem changes the fields that are not nil and returns the
+	// updated item, in one statement under the checklist lock.
+	UpdateChecklistItem(ctx context.Context, taskID string, id string, text *string, checked *bool) (*models.ChecklistItem, error)
+	ToggleChecklistItem(ctx context.Context, taskID string, id string) (*models.ChecklistItem, error)
+	// ReorderChecklistItems sets positions from itemIDs, which must list every
+	// item of the task exactly once. Either all positions change or none do.
+	ReorderChecklistItems(ctx context.Context, taskID string, itemIDs []string) error
+	DeleteChecklistItemByID(ctx context.Context, taskID string, id string) error
+}
@@ -0,0 +1,18 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/suryansh74/task-management-api-project/internal/models"
+)
+
+// ChecklistService defines business logic operations for task checklists
+type ChecklistService interface {
+	AddItem(ctx context.Context, taskID string, userID string, text string) (*models.ChecklistItem, error)
+	GetItems(ctx context.Context, taskID string, userID string) ([]*models.ChecklistItem, error)
+	// UpdateItem changes the fields that are not nil.
+	UpdateItem(ctx context.Context, taskID string, itemID string, userID string, text *string, checked *bool) (*models.ChecklistItem, error)
+	ToggleItem(ctx context.Context, taskID string, itemID string, userID string) (*models.ChecklistItem, error)
+	ReorderItems(ctx context.Context, taskID string, userID string, itemIDs []string) ([]*models.ChecklistItem, error)
+	DeleteItem(ctx context.Context, taskID string, itemID string, userID string) error
+}
@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/suryansh74/task-management-api-project/internal/models"
+)
+
+// EmailService renders templated emails and sends them asynchronously
+type EmailService interface {
+	// Enqueue renders msg and queues it, skipped (nil error) when the
+	// recipient's preferences opt out of msg.Category
+	Enqueue(ctx context.Context, msg *models.EmailMessage) error
+	// ProcessQueue sends due jobs, rescheduling failures with backoff
+	ProcessQueue(ctx context.Context, now time.Time) (int, error)
+}
@@ -0,0 +1,9 @@
+package ports
+
+import "github.com/gofiber/fiber/v2"
+
+// EmailVerificationHandler defines the HTTP adapter contract for email verification.
+type EmailVerificationHandler interface {
+	VerifyEmail(c *fiber.Ctx) error
+	ResendVerification(c *fiber.Ctx) error
+}
@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"context"
+)
+
+// EmailVerificationService proves users own the email they registered with
+type EmailVerificationService interface {
+	// SendVerification emails a signed verification link to the user
+	SendVerification(ctx context.Context, userID string) error
+	// Verify marks the email in the token verified, tokens stay valid until
+	// they expire so opening a link twice is not an error
+	Verify(ctx context.Context, token string) error
+}
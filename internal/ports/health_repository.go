@@ -0,0 +1,11 @@
+package ports
+
+import "context"
+
+// HealthRepository probes the backing stores the app cannot serve without
+type HealthRepository interface {
+	PingPostgres(ctx context.Context) error
+	PingRedis(ctx context.Context) error
+	// GetSchemaVersion highest version recorded in schema_migrations
+	GetSchemaVersion(ctx context.Context) (int, error)
+}
@@ -0,0 +1,12 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/suryansh74/task-management-api-project/internal/models"
+)
+
+// HealthService readiness of the app's dependencies
+type HealthService interface {
+	CheckReadiness(ctx context.Context) *models.HealthReport
+}
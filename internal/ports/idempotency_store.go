@@ -0,0 +1,18 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/suryansh74/task-management-api-project/internal/models"
+)
+
+// IdempotencyStore remembers the response of requests made with an idempotency key
+type IdempotencyStore interface {
+	// Begin claims key for a request with fingerprint; when the key is already
+	// taken it returns the stored record and false
+	Begin(ctx context.Context, key string, fingerprint string, lockTTL time.Duration) (*models.IdempotencyRecord, bool, error)
+	Complete(ctx context.Context, key string, record *models.IdempotencyRecord, ttl time.Duration) error
+	// Release frees a claimed key so the request can be retried
+	Release(ctx context.Context, key string) error
+}
@@ -0,0 +1,12 @@
+package ports
+
+import "context"
+
+// LocalTaskCache in-process tier in front of the shared task cache, kept
+// coherent across replicas by broadcasting invalidations
+type LocalTaskCache interface {
+	TaskCacheRepository
+
+	// Listen applies invalidations published by every replica until ctx is done
+	Listen(ctx context.Context)
+}
@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/suryansh74/task-management-api-project/internal/models"
+)
+
+// LoginAttemptStore failed login tracking shared by all replicas, account is
+// an opaque key for the email so unknown emails are tracked the same way
+type LoginAttemptStore interface {
+	// Check returns nil when the account may try a password from ip
+	Check(ctx context.Context, account string, ip string) (*models.LoginThrottle, error)
+	RecordFailure(ctx context.Context, account string, ip string, protection models.LoginProtection) (*models.LoginFailure, error)
+	// Reset clears the account's failures and delay after a successful login
+	Reset(ctx context.Context, account string) error
+}
@@ -0,0 +1,23 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/suryansh74/task-management-api-project/internal/models"
+)
+
+// MailQueue holds emails waiting to be sent, shared by every replica
+type MailQueue interface {
+	// Enqueue makes job available to workers at sendAt, a claimed job
+	// replaces its claimed entry (a retry)
+	Enqueue(ctx context.Context, job *models.MailJob, sendAt time.Time) error
+	// ClaimDue atomically leases up to limit jobs due at now. A leased job
+	// that isn't acked, enqueued again or dead lettered before the lease ends
+	// is handed out again, so a crashed worker loses no mail.
+	ClaimDue(ctx context.Context, now time.Time, limit int) ([]*models.MailJob, error)
+	// Ack removes a claimed job that was sent
+	Ack(ctx context.Context, job *models.MailJob) error
+	// DeadLetter parks a claimed job that ran out of attempts
+	DeadLetter(ctx context.Context, job *models.MailJob) error
+}
@@ -0,0 +1,12 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/suryansh74/task-management-api-project/internal/models"
+)
+
+// Mailer delivers a rendered email (SMTP, log, ...)
+type Mailer interface {
+	Send(ctx context.Context, email *models.Email) error
+}
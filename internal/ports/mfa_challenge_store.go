@@ -0,0 +1,18 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/suryansh74/task-management-api-project/internal/models"
+)
+
+// MFAChallengeStore short-lived logins waiting for their second factor
+type MFAChallengeStore interface {
+	Save(ctx context.Context, challenge *models.MFAChallenge, ttl time.Duration) error
+	// Get returns nil when the challenge expired or never existed
+	Get(ctx context.Context, id string) (*models.MFAChallenge, error)
+	// RecordFailure counts a wrong code, returns the failures so far
+	RecordFailure(ctx context.Context, id string) (int64, error)
+	Delete(ctx context.Context, id string) error
+}
@@ -0,0 +1,10 @@
+package ports
+
+import "github.com/gofiber/fiber/v2"
+
+// MFAHandler defines the HTTP adapter contract for managing two-factor authentication.
+type MFAHandler interface {
+	Enroll(c *fiber.Ctx) error
+	Confirm(c *fiber.Ctx) error
+	Disable(c *fiber.Ctx) error
+}
@@ -0,0 +1,22 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/suryansh74/task-management-api-project/internal/models"
+)
+
+type MFARepository interface {
+	// GetMFA returns the user's enrollment, not enabled and without a secret when never enrolled
+	GetMFA(ctx context.Context, userID string) (*models.UserMFA, error)
+	// SavePendingSecret starts or restarts enrollment, CONFLICT once 2FA is enabled
+	SavePendingSecret(ctx context.Context, userID string, secret string) error
+	// Enable turns on a pending enrollment and replaces the recovery codes
+	Enable(ctx context.Context, userID string, step int64, recoveryCodeHashes []string) error
+	// UseStep records a code for step as used, false when step is not newer than the last one
+	UseStep(ctx context.Context, userID string, step int64) (bool, error)
+	// UseRecoveryCode marks the code used, false when unknown or already used
+	UseRecoveryCode(ctx context.Context, userID string, codeHash string) (bool, error)
+	// Delete disables 2FA and drops the recovery codes
+	Delete(ctx context.Context, userID string) error
+}
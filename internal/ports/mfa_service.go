@@ -0,0 +1,25 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/suryansh74/task-management-api-project/internal/models"
+)
+
+// MFAService TOTP two-factor authentication
+type MFAService interface {
+	// Enroll creates a new pending secret, 2FA stays off until Confirm
+	Enroll(ctx context.Context, userID string) (*models.MFAEnrollment, error)
+	// Confirm enables 2FA with a first code and returns the recovery codes,
+	// the only time they are shown
+	Confirm(ctx context.Context, userID string, code string) ([]string, error)
+	// Disable turns 2FA off, code is a current TOTP or an unused recovery code.
+	// Wrong codes count as failed logins for the account and lock disabling
+	// after the challenge's max attempts.
+	Disable(ctx context.Context, userID string, code string, clientIP string) error
+	// StartLogin issues a challenge for a user whose password was checked
+	StartLogin(ctx context.Context, user *UserResponse) (string, error)
+	// CompleteLogin checks the second factor and returns the user to create a session for,
+	// wrong codes count as failed logins for the account
+	CompleteLogin(ctx context.Context, challengeID string, code string, clientIP string) (*UserResponse, error)
+}
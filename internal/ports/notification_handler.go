@@ -0,0 +1,9 @@
+package ports
+
+import "github.com/gofiber/fiber/v2"
+
+// NotificationHandler defines the HTTP adapter contract for notification preferences.
+type NotificationHandler interface {
+	GetPreferences(c *fiber.Ctx) error
+	UpdatePreferences(c *fiber.Ctx) error
+}
@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/suryansh74/task-management-api-project/internal/models"
+)
+
+type NotificationPreferencesRepository interface {
+	// GetPreferences returns the defaults when the user has no row yet
+	GetPreferences(ctx context.Context, userID string) (*models.NotificationPreferences, error)
+	UpsertPreferences(ctx context.Context, prefs *models.NotificationPreferences) error
+}
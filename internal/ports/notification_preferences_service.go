@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/suryansh74/task-management-api-project/internal/models"
+)
+
+// NotificationPreferencesService defines business logic operations for notification preferences
+type NotificationPreferencesService interface {
+	GetPreferences(ctx context.Context, userID string) (*models.NotificationPreferences, error)
+	UpdatePreferences(ctx context.Context, prefs *models.NotificationPreferences) (*models.NotificationPreferences, error)
+}
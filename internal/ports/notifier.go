@@ -0,0 +1,13 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/suryansh74/task-management-api-project/internal/models"
+)
+
+// Notifier delivers a reminder over one channel (log, webhook, email, ...)
+type Notifier interface {
+	Channel() string
+	Notify(ctx context.Context, reminder *models.DueReminder) error
+}
@@ -0,0 +1,9 @@
+package ports
+
+import "github.com/gofiber/fiber/v2"
+
+// PasswordHandler defines the HTTP adapter contract for password resets.
+type PasswordHandler interface {
+	ForgotPassword(c *fiber.Ctx) error
+	ResetPassword(c *fiber.Ctx) error
+}
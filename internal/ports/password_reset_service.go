@@ -0,0 +1,19 @@
+package ports
+
+import (
+	"context"
+)
+
+// PasswordResetService lets users who forgot their password set a new one
+type PasswordResetService interface {
+	// ForgotPassword emails a reset link when an account exists for email.
+	// The link is issued in the background, so the result and the response
+	// time are the same either way.
+	ForgotPassword(ctx context.Context, email string) error
+	// ResetPassword sets a new password with a token from the email and logs
+	// the user out everywhere
+	ResetPassword(ctx context.Context, token string, password string) error
+	// Wait blocks until the links being issued in the background are queued,
+	// shutdown calls it before closing the clients they use
+	Wait()
+}
@@ -0,0 +1,14 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// PasswordResetStore keeps hashed password reset tokens until they are used or expire
+type PasswordResetStore interface {
+	// Save stores tokenHash for userID, the user's previous token stops working
+	Save(ctx context.Context, userID string, tokenHash string, ttl time.Duration) error
+	// Consume deletes tokenHash and returns its user, "" when it is unknown or expired
+	Consume(ctx context.Context, tokenHash string) (string, error)
+}
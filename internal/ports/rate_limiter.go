@@ -0,0 +1,12 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/suryansh74/task-management-api-project/internal/models"
+)
+
+// RateLimiter counts requests per key and decides whether one more is allowed
+type RateLimiter interface {
+	Allow(ctx context.Context, key string, rule models.RateLimitRule) (*models.RateLimitResult, error)
+}
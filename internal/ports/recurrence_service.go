@@ -0,0 +1,22 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/suryansh74/task-management-api-project/internal/models"
+)
+
+// RecurrenceService extends TaskService with RRULE based recurring tasks.
+// Its UpdateTaskByID edits a single occurrence ("this occurrence") and
+// creates the next occurrence when an on_complete occurrence is marked done.
+type RecurrenceService interface {
+	TaskService
+	CreateRecurringTask(ctx context.Context, task *models.Task, recurrence *models.Recurrence) (string, error)
+	// UpdateFutureOccurrences edits this occurrence, every later open one and the series template
+	UpdateFutureOccurrences(ctx context.Context, taskID string, userID string, task *models.Task, recurrence *models.Recurrence) error
+	GenerateScheduledOccurrences(ctx context.Context, now time.Time) (int, error)
+	// OccurrenceCompleted spawns the next occurrence after a task was marked
+	// done outside of UpdateTaskByID. current is the task before the change.
+	OccurrenceCompleted(ctx context.Context, current *models.Task)
+}
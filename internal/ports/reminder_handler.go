@@ -0,0 +1,11 @@
+package ports
+
+import "github.com/gofiber/fiber/v2"
+
+// ReminderHandler defines the HTTP adapter contract for task reminders.
+type ReminderHandler interface {
+	CreateReminder(c *fiber.Ctx) error
+	GetReminders(c *fiber.Ctx) error
+	DeleteReminderByID(c *fiber.Ctx) error
+	GetUpcomingReminders(c *fiber.Ctx) error
+}
@@ -0,0 +1,21 @@
+package ports
+
+import (
+	"context"
+	"time"
+)
+
+// ReminderQueue is the shared schedule of reminders waiting to fire.
+// Scheduling is idempotent and each entry is claimed by exactly one caller,
+// so every replica can run the scheduler.
+type ReminderQueue interface {
+	Schedule(ctx context.Context, reminderID string, fireAt time.Time) error
+	// ClaimDue atomically removes and returns up to limit entries due at now
+	ClaimDue(ctx context.Context, now time.Time, limit int) ([]ReminderJob, error)
+}
+
+// ReminderJob is one claimed queue entry
+type ReminderJob struct {
+	ReminderID string
+	FireAt     time.Time
+}
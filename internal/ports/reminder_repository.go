@@ -0,0 +1,24 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/suryansh74/task-management-api-project/internal/models"
+)
+
+type ReminderRepository interface {
+	CreateReminder(ctx context.Context, reminder *models.Reminder) (string, error)
+	GetReminderByID(ctx context.Context, id string) (*models.Reminder, error)
+	GetRemindersByTaskID(ctx context.Context, taskID string) ([]*models.Reminder, error)
+	DeleteReminderByID(ctx context.Context, id string) error
+	// GetDueReminder resolves a reminder against its task, nil when the task
+	// has no due date or is already done
+	GetDueReminder(ctx context.Context, id string) (*models.DueReminder, error)
+	// GetPendingReminders unfired reminders whose fire time is in [from, to]
+	GetPendingReminders(ctx context.Context, from, to time.Time, limit int) ([]*models.DueReminder, error)
+	GetUpcomingReminders(ctx context.Context, userID string, now time.Time, limit int) ([]*models.DueReminder, error)
+	// MarkReminderFired records delivery for fireAt, false when it was already recorded
+	MarkReminderFired(ctx context.Context, id string, fireAt time.Time) (bool, error)
+	ClearReminderFired(ctx context.Context, id string) error
+}
@@ -0,0 +1,18 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/suryansh74/task-management-api-project/internal/models"
+)
+
+// ReminderService defines business logic operations for task reminders
+type ReminderService interface {
+	CreateReminder(ctx context.Context, taskID string, userID string, reminder *models.Reminder) (*models.Reminder, error)
+	GetReminders(ctx context.Context, taskID string, userID string) ([]*models.Reminder, error)
+	DeleteReminderByID(ctx context.Context, taskID string, reminderID string, userID string) error
+	GetUpcomingReminders(ctx context.Context, userID string, limit int) ([]*models.DueReminder, error)
+	// ProcessDueReminders queues reminders firing soon and delivers the due ones
+	ProcessDueReminders(ctx context.Context, now time.Time) (int, error)
+}
@@ -11,4 +11,9 @@ type SessionRepository interface {
 	Create(ctx context.Context, session *models.Session, sessionExpiration time.Duration) error
 	GetByID(ctx context.Context, id string) (*models.Session, error)
 	Delete(ctx context.Context, sessionID string) error
+	// DeleteByUserID deletes every session of the user, returns how many existed
+	DeleteByUserID(ctx context.Context, userID string) (int64, error)
+	// MarkEmailVerified flags every live session of the user as verified, returns how many
+	MarkEmailVerified(ctx context.Context, userID string) (int64, error)
+	CountActive(ctx context.Context) (int64, error)
 }
@@ -6,6 +6,11 @@ import (
 
 // SessionService defines business logic operations for users
 type SessionService interface {
-	CreateSession(ctx context.Context, userID string) (string, error)
+	CreateSession(ctx context.Context, userID string, emailVerified bool) (string, error)
 	Logout(ctx context.Context, sessionID string) error
+	// RevokeUserSessions logs the user out everywhere
+	RevokeUserSessions(ctx context.Context, userID string) error
+	// MarkEmailVerified lifts the unverified email restrictions from the user's open sessions
+	MarkEmailVerified(ctx context.Context, userID string) error
+	CountActiveSessions(ctx context.Context) (int64, error)
 }
@@ -0,0 +1,8 @@
+package ports
+
+import "github.com/gofiber/fiber/v2"
+
+// TaskBatchHandler defines the HTTP adapter contract for bulk task operations.
+type TaskBatchHandler interface {
+	BatchTasks(c *fiber.Ctx) error
+}
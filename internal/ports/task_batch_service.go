@@ -0,0 +1,12 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/suryansh74/task-management-api-project/internal/models"
+)
+
+// TaskBatchService applies many task operations in one request
+type TaskBatchService interface {
+	BatchTasks(ctx context.Context, userID string, ops []*models.TaskBatchOp, atomic bool) (*models.TaskBatchResult, error)
+}
@@ -20,8 +20,55 @@ type TaskCacheRepository interface {
 		key string,
 	) (*models.Task, error)
 
+	// GetTaskEntry like GetTaskByID but also reports a cached miss and the
+	// remaining TTL, nil on a cache miss
+	GetTaskEntry(
+		ctx context.Context,
+		key string,
+	) (*models.TaskCacheEntry, error)
+
+	// SetNotFound remembers that the task does not exist
+	SetNotFound(
+		ctx context.Context,
+		key string,
+		expiration time.Duration,
+	) error
+
 	DeleteTaskByID(
 		ctx context.Context,
 		key string,
 	) error
+
+	// DeleteTasks removes many keys in a single round trip
+	DeleteTasks(
+		ctx context.Context,
+		keys []string,
+	) error
+
+	// GetTaskList cached list of a user's tasks for query under the user's
+	// current list generation, nil on a miss. The generation is passed back
+	// to SetTaskList.
+	GetTaskList(
+		ctx context.Context,
+		userID string,
+		query string,
+	) (page *models.TaskPage, generation int64, err error)
+
+	// SetTaskList caches a list under the generation it was read at, a list
+	// read before an invalidation lands under a stale generation and is never served
+	SetTaskList(
+		ctx context.Context,
+		userID string,
+		query string,
+		generation int64,
+		page *models.TaskPage,
+		expiration time.Duration,
+	) error
+
+	// InvalidateTaskLists bumps the user's list generation, orphaning every
+	// cached list of the user until it expires
+	InvalidateTaskLists(
+		ctx context.Context,
+		userID string,
+	) error
 }
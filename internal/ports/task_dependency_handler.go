@@ -0,0 +1,12 @@
+package ports
+
+import "github.com/gofiber/fiber/v2"
+
+// TaskDependencyHandler defines the HTTP adapter contract for task dependencies.
+type TaskDependencyHandler interface {
+	AddBlocker(c *fiber.Ctx) error
+	RemoveBlocker(c *fiber.Ctx) error
+	GetBlockers(c *fiber.Ctx) error
+	GetDependents(c *fiber.Ctx) error
+	GetTopologicalOrder(c *fiber.Ctx) error
+}
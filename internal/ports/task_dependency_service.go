@@ -0,0 +1,17 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/suryansh74/task-management-api-project/internal/models"
+)
+
+// TaskDependencyService defines business logic operations for "blocked by" edges between tasks
+type TaskDependencyService interface {
+	AddBlocker(ctx context.Context, taskID string, blockerID string, userID string) error
+	RemoveBlocker(ctx context.Context, taskID string, blockerID string, userID string) error
+	GetBlockers(ctx context.Context, taskID string, userID string) ([]*models.Task, error)
+	GetDependents(ctx context.Context, taskID string, userID string) ([]*models.Task, error)
+	// GetTopologicalOrder returns the user's tasks with every blocker before the tasks it blocks
+	GetTopologicalOrder(ctx context.Context, userID string) ([]*models.Task, error)
+}
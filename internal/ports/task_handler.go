@@ -10,4 +10,5 @@ type TaskHandler interface {
 	GetTaskByID(c *fiber.Ctx) error
 	UpdateTaskByID(c *fiber.Ctx) error
 	DeleteTaskByID(c *fiber.Ctx) error
+	SearchTasks(c *fiber.Ctx) error
 }
@@ -0,0 +1,16 @@
+package ports
+
+import (
+	"context"
+
+	"github.com/suryansh74/task-management-api-project/internal/models"
+)
+
+// TaskQuotaService checks the per-user storage quotas before expensive writes,
+// the repositories enforce them again under a lock when writing
+type TaskQuotaService interface {
+	// CheckQuota fails when adding tasks and bytes would exceed a quota,
+	// removals (zero or negative amounts) always pass
+	CheckQuota(ctx context.Context, userID string, addTasks int, addBytes int64) error
+	GetUsage(ctx context.Context, userID string) (*models.TaskUsage, error)
+}
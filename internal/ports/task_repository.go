@@ -8,8 +8,37 @@ import (
 
 type TaskRepository interface {
 	GetAllTasks(ctx context.Context, userID string) ([]*models.Task, error)
+	// GetTaskUsage number of tasks and bytes of title plus content of the user
+	GetTaskUsage(ctx context.Context, userID string) (*models.TaskUsage, error)
+	// GetTasksWithDueDate tasks of the user that have a due date, soonest first
+	GetTasksWithDueDate(ctx context.Context, userID string) ([]*models.Task, error)
 	GetTaskByID(ctx context.Context, id string) (*models.Task, error)
+	// CreateTask, UpdateTaskByID, ApplyTaskBatch and ImportTasks enforce the
+	// quotas under a per-user lock and fail with QUOTA_EXCEEDED; a batch or
+	// import over a quota fails as a whole
 	CreateTask(ctx context.Context, task *models.Task) (string, error)
 	UpdateTaskByID(ctx context.Context, id string, task *models.Task) error
 	DeleteTaskByID(ctx context.Context, id string) error
+	SearchTasks(ctx context.Context, query *models.TaskSearchQuery) (*models.TaskSearchResult, error)
+	// ApplyTaskBatch runs ops in one transaction and returns one error slot
+	// per op. Atomic batches stop and roll back at the first failing op;
+	// otherwise a failing op only rolls back its own savepoint.
+	ApplyTaskBatch(ctx context.Context, ops []*models.TaskBatchOp, atomic bool) ([]error, error)
+
+	// ListTasksPage pages through a user's tasks ordered by (created_at, id),
+	// starting after the given task (nil for the first page)
+	ListTasksPage(ctx context.Context, userID string, after *models.Task, limit int) ([]*models.Task, error)
+	GetTaskIDsByExternalIDs(ctx context.Context, userID string, externalIDs []string) (map[string]string, error)
+	// ImportTasks inserts tasks (or upserts them on external_id) row by row in
+	// one transaction; a failing row only rolls back itself. inserted reports
+	// per row whether a new task was created.
+	ImportTasks(ctx context.Context, tasks []*models.Task, upsert bool) (inserted []bool, errs []error, err error)
+
+	// AddDependency records that taskID is blocked by blockedByID. It fails
+	// with a conflict when the edge would close a cycle.
+	AddDependency(ctx context.Context, userID string, taskID string, blockedByID string) error
+	RemoveDependency(ctx context.Context, taskID string, blockedByID string) error
+	GetBlockers(ctx context.Context, taskID string) ([]*models.Task, error)
+	GetDependents(ctx context.Context, taskID string) ([]*models.Task, error)
+	GetDependencyEdges(ctx context.Context, userID string) ([]*models.TaskDependency, error)
 }
@@ -0,0 +1,26 @@
+package ports
+
+import (
+	"context"
+	"time"
+
+	"github.com/suryansh74/task-management-api-project/internal/models"
+)
+
+type TaskSeriesRepository interface {
+	CreateSeries(ctx context.Context, series *models.TaskSeries) (string, error)
+	GetSeriesByID(ctx context.Context, id string) (*models.TaskSeries, error)
+	UpdateSeries(ctx context.Context, id string, series *models.TaskSeries) error
+	// CreateOccurrence inserts an occurrence; created is false when one
+	// already exists for the same series and due date (e.g. another replica won).
+	// An owner at a quota gets QUOTA_EXCEEDED instead.
+	CreateOccurrence(ctx context.Context, task *models.Task) (id string, created bool, err error)
+	GetOpenOccurrences(ctx context.Context, seriesID string, after time.Time) ([]*models.Task, error)
+	GetDueScheduledSeries(ctx context.Context, now time.Time) ([]*DueSeries, error)
+}
+
+// DueSeries is a scheduled series whose latest occurrence is already due
+type DueSeries struct {
+	Series      *models.TaskSeries
+	LatestDueAt time.Time
+}
@@ -9,8 +9,10 @@ import (
 // TaskService defines business logic operations for task
 type TaskService interface {
 	GetTasks(ctx context.Context, userID string) ([]*models.Task, error)
+	ListTasks(ctx context.Context, query *models.TaskListQuery) (*models.TaskPage, error)
 	GetTaskByID(ctx context.Context, taskID string, userID string) (*models.Task, error)
 	CreateTask(ctx context.Context, task *models.Task) (string, error)
 	UpdateTaskByID(ctx context.Context, taskID string, userID string, task *models.Task) error
 	DeleteTaskByID(ctx context.Context, taskID string, userID string) error
+	SearchTasks(ctx context.Context, query *models.TaskSearchQuery) (*models.TaskSearchResult, error)
 }
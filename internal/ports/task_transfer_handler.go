@@ -0,0 +1,9 @@
+package ports
+
+import "github.com/gofiber/fiber/v2"
+
+// TaskTransferHandler defines the HTTP adapter contract for task import/export.
+type TaskTransferHandler interface {
+	ExportTasks(c *fiber.Ctx) error
+	ImportTasks(c *fiber.Ctx) error
+}
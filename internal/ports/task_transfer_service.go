@@ -0,0 +1,15 @@
+package ports
+
+import (
+	"context"
+	"io"
+
+	"github.com/suryansh74/task-management-api-project/internal/models"
+)
+
+// TaskTransferService imports and exports tasks as CSV, JSON or NDJSON
+type TaskTransferService interface {
+	// ExportTasks streams every task of the user to w, page by page
+	ExportTasks(ctx context.Context, userID string, format string, w io.Writer) error
+	ImportTasks(ctx context.Context, userID string, r io.Reader, opts models.TaskImportOptions) (*models.TaskImportResult, error)
+}
@@ -6,5 +6,6 @@ import "github.com/gofiber/fiber/v2"
 type UserHandler interface {
 	Register(c *fiber.Ctx) error
 	Login(c *fiber.Ctx) error
+	LoginMFA(c *fiber.Ctx) error
 	Logout(c *fiber.Ctx) error
 }
@@ -9,4 +9,9 @@ import (
 type UserRepository interface {
 	CreateUser(ctx context.Context, user *models.User) (string, error)
 	FindByEmail(ctx context.Context, email string) (*models.User, error)
+	FindByID(ctx context.Context, id string) (*models.User, error)
+	UpdatePassword(ctx context.Context, userID string, hashedPassword string) error
+	// MarkEmailVerified verifies email for the user, NOT_FOUND when the user's
+	// email has changed since; verifying twice keeps the first time
+	MarkEmailVerified(ctx context.Context, userID string, email string) error
 }
@@ -7,12 +7,15 @@ import (
 // UserService defines business logic operations for users
 type UserService interface {
 	Register(ctx context.Context, name, email, password string) (*UserResponse, error)
-	Login(ctx context.Context, email string, password string) (*UserResponse, error)
+	// Login checks the password, clientIP feeds brute-force protection
+	Login(ctx context.Context, email string, password string, clientIP string) (*UserResponse, error)
 }
 
 // UserResponse is the service layer response for user data
 type UserResponse struct {
-	ID    string `json:"id"`
-	Name  string `json:"name"`
-	Email string `json:"email"`
+	ID            string `json:"id"`
+	Name          string `json:"name"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"email_verified"`
+	MFAEnabled    bool   `json:"mfa_enabled"`
 }
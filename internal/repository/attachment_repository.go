@@ -0,0 +1,225 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/logger"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+)
+
+type attachmentRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewAttachmentRepository(db *pgxpool.Pool) ports.AttachmentRepository {
+	logger.Log.Info().Msg("initializing attachment repository")
+	return &attachmentRepository{db: db}
+}
+
+// CreateAttachment stores attachment metadata
+// =========================================================================
+func (ar *attachmentRepository) CreateAttachment(ctx context.Context, attachment *models.Attachment) (string, error) {
+	zerolog.Ctx(ctx).Debug().
+		Str("task_id", attachment.TaskID).
+		Str("filename", attachment.Filename).
+		Msg("creating attachment metadata")
+
+	var id string
+	err := ar.db.QueryRow(ctx,
+		`INSERT INTO attachments (task_id, user_id, filename, content_type, size_bytes, storage_key)
+		 VALUES ($1, $2, $3, $4, $5, $6) RETURNING id`,
+		attachment.TaskID,
+		attachment.UserID,
+		attachment.Filename,
+		attachment.ContentType,
+		attachment.SizeBytes,
+		attachment.StorageKey,
+	).Scan(&id)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("task_id", attachment.TaskID).
+			Str("storage_key", attachment.StorageKey).
+			Msg("failed to create attachment metadata")
+		return "", apperror.NewInternalError("Failed to save attachment", err)
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("attachment_id", id).
+		Str("task_id", attachment.TaskID).
+		Int64("size_bytes", attachment.SizeBytes).
+		Msg("attachment metadata created successfully")
+	return id, nil
+}
+
+// GetAttachmentByID get attachment metadata by id
+// =========================================================================
+func (ar *attachmentRepository) GetAttachmentByID(ctx context.Context, id string) (*models.Attachment, error) {
+	zerolog.Ctx(ctx).Debug().
+		Str("attachment_id", id).
+		Msg("fetching attachment by id")
+
+	attachment := new(models.Attachment)
+	err := ar.db.QueryRow(ctx,
+		`SELECT id, task_id, user_id, filename, content_type, size_bytes, storage_key, created_at
+		 FROM attachments WHERE id = $1`,
+		id,
+	).Scan(
+		&attachment.ID,
+		&attachment.TaskID,
+		&attachment.UserID,
+		&attachment.Filename,
+		&attachment.ContentType,
+		&attachment.SizeBytes,
+		&attachment.StorageKey,
+		&attachment.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			zerolog.Ctx(ctx).Warn().
+				Str("attachment_id", id).
+				Msg("attachment not found")
+			return nil, apperror.NewNotFoundError("attachment not found")
+		}
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("attachment_id", id).
+			Msg("failed to fetch attachment")
+		return nil, err
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("attachment_id", attachment.ID).
+		Str("task_id", attachment.TaskID).
+		Msg("attachment fetched successfully")
+	return attachment, nil
+}
+
+// GetAttachmentsByTaskID get all attachments of a task
+// =========================================================================
+func (ar *attachmentRepository) GetAttachmentsByTaskID(ctx context.Context, taskID string) ([]*models.Attachment, error) {
+	zerolog.Ctx(ctx).Debug().
+		Str("task_id", taskID).
+		Msg("fetching attachments for task")
+
+	rows, err := ar.db.Query(ctx,
+		`SELECT id, task_id, user_id, filename, content_type, size_bytes, storage_key, created_at
+		 FROM attachments WHERE task_id = $1 ORDER BY created_at`,
+		taskID,
+	)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("task_id", taskID).
+			Msg("failed to query attachments")
+		return nil, err
+	}
+	defer rows.Close()
+
+	attachments := []*models.Attachment{}
+	for rows.Next() {
+		var attachment models.Attachment
+		err := rows.Scan(
+			&attachment.ID,
+			&attachment.TaskID,
+			&attachment.UserID,
+			&attachment.Filename,
+			&attachment.ContentType,
+			&attachment.SizeBytes,
+			&attachment.StorageKey,
+			&attachment.CreatedAt,
+		)
+		if err != nil {
+			zerolog.Ctx(ctx).Error().
+				Err(err).
+				Str("task_id", taskID).
+				Msg("failed to scan attachment row")
+			return nil, err
+		}
+		attachments = append(attachments, &attachment)
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("task_id", taskID).
+		Int("attachment_count", len(attachments)).
+		Msg("successfully fetched attachments for task")
+	return attachments, nil
+}
+
+// DeleteAttachmentByID delete attachment metadata by id
+// =========================================================================
+func (ar *attachmentRepository) DeleteAttachmentByID(ctx context.Context, id string) error {
+	zerolog.Ctx(ctx).Debug().
+		Str("attachment_id", id).
+		Msg("deleting attachment metadata")
+
+	cmd, err := ar.db.Exec(ctx, `DELETE FROM attachments WHERE id = $1`, id)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("attachment_id", id).
+			Msg("failed to delete attachment")
+		return err
+	}
+
+	if cmd.RowsAffected() == 0 {
+		zerolog.Ctx(ctx).Warn().
+			Str("attachment_id", id).
+			Msg("attachment not found for deletion")
+		return apperror.NewNotFoundError("attachment not found")
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("attachment_id", id).
+		Msg("attachment deleted successfully")
+	return nil
+}
+
+// GetDeletedBlobKeys oldest storage keys queued by the attachments delete trigger
+// =========================================================================
+func (ar *attachmentRepository) GetDeletedBlobKeys(ctx context.Context, limit int) ([]string, error) {
+	rows, err := ar.db.Query(ctx,
+		`SELECT storage_key FROM attachment_blob_deletions ORDER BY deleted_at LIMIT $1`,
+		limit,
+	)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Msg("failed to query deleted attachment blobs")
+		return nil, err
+	}
+	defer rows.Close()
+
+	keys := []string{}
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+// ForgetDeletedBlobKeys dequeue keys whose blobs were deleted
+// =========================================================================
+func (ar *attachmentRepository) ForgetDeletedBlobKeys(ctx context.Context, keys []string) error {
+	_, err := ar.db.Exec(ctx,
+		`DELETE FROM attachment_blob_deletions WHERE storage_key = ANY($1)`,
+		keys,
+	)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Int("key_count", len(keys)).
+			Msg("failed to forget deleted attachment blobs")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+	"github.com/suryansh74/task-management-api-project/internal/logger"
+	"github.com/suryansh74/task-management-api-project/internal/metrics"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+)
+
+type logAuditLog struct{}
+
+// NewLogAuditLog writes audit events to the application log, marked with
+// "audit": true so log pipelines can route them, and counts them
+// =========================================================================
+func NewLogAuditLog() ports.AuditLog {
+	logger.Log.Info().Msg("initializing audit log")
+	return &logAuditLog{}
+}
+
+// Record log the event
+// =========================================================================
+func (a *logAuditLog) Record(ctx context.Context, event *models.AuditEvent) {
+	metrics.AuthEvents.WithLabelValues(event.Type).Inc()
+
+	level := zerolog.InfoLevel
+	switch event.Type {
+	case models.AuditLoginFailed, models.AuditLoginThrottled:
+		level = zerolog.WarnLevel
+	case models.AuditAccountLocked, models.AuditCredentialStuffing, models.AuditDistributedAttack:
+		level = zerolog.ErrorLevel
+	}
+
+	entry := zerolog.Ctx(ctx).WithLevel(level).
+		Bool("audit", true).
+		Str("event", event.Type).
+		Str("email", event.Email).
+		Str("ip", event.IP)
+	if event.UserID != "" {
+		entry = entry.Str("user_id", event.UserID)
+	}
+	if event.Reason != "" {
+		entry = entry.Str("reason", event.Reason)
+	}
+	if event.Failures > 0 {
+		entry = entry.Int64("failures", event.Failures)
+	}
+	if event.Distinct > 0 {
+		entry = entry.Int64("distinct", event.Distinct)
+	}
+	if event.RetryAfter > 0 {
+		entry = entry.Dur("retry_after", event.RetryAfter)
+	}
+	entry.Msg("audit event")
+}
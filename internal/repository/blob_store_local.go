@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/logger"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+	"github.com/suryansh74/task-management-api-project/internal/utils"
+)
+
+type localBlobStore struct {
+	baseDir    string
+	baseURL    string
+	signingKey string
+}
+
+// NewLocalBlobStore stores blobs under baseDir and signs download links
+// pointing at the API's own /files route.
+// =========================================================================
+func NewLocalBlobStore(baseDir, baseURL, signingKey string) (ports.BlobStore, error) {
+	logger.Log.Info().
+		Str("base_dir", baseDir).
+		Msg("initializing local blob store")
+
+	if err := os.MkdirAll(baseDir, 0o755); err != nil {
+		return nil, fmt.Errorf("create blob dir: %w", err)
+	}
+	return &localBlobStore{
+		baseDir:    baseDir,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		signingKey: signingKey,
+	}, nil
+}
+
+// Put writes blob to disk
+// =========================================================================
+func (s *localBlobStore) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	path, err := s.pathFor(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("storage_key", key).
+			Msg("failed to create blob directory")
+		return apperror.NewInternalError("unable to store file", err)
+	}
+
+	// write to a temp file first so readers never observe a partial blob
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return apperror.NewInternalError("unable to store file", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("storage_key", key).
+			Msg("failed to write blob")
+		return apperror.NewInternalError("unable to store file", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return apperror.NewInternalError("unable to store file", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return apperror.NewInternalError("unable to store file", err)
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("storage_key", key).
+		Int64("size_bytes", size).
+		Msg("blob stored on local disk")
+	return nil
+}
+
+// Get opens blob from disk
+// =========================================================================
+func (s *localBlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.pathFor(key)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, apperror.NewNotFoundError("file not found")
+		}
+		return nil, apperror.NewInternalError("unable to read file", err)
+	}
+	return f, nil
+}
+
+// Delete removes blob from disk, missing blobs are not an error
+// =========================================================================
+func (s *localBlobStore) Delete(ctx context.Context, key string) error {
+	path, err := s.pathFor(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("storage_key", key).
+			Msg("failed to delete blob")
+		return apperror.NewInternalError("unable to delete file", err)
+	}
+	return nil
+}
+
+// SignedURL builds an HMAC-signed link to GET /files/<key>
+// =========================================================================
+func (s *localBlobStore) SignedURL(ctx context.Context, key string, filename string, expiry time.Duration) (string, error) {
+	expires := strconv.FormatInt(time.Now().Add(expiry).Unix(), 10)
+	signature := utils.SignPayload(s.signingKey, key, filename, expires)
+
+	query := url.Values{}
+	query.Set("filename", filename)
+	query.Set("expires", expires)
+	query.Set("signature", signature)
+
+	return fmt.Sprintf("%s/files/%s?%s", s.baseURL, key, query.Encode()), nil
+}
+
+// pathFor maps a key to a path, refusing anything that escapes baseDir
+func (s *localBlobStore) pathFor(key string) (string, error) {
+	path := filepath.Join(s.baseDir, filepath.FromSlash(key))
+	rel, err := filepath.Rel(s.baseDir, path)
+	if err != nil || rel == "." || strings.HasPrefix(rel, "..") {
+		return "", apperror.NewBadRequestError("invalid file key")
+	}
+	return path, nil
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/url"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/rs/zerolog"
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+)
+
+type s3BlobStore struct {
+	client *minio.Client
+	bucket string
+}
+
+// NewS3BlobStore stores blobs in any S3-compatible bucket (AWS S3, MinIO, ...)
+// and creates the bucket on startup if it does not exist yet.
+// =========================================================================
+func NewS3BlobStore(ctx context.Context, endpoint, region, bucket, accessKey, secretKey string, useSSL bool) (ports.BlobStore, error) {
+	zerolog.Ctx(ctx).Info().
+		Str("endpoint", endpoint).
+		Str("bucket", bucket).
+		Bool("use_ssl", useSSL).
+		Msg("initializing s3 blob store")
+
+	client, err := minio.New(endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(accessKey, secretKey, ""),
+		Secure: useSSL,
+		Region: region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("s3 client: %w", err)
+	}
+
+	exists, err := client.BucketExists(ctx, bucket)
+	if err != nil {
+		return nil, fmt.Errorf("s3 bucket check: %w", err)
+	}
+	if !exists {
+		if err := client.MakeBucket(ctx, bucket, minio.MakeBucketOptions{Region: region}); err != nil {
+			return nil, fmt.Errorf("s3 make bucket: %w", err)
+		}
+		zerolog.Ctx(ctx).Info().
+			Str("bucket", bucket).
+			Msg("created s3 bucket")
+	}
+
+	return &s3BlobStore{client: client, bucket: bucket}, nil
+}
+
+// Put uploads blob to the bucket
+// =========================================================================
+func (s *s3BlobStore) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	_, err := s.client.PutObject(ctx, s.bucket, key, r, size, minio.PutObjectOptions{
+		ContentType: contentType,
+	})
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("bucket", s.bucket).
+			Str("storage_key", key).
+			Msg("failed to upload blob to s3")
+		return apperror.NewInternalError("unable to store file", err)
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("bucket", s.bucket).
+		Str("storage_key", key).
+		Int64("size_bytes", size).
+		Msg("blob stored in s3")
+	return nil
+}
+
+// Get streams blob from the bucket
+// =========================================================================
+func (s *s3BlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	obj, err := s.client.GetObject(ctx, s.bucket, key, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, apperror.NewInternalError("unable to read file", err)
+	}
+
+	// GetObject is lazy, Stat surfaces a missing key before we start streaming
+	if _, err := obj.Stat(); err != nil {
+		obj.Close()
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, apperror.NewNotFoundError("file not found")
+		}
+		return nil, apperror.NewInternalError("unable to read file", err)
+	}
+	return obj, nil
+}
+
+// Delete removes blob from the bucket
+// =========================================================================
+func (s *s3BlobStore) Delete(ctx context.Context, key string) error {
+	if err := s.client.RemoveObject(ctx, s.bucket, key, minio.RemoveObjectOptions{}); err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("bucket", s.bucket).
+			Str("storage_key", key).
+			Msg("failed to delete blob from s3")
+		return apperror.NewInternalError("unable to delete file", err)
+	}
+	return nil
+}
+
+// SignedURL returns a presigned GET url served directly by the bucket
+// =========================================================================
+func (s *s3BlobStore) SignedURL(ctx context.Context, key string, filename string, expiry time.Duration) (string, error) {
+	params := url.Values{}
+	params.Set("response-content-disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+
+	u, err := s.client.PresignedGetObject(ctx, s.bucket, key, expiry, params)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("bucket", s.bucket).
+			Str("storage_key", key).
+			Msg("failed to presign s3 url")
+		return "", apperror.NewInternalError("unable to create download link", err)
+	}
+	return u.String(), nil
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/logger"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+)
+
+type calendarFeedRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewCalendarFeedRepository(db *pgxpool.Pool) ports.CalendarFeedRepository {
+	logger.Log.Info().Msg("initializing calendar feed repository")
+	return &calendarFeedRepository{db: db}
+}
+
+// SaveFeedToken create the feed or replace its token
+// =========================================================================
+func (cr *calendarFeedRepository) SaveFeedToken(ctx context.Context, userID string, tokenHash string) (*models.CalendarFeed, error) {
+	zerolog.Ctx(ctx).Debug().
+		Str("user_id", userID).
+		Msg("saving calendar feed token")
+
+	feed := &models.CalendarFeed{UserID: userID, Enabled: true}
+	err := cr.db.QueryRow(ctx,
+		`INSERT INTO calendar_feeds (user_id, token_hash)
+		 VALUES ($1, $2)
+		 ON CONFLICT (user_id) DO UPDATE
+		 SET token_hash = EXCLUDED.token_hash,
+		     created_at = NOW()
+		 RETURNING created_at`,
+		userID,
+		tokenHash,
+	).Scan(&feed.CreatedAt)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("user_id", userID).
+			Msg("failed to save calendar feed token")
+		return nil, apperror.NewInternalError("Failed to save calendar feed", err)
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("user_id", userID).
+		Msg("calendar feed token saved successfully")
+	return feed, nil
+}
+
+// GetFeed get feed of the user, disabled when never created
+// =========================================================================
+func (cr *calendarFeedRepository) GetFeed(ctx context.Context, userID string) (*models.CalendarFeed, error) {
+	feed := &models.CalendarFeed{UserID: userID}
+	err := cr.db.QueryRow(ctx,
+		`SELECT created_at FROM calendar_feeds WHERE user_id = $1`,
+		userID,
+	).Scan(&feed.CreatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return feed, nil
+	}
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("user_id", userID).
+			Msg("failed to fetch calendar feed")
+		return nil, apperror.NewInternalError("Failed to fetch calendar feed", err)
+	}
+	feed.Enabled = true
+	return feed, nil
+}
+
+// GetUserIDByTokenHash resolve feed token to its owner
+// =========================================================================
+func (cr *calendarFeedRepository) GetUserIDByTokenHash(ctx context.Context, tokenHash string) (string, error) {
+	var userID string
+	err := cr.db.QueryRow(ctx,
+		`SELECT user_id FROM calendar_feeds WHERE token_hash = $1`,
+		tokenHash,
+	).Scan(&userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", apperror.NewNotFoundError("calendar feed not found")
+	}
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Msg("failed to resolve calendar feed token")
+		return "", apperror.NewInternalError("Failed to fetch calendar feed", err)
+	}
+	return userID, nil
+}
+
+// DeleteFeed disable the feed of the user
+// =========================================================================
+func (cr *calendarFeedRepository) DeleteFeed(ctx context.Context, userID string) error {
+	cmd, err := cr.db.Exec(ctx, `DELETE FROM calendar_feeds WHERE user_id = $1`, userID)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("user_id", userID).
+			Msg("failed to delete calendar feed")
+		return apperror.NewInternalError("Failed to delete calendar feed", err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return apperror.NewNotFoundError("calendar feed not found")
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("user_id", userID).
+		Msg("calendar feed deleted successfully")
+	return nil
+}
@@ -0,0 +1,368 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/logger"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+)
+
+type checklistRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewChecklistRepository(db *pgxpool.Pool) ports.ChecklistRepository {
+	logger.Log.Info().Msg("initializing checklist repository")
+	return &checklistRepository{db: db}
+}
+
+// CreateChecklistItem append item to the task's checklist
+// =========================================================================
+func (cr *checklistRepository) CreateChecklistItem(ctx context.Context, item *models.ChecklistItem) (string, error) {
+	zerolog.Ctx(ctx).Debug().
+		Str("task_id", item.TaskID).
+		Msg("creating checklist item")
+
+	tx, err := cr.db.Begin(ctx)
+	if err != nil {
+		return "", apperror.NewInternalError("Failed to add checklist item", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockChecklist(ctx, tx, item.TaskID); err != nil {
+		return "", err
+	}
+
+	err = tx.QueryRow(ctx,
+		`INSERT INTO checklist_items (task_id, text, checked, position)
+		 VALUES ($1, $2, $3, (SELECT COUNT(*) FROM checklist_items WHERE task_id = $1))
+		 RETURNING id, position, created_at, updated_at`,
+		item.TaskID,
+		item.Text,
+		item.Checked,
+	).Scan(&item.ID, &item.Position, &item.CreatedAt, &item.UpdatedAt)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("task_id", item.TaskID).
+			Msg("failed to create checklist item")
+		return "", apperror.NewInternalError("Failed to add checklist item", err)
+	}
+
+	if err := syncChecklistCounts(ctx, tx, item.TaskID); err != nil {
+		return "", err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return "", apperror.NewInternalError("Failed to add checklist item", err)
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("item_id", item.ID).
+		Str("task_id", item.TaskID).
+		Int("position", item.Position).
+		Msg("checklist item created successfully")
+	return item.ID, nil
+}
+
+// GetChecklistItemByID get checklist item by id
+// =========================================================================
+func (cr *checklistRepository) GetChecklistItemByID(ctx context.Context, id string) (*models.ChecklistItem, error) {
+	item := new(models.ChecklistItem)
+	err := cr.db.QueryRow(ctx,
+		`SELECT id, task_id, text, checked, position, created_at, updated_at
+		 FROM checklist_items WHERE id = $1`,
+		id,
+	).Scan(&item.ID, &item.TaskID, &item.Text, &item.Checked, &item.Position, &item.CreatedAt, &item.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.NewNotFoundError("checklist item not found")
+		}
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("item_id", id).
+			Msg("failed to fetch checklist item")
+		return nil, err
+	}
+	return item, nil
+}
+
+// GetChecklistItemsByTaskID checklist of a task in position order
+// =========================================================================
+func (cr *checklistRepository) GetChecklistItemsByTaskID(ctx context.Context, taskID string) ([]*models.ChecklistItem, error) {
+	rows, err := cr.db.Query(ctx,
+		`SELECT id, task_id, text, checked, position, created_at, updated_at
+		 FROM checklist_items WHERE task_id = $1
+		 ORDER BY position`,
+		taskID,
+	)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("task_id", taskID).
+			Msg("failed to query checklist items")
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []*models.ChecklistItem{}
+	for rows.Next() {
+		var item models.ChecklistItem
+		if err := rows.Scan(&item.ID, &item.TaskID, &item.Text, &item.Checked, &item.Position, &item.CreatedAt, &item.UpdatedAt); err != nil {
+			return nil, err
+		}
+		items = append(items, &item)
+	}
+	return items, rows.Err()
+}
+
+// UpdateChecklistItem change text and/or checked state in place, nil keeps
+// the stored value
+// =========================================================================
+func (cr *checklistRepository) UpdateChecklistItem(ctx context.Context, taskID string, id string, text *string, checked *bool) (*models.ChecklistItem, error) {
+	tx, err := cr.db.Begin(ctx)
+	if err != nil {
+		return nil, apperror.NewInternalError("Failed to update checklist item", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockChecklist(ctx, tx, taskID); err != nil {
+		return nil, err
+	}
+
+	item := new(models.ChecklistItem)
+	err = tx.QueryRow(ctx,
+		`UPDATE checklist_items
+		 SET text = COALESCE($1, text), checked = COALESCE($2, checked), updated_at = CURRENT_TIMESTAMP
+		 WHERE id = $3 AND task_id = $4
+		 RETURNING id, task_id, text, checked, position, created_at, updated_at`,
+		text,
+		checked,
+		id,
+		taskID,
+	).Scan(&item.ID, &item.TaskID, &item.Text, &item.Checked, &item.Position, &item.CreatedAt, &item.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.NewNotFoundError("checklist item not found")
+		}
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("item_id", id).
+			Msg("failed to update checklist item")
+		return nil, apperror.NewInternalError("Failed to update checklist item", err)
+	}
+
+	if err := syncChecklistCounts(ctx, tx, taskID); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, apperror.NewInternalError("Failed to update checklist item", err)
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("item_id", id).
+		Str("task_id", taskID).
+		Bool("checked", item.Checked).
+		Msg("checklist item updated successfully")
+	return item, nil
+}
+
+// ToggleChecklistItem flip checked state in place
+// =========================================================================
+func (cr *checklistRepository) ToggleChecklistItem(ctx context.Context, taskID string, id string) (*models.ChecklistItem, error) {
+	tx, err := cr.db.Begin(ctx)
+	if err != nil {
+		return nil, apperror.NewInternalError("Failed to toggle checklist item", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockChecklist(ctx, tx, taskID); err != nil {
+		return nil, err
+	}
+
+	item := new(models.ChecklistItem)
+	err = tx.QueryRow(ctx,
+		`UPDATE checklist_items
+		 SET checked = NOT checked, updated_at = CURRENT_TIMESTAMP
+		 WHERE id = $1 AND task_id = $2
+		 RETURNING id, task_id, text, checked, position, created_at, updated_at`,
+		id,
+		taskID,
+	).Scan(&item.ID, &item.TaskID, &item.Text, &item.Checked, &item.Position, &item.CreatedAt, &item.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, apperror.NewNotFoundError("checklist item not found")
+		}
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("item_id", id).
+			Msg("failed to toggle checklist item")
+		return nil, apperror.NewInternalError("Failed to toggle checklist item", err)
+	}
+
+	if err := syncChecklistCounts(ctx, tx, taskID); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return nil, apperror.NewInternalError("Failed to toggle checklist item", err)
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("item_id", id).
+		Str("task_id", taskID).
+		Bool("checked", item.Checked).
+		Msg("checklist item toggled successfully")
+	return item, nil
+}
+
+// ReorderChecklistItems rewrite every position of the task in one transaction
+// =========================================================================
+func (cr *checklistRepository) ReorderChecklistItems(ctx context.Context, taskID string, itemIDs []string) error {
+	zerolog.Ctx(ctx).Debug().
+		Str("task_id", taskID).
+		Int("item_count", len(itemIDs)).
+		Msg("reordering checklist items")
+
+	tx, err := cr.db.Begin(ctx)
+	if err != nil {
+		return apperror.NewInternalError("Failed to reorder checklist", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockChecklist(ctx, tx, taskID); err != nil {
+		return err
+	}
+
+	var total int
+	if err := tx.QueryRow(ctx, `SELECT COUNT(*) FROM checklist_items WHERE task_id = $1`, taskID).Scan(&total); err != nil {
+		return apperror.NewInternalError("Failed to reorder checklist", err)
+	}
+
+	// the (task_id, position) constraint is deferred, so intermediate
+	// duplicates are fine as long as the final order is a permutation
+	cmd, err := tx.Exec(ctx,
+		`UPDATE checklist_items ci
+		 SET position = o.ord - 1, updated_at = CURRENT_TIMESTAMP
+		 FROM unnest($2::uuid[]) WITH ORDINALITY AS o(id, ord)
+		 WHERE ci.id = o.id AND ci.task_id = $1`,
+		taskID,
+		itemIDs,
+	)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("task_id", taskID).
+			Msg("failed to reorder checklist items")
+		return apperror.NewInternalError("Failed to reorder checklist", err)
+	}
+	if int(cmd.RowsAffected()) != total || len(itemIDs) != total {
+		zerolog.Ctx(ctx).Warn().
+			Str("task_id", taskID).
+			Int("item_count", len(itemIDs)).
+			Int("matched", int(cmd.RowsAffected())).
+			Int("total", total).
+			Msg("checklist reorder rejected: order does not match items")
+		return apperror.NewBadRequestError("order must list every checklist item of the task exactly once")
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return apperror.NewInternalError("Failed to reorder checklist", err)
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("task_id", taskID).
+		Int("item_count", total).
+		Msg("checklist reordered successfully")
+	return nil
+}
+
+// DeleteChecklistItemByID delete item and close the gap it leaves
+// =========================================================================
+func (cr *checklistRepository) DeleteChecklistItemByID(ctx context.Context, taskID string, id string) error {
+	tx, err := cr.db.Begin(ctx)
+	if err != nil {
+		return apperror.NewInternalError("Failed to delete checklist item", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockChecklist(ctx, tx, taskID); err != nil {
+		return err
+	}
+
+	var position int
+	err = tx.QueryRow(ctx,
+		`DELETE FROM checklist_items WHERE id = $1 AND task_id = $2 RETURNING position`,
+		id,
+		taskID,
+	).Scan(&position)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apperror.NewNotFoundError("checklist item not found")
+		}
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("item_id", id).
+			Msg("failed to delete checklist item")
+		return apperror.NewInternalError("Failed to delete checklist item", err)
+	}
+
+	_, err = tx.Exec(ctx,
+		`UPDATE checklist_items SET position = position - 1
+		 WHERE task_id = $1 AND position > $2`,
+		taskID,
+		position,
+	)
+	if err != nil {
+		return apperror.NewInternalError("Failed to delete checklist item", err)
+	}
+
+	if err := syncChecklistCounts(ctx, tx, taskID); err != nil {
+		return err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return apperror.NewInternalError("Failed to delete checklist item", err)
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("item_id", id).
+		Str("task_id", taskID).
+		Msg("checklist item deleted successfully")
+	return nil
+}
+
+// lockChecklist serializes checklist writes of one task on its task row
+func lockChecklist(ctx context.Context, tx pgx.Tx, taskID string) error {
+	var id string
+	err := tx.QueryRow(ctx, `SELECT id FROM tasks WHERE id = $1 FOR UPDATE`, taskID).Scan(&id)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return apperror.NewNotFoundError("task not found")
+		}
+		return apperror.NewInternalError("Failed to lock checklist", err)
+	}
+	return nil
+}
+
+// syncChecklistCounts recompute the task's checklist counts from its items
+func syncChecklistCounts(ctx context.Context, tx pgx.Tx, taskID string) error {
+	_, err := tx.Exec(ctx,
+		`UPDATE tasks SET
+		     checklist_total = (SELECT COUNT(*) FROM checklist_items WHERE task_id = $1),
+		     checklist_done = (SELECT COUNT(*) FROM checklist_items WHERE task_id = $1 AND checked)
+		 WHERE id = $1`,
+		taskID,
+	)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("task_id", taskID).
+			Msg("failed to sync checklist counts")
+		return apperror.NewInternalError("Failed to update checklist counts", err)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/redis/go-redis/v9"
+	"github.com/suryansh74/task-management-api-project/internal/logger"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+)
+
+type healthRepository struct {
+	db          *pgxpool.Pool
+	redisClient *redis.Client
+}
+
+// NewHealthRepository constructor for the dependency probes
+// =========================================================================
+func NewHealthRepository(db *pgxpool.Pool, redisClient *redis.Client) ports.HealthRepository {
+	logger.Log.Info().Msg("initializing health repository")
+	return &healthRepository{
+		db:          db,
+		redisClient: redisClient,
+	}
+}
+
+// PingPostgres round trip to the database on a pooled connection; a ping
+// cut off by its timeout only costs that connection, the pool dials a new one
+// =========================================================================
+func (hr *healthRepository) PingPostgres(ctx context.Context) error {
+	return hr.db.Ping(ctx)
+}
+
+// PingRedis round trip to redis
+// =========================================================================
+func (hr *healthRepository) PingRedis(ctx context.Context) error {
+	return hr.redisClient.Ping(ctx).Err()
+}
+
+// GetSchemaVersion latest applied schema version, 0 when none is recorded
+// =========================================================================
+func (hr *healthRepository) GetSchemaVersion(ctx context.Context) (int, error) {
+	var version int
+	err := hr.db.QueryRow(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version)
+	return version, err
+}
@@ -0,0 +1,101 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+	"github.com/suryansh74/task-management-api-project/internal/logger"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+)
+
+type idempotencyStore struct {
+	redisClient  *redis.Client
+	redisAppName string
+}
+
+// NewIdempotencyStore constructor for the redis backed idempotency store
+// =========================================================================
+func NewIdempotencyStore(redisClient *redis.Client, redisAppName string) ports.IdempotencyStore {
+	logger.Log.Info().Msg("initializing idempotency store")
+	return &idempotencyStore{
+		redisClient:  redisClient,
+		redisAppName: redisAppName,
+	}
+}
+
+func (s *idempotencyStore) redisKey(key string) string {
+	return fmt.Sprintf("%s:idempotency:%s", s.redisAppName, key)
+}
+
+// Begin claim the key with an in-flight record unless it exists
+// =========================================================================
+func (s *idempotencyStore) Begin(ctx context.Context, key string, fingerprint string, lockTTL time.Duration) (*models.IdempotencyRecord, bool, error) {
+	claim, err := json.Marshal(&models.IdempotencyRecord{
+		State:       models.IdempotencyStateInFlight,
+		Fingerprint: fingerprint,
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	// the stored record can expire between SETNX and GET, then claim again
+	for attempt := 0; attempt < 2; attempt++ {
+		ok, err := s.redisClient.SetNX(ctx, s.redisKey(key), claim, lockTTL).Result()
+		if err != nil {
+			zerolog.Ctx(ctx).Error().
+				Err(err).
+				Msg("failed to claim idempotency key")
+			return nil, false, err
+		}
+		if ok {
+			return nil, true, nil
+		}
+
+		val, err := s.redisClient.Get(ctx, s.redisKey(key)).Bytes()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+		if err != nil {
+			zerolog.Ctx(ctx).Error().
+				Err(err).
+				Msg("failed to get idempotency record")
+			return nil, false, err
+		}
+
+		var record models.IdempotencyRecord
+		if err := json.Unmarshal(val, &record); err != nil {
+			return nil, false, err
+		}
+		return &record, false, nil
+	}
+	return nil, false, errors.New("idempotency key keeps expiring")
+}
+
+// Complete store the final response for replays
+// =========================================================================
+func (s *idempotencyStore) Complete(ctx context.Context, key string, record *models.IdempotencyRecord, ttl time.Duration) error {
+	record.State = models.IdempotencyStateCompleted
+	val, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	if err := s.redisClient.Set(ctx, s.redisKey(key), val, ttl).Err(); err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Msg("failed to store idempotency record")
+		return err
+	}
+	return nil
+}
+
+// Release drop the claim
+// =========================================================================
+func (s *idempotencyStore) Release(ctx context.Context, key string) error {
+	return s.redisClient.Del(ctx, s.redisKey(key)).Err()
+}
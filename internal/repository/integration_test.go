@@ -4,12 +4,16 @@ package repository_test
 
 import (
 	"context"
+	"io"
+	"net/http"
 	"os"
 	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 
-	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 	goredis "github.com/redis/go-redis/v9"
 	"github.com/stretchr/testify/require"
 	"github.com/testcontainers/testcontainers-go"
@@ -17,9 +21,11 @@ import (
 	tcredis "github.com/testcontainers/testcontainers-go/modules/redis"
 	"github.com/testcontainers/testcontainers-go/wait"
 
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
 	"github.com/suryansh74/task-management-api-project/internal/logger"
 	"github.com/suryansh74/task-management-api-project/internal/models"
 	"github.com/suryansh74/task-management-api-project/internal/repository"
+	"github.com/suryansh74/task-management-api-project/internal/service"
 )
 
 func init() {
@@ -51,7 +57,7 @@ func findInitSQL(t *testing.T) string {
 	return ""
 }
 
-func setupPostgres(t *testing.T) (*pgx.Conn, func()) {
+func setupPostgres(t *testing.T) (*pgxpool.Pool, func()) {
 	t.Helper()
 	ctx := context.Background()
 	initSQL := findInitSQL(t)
@@ -73,11 +79,11 @@ func setupPostgres(t *testing.T) (*pgx.Conn, func()) {
 	connStr, err := pgContainer.ConnectionString(ctx, "sslmode=disable")
 	require.NoError(t, err)
 
-	conn, err := pgx.Connect(ctx, connStr)
+	conn, err := pgxpool.New(ctx, connStr)
 	require.NoError(t, err)
 
 	cleanup := func() {
-		conn.Close(ctx)
+		conn.Close()
 		_ = pgContainer.Terminate(ctx)
 	}
 	return conn, cleanup
@@ -145,6 +151,42 @@ func TestUserRepository_Integration(t *testing.T) {
 		_, err := repo.FindByEmail(ctx, "does-not-exist@example.com")
 		require.Error(t, err)
 	})
+
+	t.Run("UpdatePassword", func(t *testing.T) {
+		found, err := repo.FindByEmail(ctx, "integration@example.com")
+		require.NoError(t, err)
+
+		require.NoError(t, repo.UpdatePassword(ctx, found.ID, "new-hashed-password"))
+		found, err = repo.FindByEmail(ctx, "integration@example.com")
+		require.NoError(t, err)
+		require.Equal(t, "new-hashed-password", found.Password)
+
+		err = repo.UpdatePassword(ctx, "00000000-0000-0000-0000-000000000000", "x")
+		require.Error(t, err)
+	})
+
+	t.Run("MarkEmailVerified", func(t *testing.T) {
+		found, err := repo.FindByEmail(ctx, "integration@example.com")
+		require.NoError(t, err)
+		require.Nil(t, found.EmailVerifiedAt)
+
+		// a link for an address the user no longer has verifies nothing
+		require.Error(t, repo.MarkEmailVerified(ctx, found.ID, "old@example.com"))
+
+		require.NoError(t, repo.MarkEmailVerified(ctx, found.ID, found.Email))
+		byID, err := repo.FindByID(ctx, found.ID)
+		require.NoError(t, err)
+		require.NotNil(t, byID.EmailVerifiedAt)
+
+		// verifying again keeps the first time
+		require.NoError(t, repo.MarkEmailVerified(ctx, found.ID, found.Email))
+		again, err := repo.FindByID(ctx, found.ID)
+		require.NoError(t, err)
+		require.True(t, byID.EmailVerifiedAt.Equal(*again.EmailVerifiedAt))
+
+		_, err = repo.FindByID(ctx, "not-a-uuid")
+		require.Error(t, err)
+	})
 }
 
 func TestTaskRepository_Integration(t *testing.T) {
@@ -152,7 +194,7 @@ func TestTaskRepository_Integration(t *testing.T) {
 	defer cleanup()
 
 	userRepo := repository.NewUserRepository(conn)
-	taskRepo := repository.NewTaskRepository(conn)
+	taskRepo := repository.NewTaskRepository(conn, models.TaskQuota{})
 	ctx := context.Background()
 
 	userID, err := userRepo.CreateUser(ctx, &models.User{
@@ -209,7 +251,7 @@ func TestTaskCacheRepository_Integration(t *testing.T) {
 	rdb, cleanup := setupRedis(t)
 	defer cleanup()
 
-	cacheRepo := repository.NewTaskCacheRepository(rdb)
+	cacheRepo := repository.NewTaskCacheRepository(rdb, "test", time.Minute)
 	ctx := context.Background()
 
 	task := &models.Task{
@@ -240,4 +282,1341 @@ func TestTaskCacheRepository_Integration(t *testing.T) {
 		require.NoError(t, err)
 		require.Nil(t, got)
 	})
+
+	t.Run("Entry and NotFound", func(t *testing.T) {
+		require.NoError(t, cacheRepo.SetTask(ctx, task, key, 2*time.Minute))
+		entry, err := cacheRepo.GetTaskEntry(ctx, key)
+		require.NoError(t, err)
+		require.NotNil(t, entry)
+		require.False(t, entry.NotFound)
+		require.Equal(t, task.ID, entry.Task.ID)
+		require.Greater(t, entry.TTL, time.Minute)
+
+		require.NoError(t, cacheRepo.SetNotFound(ctx, key, 30*time.Second))
+		entry, err = cacheRepo.GetTaskEntry(ctx, key)
+		require.NoError(t, err)
+		require.True(t, entry.NotFound)
+		require.Nil(t, entry.Task)
+		require.LessOrEqual(t, entry.TTL, 30*time.Second)
+
+		// a cached miss is not a task
+		got, err := cacheRepo.GetTaskByID(ctx, key)
+		require.NoError(t, err)
+		require.Nil(t, got)
+	})
+
+	t.Run("Task lists by generation", func(t *testing.T) {
+		page, generation, err := cacheRepo.GetTaskList(ctx, "user-1", "all")
+		require.NoError(t, err)
+		require.Nil(t, page)
+		require.Zero(t, generation)
+
+		cached := &models.TaskPage{Tasks: []*models.Task{task}, NextCursor: "next", Limit: 1}
+		require.NoError(t, cacheRepo.SetTaskList(ctx, "user-1", "all", generation, cached, time.Minute))
+		page, _, err = cacheRepo.GetTaskList(ctx, "user-1", "all")
+		require.NoError(t, err)
+		require.NotNil(t, page)
+		require.Equal(t, task.ID, page.Tasks[0].ID)
+		require.Equal(t, "next", page.NextCursor)
+
+		// a bump orphans the list, a list stored under the old generation stays unreachable
+		require.NoError(t, cacheRepo.InvalidateTaskLists(ctx, "user-1"))
+		require.NoError(t, cacheRepo.SetTaskList(ctx, "user-1", "all", generation, cached, time.Minute))
+		page, newGeneration, err := cacheRepo.GetTaskList(ctx, "user-1", "all")
+		require.NoError(t, err)
+		require.Nil(t, page)
+		require.Equal(t, generation+1, newGeneration)
+
+		// other users keep their lists
+		require.NoError(t, cacheRepo.SetTaskList(ctx, "user-2", "all", 0, cached, time.Minute))
+		require.NoError(t, cacheRepo.InvalidateTaskLists(ctx, "user-1"))
+		page, _, err = cacheRepo.GetTaskList(ctx, "user-2", "all")
+		require.NoError(t, err)
+		require.NotNil(t, page)
+	})
+
+	t.Run("Generation outlives long list expirations", func(t *testing.T) {
+		ttl, err := rdb.TTL(ctx, "test:cache:tasks_gen:user-1").Result()
+		require.NoError(t, err)
+		require.InDelta(t, (24 * time.Hour).Seconds(), ttl.Seconds(), 5)
+
+		longRepo := repository.NewTaskCacheRepository(rdb, "test", 48*time.Hour)
+		require.NoError(t, longRepo.InvalidateTaskLists(ctx, "user-3"))
+		ttl, err = rdb.TTL(ctx, "test:cache:tasks_gen:user-3").Result()
+		require.NoError(t, err)
+		require.InDelta(t, (96 * time.Hour).Seconds(), ttl.Seconds(), 5)
+	})
+}
+
+func setupMinIO(t *testing.T) (string, func()) {
+	t.Helper()
+	ctx := context.Background()
+
+	minioContainer, err := testcontainers.Run(ctx, "minio/minio:latest",
+		testcontainers.WithExposedPorts("9000/tcp"),
+		testcontainers.WithEnv(map[string]string{
+			"MINIO_ROOT_USER":     "minioadmin",
+			"MINIO_ROOT_PASSWORD": "minioadmin",
+		}),
+		testcontainers.WithCmd("server", "/data"),
+		testcontainers.WithWaitStrategy(
+			wait.ForHTTP("/minio/health/live").WithPort("9000/tcp").WithStartupTimeout(60*time.Second),
+		),
+	)
+	require.NoError(t, err)
+
+	endpoint, err := minioContainer.PortEndpoint(ctx, "9000/tcp", "")
+	require.NoError(t, err)
+
+	cleanup := func() {
+		_ = minioContainer.Terminate(ctx)
+	}
+	return endpoint, cleanup
+}
+
+func TestS3BlobStore_Integration(t *testing.T) {
+	endpoint, cleanup := setupMinIO(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	store, err := repository.NewS3BlobStore(ctx, endpoint, "us-east-1", "task-attachments", "minioadmin", "minioadmin", false)
+	require.NoError(t, err)
+
+	t.Run("Put Get SignedURL Delete", func(t *testing.T) {
+		key := "attachments/task-1/blob-1"
+		err := store.Put(ctx, key, strings.NewReader("hello minio"), 11, "text/plain")
+		require.NoError(t, err)
+
+		body, err := store.Get(ctx, key)
+		require.NoError(t, err)
+		data, err := io.ReadAll(body)
+		require.NoError(t, err)
+		require.NoError(t, body.Close())
+		require.Equal(t, "hello minio", string(data))
+
+		url, err := store.SignedURL(ctx, key, "hello.txt", time.Minute)
+		require.NoError(t, err)
+		resp, err := http.Get(url)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Contains(t, resp.Header.Get("Content-Disposition"), "hello.txt")
+
+		require.NoError(t, store.Delete(ctx, key))
+		_, err = store.Get(ctx, key)
+		require.Error(t, err)
+	})
+}
+
+func TestLocalBlobStore_Integration(t *testing.T) {
+	ctx := context.Background()
+	store, err := repository.NewLocalBlobStore(t.TempDir(), "http://localhost:8000", "signing-key")
+	require.NoError(t, err)
+
+	t.Run("Put Get Delete", func(t *testing.T) {
+		key := "attachments/task-1/blob-1"
+		require.NoError(t, store.Put(ctx, key, strings.NewReader("hello disk"), 10, "text/plain"))
+
+		body, err := store.Get(ctx, key)
+		require.NoError(t, err)
+		data, err := io.ReadAll(body)
+		require.NoError(t, err)
+		require.NoError(t, body.Close())
+		require.Equal(t, "hello disk", string(data))
+
+		url, err := store.SignedURL(ctx, key, "hello.txt", time.Minute)
+		require.NoError(t, err)
+		require.True(t, strings.HasPrefix(url, "http://localhost:8000/files/"+key+"?"))
+
+		require.NoError(t, store.Delete(ctx, key))
+		_, err = store.Get(ctx, key)
+		require.Error(t, err)
+	})
+
+	t.Run("rejects keys escaping base dir", func(t *testing.T) {
+		err := store.Put(ctx, "../outside", strings.NewReader("x"), 1, "text/plain")
+		require.Error(t, err)
+	})
+}
+
+func TestAttachmentRepository_Integration(t *testing.T) {
+	conn, cleanup := setupPostgres(t)
+	defer cleanup()
+
+	userRepo := repository.NewUserRepository(conn)
+	taskRepo := repository.NewTaskRepository(conn, models.TaskQuota{})
+	attachmentRepo := repository.NewAttachmentRepository(conn)
+	ctx := context.Background()
+
+	userID, err := userRepo.CreateUser(ctx, &models.User{
+		Name:     "Attachment Owner",
+		Email:    "attachments@example.com",
+		Password: "pass",
+	})
+	require.NoError(t, err)
+
+	taskID, err := taskRepo.CreateTask(ctx, &models.Task{UserID: userID, Title: "With files"})
+	require.NoError(t, err)
+
+	t.Run("CRUD lifecycle", func(t *testing.T) {
+		id, err := attachmentRepo.CreateAttachment(ctx, &models.Attachment{
+			TaskID:      taskID,
+			UserID:      userID,
+			Filename:    "log.txt",
+			ContentType: "text/plain",
+			SizeBytes:   42,
+			StorageKey:  "attachments/" + taskID + "/abc",
+		})
+		require.NoError(t, err)
+
+		got, err := attachmentRepo.GetAttachmentByID(ctx, id)
+		require.NoError(t, err)
+		require.Equal(t, "log.txt", got.Filename)
+		require.Equal(t, int64(42), got.SizeBytes)
+
+		all, err := attachmentRepo.GetAttachmentsByTaskID(ctx, taskID)
+		require.NoError(t, err)
+		require.Len(t, all, 1)
+
+		require.NoError(t, attachmentRepo.DeleteAttachmentByID(ctx, id))
+		_, err = attachmentRepo.GetAttachmentByID(ctx, id)
+		require.Error(t, err)
+	})
+
+	t.Run("deleting the task queues its blobs", func(t *testing.T) {
+		key := "attachments/" + taskID + "/def"
+		_, err := attachmentRepo.CreateAttachment(ctx, &models.Attachment{
+			TaskID:      taskID,
+			UserID:      userID,
+			Filename:    "trace.txt",
+			ContentType: "text/plain",
+			SizeBytes:   7,
+			StorageKey:  key,
+		})
+		require.NoError(t, err)
+		require.NoError(t, taskRepo.DeleteTaskByID(ctx, taskID))
+
+		keys, err := attachmentRepo.GetDeletedBlobKeys(ctx, 10)
+		require.NoError(t, err)
+		require.ElementsMatch(t, []string{"attachments/" + taskID + "/abc", key}, keys)
+
+		require.NoError(t, attachmentRepo.ForgetDeletedBlobKeys(ctx, keys))
+		keys, err = attachmentRepo.GetDeletedBlobKeys(ctx, 10)
+		require.NoError(t, err)
+		require.Empty(t, keys)
+	})
+}
+
+func TestReminderRepository_Integration(t *testing.T) {
+	conn, cleanup := setupPostgres(t)
+	defer cleanup()
+
+	userRepo := repository.NewUserRepository(conn)
+	taskRepo := repository.NewTaskRepository(conn, models.TaskQuota{})
+	reminderRepo := repository.NewReminderRepository(conn)
+	ctx := context.Background()
+
+	userID, err := userRepo.CreateUser(ctx, &models.User{
+		Name:     "Reminder Owner",
+		Email:    "reminders@example.com",
+		Password: "pass",
+	})
+	require.NoError(t, err)
+
+	due := time.Now().Add(2 * time.Hour).Truncate(time.Second)
+	taskID, err := taskRepo.CreateTask(ctx, &models.Task{UserID: userID, Title: "Due soon", DueAt: &due})
+	require.NoError(t, err)
+
+	id, err := reminderRepo.CreateReminder(ctx, &models.Reminder{
+		TaskID:        taskID,
+		UserID:        userID,
+		OffsetSeconds: 3600,
+		Channel:       models.ReminderChannelLog,
+	})
+	require.NoError(t, err)
+
+	t.Run("duplicate rule conflicts", func(t *testing.T) {
+		_, err := reminderRepo.CreateReminder(ctx, &models.Reminder{
+			TaskID:        taskID,
+			UserID:        userID,
+			OffsetSeconds: 3600,
+			Channel:       models.ReminderChannelLog,
+		})
+		require.Error(t, err)
+	})
+
+	t.Run("resolves fire time against due date", func(t *testing.T) {
+		r, err := reminderRepo.GetDueReminder(ctx, id)
+		require.NoError(t, err)
+		require.NotNil(t, r)
+		require.True(t, r.FireAt.Equal(due.Add(-time.Hour)))
+		require.Equal(t, "reminders@example.com", r.UserEmail)
+
+		upcoming, err := reminderRepo.GetUpcomingReminders(ctx, userID, time.Now(), 10)
+		require.NoError(t, err)
+		require.Len(t, upcoming, 1)
+	})
+
+	t.Run("fired reminders leave the pending window", func(t *testing.T) {
+		from, to := time.Now(), due
+		pending, err := reminderRepo.GetPendingReminders(ctx, from, to, 10)
+		require.NoError(t, err)
+		require.Len(t, pending, 1)
+
+		fired, err := reminderRepo.MarkReminderFired(ctx, id, pending[0].FireAt)
+		require.NoError(t, err)
+		require.True(t, fired)
+
+		fired, err = reminderRepo.MarkReminderFired(ctx, id, pending[0].FireAt)
+		require.NoError(t, err)
+		require.False(t, fired)
+
+		pending, err = reminderRepo.GetPendingReminders(ctx, from, to, 10)
+		require.NoError(t, err)
+		require.Empty(t, pending)
+	})
+}
+
+func TestReminderQueue_Integration(t *testing.T) {
+	rdb, cleanup := setupRedis(t)
+	defer cleanup()
+
+	queue := repository.NewReminderQueue(rdb, "test-app")
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+
+	require.NoError(t, queue.Schedule(ctx, "rem-due", now.Add(-time.Minute)))
+	require.NoError(t, queue.Schedule(ctx, "rem-due", now.Add(-time.Minute)))
+	require.NoError(t, queue.Schedule(ctx, "rem-later", now.Add(time.Hour)))
+
+	jobs, err := queue.ClaimDue(ctx, now, 10)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	require.Equal(t, "rem-due", jobs[0].ReminderID)
+	require.True(t, jobs[0].FireAt.Equal(now.Add(-time.Minute)))
+
+	// a claimed entry is gone for everyone else
+	jobs, err = queue.ClaimDue(ctx, now, 10)
+	require.NoError(t, err)
+	require.Empty(t, jobs)
+}
+
+func TestNotificationPreferencesRepository_Integration(t *testing.T) {
+	conn, cleanup := setupPostgres(t)
+	defer cleanup()
+
+	userRepo := repository.NewUserRepository(conn)
+	prefsRepo := repository.NewNotificationPreferencesRepository(conn)
+	ctx := context.Background()
+
+	userID, err := userRepo.CreateUser(ctx, &models.User{
+		Name:     "Prefs Owner",
+		Email:    "prefs@example.com",
+		Password: "pass",
+	})
+	require.NoError(t, err)
+
+	prefs, err := prefsRepo.GetPreferences(ctx, userID)
+	require.NoError(t, err)
+	require.True(t, prefs.EmailEnabled && prefs.Reminders && prefs.Invitations, "defaults before first save")
+
+	prefs.Reminders = false
+	require.NoError(t, prefsRepo.UpsertPreferences(ctx, prefs))
+	prefs.Invitations = false
+	require.NoError(t, prefsRepo.UpsertPreferences(ctx, prefs))
+
+	got, err := prefsRepo.GetPreferences(ctx, userID)
+	require.NoError(t, err)
+	require.True(t, got.EmailEnabled)
+	require.False(t, got.Reminders)
+	require.False(t, got.Invitations)
+}
+
+func TestMailQueue_Integration(t *testing.T) {
+	rdb, cleanup := setupRedis(t)
+	defer cleanup()
+
+	queue := repository.NewMailQueue(rdb, "test-app", time.Minute)
+	ctx := context.Background()
+	now := time.Now()
+
+	job := &models.MailJob{ID: "job-1", Email: models.Email{To: []string{"a@example.com"}, Subject: "hi"}}
+	require.NoError(t, queue.Enqueue(ctx, job, now))
+	require.NoError(t, queue.Enqueue(ctx, &models.MailJob{ID: "job-2"}, now.Add(time.Minute)))
+
+	jobs, err := queue.ClaimDue(ctx, now, 10)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	require.Equal(t, "job-1", jobs[0].ID)
+	require.Equal(t, "hi", jobs[0].Email.Subject)
+
+	// leased, so hidden from other workers until the claim times out
+	jobs, err = queue.ClaimDue(ctx, now, 10)
+	require.NoError(t, err)
+	require.Empty(t, jobs)
+
+	jobs, err = queue.ClaimDue(ctx, now.Add(2*time.Minute), 10)
+	require.NoError(t, err)
+	require.Len(t, jobs, 2)
+	for _, claimed := range jobs {
+		if claimed.ID == "job-2" {
+			require.NoError(t, queue.Ack(ctx, claimed))
+			continue
+		}
+		require.NoError(t, queue.DeadLetter(ctx, claimed))
+	}
+
+	n, err := rdb.LLen(ctx, "test-app:mail:dead").Result()
+	require.NoError(t, err)
+	require.Equal(t, int64(1), n)
+	n, err = rdb.ZCard(ctx, "test-app:mail:queue").Result()
+	require.NoError(t, err)
+	require.Zero(t, n)
+}
+
+func TestTaskDependencies_Integration(t *testing.T) {
+	conn, cleanup := setupPostgres(t)
+	defer cleanup()
+
+	userRepo := repository.NewUserRepository(conn)
+	taskRepo := repository.NewTaskRepository(conn, models.TaskQuota{})
+	ctx := context.Background()
+
+	userID, err := userRepo.CreateUser(ctx, &models.User{
+		Name:     "Graph Owner",
+		Email:    "graph@example.com",
+		Password: "pass",
+	})
+	require.NoError(t, err)
+
+	ids := map[string]string{}
+	for _, title := range []string{"build", "test", "deploy"} {
+		id, err := taskRepo.CreateTask(ctx, &models.Task{UserID: userID, Title: title})
+		require.NoError(t, err)
+		ids[title] = id
+	}
+
+	require.NoError(t, taskRepo.AddDependency(ctx, userID, ids["test"], ids["build"]))
+	require.NoError(t, taskRepo.AddDependency(ctx, userID, ids["deploy"], ids["test"]))
+	// adding the same edge twice is a no-op
+	require.NoError(t, taskRepo.AddDependency(ctx, userID, ids["deploy"], ids["test"]))
+
+	t.Run("rejects transitive cycle", func(t *testing.T) {
+		err := taskRepo.AddDependency(ctx, userID, ids["build"], ids["deploy"])
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "cycle")
+	})
+
+	t.Run("blockers and dependents", func(t *testing.T) {
+		blockers, err := taskRepo.GetBlockers(ctx, ids["deploy"])
+		require.NoError(t, err)
+		require.Len(t, blockers, 1)
+		require.Equal(t, ids["test"], blockers[0].ID)
+
+		dependents, err := taskRepo.GetDependents(ctx, ids["build"])
+		require.NoError(t, err)
+		require.Len(t, dependents, 1)
+		require.Equal(t, ids["test"], dependents[0].ID)
+
+		edges, err := taskRepo.GetDependencyEdges(ctx, userID)
+		require.NoError(t, err)
+		require.Len(t, edges, 2)
+	})
+
+	t.Run("remove edge", func(t *testing.T) {
+		require.NoError(t, taskRepo.RemoveDependency(ctx, ids["deploy"], ids["test"]))
+		require.Error(t, taskRepo.RemoveDependency(ctx, ids["deploy"], ids["test"]))
+	})
+}
+
+func TestChecklistRepository_Integration(t *testing.T) {
+	conn, cleanup := setupPostgres(t)
+	defer cleanup()
+
+	userRepo := repository.NewUserRepository(conn)
+	taskRepo := repository.NewTaskRepository(conn, models.TaskQuota{})
+	checklistRepo := repository.NewChecklistRepository(conn)
+	ctx := context.Background()
+
+	userID, err := userRepo.CreateUser(ctx, &models.User{
+		Name:     "Checklist Owner",
+		Email:    "checklist@example.com",
+		Password: "pass",
+	})
+	require.NoError(t, err)
+	taskID, err := taskRepo.CreateTask(ctx, &models.Task{UserID: userID, Title: "Groceries"})
+	require.NoError(t, err)
+	otherTaskID, err := taskRepo.CreateTask(ctx, &models.Task{UserID: userID, Title: "Errands"})
+	require.NoError(t, err)
+
+	var ids []string
+	for _, text := range []string{"milk", "eggs", "bread"} {
+		item := &models.ChecklistItem{TaskID: taskID, Text: text}
+		id, err := checklistRepo.CreateChecklistItem(ctx, item)
+		require.NoError(t, err)
+		require.Equal(t, len(ids), item.Position)
+		ids = append(ids, id)
+	}
+
+	t.Run("toggle updates task counts", func(t *testing.T) {
+		item, err := checklistRepo.ToggleChecklistItem(ctx, taskID, ids[1])
+		require.NoError(t, err)
+		require.True(t, item.Checked)
+
+		task, err := taskRepo.GetTaskByID(ctx, taskID)
+		require.NoError(t, err)
+		require.Equal(t, 3, task.ChecklistTotal)
+		require.Equal(t, 1, task.ChecklistDone)
+	})
+
+	t.Run("reorder", func(t *testing.T) {
+		require.NoError(t, checklistRepo.ReorderChecklistItems(ctx, taskID, []string{ids[2], ids[0], ids[1]}))
+
+		items, err := checklistRepo.GetChecklistItemsByTaskID(ctx, taskID)
+		require.NoError(t, err)
+		require.Equal(t, []string{"bread", "milk", "eggs"}, []string{items[0].Text, items[1].Text, items[2].Text})
+	})
+
+	t.Run("partial reorder is rejected and changes nothing", func(t *testing.T) {
+		require.Error(t, checklistRepo.ReorderChecklistItems(ctx, taskID, []string{ids[0], ids[1]}))
+
+		items, err := checklistRepo.GetChecklistItemsByTaskID(ctx, taskID)
+		require.NoError(t, err)
+		require.Equal(t, "bread", items[0].Text)
+	})
+
+	t.Run("delete closes the gap", func(t *testing.T) {
+		require.NoError(t, checklistRepo.DeleteChecklistItemByID(ctx, taskID, ids[0]))
+
+		items, err := checklistRepo.GetChecklistItemsByTaskID(ctx, taskID)
+		require.NoError(t, err)
+		require.Len(t, items, 2)
+		require.Equal(t, 0, items[0].Position)
+		require.Equal(t, 1, items[1].Position)
+
+		task, err := taskRepo.GetTaskByID(ctx, taskID)
+		require.NoError(t, err)
+		require.Equal(t, 2, task.ChecklistTotal)
+		require.Equal(t, 1, task.ChecklistDone)
+	})
+
+	t.Run("update changes only the given fields", func(t *testing.T) {
+		text := "free-range eggs"
+		item, err := checklistRepo.UpdateChecklistItem(ctx, taskID, ids[1], &text, nil)
+		require.NoError(t, err)
+		require.Equal(t, text, item.Text)
+		require.True(t, item.Checked)
+
+		checked := false
+		item, err = checklistRepo.UpdateChecklistItem(ctx, taskID, ids[1], nil, &checked)
+		require.NoError(t, err)
+		require.Equal(t, text, item.Text)
+		require.False(t, item.Checked)
+
+		task, err := taskRepo.GetTaskByID(ctx, taskID)
+		require.NoError(t, err)
+		require.Equal(t, 0, task.ChecklistDone)
+
+		_, err = checklistRepo.UpdateChecklistItem(ctx, otherTaskID, ids[1], &text, nil)
+		require.Error(t, err)
+	})
+}
+
+func TestTaskSearch_Integration(t *testing.T) {
+	conn, cleanup := setupPostgres(t)
+	defer cleanup()
+
+	userRepo := repository.NewUserRepository(conn)
+	taskRepo := repository.NewTaskRepository(conn, models.TaskQuota{})
+	ctx := context.Background()
+
+	userID, err := userRepo.CreateUser(ctx, &models.User{
+		Name:     "Search Owner",
+		Email:    "search@example.com",
+		Password: "pass",
+	})
+	require.NoError(t, err)
+
+	tasks := []*models.Task{
+		{UserID: userID, Title: "Quarterly report", Content: "collect numbers for the finance review", Labels: []string{"work", "finance"}},
+		{UserID: userID, Title: "Dentist", Content: "bring the insurance report", Status: models.TaskStatusDone, Labels: []string{"health"}},
+		{UserID: userID, Title: "Groceries", Content: "milk and eggs"},
+	}
+	for _, task := range tasks {
+		task.ID, err = taskRepo.CreateTask(ctx, task)
+		require.NoError(t, err)
+	}
+
+	t.Run("prefix match ranks title above content", func(t *testing.T) {
+		result, err := taskRepo.SearchTasks(ctx, &models.TaskSearchQuery{UserID: userID, Terms: []string{"rep"}, Limit: 10})
+		require.NoError(t, err)
+		require.Equal(t, 2, result.Total)
+		require.Equal(t, "Quarterly report", result.Hits[0].Task.Title)
+		require.Contains(t, result.Hits[0].TitleHighlight, "<mark>report</mark>")
+		require.Contains(t, result.Hits[1].Snippet, "<mark>report</mark>")
+	})
+
+	t.Run("status filter", func(t *testing.T) {
+		result, err := taskRepo.SearchTasks(ctx, &models.TaskSearchQuery{
+			UserID:   userID,
+			Terms:    []string{"report"},
+			Statuses: []string{models.TaskStatusDone},
+			Limit:    10,
+		})
+		require.NoError(t, err)
+		require.Len(t, result.Hits, 1)
+		require.Equal(t, "Dentist", result.Hits[0].Task.Title)
+	})
+
+	t.Run("label filter needs every label", func(t *testing.T) {
+		result, err := taskRepo.SearchTasks(ctx, &models.TaskSearchQuery{
+			UserID: userID,
+			Terms:  []string{"report"},
+			Labels: []string{"work", "finance"},
+			Limit:  10,
+		})
+		require.NoError(t, err)
+		require.Len(t, result.Hits, 1)
+		require.Equal(t, []string{"work", "finance"}, result.Hits[0].Task.Labels)
+
+		result, err = taskRepo.SearchTasks(ctx, &models.TaskSearchQuery{
+			UserID: userID,
+			Terms:  []string{"report"},
+			Labels: []string{"work", "health"},
+			Limit:  10,
+		})
+		require.NoError(t, err)
+		require.Empty(t, result.Hits)
+	})
+
+	t.Run("update without labels keeps them", func(t *testing.T) {
+		require.NoError(t, taskRepo.UpdateTaskByID(ctx, tasks[1].ID, &models.Task{Title: "Dentist", Content: "bring the insurance report"}))
+		task, err := taskRepo.GetTaskByID(ctx, tasks[1].ID)
+		require.NoError(t, err)
+		require.Equal(t, []string{"health"}, task.Labels)
+	})
+
+	t.Run("pagination keeps total", func(t *testing.T) {
+		result, err := taskRepo.SearchTasks(ctx, &models.TaskSearchQuery{UserID: userID, Terms: []string{"report"}, Limit: 1, Offset: 1})
+		require.NoError(t, err)
+		require.Len(t, result.Hits, 1)
+		require.Equal(t, 2, result.Total)
+	})
+
+	t.Run("other users see nothing", func(t *testing.T) {
+		result, err := taskRepo.SearchTasks(ctx, &models.TaskSearchQuery{UserID: "00000000-0000-0000-0000-000000000000", Terms: []string{"report"}, Limit: 10})
+		require.NoError(t, err)
+		require.Empty(t, result.Hits)
+	})
+}
+
+func TestTaskQuota_Integration(t *testing.T) {
+	conn, cleanup := setupPostgres(t)
+	defer cleanup()
+
+	quota := models.TaskQuota{MaxTasks: 3, MaxContentBytes: 100}
+	userRepo := repository.NewUserRepository(conn)
+	taskRepo := repository.NewTaskRepository(conn, quota)
+	seriesRepo := repository.NewTaskSeriesRepository(conn, quota)
+	ctx := context.Background()
+
+	userID, err := userRepo.CreateUser(ctx, &models.User{
+		Name:     "Quota Owner",
+		Email:    "quota@example.com",
+		Password: "pass",
+	})
+	require.NoError(t, err)
+
+	requireQuotaExceeded := func(t *testing.T, err error) {
+		t.Helper()
+		var appErr *apperror.AppError
+		require.ErrorAs(t, err, &appErr)
+		require.Equal(t, "QUOTA_EXCEEDED", appErr.Code)
+	}
+
+	t.Run("concurrent creates never pass the task quota", func(t *testing.T) {
+		var wg sync.WaitGroup
+		errs := make([]error, 6)
+		for i := range errs {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				_, errs[i] = taskRepo.CreateTask(ctx, &models.Task{UserID: userID, Title: "Racer"})
+			}(i)
+		}
+		wg.Wait()
+
+		created := 0
+		for _, err := range errs {
+			if err == nil {
+				created++
+				continue
+			}
+			requireQuotaExceeded(t, err)
+		}
+		require.Equal(t, 3, created)
+
+		usage, err := taskRepo.GetTaskUsage(ctx, userID)
+		require.NoError(t, err)
+		require.Equal(t, 3, usage.Tasks)
+	})
+
+	t.Run("updates count their growth", func(t *testing.T) {
+		tasks, err := taskRepo.GetAllTasks(ctx, userID)
+		require.NoError(t, err)
+		id := tasks[0].ID
+
+		// 15 bytes stored, 85 left
+		require.NoError(t, taskRepo.UpdateTaskByID(ctx, id, &models.Task{Title: "Racer", Content: strings.Repeat("x", 85)}))
+		requireQuotaExceeded(t, taskRepo.UpdateTaskByID(ctx, id, &models.Task{Title: "Racer", Content: strings.Repeat("x", 86)}))
+
+		// shrinking always passes
+		require.NoError(t, taskRepo.UpdateTaskByID(ctx, id, &models.Task{Title: "Racer"}))
+	})
+
+	t.Run("generated occurrences count against the quota", func(t *testing.T) {
+		tasks, err := taskRepo.GetAllTasks(ctx, userID)
+		require.NoError(t, err)
+		require.NoError(t, taskRepo.DeleteTaskByID(ctx, tasks[0].ID))
+
+		seriesID, err := seriesRepo.CreateSeries(ctx, &models.TaskSeries{
+			UserID:   userID,
+			Title:    "Standup",
+			RRule:    "FREQ=DAILY",
+			Timezone: "UTC",
+			DTStart:  time.Now().UTC(),
+			Mode:     models.SeriesModeScheduled,
+		})
+		require.NoError(t, err)
+
+		due := time.Now().UTC().Add(time.Hour).Truncate(time.Second)
+		_, created, err := seriesRepo.CreateOccurrence(ctx, &models.Task{UserID: userID, Title: "Standup", DueAt: &due, SeriesID: &seriesID})
+		require.NoError(t, err)
+		require.True(t, created)
+
+		next := due.Add(24 * time.Hour)
+		_, created, err = seriesRepo.CreateOccurrence(ctx, &models.Task{UserID: userID, Title: "Standup", DueAt: &next, SeriesID: &seriesID})
+		requireQuotaExceeded(t, err)
+		require.False(t, created)
+	})
+
+	t.Run("an atomic batch over the quota fails as a whole", func(t *testing.T) {
+		tasks, err := taskRepo.GetAllTasks(ctx, userID)
+		require.NoError(t, err)
+		require.NoError(t, taskRepo.DeleteTaskByID(ctx, tasks[0].ID))
+
+		_, err = taskRepo.ApplyTaskBatch(ctx, []*models.TaskBatchOp{
+			{Op: models.BatchOpCreate, UserID: userID, Title: "One"},
+			{Op: models.BatchOpCreate, UserID: userID, Title: "Two"},
+		}, true)
+		requireQuotaExceeded(t, err)
+
+		usage, err := taskRepo.GetTaskUsage(ctx, userID)
+		require.NoError(t, err)
+		require.Equal(t, 2, usage.Tasks)
+	})
+
+	t.Run("a non-atomic batch fails only the ops over the quota", func(t *testing.T) {
+		errs, err := taskRepo.ApplyTaskBatch(ctx, []*models.TaskBatchOp{
+			{Op: models.BatchOpCreate, UserID: userID, Title: "One"},
+			{Op: models.BatchOpCreate, UserID: userID, Title: "Two"},
+		}, false)
+		require.NoError(t, err)
+		require.NoError(t, errs[0])
+		requireQuotaExceeded(t, errs[1])
+
+		usage, err := taskRepo.GetTaskUsage(ctx, userID)
+		require.NoError(t, err)
+		require.Equal(t, 3, usage.Tasks)
+	})
+}
+
+func TestTaskBatch_Integration(t *testing.T) {
+	conn, cleanup := setupPostgres(t)
+	defer cleanup()
+
+	userRepo := repository.NewUserRepository(conn)
+	taskRepo := repository.NewTaskRepository(conn, models.TaskQuota{})
+	ctx := context.Background()
+
+	userID, err := userRepo.CreateUser(ctx, &models.User{
+		Name:     "Batch Owner",
+		Email:    "batch@example.com",
+		Password: "pass",
+	})
+	require.NoError(t, err)
+	existing, err := taskRepo.CreateTask(ctx, &models.Task{UserID: userID, Title: "Existing"})
+	require.NoError(t, err)
+	missing := "00000000-0000-0000-0000-000000000000"
+
+	t.Run("atomic rolls back earlier ops", func(t *testing.T) {
+		errs, err := taskRepo.ApplyTaskBatch(ctx, []*models.TaskBatchOp{
+			{Op: models.BatchOpCreate, UserID: userID, Title: "Never"},
+			{Op: models.BatchOpSetStatus, ID: existing, Status: models.TaskStatusDone},
+			{Op: models.BatchOpDelete, ID: missing},
+		}, true)
+		require.NoError(t, err)
+		require.Error(t, errs[2])
+
+		tasks, err := taskRepo.GetAllTasks(ctx, userID)
+		require.NoError(t, err)
+		require.Len(t, tasks, 1)
+		require.Equal(t, models.TaskStatusTodo, tasks[0].Status)
+	})
+
+	t.Run("non-atomic keeps successful ops", func(t *testing.T) {
+		ops := []*models.TaskBatchOp{
+			{Op: models.BatchOpCreate, UserID: userID, Title: "Kept"},
+			{Op: models.BatchOpDelete, ID: missing},
+			{Op: models.BatchOpSetStatus, ID: existing, Status: models.TaskStatusDone},
+		}
+		errs, err := taskRepo.ApplyTaskBatch(ctx, ops, false)
+		require.NoError(t, err)
+		require.NoError(t, errs[0])
+		require.Error(t, errs[1])
+		require.NoError(t, errs[2])
+		require.NotEmpty(t, ops[0].ID)
+
+		task, err := taskRepo.GetTaskByID(ctx, existing)
+		require.NoError(t, err)
+		require.Equal(t, models.TaskStatusDone, task.Status)
+
+		tasks, err := taskRepo.GetAllTasks(ctx, userID)
+		require.NoError(t, err)
+		require.Len(t, tasks, 2)
+	})
+}
+
+func TestTaskImportExport_Integration(t *testing.T) {
+	conn, cleanup := setupPostgres(t)
+	defer cleanup()
+
+	userRepo := repository.NewUserRepository(conn)
+	taskRepo := repository.NewTaskRepository(conn, models.TaskQuota{})
+	ctx := context.Background()
+
+	userID, err := userRepo.CreateUser(ctx, &models.User{
+		Name:     "Import Owner",
+		Email:    "import@example.com",
+		Password: "pass",
+	})
+	require.NoError(t, err)
+
+	due := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+	gh1, gh2 := "GH-1", "GH-2"
+
+	inserted, errs, err := taskRepo.ImportTasks(ctx, []*models.Task{
+		{UserID: userID, Title: "First", ExternalID: &gh1, Status: models.TaskStatusInProgress, DueAt: &due, Labels: []string{"work"}},
+		{UserID: userID, Title: "Second", ExternalID: &gh2},
+		{UserID: userID, Title: "Third"},
+	}, false)
+	require.NoError(t, err)
+	require.Equal(t, []bool{true, true, true}, inserted)
+	require.Equal(t, []error{nil, nil, nil}, errs)
+
+	ids, err := taskRepo.GetTaskIDsByExternalIDs(ctx, userID, []string{gh1, gh2, "GH-404"})
+	require.NoError(t, err)
+	require.Len(t, ids, 2)
+
+	t.Run("create mode rejects existing external id", func(t *testing.T) {
+		_, errs, err := taskRepo.ImportTasks(ctx, []*models.Task{
+			{UserID: userID, Title: "Again", ExternalID: &gh1},
+			{UserID: userID, Title: "Fourth"},
+		}, false)
+		require.NoError(t, err)
+		require.Error(t, errs[0])
+		require.NoError(t, errs[1])
+	})
+
+	t.Run("upsert updates and keeps missing fields", func(t *testing.T) {
+		inserted, errs, err := taskRepo.ImportTasks(ctx, []*models.Task{
+			{UserID: userID, Title: "First renamed", ExternalID: &gh1},
+		}, true)
+		require.NoError(t, err)
+		require.NoError(t, errs[0])
+		require.False(t, inserted[0])
+
+		task, err := taskRepo.GetTaskByID(ctx, ids[gh1])
+		require.NoError(t, err)
+		require.Equal(t, "First renamed", task.Title)
+		require.Equal(t, models.TaskStatusInProgress, task.Status)
+		require.NotNil(t, task.DueAt)
+		require.True(t, task.DueAt.Equal(due))
+		require.Equal(t, []string{"work"}, task.Labels)
+	})
+
+	t.Run("upsert with empty labels clears them", func(t *testing.T) {
+		_, errs, err := taskRepo.ImportTasks(ctx, []*models.Task{
+			{UserID: userID, Title: "First renamed", ExternalID: &gh1, Labels: []string{}},
+		}, true)
+		require.NoError(t, err)
+		require.NoError(t, errs[0])
+
+		task, err := taskRepo.GetTaskByID(ctx, ids[gh1])
+		require.NoError(t, err)
+		require.Empty(t, task.Labels)
+	})
+
+	t.Run("pages cover every task once", func(t *testing.T) {
+		var all []*models.Task
+		var after *models.Task
+		for {
+			page, err := taskRepo.ListTasksPage(ctx, userID, after, 2)
+			require.NoError(t, err)
+			all = append(all, page...)
+			if len(page) < 2 {
+				break
+			}
+			after = page[len(page)-1]
+		}
+		require.Len(t, all, 4)
+
+		// rows imported together share created_at, the id tie-break keeps paging stable
+		seen := map[string]bool{}
+		for _, task := range all {
+			require.False(t, seen[task.ID])
+			seen[task.ID] = true
+			if task.ID == ids[gh1] {
+				require.Equal(t, gh1, *task.ExternalID)
+			}
+		}
+	})
+}
+
+func TestCalendarFeedRepository_Integration(t *testing.T) {
+	conn, cleanup := setupPostgres(t)
+	defer cleanup()
+
+	userRepo := repository.NewUserRepository(conn)
+	taskRepo := repository.NewTaskRepository(conn, models.TaskQuota{})
+	feedRepo := repository.NewCalendarFeedRepository(conn)
+	ctx := context.Background()
+
+	userID, err := userRepo.CreateUser(ctx, &models.User{
+		Name:     "Feed Owner",
+		Email:    "feed@example.com",
+		Password: "pass",
+	})
+	require.NoError(t, err)
+
+	feed, err := feedRepo.GetFeed(ctx, userID)
+	require.NoError(t, err)
+	require.False(t, feed.Enabled)
+
+	first := strings.Repeat("a", 64)
+	second := strings.Repeat("b", 64)
+	_, err = feedRepo.SaveFeedToken(ctx, userID, first)
+	require.NoError(t, err)
+	_, err = feedRepo.SaveFeedToken(ctx, userID, second)
+	require.NoError(t, err)
+
+	// rotation replaces the old token
+	_, err = feedRepo.GetUserIDByTokenHash(ctx, first)
+	require.Error(t, err)
+	owner, err := feedRepo.GetUserIDByTokenHash(ctx, second)
+	require.NoError(t, err)
+	require.Equal(t, userID, owner)
+
+	feed, err = feedRepo.GetFeed(ctx, userID)
+	require.NoError(t, err)
+	require.True(t, feed.Enabled)
+	require.NotNil(t, feed.CreatedAt)
+
+	later := time.Now().Add(48 * time.Hour)
+	sooner := time.Now().Add(24 * time.Hour)
+	_, err = taskRepo.CreateTask(ctx, &models.Task{UserID: userID, Title: "Later", DueAt: &later})
+	require.NoError(t, err)
+	_, err = taskRepo.CreateTask(ctx, &models.Task{UserID: userID, Title: "Undated"})
+	require.NoError(t, err)
+	_, err = taskRepo.CreateTask(ctx, &models.Task{UserID: userID, Title: "Sooner", DueAt: &sooner})
+	require.NoError(t, err)
+
+	tasks, err := taskRepo.GetTasksWithDueDate(ctx, userID)
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+	require.Equal(t, "Sooner", tasks[0].Title)
+	require.Equal(t, "Later", tasks[1].Title)
+
+	require.NoError(t, feedRepo.DeleteFeed(ctx, userID))
+	_, err = feedRepo.GetUserIDByTokenHash(ctx, second)
+	require.Error(t, err)
+	require.Error(t, feedRepo.DeleteFeed(ctx, userID))
+}
+
+func TestIdempotencyStore_Integration(t *testing.T) {
+	client, cleanup := setupRedis(t)
+	defer cleanup()
+
+	store := repository.NewIdempotencyStore(client, "test-app")
+	ctx := context.Background()
+
+	record, claimed, err := store.Begin(ctx, "rest:user-1:key-1", "fp-1", time.Minute)
+	require.NoError(t, err)
+	require.True(t, claimed)
+	require.Nil(t, record)
+
+	// a retry while the first request runs sees the in-flight claim
+	record, claimed, err = store.Begin(ctx, "rest:user-1:key-1", "fp-1", time.Minute)
+	require.NoError(t, err)
+	require.False(t, claimed)
+	require.Equal(t, models.IdempotencyStateInFlight, record.State)
+
+	require.NoError(t, store.Complete(ctx, "rest:user-1:key-1", &models.IdempotencyRecord{
+		Fingerprint: "fp-1",
+		StatusCode:  201,
+		ContentType: "application/json",
+		Body:        []byte(`{"success":true}`),
+	}, time.Hour))
+
+	record, claimed, err = store.Begin(ctx, "rest:user-1:key-1", "fp-2", time.Minute)
+	require.NoError(t, err)
+	require.False(t, claimed)
+	require.Equal(t, models.IdempotencyStateCompleted, record.State)
+	require.Equal(t, "fp-1", record.Fingerprint)
+	require.Equal(t, 201, record.StatusCode)
+	require.Equal(t, `{"success":true}`, string(record.Body))
+
+	ttl, err := client.TTL(ctx, "test-app:idempotency:rest:user-1:key-1").Result()
+	require.NoError(t, err)
+	require.Greater(t, ttl, time.Minute)
+
+	// a released key can be claimed again
+	_, claimed, err = store.Begin(ctx, "rest:user-1:key-2", "fp-1", time.Minute)
+	require.NoError(t, err)
+	require.True(t, claimed)
+	require.NoError(t, store.Release(ctx, "rest:user-1:key-2"))
+	_, claimed, err = store.Begin(ctx, "rest:user-1:key-2", "fp-1", time.Minute)
+	require.NoError(t, err)
+	require.True(t, claimed)
+}
+
+func TestLocalTaskCache_Integration(t *testing.T) {
+	client, cleanup := setupRedis(t)
+	defer cleanup()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// two replicas sharing one Redis
+	shared := repository.NewTaskCacheRepository(client, "test-app", time.Minute)
+	replicaA := repository.NewLocalTaskCache(shared, client, "test-app", 100, time.Minute)
+	replicaB := repository.NewLocalTaskCache(shared, client, "test-app", 100, time.Minute)
+	go replicaB.Listen(ctx)
+
+	key := "test-app:cache:task:t1"
+	require.NoError(t, shared.SetTask(ctx, &models.Task{ID: "t1", Title: "v1"}, key, time.Minute))
+	// give the subscription time to be established before B caches anything
+	time.Sleep(200 * time.Millisecond)
+
+	entry, err := replicaB.GetTaskEntry(ctx, key)
+	require.NoError(t, err)
+	require.Equal(t, "v1", entry.Task.Title)
+
+	// B now serves its local copy even though Redis changed underneath
+	require.NoError(t, shared.SetTask(ctx, &models.Task{ID: "t1", Title: "v2"}, key, time.Minute))
+	entry, err = replicaB.GetTaskEntry(ctx, key)
+	require.NoError(t, err)
+	require.Equal(t, "v1", entry.Task.Title)
+
+	// an update on A evicts the key on B too
+	require.NoError(t, replicaA.DeleteTaskByID(ctx, key))
+	require.NoError(t, shared.SetTask(ctx, &models.Task{ID: "t1", Title: "v3"}, key, time.Minute))
+	require.Eventually(t, func() bool {
+		entry, err := replicaB.GetTaskEntry(ctx, key)
+		return err == nil && entry != nil && entry.Task.Title == "v3"
+	}, 2*time.Second, 20*time.Millisecond)
+}
+
+func TestSessionRepository_Integration(t *testing.T) {
+	client, cleanup := setupRedis(t)
+	defer cleanup()
+
+	sessionRepo := repository.NewSessionRepository(client, "test-app")
+	ctx := context.Background()
+
+	require.NoError(t, sessionRepo.Create(ctx, &models.Session{ID: "test-app:sessions:a", UserID: "u1"}, time.Minute))
+	require.NoError(t, sessionRepo.Create(ctx, &models.Session{ID: "test-app:sessions:b", UserID: "u2"}, time.Minute))
+
+	count, err := sessionRepo.CountActive(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), count)
+
+	require.NoError(t, sessionRepo.Delete(ctx, "test-app:sessions:a"))
+	count, err = sessionRepo.CountActive(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+
+	// expired entries are dropped from the index on count
+	require.NoError(t, client.ZAdd(ctx, "test-app:session_index", goredis.Z{Score: 1, Member: "test-app:sessions:old"}).Err())
+	count, err = sessionRepo.CountActive(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+
+	// revoking a user deletes all of their sessions and nobody else's
+	require.NoError(t, sessionRepo.Create(ctx, &models.Session{ID: "test-app:sessions:c", UserID: "u2"}, time.Minute))
+	require.NoError(t, sessionRepo.Create(ctx, &models.Session{ID: "test-app:sessions:d", UserID: "u3"}, time.Minute))
+	deleted, err := sessionRepo.DeleteByUserID(ctx, "u2")
+	require.NoError(t, err)
+	require.Equal(t, int64(2), deleted)
+	require.Equal(t, int64(0), client.Exists(ctx, "test-app:sessions:b", "test-app:sessions:c").Val())
+	require.Equal(t, int64(1), client.Exists(ctx, "test-app:sessions:d").Val())
+	count, err = sessionRepo.CountActive(ctx)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), count)
+
+	// verifying an email updates the user's live sessions in place
+	require.Equal(t, "0", client.HGet(ctx, "test-app:sessions:d", "email_verified").Val())
+	updated, err := sessionRepo.MarkEmailVerified(ctx, "u3")
+	require.NoError(t, err)
+	require.Equal(t, int64(1), updated)
+	require.Equal(t, "1", client.HGet(ctx, "test-app:sessions:d", "email_verified").Val())
+}
+
+func TestPasswordResetStore_Integration(t *testing.T) {
+	client, cleanup := setupRedis(t)
+	defer cleanup()
+
+	store := repository.NewPasswordResetStore(client, "test-app")
+	ctx := context.Background()
+
+	require.NoError(t, store.Save(ctx, "u1", "hash-1", time.Minute))
+	// a new token replaces the old one
+	require.NoError(t, store.Save(ctx, "u1", "hash-2", time.Minute))
+
+	userID, err := store.Consume(ctx, "hash-1")
+	require.NoError(t, err)
+	require.Empty(t, userID)
+
+	userID, err = store.Consume(ctx, "hash-2")
+	require.NoError(t, err)
+	require.Equal(t, "u1", userID)
+
+	// single use
+	userID, err = store.Consume(ctx, "hash-2")
+	require.NoError(t, err)
+	require.Empty(t, userID)
+
+	// expired
+	require.NoError(t, store.Save(ctx, "u1", "hash-3", 50*time.Millisecond))
+	time.Sleep(100 * time.Millisecond)
+	userID, err = store.Consume(ctx, "hash-3")
+	require.NoError(t, err)
+	require.Empty(t, userID)
+}
+
+func TestMFARepository_Integration(t *testing.T) {
+	conn, cleanup := setupPostgres(t)
+	defer cleanup()
+
+	userRepo := repository.NewUserRepository(conn)
+	repo := repository.NewMFARepository(conn)
+	ctx := context.Background()
+
+	userID, err := userRepo.CreateUser(ctx, &models.User{Name: "MFA User", Email: "mfa@example.com", Password: "pass"})
+	require.NoError(t, err)
+
+	mfa, err := repo.GetMFA(ctx, userID)
+	require.NoError(t, err)
+	require.False(t, mfa.Enabled)
+	require.Empty(t, mfa.Secret)
+
+	// re-enrolling replaces a pending secret
+	require.NoError(t, repo.SavePendingSecret(ctx, userID, "sealed-1"))
+	require.NoError(t, repo.SavePendingSecret(ctx, userID, "sealed-2"))
+	require.NoError(t, repo.Enable(ctx, userID, 100, []string{"hash-a", "hash-b"}))
+
+	mfa, err = repo.GetMFA(ctx, userID)
+	require.NoError(t, err)
+	require.True(t, mfa.Enabled)
+	require.Equal(t, "sealed-2", mfa.Secret)
+	require.Equal(t, int64(100), mfa.LastUsedStep)
+
+	// an enabled enrollment is never overwritten
+	require.Error(t, repo.SavePendingSecret(ctx, userID, "sealed-3"))
+	require.Error(t, repo.Enable(ctx, userID, 101, nil))
+
+	// steps only move forward
+	ok, err := repo.UseStep(ctx, userID, 100)
+	require.NoError(t, err)
+	require.False(t, ok)
+	ok, err = repo.UseStep(ctx, userID, 101)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	ok, err = repo.UseRecoveryCode(ctx, userID, "hash-a")
+	require.NoError(t, err)
+	require.True(t, ok)
+	ok, err = repo.UseRecoveryCode(ctx, userID, "hash-a")
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	require.NoError(t, repo.Delete(ctx, userID))
+	mfa, err = repo.GetMFA(ctx, userID)
+	require.NoError(t, err)
+	require.False(t, mfa.Enabled)
+	ok, err = repo.UseRecoveryCode(ctx, userID, "hash-b")
+	require.NoError(t, err)
+	require.False(t, ok)
+}
+
+func TestMFAChallengeStore_Integration(t *testing.T) {
+	client, cleanup := setupRedis(t)
+	defer cleanup()
+
+	store := repository.NewMFAChallengeStore(client, "test-app")
+	ctx := context.Background()
+
+	require.NoError(t, store.Save(ctx, &models.MFAChallenge{ID: "c1", UserID: "u1", EmailVerified: true}, time.Minute))
+	challenge, err := store.Get(ctx, "c1")
+	require.NoError(t, err)
+	require.Equal(t, &models.MFAChallenge{ID: "c1", UserID: "u1", EmailVerified: true}, challenge)
+
+	failures, err := store.RecordFailure(ctx, "c1")
+	require.NoError(t, err)
+	require.Equal(t, int64(1), failures)
+
+	require.NoError(t, store.Delete(ctx, "c1"))
+	challenge, err = store.Get(ctx, "c1")
+	require.NoError(t, err)
+	require.Nil(t, challenge)
+
+	// failures never bring back an expired challenge
+	failures, err = store.RecordFailure(ctx, "c1")
+	require.NoError(t, err)
+	require.Equal(t, int64(-1), failures)
+	require.Equal(t, int64(0), client.Exists(ctx, "test-app:mfa_challenge:c1").Val())
+}
+
+func TestLoginAttemptStore_Integration(t *testing.T) {
+	client, cleanup := setupRedis(t)
+	defer cleanup()
+
+	store := repository.NewLoginAttemptStore(client, "test-app")
+	ctx := context.Background()
+	protection := models.LoginProtection{
+		MaxFailures:      3,
+		Window:           time.Minute,
+		Lockout:          time.Minute,
+		BaseDelay:        time.Second,
+		MaxDelay:         30 * time.Second,
+		StuffingAccounts: 3,
+		StuffingBlock:    time.Hour,
+		DistributedIPs:   2,
+	}
+
+	throttle, err := store.Check(ctx, "acct-1", "10.0.0.1")
+	require.NoError(t, err)
+	require.Nil(t, throttle)
+
+	// the first failure is free, the second one adds the base delay
+	failure, err := store.RecordFailure(ctx, "acct-1", "10.0.0.1", protection)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), failure.Failures)
+	require.Zero(t, failure.RetryAfter)
+
+	failure, err = store.RecordFailure(ctx, "acct-1", "10.0.0.2", protection)
+	require.NoError(t, err)
+	require.Equal(t, time.Second, failure.RetryAfter)
+	require.Equal(t, int64(2), failure.DistinctIPs)
+	require.True(t, failure.Distributed)
+	throttle, err = store.Check(ctx, "acct-1", "10.0.0.1")
+	require.NoError(t, err)
+	require.Equal(t, models.LoginThrottleDelay, throttle.Reason)
+
+	// flagged once, a known IP doesn't flag the account again
+	failure, err = store.RecordFailure(ctx, "acct-1", "10.0.0.1", protection)
+	require.NoError(t, err)
+	require.True(t, failure.Locked)
+	require.False(t, failure.Distributed)
+	throttle, err = store.Check(ctx, "acct-1", "10.0.0.1")
+	require.NoError(t, err)
+	require.Equal(t, models.LoginThrottleLocked, throttle.Reason)
+	require.Greater(t, throttle.RetryAfter, 30*time.Second)
+
+	require.NoError(t, store.Reset(ctx, "acct-1"))
+	throttle, err = store.Check(ctx, "acct-1", "10.0.0.1")
+	require.NoError(t, err)
+	require.Nil(t, throttle)
+
+	// one IP failing on many accounts is blocked once, for every account
+	for _, account := range []string{"acct-2", "acct-3"} {
+		failure, err = store.RecordFailure(ctx, account, "10.0.0.9", protection)
+		require.NoError(t, err)
+		require.False(t, failure.IPBlocked)
+	}
+	failure, err = store.RecordFailure(ctx, "acct-4", "10.0.0.9", protection)
+	require.NoError(t, err)
+	require.True(t, failure.IPBlocked)
+	require.Equal(t, int64(3), failure.DistinctAccounts)
+	failure, err = store.RecordFailure(ctx, "acct-5", "10.0.0.9", protection)
+	require.NoError(t, err)
+	require.False(t, failure.IPBlocked)
+
+	throttle, err = store.Check(ctx, "acct-6", "10.0.0.9")
+	require.NoError(t, err)
+	require.Equal(t, models.LoginThrottleIPBlocked, throttle.Reason)
+}
+
+func TestRateLimiter_Integration(t *testing.T) {
+	client, cleanup := setupRedis(t)
+	defer cleanup()
+
+	limiter := repository.NewRateLimiter(client, "test-app")
+	ctx := context.Background()
+	rule := models.RateLimitRule{Limit: 3, Window: time.Second}
+
+	for i := 0; i < 3; i++ {
+		result, err := limiter.Allow(ctx, "task:user:u1", rule)
+		require.NoError(t, err)
+		require.True(t, result.Allowed)
+		require.Equal(t, 2-i, result.Remaining)
+	}
+
+	result, err := limiter.Allow(ctx, "task:user:u1", rule)
+	require.NoError(t, err)
+	require.False(t, result.Allowed)
+	require.Equal(t, 0, result.Remaining)
+	require.Greater(t, result.Reset, time.Duration(0))
+	require.LessOrEqual(t, result.Reset, time.Second)
+
+	// other keys have their own window
+	result, err = limiter.Allow(ctx, "task:user:u2", rule)
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+
+	// the key always carries an expiry
+	ttl, err := client.PTTL(ctx, "test-app:ratelimit:task:user:u1").Result()
+	require.NoError(t, err)
+	require.Greater(t, ttl, time.Duration(0))
+
+	// rejected requests are not counted, so the window slides open again
+	time.Sleep(rule.Window + 100*time.Millisecond)
+	result, err = limiter.Allow(ctx, "task:user:u1", rule)
+	require.NoError(t, err)
+	require.True(t, result.Allowed)
+}
+
+func TestHealthRepository_Integration(t *testing.T) {
+	conn, cleanupPG := setupPostgres(t)
+	defer cleanupPG()
+	client, cleanupRedis := setupRedis(t)
+	defer cleanupRedis()
+
+	ctx := context.Background()
+	repo := repository.NewHealthRepository(conn, client)
+
+	require.NoError(t, repo.PingPostgres(ctx))
+	require.NoError(t, repo.PingRedis(ctx))
+
+	// init.sql and the code must agree on the schema version
+	version, err := repo.GetSchemaVersion(ctx)
+	require.NoError(t, err)
+	require.Equal(t, service.SchemaVersion, version)
+
+	report := service.NewHealthService(repo, time.Second).CheckReadiness(ctx)
+	require.True(t, report.OK(), "%+v", report.Checks)
+
+	require.NoError(t, client.Close())
+	report = service.NewHealthService(repo, time.Second).CheckReadiness(ctx)
+	require.False(t, report.OK())
 }
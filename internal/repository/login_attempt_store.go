@@ -0,0 +1,173 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/logger"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+)
+
+// recordLoginFailureScript counts a failure, then delays or locks the account,
+// blocks the IP once it has failed on too many accounts and flags the account
+// once it has failed from too many IPs.
+//
+// KEYS[1] account failures, KEYS[2] account wait, KEYS[3] account IPs,
+// KEYS[4] IP accounts, KEYS[5] IP block
+// ARGV[1] account, ARGV[2] ip, ARGV[3] window ms, ARGV[4] max failures,
+// ARGV[5] lockout ms, ARGV[6] base delay ms, ARGV[7] max delay ms,
+// ARGV[8] stuffing accounts (0 = off), ARGV[9] IP block ms,
+// ARGV[10] distributed IPs (0 = off)
+// returns {failures, locked, wait ms, distinct accounts, distinct IPs, ip blocked, distributed}
+var recordLoginFailureScript = redis.NewScript(`
+local window = tonumber(ARGV[3])
+local failures = redis.call('INCR', KEYS[1])
+redis.call('PEXPIRE', KEYS[1], window)
+
+local locked, wait = 0, 0
+if failures >= tonumber(ARGV[4]) then
+  wait = tonumber(ARGV[5])
+  redis.call('SET', KEYS[2], 'locked', 'PX', wait)
+  redis.call('DEL', KEYS[1])
+  locked = 1
+elseif failures >= 2 and redis.call('GET', KEYS[2]) ~= 'locked' then
+  wait = math.min(tonumber(ARGV[6]) * 2 ^ (failures - 2), tonumber(ARGV[7]))
+  if wait > 0 then
+    redis.call('SET', KEYS[2], 'delay', 'PX', wait)
+  end
+end
+
+local newIP = redis.call('SADD', KEYS[3], ARGV[2])
+redis.call('PEXPIRE', KEYS[3], window)
+local ips = redis.call('SCARD', KEYS[3])
+redis.call('SADD', KEYS[4], ARGV[1])
+redis.call('PEXPIRE', KEYS[4], window)
+local accounts = redis.call('SCARD', KEYS[4])
+
+local blocked = 0
+local threshold = tonumber(ARGV[8])
+if threshold > 0 and accounts >= threshold then
+  if redis.call('SET', KEYS[5], 1, 'PX', ARGV[9], 'NX') then
+    blocked = 1
+  end
+end
+-- flagged once per window, when the IP that reaches the threshold is added
+local distributed = 0
+if tonumber(ARGV[10]) > 0 and newIP == 1 and ips == tonumber(ARGV[10]) then
+  distributed = 1
+end
+return {failures, locked, wait, accounts, ips, blocked, distributed}
+`)
+
+type loginAttemptStore struct {
+	redisClient  *redis.Client
+	redisAppName string
+}
+
+// NewLoginAttemptStore constructor for the redis backed login attempt store
+// =========================================================================
+func NewLoginAttemptStore(redisClient *redis.Client, redisAppName string) ports.LoginAttemptStore {
+	logger.Log.Info().Msg("initializing login attempt store")
+	return &loginAttemptStore{
+		redisClient:  redisClient,
+		redisAppName: redisAppName,
+	}
+}
+
+func (s *loginAttemptStore) key(kind string, id string) string {
+	return fmt.Sprintf("%s:login:%s:%s", s.redisAppName, kind, id)
+}
+
+// Check refuse the attempt while the IP is blocked or the account waits
+// =========================================================================
+func (s *loginAttemptStore) Check(ctx context.Context, account string, ip string) (*models.LoginThrottle, error) {
+	var reason *redis.StringCmd
+	var wait, block *redis.DurationCmd
+	_, err := s.redisClient.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		reason = pipe.Get(ctx, s.key("wait", account))
+		wait = pipe.PTTL(ctx, s.key("wait", account))
+		block = pipe.PTTL(ctx, s.key("ip_block", ip))
+		return nil
+	})
+	if err != nil && !errors.Is(err, redis.Nil) {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Msg("failed to check login attempts")
+		return nil, apperror.NewInternalError("unable to check login attempts", err)
+	}
+
+	// PTTL is negative for missing keys
+	if block.Val() > 0 {
+		return &models.LoginThrottle{Reason: models.LoginThrottleIPBlocked, RetryAfter: block.Val()}, nil
+	}
+	if wait.Val() > 0 {
+		throttle := &models.LoginThrottle{Reason: models.LoginThrottleDelay, RetryAfter: wait.Val()}
+		if reason.Val() == "locked" {
+			throttle.Reason = models.LoginThrottleLocked
+		}
+		return throttle, nil
+	}
+	return nil, nil
+}
+
+// RecordFailure count a failed login against the account and the IP
+// =========================================================================
+func (s *loginAttemptStore) RecordFailure(ctx context.Context, account string, ip string, protection models.LoginProtection) (*models.LoginFailure, error) {
+	res, err := recordLoginFailureScript.Run(ctx, s.redisClient,
+		[]string{
+			s.key("failures", account),
+			s.key("wait", account),
+			s.key("account_ips", account),
+			s.key("ip_accounts", ip),
+			s.key("ip_block", ip),
+		},
+		account,
+		ip,
+		protection.Window.Milliseconds(),
+		protection.MaxFailures,
+		protection.Lockout.Milliseconds(),
+		protection.BaseDelay.Milliseconds(),
+		protection.MaxDelay.Milliseconds(),
+		protection.StuffingAccounts,
+		protection.StuffingBlock.Milliseconds(),
+		protection.DistributedIPs,
+	).Int64Slice()
+	if err == nil && len(res) != 7 {
+		err = fmt.Errorf("unexpected login failure reply of length %d", len(res))
+	}
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Msg("failed to record login failure")
+		return nil, apperror.NewInternalError("unable to record login failure", err)
+	}
+
+	return &models.LoginFailure{
+		Failures:         res[0],
+		Locked:           res[1] == 1,
+		RetryAfter:       time.Duration(res[2]) * time.Millisecond,
+		DistinctAccounts: res[3],
+		DistinctIPs:      res[4],
+		IPBlocked:        res[5] == 1,
+		Distributed:      res[6] == 1,
+	}, nil
+}
+
+// Reset forget the account's failures, the IP keeps its history
+// =========================================================================
+func (s *loginAttemptStore) Reset(ctx context.Context, account string) error {
+	err := s.redisClient.Del(ctx, s.key("failures", account), s.key("wait", account), s.key("account_ips", account)).Err()
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Msg("failed to reset login attempts")
+		return apperror.NewInternalError("unable to reset login attempts", err)
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+	"github.com/suryansh74/task-management-api-project/internal/logger"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+)
+
+// deadLetterLimit keeps the dead letter list from growing without bound
+const deadLetterLimit = 1000
+
+// leaseDueScript pushes due members back by the lease instead of removing
+// them, so a member is handed to one replica at a time and comes back if
+// that replica never finishes it
+var leaseDueScript = redis.NewScript(`
+local items = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, ARGV[2])
+for _, item in ipairs(items) do
+	redis.call('ZADD', KEYS[1], 'XX', ARGV[3], item)
+end
+return items
+`)
+
+type mailQueue struct {
+	redisClient  *redis.Client
+	key          string
+	deadKey      string
+	claimTimeout time.Duration
+}
+
+// NewMailQueue sorted set of JSON jobs scored by send time (unix seconds),
+// claimed jobs are leased for claimTimeout
+// =========================================================================
+func NewMailQueue(redisClient *redis.Client, redisAppName string, claimTimeout time.Duration) ports.MailQueue {
+	logger.Log.Info().
+		Dur("claim_timeout", claimTimeout).
+		Msg("initializing mail queue")
+	return &mailQueue{
+		redisClient:  redisClient,
+		key:          fmt.Sprintf("%s:mail:queue", redisAppName),
+		deadKey:      fmt.Sprintf("%s:mail:dead", redisAppName),
+		claimTimeout: claimTimeout,
+	}
+}
+
+// Enqueue add job to the queue
+// =========================================================================
+func (q *mailQueue) Enqueue(ctx context.Context, job *models.MailJob, sendAt time.Time) error {
+	payload, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	pipe := q.redisClient.TxPipeline()
+	if job.Receipt != "" {
+		pipe.ZRem(ctx, q.key, job.Receipt)
+	}
+	pipe.ZAdd(ctx, q.key, redis.Z{
+		Score:  float64(sendAt.Unix()),
+		Member: payload,
+	})
+	if _, err := pipe.Exec(ctx); err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("job_id", job.ID).
+			Msg("failed to enqueue email")
+		return err
+	}
+	job.Receipt = string(payload)
+	return nil
+}
+
+// ClaimDue atomically lease due jobs
+// =========================================================================
+func (q *mailQueue) ClaimDue(ctx context.Context, now time.Time, limit int) ([]*models.MailJob, error) {
+	leaseEnd := now.Add(q.claimTimeout).Unix()
+	members, err := leaseDueScript.Run(ctx, q.redisClient, []string{q.key}, now.Unix(), limit, leaseEnd).StringSlice()
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Msg("failed to claim due emails")
+		return nil, err
+	}
+
+	jobs := make([]*models.MailJob, 0, len(members))
+	for _, member := range members {
+		var job models.MailJob
+		if err := json.Unmarshal([]byte(member), &job); err != nil {
+			zerolog.Ctx(ctx).Error().
+				Err(err).
+				Msg("dropping malformed mail job")
+			q.redisClient.ZRem(ctx, q.key, member)
+			continue
+		}
+		job.Receipt = member
+		jobs = append(jobs, &job)
+	}
+	return jobs, nil
+}
+
+// Ack remove a sent job
+// =========================================================================
+func (q *mailQueue) Ack(ctx context.Context, job *models.MailJob) error {
+	if err := q.redisClient.ZRem(ctx, q.key, job.Receipt).Err(); err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("job_id", job.ID).
+			Msg("failed to ack email")
+		return err
+	}
+	return nil
+}
+
+// DeadLetter park job for inspection
+// =========================================================================
+func (q *mailQueue) DeadLetter(ctx context.Context, job *models.MailJob) error {
+	payload, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	pipe := q.redisClient.TxPipeline()
+	if job.Receipt != "" {
+		pipe.ZRem(ctx, q.key, job.Receipt)
+	}
+	pipe.LPush(ctx, q.deadKey, payload)
+	pipe.LTrim(ctx, q.deadKey, 0, deadLetterLimit-1)
+	if _, err := pipe.Exec(ctx); err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("job_id", job.ID).
+			Msg("failed to dead letter email")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+	"github.com/suryansh74/task-management-api-project/internal/logger"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+)
+
+type logMailer struct{}
+
+// NewLogMailer writes emails to the log instead of sending them (SMTP_HOST unset)
+// =========================================================================
+func NewLogMailer() ports.Mailer {
+	logger.Log.Warn().Msg("initializing log mailer, emails will not be delivered")
+	return &logMailer{}
+}
+
+// Send log the email
+// =========================================================================
+func (m *logMailer) Send(ctx context.Context, email *models.Email) error {
+	zerolog.Ctx(ctx).Info().
+		Strs("to", email.To).
+		Str("subject", email.Subject).
+		Str("text", email.Text).
+		Msg("email (not sent, log mailer)")
+	return nil
+}
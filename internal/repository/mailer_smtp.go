@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/suryansh74/task-management-api-project/internal/logger"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+	"github.com/suryansh74/task-management-api-project/internal/utils"
+)
+
+type smtpMailer struct {
+	addr     string
+	host     string
+	username string
+	password string
+	from     string
+	timeout  time.Duration
+}
+
+// NewSMTPMailer sends mail through an SMTP relay (STARTTLS when offered,
+// PLAIN auth when username is set). MailHog works for local development.
+// A send, dial included, gives up after timeout or when its ctx ends.
+// =========================================================================
+func NewSMTPMailer(host, port, username, password, from string, timeout time.Duration) ports.Mailer {
+	logger.Log.Info().
+		Str("smtp_host", host).
+		Str("smtp_port", port).
+		Dur("smtp_timeout", timeout).
+		Msg("initializing smtp mailer")
+	return &smtpMailer{
+		addr:     net.JoinHostPort(host, port),
+		host:     host,
+		username: username,
+		password: password,
+		from:     from,
+		timeout:  timeout,
+	}
+}
+
+// Send deliver one email
+// =========================================================================
+func (m *smtpMailer) Send(ctx context.Context, email *models.Email) error {
+	msg, err := buildMIMEMessage(m.from, email)
+	if err != nil {
+		return err
+	}
+
+	if err := m.send(ctx, email.To, msg); err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Strs("to", email.To).
+			Msg("smtp send failed")
+		return err
+	}
+
+	zerolog.Ctx(ctx).Debug().
+		Strs("to", email.To).
+		Str("subject", email.Subject).
+		Msg("email sent")
+	return nil
+}
+
+// send the smtp.SendMail conversation over a connection bounded by the
+// timeout and ctx, a stalled relay can't hold the mail worker forever
+func (m *smtpMailer) send(ctx context.Context, to []string, msg []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, m.timeout)
+	defer cancel()
+
+	dialer := net.Dialer{Timeout: m.timeout}
+	conn, err := dialer.DialContext(ctx, "tcp", m.addr)
+	if err != nil {
+		return err
+	}
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return err
+	}
+	// a cancelled ctx fails the blocked read or write right away
+	stop := context.AfterFunc(ctx, func() { conn.SetDeadline(time.Now()) })
+	defer stop()
+
+	c, err := smtp.NewClient(conn, m.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if ok, _ := c.Extension("STARTTLS"); ok {
+		if err := c.StartTLS(&tls.Config{ServerName: m.host}); err != nil {
+			return err
+		}
+	}
+	if m.username != "" {
+		if ok, _ := c.Extension("AUTH"); !ok {
+			return errors.New("smtp: server doesn't support AUTH")
+		}
+		if err := c.Auth(smtp.PlainAuth("", m.username, m.password, m.host)); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(m.from); err != nil {
+		return err
+	}
+	for _, addr := range to {
+		if err := c.Rcpt(addr); err != nil {
+			return err
+		}
+	}
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
+
+// buildMIMEMessage multipart/alternative with text and optional HTML parts
+func buildMIMEMessage(from string, email *models.Email) ([]byte, error) {
+	var buf bytes.Buffer
+	// subjects and addresses may carry user input, never let it add headers
+	clean := strings.NewReplacer("\r", "", "\n", " ")
+
+	fmt.Fprintf(&buf, "From: %s\r\n", clean.Replace(from))
+	fmt.Fprintf(&buf, "To: %s\r\n", clean.Replace(strings.Join(email.To, ", ")))
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", clean.Replace(email.Subject)))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&buf, "Message-ID: <%s@%s>\r\n", utils.MustRandomID(), messageIDHost(from))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+
+	mw := multipart.NewWriter(&buf)
+	fmt.Fprintf(&buf, "Content-Type: multipart/alternative; boundary=%q\r\n\r\n", mw.Boundary())
+
+	parts := []struct{ contentType, body string }{{"text/plain; charset=UTF-8", email.Text}}
+	if email.HTML != "" {
+		parts = append(parts, struct{ contentType, body string }{"text/html; charset=UTF-8", email.HTML})
+	}
+	for _, part := range parts {
+		w, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, err
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write([]byte(part.body)); err != nil {
+			return nil, err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, err
+		}
+	}
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func messageIDHost(from string) string {
+	if _, host, ok := strings.Cut(from, "@"); ok {
+		return strings.Trim(host, "> ")
+	}
+	return "localhost"
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/logger"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+)
+
+// recordMFAFailureScript counts a failed code without resurrecting an expired challenge.
+//
+// KEYS[1] challenge key
+// returns the failures so far, -1 when the challenge is gone
+var recordMFAFailureScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+  return -1
+end
+return redis.call('HINCRBY', KEYS[1], 'failures', 1)
+`)
+
+type mfaChallengeStore struct {
+	redisClient  *redis.Client
+	redisAppName string
+}
+
+// NewMFAChallengeStore constructor for the redis backed mfa challenge store
+// =========================================================================
+func NewMFAChallengeStore(redisClient *redis.Client, redisAppName string) ports.MFAChallengeStore {
+	logger.Log.Info().Msg("initializing mfa challenge store")
+	return &mfaChallengeStore{
+		redisClient:  redisClient,
+		redisAppName: redisAppName,
+	}
+}
+
+func (s *mfaChallengeStore) key(id string) string {
+	return fmt.Sprintf("%s:mfa_challenge:%s", s.redisAppName, id)
+}
+
+// Save store the challenge until ttl
+// =========================================================================
+func (s *mfaChallengeStore) Save(ctx context.Context, challenge *models.MFAChallenge, ttl time.Duration) error {
+	key := s.key(challenge.ID)
+	_, err := s.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key, *challenge)
+		pipe.Expire(ctx, key, ttl)
+		return nil
+	})
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("user_id", challenge.UserID).
+			Msg("failed to save mfa challenge")
+		return apperror.NewInternalError("unable to start two-factor login", err)
+	}
+	return nil
+}
+
+// Get load the challenge, nil when expired
+// =========================================================================
+func (s *mfaChallengeStore) Get(ctx context.Context, id string) (*models.MFAChallenge, error) {
+	var challenge models.MFAChallenge
+	res := s.redisClient.HGetAll(ctx, s.key(id))
+	if err := res.Err(); err != nil && !errors.Is(err, redis.Nil) {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Msg("failed to load mfa challenge")
+		return nil, apperror.NewInternalError("unable to load two-factor login", err)
+	}
+	if len(res.Val()) == 0 {
+		return nil, nil
+	}
+	if err := res.Scan(&challenge); err != nil {
+		return nil, apperror.NewInternalError("unable to load two-factor login", err)
+	}
+	return &challenge, nil
+}
+
+// RecordFailure count a wrong code against the challenge
+// =========================================================================
+func (s *mfaChallengeStore) RecordFailure(ctx context.Context, id string) (int64, error) {
+	failures, err := recordMFAFailureScript.Run(ctx, s.redisClient, []string{s.key(id)}).Int64()
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Msg("failed to record mfa failure")
+		return 0, apperror.NewInternalError("unable to check two-factor code", err)
+	}
+	return failures, nil
+}
+
+// Delete end the challenge
+// =========================================================================
+func (s *mfaChallengeStore) Delete(ctx context.Context, id string) error {
+	if err := s.redisClient.Unlink(ctx, s.key(id)).Err(); err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Msg("failed to delete mfa challenge")
+		return apperror.NewInternalError("unable to finish two-factor login", err)
+	}
+	return nil
+}
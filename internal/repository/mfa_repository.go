@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/logger"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+)
+
+type mfaRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewMFARepository(db *pgxpool.Pool) ports.MFARepository {
+	logger.Log.Info().Msg("initializing mfa repository")
+	return &mfaRepository{db: db}
+}
+
+// GetMFA get the user's enrollment, empty when never enrolled
+// =========================================================================
+func (mr *mfaRepository) GetMFA(ctx context.Context, userID string) (*models.UserMFA, error) {
+	mfa := &models.UserMFA{UserID: userID}
+	err := mr.db.QueryRow(ctx,
+		`SELECT secret, enabled_at, last_used_step FROM user_mfa WHERE user_id = $1`,
+		userID,
+	).Scan(&mfa.Secret, &mfa.EnabledAt, &mfa.LastUsedStep)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return mfa, nil
+	}
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("user_id", userID).
+			Msg("failed to fetch mfa enrollment")
+		return nil, apperror.NewInternalError("Failed to fetch two-factor settings", err)
+	}
+	mfa.Enabled = mfa.EnabledAt != nil
+	return mfa, nil
+}
+
+// SavePendingSecret create or replace a pending enrollment, never an enabled one
+// =========================================================================
+func (mr *mfaRepository) SavePendingSecret(ctx context.Context, userID string, secret string) error {
+	zerolog.Ctx(ctx).Debug().
+		Str("user_id", userID).
+		Msg("saving pending mfa secret")
+
+	cmd, err := mr.db.Exec(ctx,
+		`INSERT INTO user_mfa (user_id, secret)
+		 VALUES ($1, $2)
+		 ON CONFLICT (user_id) DO UPDATE
+		 SET secret = EXCLUDED.secret,
+		     last_used_step = 0,
+		     created_at = NOW()
+		 WHERE user_mfa.enabled_at IS NULL`,
+		userID,
+		secret,
+	)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("user_id", userID).
+			Msg("failed to save pending mfa secret")
+		return apperror.NewInternalError("Failed to start two-factor enrollment", err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return apperror.NewConflictError("two-factor authentication is already enabled")
+	}
+	return nil
+}
+
+// Enable turn on a pending enrollment and store its recovery codes in one transaction
+// =========================================================================
+func (mr *mfaRepository) Enable(ctx context.Context, userID string, step int64, recoveryCodeHashes []string) error {
+	zerolog.Ctx(ctx).Debug().
+		Str("user_id", userID).
+		Int("recovery_codes", len(recoveryCodeHashes)).
+		Msg("enabling mfa")
+
+	tx, err := mr.db.Begin(ctx)
+	if err != nil {
+		return apperror.NewInternalError("Failed to enable two-factor authentication", err)
+	}
+	defer tx.Rollback(ctx)
+
+	cmd, err := tx.Exec(ctx,
+		`UPDATE user_mfa SET enabled_at = NOW(), last_used_step = $2
+		 WHERE user_id = $1 AND enabled_at IS NULL`,
+		userID,
+		step,
+	)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("user_id", userID).
+			Msg("failed to enable mfa")
+		return apperror.NewInternalError("Failed to enable two-factor authentication", err)
+	}
+	if cmd.RowsAffected() == 0 {
+		// confirmed concurrently, or the enrollment was never started
+		return apperror.NewConflictError("two-factor authentication is already enabled or not enrolled")
+	}
+
+	if _, err := tx.Exec(ctx, `DELETE FROM mfa_recovery_codes WHERE user_id = $1`, userID); err != nil {
+		return apperror.NewInternalError("Failed to enable two-factor authentication", err)
+	}
+	_, err = tx.Exec(ctx,
+		`INSERT INTO mfa_recovery_codes (user_id, code_hash)
+		 SELECT $1, unnest($2::text[])`,
+		userID,
+		recoveryCodeHashes,
+	)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("user_id", userID).
+			Msg("failed to store recovery codes")
+		return apperror.NewInternalError("Failed to enable two-factor authentication", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return apperror.NewInternalError("Failed to enable two-factor authentication", err)
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("user_id", userID).
+		Msg("mfa enabled successfully")
+	return nil
+}
+
+// UseStep move last_used_step forward, each code is accepted once
+// =========================================================================
+func (mr *mfaRepository) UseStep(ctx context.Context, userID string, step int64) (bool, error) {
+	cmd, err := mr.db.Exec(ctx,
+		`UPDATE user_mfa SET last_used_step = $2
+		 WHERE user_id = $1 AND enabled_at IS NOT NULL AND last_used_step < $2`,
+		userID,
+		step,
+	)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("user_id", userID).
+			Msg("failed to record totp step")
+		return false, apperror.NewInternalError("Failed to check two-factor code", err)
+	}
+	return cmd.RowsAffected() == 1, nil
+}
+
+// UseRecoveryCode mark an unused code used
+// =========================================================================
+func (mr *mfaRepository) UseRecoveryCode(ctx context.Context, userID string, codeHash string) (bool, error) {
+	cmd, err := mr.db.Exec(ctx,
+		`UPDATE mfa_recovery_codes SET used_at = NOW()
+		 WHERE user_id = $1 AND code_hash = $2 AND used_at IS NULL`,
+		userID,
+		codeHash,
+	)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("user_id", userID).
+			Msg("failed to use recovery code")
+		return false, apperror.NewInternalError("Failed to check recovery code", err)
+	}
+	return cmd.RowsAffected() == 1, nil
+}
+
+// Delete drop the enrollment, recovery codes go with it
+// =========================================================================
+func (mr *mfaRepository) Delete(ctx context.Context, userID string) error {
+	_, err := mr.db.Exec(ctx, `DELETE FROM user_mfa WHERE user_id = $1`, userID)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("user_id", userID).
+			Msg("failed to delete mfa enrollment")
+		return apperror.NewInternalError("Failed to disable two-factor authentication", err)
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("user_id", userID).
+		Msg("mfa disabled successfully")
+	return nil
+}
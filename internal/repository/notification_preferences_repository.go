@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/logger"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+)
+
+type notificationPreferencesRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewNotificationPreferencesRepository(db *pgxpool.Pool) ports.NotificationPreferencesRepository {
+	logger.Log.Info().Msg("initializing notification preferences repository")
+	return &notificationPreferencesRepository{db: db}
+}
+
+// GetPreferences get preferences, defaults when never saved
+// =========================================================================
+func (pr *notificationPreferencesRepository) GetPreferences(ctx context.Context, userID string) (*models.NotificationPreferences, error) {
+	zerolog.Ctx(ctx).Debug().
+		Str("user_id", userID).
+		Msg("fetching notification preferences")
+
+	prefs := new(models.NotificationPreferences)
+	err := pr.db.QueryRow(ctx,
+		`SELECT user_id, email_enabled, reminders, invitations, updated_at
+		 FROM notification_preferences WHERE user_id = $1`,
+		userID,
+	).Scan(
+		&prefs.UserID,
+		&prefs.EmailEnabled,
+		&prefs.Reminders,
+		&prefs.Invitations,
+		&prefs.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return models.DefaultNotificationPreferences(userID), nil
+	}
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("user_id", userID).
+			Msg("failed to fetch notification preferences")
+		return nil, apperror.NewInternalError("Failed to fetch notification preferences", err)
+	}
+	return prefs, nil
+}
+
+// UpsertPreferences save preferences
+// =========================================================================
+func (pr *notificationPreferencesRepository) UpsertPreferences(ctx context.Context, prefs *models.NotificationPreferences) error {
+	zerolog.Ctx(ctx).Debug().
+		Str("user_id", prefs.UserID).
+		Msg("saving notification preferences")
+
+	err := pr.db.QueryRow(ctx,
+		`INSERT INTO notification_preferences (user_id, email_enabled, reminders, invitations)
+		 VALUES ($1, $2, $3, $4)
+		 ON CONFLICT (user_id) DO UPDATE
+		 SET email_enabled = EXCLUDED.email_enabled,
+		     reminders = EXCLUDED.reminders,
+		     invitations = EXCLUDED.invitations,
+		     updated_at = NOW()
+		 RETURNING updated_at`,
+		prefs.UserID,
+		prefs.EmailEnabled,
+		prefs.Reminders,
+		prefs.Invitations,
+	).Scan(&prefs.UpdatedAt)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("user_id", prefs.UserID).
+			Msg("failed to save notification preferences")
+		return apperror.NewInternalError("Failed to save notification preferences", err)
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("user_id", prefs.UserID).
+		Msg("notification preferences saved successfully")
+	return nil
+}
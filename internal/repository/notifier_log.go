@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+	"github.com/suryansh74/task-management-api-project/internal/logger"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+)
+
+type logNotifier struct{}
+
+// NewLogNotifier writes reminders to the application log
+// =========================================================================
+func NewLogNotifier() ports.Notifier {
+	logger.Log.Info().Msg("initializing log notifier")
+	return &logNotifier{}
+}
+
+func (n *logNotifier) Channel() string {
+	return models.ReminderChannelLog
+}
+
+// Notify log the reminder
+// =========================================================================
+func (n *logNotifier) Notify(ctx context.Context, reminder *models.DueReminder) error {
+	zerolog.Ctx(ctx).Info().
+		Str("reminder_id", reminder.ReminderID).
+		Str("task_id", reminder.TaskID).
+		Str("user_id", reminder.UserID).
+		Str("title", reminder.TaskTitle).
+		Time("due_at", reminder.DueAt).
+		Msg("task reminder")
+	return nil
+}
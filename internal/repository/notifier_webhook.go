@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/suryansh74/task-management-api-project/internal/logger"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+	"github.com/suryansh74/task-management-api-project/internal/utils"
+)
+
+type webhookNotifier struct {
+	client *http.Client
+	secret string
+}
+
+// errWebhookRedirect redirects are not followed, a public URL could point
+// the request at an internal one
+var errWebhookRedirect = errors.New("webhook redirects are not followed")
+
+// NewWebhookNotifier POSTs reminders as JSON to the reminder's target URL.
+// Requests carry X-Reminder-Timestamp and X-Reminder-Signature
+// (hex HMAC-SHA256 of "timestamp|body") when secret is set.
+// Targets are user supplied, so connections to loopback, private and
+// link-local addresses are refused after DNS resolution.
+// =========================================================================
+func NewWebhookNotifier(secret string, timeout time.Duration) ports.Notifier {
+	logger.Log.Info().
+		Dur("timeout", timeout).
+		Bool("signed", secret != "").
+		Msg("initializing webhook notifier")
+	dialer := &net.Dialer{Timeout: timeout, Control: utils.PublicDialControl}
+	return &webhookNotifier{
+		client: &http.Client{
+			Timeout: timeout,
+			// no Proxy: the dial check would only see the proxy's address
+			Transport: &http.Transport{
+				DialContext:         dialer.DialContext,
+				ForceAttemptHTTP2:   true,
+				MaxIdleConns:        10,
+				IdleConnTimeout:     90 * time.Second,
+				TLSHandshakeTimeout: timeout,
+			},
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return errWebhookRedirect
+			},
+		},
+		secret: secret,
+	}
+}
+
+func (n *webhookNotifier) Channel() string {
+	return models.ReminderChannelWebhook
+}
+
+// Notify post the reminder to the webhook
+// =========================================================================
+func (n *webhookNotifier) Notify(ctx context.Context, reminder *models.DueReminder) error {
+	body, err := json.Marshal(reminder)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, reminder.Target, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if n.secret != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set("X-Reminder-Timestamp", timestamp)
+		req.Header.Set("X-Reminder-Signature", utils.SignPayload(n.secret, timestamp, string(body)))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("reminder_id", reminder.ReminderID).
+			Msg("webhook delivery failed")
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		zerolog.Ctx(ctx).Warn().
+			Str("reminder_id", reminder.ReminderID).
+			Int("status", resp.StatusCode).
+			Msg("webhook rejected reminder")
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+
+	zerolog.Ctx(ctx).Debug().
+		Str("reminder_id", reminder.ReminderID).
+		Int("status", resp.StatusCode).
+		Msg("webhook delivered reminder")
+	return nil
+}
@@ -0,0 +1,35 @@
+package repository_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/suryansh74/task-management-api-project/internal/models"
+	"github.com/suryansh74/task-management-api-project/internal/repository"
+	"github.com/suryansh74/task-management-api-project/internal/utils"
+)
+
+func TestWebhookNotifier_RefusesInternalTargets(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+	}))
+	defer srv.Close()
+
+	notifier := repository.NewWebhookNotifier("secret", time.Second)
+	for _, target := range []string{srv.URL, "http://localhost:1/hook"} {
+		err := notifier.Notify(context.Background(), &models.DueReminder{
+			ReminderID: "rem-1",
+			Channel:    models.ReminderChannelWebhook,
+			Target:     target,
+		})
+		require.ErrorIs(t, err, utils.ErrNonPublicAddress, target)
+	}
+	require.Zero(t, hits.Load(), "the loopback server must never be reached")
+}
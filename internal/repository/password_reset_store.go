@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/logger"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+)
+
+// savePasswordResetScript replaces the user's token.
+//
+// KEYS[1] user key, KEYS[2] token key
+// ARGV[1] token key prefix, ARGV[2] user id, ARGV[3] token hash, ARGV[4] ttl in ms
+var savePasswordResetScript = redis.NewScript(`
+local old = redis.call('GET', KEYS[1])
+if old then
+  redis.call('DEL', ARGV[1] .. old)
+end
+redis.call('SET', KEYS[2], ARGV[2], 'PX', ARGV[4])
+redis.call('SET', KEYS[1], ARGV[3], 'PX', ARGV[4])
+return 1
+`)
+
+// consumePasswordResetScript deletes the token and returns its user.
+//
+// KEYS[1] token key, ARGV[1] user key prefix, ARGV[2] token hash
+// returns the user id or nil
+var consumePasswordResetScript = redis.NewScript(`
+local user = redis.call('GETDEL', KEYS[1])
+if not user then
+  return false
+end
+local userKey = ARGV[1] .. user
+if redis.call('GET', userKey) == ARGV[2] then
+  redis.call('DEL', userKey)
+end
+return user
+`)
+
+type passwordResetStore struct {
+	redisClient  *redis.Client
+	redisAppName string
+}
+
+// NewPasswordResetStore constructor for the redis backed password reset store
+// =========================================================================
+func NewPasswordResetStore(redisClient *redis.Client, redisAppName string) ports.PasswordResetStore {
+	logger.Log.Info().Msg("initializing password reset store")
+	return &passwordResetStore{
+		redisClient:  redisClient,
+		redisAppName: redisAppName,
+	}
+}
+
+func (s *passwordResetStore) tokenKeyPrefix() string {
+	return fmt.Sprintf("%s:password_reset:token:", s.redisAppName)
+}
+
+func (s *passwordResetStore) userKeyPrefix() string {
+	return fmt.Sprintf("%s:password_reset:user:", s.redisAppName)
+}
+
+// Save store the token hash, replacing the user's previous token
+// =========================================================================
+func (s *passwordResetStore) Save(ctx context.Context, userID string, tokenHash string, ttl time.Duration) error {
+	err := savePasswordResetScript.Run(ctx, s.redisClient,
+		[]string{s.userKeyPrefix() + userID, s.tokenKeyPrefix() + tokenHash},
+		s.tokenKeyPrefix(),
+		userID,
+		tokenHash,
+		ttl.Milliseconds(),
+	).Err()
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("user_id", userID).
+			Msg("failed to save password reset token")
+		return apperror.NewInternalError("unable to save password reset token", err)
+	}
+
+	zerolog.Ctx(ctx).Debug().
+		Str("user_id", userID).
+		Dur("ttl", ttl).
+		Msg("password reset token saved")
+	return nil
+}
+
+// Consume delete the token, a token works exactly once
+// =========================================================================
+func (s *passwordResetStore) Consume(ctx context.Context, tokenHash string) (string, error) {
+	userID, err := consumePasswordResetScript.Run(ctx, s.redisClient,
+		[]string{s.tokenKeyPrefix() + tokenHash},
+		s.userKeyPrefix(),
+		tokenHash,
+	).Text()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Msg("failed to consume password reset token")
+		return "", apperror.NewInternalError("unable to check password reset token", err)
+	}
+	return userID, nil
+}
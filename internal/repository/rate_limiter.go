@@ -0,0 +1,80 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/suryansh74/task-management-api-project/internal/logger"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+	"github.com/suryansh74/task-management-api-project/internal/utils"
+)
+
+// slidingWindowScript keeps one sorted set entry per allowed request, scored
+// by its time in ms. Pruning, counting, adding and the expiry happen in one
+// atomic step; rejected requests are not recorded. The clock is Redis's, so
+// app replicas with skewed clocks agree.
+//
+// KEYS[1] key, ARGV[1] limit, ARGV[2] window ms, ARGV[3] unique member
+// returns {allowed, remaining, reset ms}
+var slidingWindowScript = redis.NewScript(`
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+local limit = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+
+redis.call('ZREMRANGEBYSCORE', KEYS[1], '-inf', now - window)
+local count = redis.call('ZCARD', KEYS[1])
+local allowed = 0
+if count < limit then
+	redis.call('ZADD', KEYS[1], now, now .. '-' .. ARGV[3])
+	count = count + 1
+	allowed = 1
+end
+redis.call('PEXPIRE', KEYS[1], window)
+
+local reset = window
+local oldest = redis.call('ZRANGE', KEYS[1], 0, 0, 'WITHSCORES')
+if oldest[2] then
+	reset = tonumber(oldest[2]) + window - now
+end
+return {allowed, limit - count, reset}
+`)
+
+type rateLimiter struct {
+	redisClient  *redis.Client
+	redisAppName string
+}
+
+// NewRateLimiter constructor for the redis sliding window rate limiter
+// =========================================================================
+func NewRateLimiter(redisClient *redis.Client, redisAppName string) ports.RateLimiter {
+	logger.Log.Info().Msg("initializing rate limiter")
+	return &rateLimiter{
+		redisClient:  redisClient,
+		redisAppName: redisAppName,
+	}
+}
+
+// Allow record one request for key unless the window is full
+// =========================================================================
+func (rl *rateLimiter) Allow(ctx context.Context, key string, rule models.RateLimitRule) (*models.RateLimitResult, error) {
+	res, err := slidingWindowScript.Run(ctx, rl.redisClient,
+		[]string{fmt.Sprintf("%s:ratelimit:%s", rl.redisAppName, key)},
+		rule.Limit,
+		rule.Window.Milliseconds(),
+		utils.MustRandomID(),
+	).Int64Slice()
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.RateLimitResult{
+		Allowed:   res[0] == 1,
+		Limit:     rule.Limit,
+		Remaining: int(res[1]),
+		Reset:     time.Duration(res[2]) * time.Millisecond,
+	}, nil
+}
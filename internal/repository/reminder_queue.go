@@ -0,0 +1,89 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+	"github.com/suryansh74/task-management-api-project/internal/logger"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+)
+
+// claimDueScript pops due members in one step, so a member is only ever
+// handed to one replica
+var claimDueScript = redis.NewScript(`
+local items = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, ARGV[2])
+if #items > 0 then
+	redis.call('ZREM', KEYS[1], unpack(items))
+end
+return items
+`)
+
+type reminderQueue struct {
+	redisClient *redis.Client
+	key         string
+}
+
+// NewReminderQueue sorted set scored by fire time (unix seconds)
+// =========================================================================
+func NewReminderQueue(redisClient *redis.Client, redisAppName string) ports.ReminderQueue {
+	logger.Log.Info().Msg("initializing reminder queue")
+	return &reminderQueue{
+		redisClient: redisClient,
+		key:         fmt.Sprintf("%s:reminders:due", redisAppName),
+	}
+}
+
+// Schedule add a reminder, members carry the fire time so a moved due date is a new entry
+// =========================================================================
+func (q *reminderQueue) Schedule(ctx context.Context, reminderID string, fireAt time.Time) error {
+	err := q.redisClient.ZAddNX(ctx, q.key, redis.Z{
+		Score:  float64(fireAt.Unix()),
+		Member: fmt.Sprintf("%s|%d", reminderID, fireAt.Unix()),
+	}).Err()
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("reminder_id", reminderID).
+			Time("fire_at", fireAt).
+			Msg("failed to schedule reminder")
+		return err
+	}
+	return nil
+}
+
+// ClaimDue atomically take due reminders off the queue
+// =========================================================================
+func (q *reminderQueue) ClaimDue(ctx context.Context, now time.Time, limit int) ([]ports.ReminderJob, error) {
+	members, err := claimDueScript.Run(ctx, q.redisClient, []string{q.key}, now.Unix(), limit).StringSlice()
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Msg("failed to claim due reminders")
+		return nil, err
+	}
+
+	jobs := make([]ports.ReminderJob, 0, len(members))
+	for _, member := range members {
+		id, unix, ok := strings.Cut(member, "|")
+		if !ok {
+			continue
+		}
+		sec, err := strconv.ParseInt(unix, 10, 64)
+		if err != nil {
+			continue
+		}
+		jobs = append(jobs, ports.ReminderJob{ReminderID: id, FireAt: time.Unix(sec, 0)})
+	}
+
+	if len(jobs) > 0 {
+		zerolog.Ctx(ctx).Debug().
+			Int("claimed", len(jobs)).
+			Msg("claimed due reminders")
+	}
+	return jobs, nil
+}
@@ -0,0 +1,284 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/logger"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+)
+
+// dueReminderSelect resolves reminders against their task, fire_at = due_at - offset
+const dueReminderSelect = `SELECT r.id, r.task_id, r.user_id, t.title, r.channel, COALESCE(r.target, ''), u.email,
+	        t.due_at, t.due_at - r.offset_seconds * INTERVAL '1 second' AS fire_at
+	 FROM task_reminders r
+	 JOIN tasks t ON t.id = r.task_id
+	 JOIN users u ON u.id = r.user_id
+	 WHERE t.due_at IS NOT NULL AND t.status <> 'done'`
+
+type reminderRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewReminderRepository(db *pgxpool.Pool) ports.ReminderRepository {
+	logger.Log.Info().Msg("initializing reminder repository")
+	return &reminderRepository{db: db}
+}
+
+// CreateReminder stores a reminder rule
+// =========================================================================
+func (rr *reminderRepository) CreateReminder(ctx context.Context, reminder *models.Reminder) (string, error) {
+	zerolog.Ctx(ctx).Debug().
+		Str("task_id", reminder.TaskID).
+		Int64("offset_seconds", reminder.OffsetSeconds).
+		Str("channel", reminder.Channel).
+		Msg("creating reminder")
+
+	var id string
+	err := rr.db.QueryRow(ctx,
+		`INSERT INTO task_reminders (task_id, user_id, offset_seconds, channel, target)
+		 VALUES ($1, $2, $3, $4, NULLIF($5, '')) RETURNING id, created_at`,
+		reminder.TaskID,
+		reminder.UserID,
+		reminder.OffsetSeconds,
+		reminder.Channel,
+		reminder.Target,
+	).Scan(&id, &reminder.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			zerolog.Ctx(ctx).Warn().
+				Str("task_id", reminder.TaskID).
+				Msg("duplicate reminder")
+			return "", apperror.NewConflictError("reminder already exists for this task")
+		}
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("task_id", reminder.TaskID).
+			Msg("failed to create reminder")
+		return "", apperror.NewInternalError("Failed to save reminder", err)
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("reminder_id", id).
+		Str("task_id", reminder.TaskID).
+		Msg("reminder created successfully")
+	return id, nil
+}
+
+// GetReminderByID get reminder rule by id
+// =========================================================================
+func (rr *reminderRepository) GetReminderByID(ctx context.Context, id string) (*models.Reminder, error) {
+	zerolog.Ctx(ctx).Debug().
+		Str("reminder_id", id).
+		Msg("fetching reminder by id")
+
+	reminder := new(models.Reminder)
+	err := rr.db.QueryRow(ctx,
+		`SELECT id, task_id, user_id, offset_seconds, channel, COALESCE(target, ''), created_at
+		 FROM task_reminders WHERE id = $1`,
+		id,
+	).Scan(
+		&reminder.ID,
+		&reminder.TaskID,
+		&reminder.UserID,
+		&reminder.OffsetSeconds,
+		&reminder.Channel,
+		&reminder.Target,
+		&reminder.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			zerolog.Ctx(ctx).Warn().
+				Str("reminder_id", id).
+				Msg("reminder not found")
+			return nil, apperror.NewNotFoundError("reminder not found")
+		}
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("reminder_id", id).
+			Msg("failed to fetch reminder")
+		return nil, apperror.NewInternalError("Failed to fetch reminder", err)
+	}
+	return reminder, nil
+}
+
+// GetRemindersByTaskID list reminder rules of a task
+// =========================================================================
+func (rr *reminderRepository) GetRemindersByTaskID(ctx context.Context, taskID string) ([]*models.Reminder, error) {
+	rows, err := rr.db.Query(ctx,
+		`SELECT id, task_id, user_id, offset_seconds, channel, COALESCE(target, ''), created_at
+		 FROM task_reminders WHERE task_id = $1 ORDER BY offset_seconds DESC`,
+		taskID,
+	)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("task_id", taskID).
+			Msg("failed to query reminders")
+		return nil, apperror.NewInternalError("Failed to fetch reminders", err)
+	}
+	defer rows.Close()
+
+	reminders := []*models.Reminder{}
+	for rows.Next() {
+		var reminder models.Reminder
+		if err := rows.Scan(&reminder.ID, &reminder.TaskID, &reminder.UserID, &reminder.OffsetSeconds, &reminder.Channel, &reminder.Target, &reminder.CreatedAt); err != nil {
+			return nil, err
+		}
+		reminders = append(reminders, &reminder)
+	}
+	return reminders, rows.Err()
+}
+
+// DeleteReminderByID delete reminder rule
+// =========================================================================
+func (rr *reminderRepository) DeleteReminderByID(ctx context.Context, id string) error {
+	zerolog.Ctx(ctx).Debug().
+		Str("reminder_id", id).
+		Msg("deleting reminder")
+
+	cmd, err := rr.db.Exec(ctx, `DELETE FROM task_reminders WHERE id = $1`, id)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("reminder_id", id).
+			Msg("failed to delete reminder")
+		return apperror.NewInternalError("Failed to delete reminder", err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return apperror.NewNotFoundError("reminder not found")
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("reminder_id", id).
+		Msg("reminder deleted successfully")
+	return nil
+}
+
+// GetDueReminder resolve one reminder against its task
+// =========================================================================
+func (rr *reminderRepository) GetDueReminder(ctx context.Context, id string) (*models.DueReminder, error) {
+	rows, err := rr.db.Query(ctx, dueReminderSelect+` AND r.id = $1`, id)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("reminder_id", id).
+			Msg("failed to resolve reminder")
+		return nil, err
+	}
+	reminders, err := scanDueReminders(rows)
+	if err != nil || len(reminders) == 0 {
+		return nil, err
+	}
+	return reminders[0], nil
+}
+
+// GetPendingReminders unfired reminders firing inside a window
+// =========================================================================
+func (rr *reminderRepository) GetPendingReminders(ctx context.Context, from, to time.Time, limit int) ([]*models.DueReminder, error) {
+	rows, err := rr.db.Query(ctx,
+		dueReminderSelect+`
+		 AND t.due_at - r.offset_seconds * INTERVAL '1 second' BETWEEN $1 AND $2
+		 AND r.fired_for IS DISTINCT FROM t.due_at - r.offset_seconds * INTERVAL '1 second'
+		 ORDER BY fire_at
+		 LIMIT $3`,
+		from,
+		to,
+		limit,
+	)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Msg("failed to query pending reminders")
+		return nil, err
+	}
+	return scanDueReminders(rows)
+}
+
+// GetUpcomingReminders next reminders of a user
+// =========================================================================
+func (rr *reminderRepository) GetUpcomingReminders(ctx context.Context, userID string, now time.Time, limit int) ([]*models.DueReminder, error) {
+	rows, err := rr.db.Query(ctx,
+		dueReminderSelect+`
+		 AND r.user_id = $1
+		 AND t.due_at - r.offset_seconds * INTERVAL '1 second' >= $2
+		 ORDER BY fire_at
+		 LIMIT $3`,
+		userID,
+		now,
+		limit,
+	)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("user_id", userID).
+			Msg("failed to query upcoming reminders")
+		return nil, apperror.NewInternalError("Failed to fetch reminders", err)
+	}
+	return scanDueReminders(rows)
+}
+
+// MarkReminderFired record delivery for one fire time
+// =========================================================================
+func (rr *reminderRepository) MarkReminderFired(ctx context.Context, id string, fireAt time.Time) (bool, error) {
+	cmd, err := rr.db.Exec(ctx,
+		`UPDATE task_reminders SET fired_for = $2
+		 WHERE id = $1 AND fired_for IS DISTINCT FROM $2`,
+		id,
+		fireAt,
+	)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("reminder_id", id).
+			Msg("failed to mark reminder fired")
+		return false, err
+	}
+	return cmd.RowsAffected() == 1, nil
+}
+
+// ClearReminderFired forget a delivery so the reminder is picked up again
+// =========================================================================
+func (rr *reminderRepository) ClearReminderFired(ctx context.Context, id string) error {
+	_, err := rr.db.Exec(ctx, `UPDATE task_reminders SET fired_for = NULL WHERE id = $1`, id)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("reminder_id", id).
+			Msg("failed to clear reminder fired state")
+	}
+	return err
+}
+
+func scanDueReminders(rows pgx.Rows) ([]*models.DueReminder, error) {
+	defer rows.Close()
+
+	reminders := []*models.DueReminder{}
+	for rows.Next() {
+		var r models.DueReminder
+		err := rows.Scan(
+			&r.ReminderID,
+			&r.TaskID,
+			&r.UserID,
+			&r.TaskTitle,
+			&r.Channel,
+			&r.Target,
+			&r.UserEmail,
+			&r.DueAt,
+			&r.FireAt,
+		)
+		if err != nil {
+			return nil, err
+		}
+		reminders = append(reminders, &r)
+	}
+	return reminders, rows.Err()
+}
@@ -2,40 +2,79 @@ package repository
 
 import (
 	"context"
+	"strconv"
 	"time"
 
 	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
 	"github.com/suryansh74/task-management-api-project/internal/apperror"
 	"github.com/suryansh74/task-management-api-project/internal/logger"
 	"github.com/suryansh74/task-management-api-project/internal/models"
 	"github.com/suryansh74/task-management-api-project/internal/ports"
 )
 
+// deleteUserSessionsScript deletes every session listed in a user's set.
+// Logged out or expired sessions stay listed until then, deleting them is a no-op.
+//
+// KEYS[1] user sessions set, KEYS[2] session index
+// returns the number of sessions deleted
+var deleteUserSessionsScript = redis.NewScript(`
+local deleted = 0
+for _, id in ipairs(redis.call('SMEMBERS', KEYS[1])) do
+  deleted = deleted + redis.call('UNLINK', id)
+  redis.call('ZREM', KEYS[2], id)
+end
+redis.call('DEL', KEYS[1])
+return deleted
+`)
+
+// markSessionsVerifiedScript flags every live session in a user's set as verified,
+// sessions that are gone are skipped so they aren't recreated without a TTL.
+//
+// KEYS[1] user sessions set
+// returns the number of sessions updated
+var markSessionsVerifiedScript = redis.NewScript(`
+local updated = 0
+for _, id in ipairs(redis.call('SMEMBERS', KEYS[1])) do
+  if redis.call('EXISTS', id) == 1 then
+    redis.call('HSET', id, 'email_verified', 1)
+    updated = updated + 1
+  end
+end
+return updated
+`)
+
 type sessionRepository struct {
 	redisClient *redis.Client
+	// sorted set of session ids scored by expiry, sessions themselves expire by TTL
+	indexKey string
+	// <prefix><user id> is a set of the user's session ids
+	userKeyPrefix string
 }
 
 // NewSessionRepository constructor for session repository
 // =========================================================================
-func NewSessionRepository(redisClient *redis.Client) ports.SessionRepository {
+func NewSessionRepository(redisClient *redis.Client, redisAppName string) ports.SessionRepository {
 	logger.Log.Info().Msg("initializing session repository")
 	return &sessionRepository{
-		redisClient: redisClient,
+		redisClient:   redisClient,
+		indexKey:      redisAppName + ":session_index",
+		userKeyPrefix: redisAppName + ":user_sessions:",
 	}
 }
 
 // Create it set session
 // =========================================================================
 func (us *sessionRepository) Create(ctx context.Context, session *models.Session, sessionExpiration time.Duration) error {
-	logger.Log.Debug().
+	zerolog.Ctx(ctx).Debug().
 		Str("session_id", session.ID).
 		Str("user_id", session.UserID).
 		Dur("expiration", sessionExpiration).
 		Msg("creating user session")
 
-	err := us.redisClient.HSet(ctx, session.ID, models.Session{ID: session.ID, UserID: session.UserID}).Err()
+	err := us.redisClient.HSet(ctx, session.ID, models.Session{ID: session.ID, UserID: session.UserID, EmailVerified: session.EmailVerified}).Err()
 	if err != nil {
-		logger.Log.Error().
+		zerolog.Ctx(ctx).Error().
 			Err(err).
 			Str("session_id", session.ID).
 			Str("user_id", session.UserID).
@@ -43,13 +82,13 @@ func (us *sessionRepository) Create(ctx context.Context, session *models.Session
 		return apperror.NewInternalError("unable to set user session in redis", err)
 	}
 
-	logger.Log.Debug().
+	zerolog.Ctx(ctx).Debug().
 		Str("session_id", session.ID).
 		Msg("session created successfully, setting expiration")
 
 	err = us.redisClient.Expire(ctx, session.ID, sessionExpiration).Err()
 	if err != nil {
-		logger.Log.Error().
+		zerolog.Ctx(ctx).Error().
 			Err(err).
 			Str("session_id", session.ID).
 			Dur("expiration", sessionExpiration).
@@ -57,7 +96,32 @@ func (us *sessionRepository) Create(ctx context.Context, session *models.Session
 		return apperror.NewInternalError("unable to set expiry for user session in redis", err)
 	}
 
-	logger.Log.Info().
+	// a session the user's set does not know about could not be revoked
+	userKey := us.userKeyPrefix + session.UserID
+	_, err = us.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.SAdd(ctx, userKey, session.ID)
+		pipe.Expire(ctx, userKey, sessionExpiration)
+		return nil
+	})
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("session_id", session.ID).
+			Str("user_id", session.UserID).
+			Msg("failed to add session to user sessions")
+		return apperror.NewInternalError("unable to set user session in redis", err)
+	}
+
+	// the index only feeds the active sessions gauge, login must not fail on it
+	expiresAt := float64(time.Now().Add(sessionExpiration).Unix())
+	if err := us.redisClient.ZAdd(ctx, us.indexKey, redis.Z{Score: expiresAt, Member: session.ID}).Err(); err != nil {
+		zerolog.Ctx(ctx).Warn().
+			Err(err).
+			Str("session_id", session.ID).
+			Msg("failed to index user session")
+	}
+
+	zerolog.Ctx(ctx).Info().
 		Str("session_id", session.ID).
 		Str("user_id", session.UserID).
 		Dur("expiration", sessionExpiration).
@@ -68,7 +132,7 @@ func (us *sessionRepository) Create(ctx context.Context, session *models.Session
 // GetByID finds session
 // =========================================================================
 func (us *sessionRepository) GetByID(ctx context.Context, id string) (*models.Session, error) {
-	logger.Log.Debug().
+	zerolog.Ctx(ctx).Debug().
 		Str("session_id", id).
 		Msg("retrieving session by id")
 	return nil, nil
@@ -77,21 +141,95 @@ func (us *sessionRepository) GetByID(ctx context.Context, id string) (*models.Se
 // Delete it unlink session
 // =========================================================================
 func (us *sessionRepository) Delete(ctx context.Context, sessionID string) error {
-	logger.Log.Debug().
+	zerolog.Ctx(ctx).Debug().
 		Str("session_id", sessionID).
 		Msg("deleting user session")
 
 	err := us.redisClient.Unlink(ctx, sessionID).Err()
 	if err != nil {
-		logger.Log.Error().
+		zerolog.Ctx(ctx).Error().
 			Err(err).
 			Str("session_id", sessionID).
 			Msg("failed to unlink session from redis")
 		return apperror.NewInternalError("unable to delete session", err)
 	}
+	if err := us.redisClient.ZRem(ctx, us.indexKey, sessionID).Err(); err != nil {
+		zerolog.Ctx(ctx).Warn().
+			Err(err).
+			Str("session_id", sessionID).
+			Msg("failed to remove session from index")
+	}
 
-	logger.Log.Info().
+	zerolog.Ctx(ctx).Info().
 		Str("session_id", sessionID).
 		Msg("user session deleted successfully")
 	return nil
 }
+
+// DeleteByUserID delete every session of the user in one step
+// =========================================================================
+func (us *sessionRepository) DeleteByUserID(ctx context.Context, userID string) (int64, error) {
+	zerolog.Ctx(ctx).Debug().
+		Str("user_id", userID).
+		Msg("deleting all user sessions")
+
+	deleted, err := deleteUserSessionsScript.Run(ctx, us.redisClient,
+		[]string{us.userKeyPrefix + userID, us.indexKey},
+	).Int64()
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("user_id", userID).
+			Msg("failed to delete user sessions")
+		return 0, apperror.NewInternalError("unable to delete sessions", err)
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("user_id", userID).
+		Int64("deleted", deleted).
+		Msg("user sessions deleted successfully")
+	return deleted, nil
+}
+
+// MarkEmailVerified flag the user's live sessions as verified in one step
+// =========================================================================
+func (us *sessionRepository) MarkEmailVerified(ctx context.Context, userID string) (int64, error) {
+	zerolog.Ctx(ctx).Debug().
+		Str("user_id", userID).
+		Msg("marking user sessions email verified")
+
+	updated, err := markSessionsVerifiedScript.Run(ctx, us.redisClient,
+		[]string{us.userKeyPrefix + userID},
+	).Int64()
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("user_id", userID).
+			Msg("failed to mark user sessions email verified")
+		return 0, apperror.NewInternalError("unable to update sessions", err)
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("user_id", userID).
+		Int64("updated", updated).
+		Msg("user sessions marked email verified")
+	return updated, nil
+}
+
+// CountActive drop expired entries from the index, then count the rest
+// =========================================================================
+func (us *sessionRepository) CountActive(ctx context.Context) (int64, error) {
+	var card *redis.IntCmd
+	_, err := us.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRemRangeByScore(ctx, us.indexKey, "-inf", strconv.FormatInt(time.Now().Unix(), 10))
+		card = pipe.ZCard(ctx, us.indexKey)
+		return nil
+	})
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Msg("failed to count active sessions")
+		return 0, apperror.NewInternalError("unable to count sessions", err)
+	}
+	return card.Val(), nil
+}
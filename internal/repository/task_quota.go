@@ -0,0 +1,115 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog"
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+)
+
+// taskUsageSQL tasks and stored bytes of user $1, must match models.TaskContentBytes
+const taskUsageSQL = `SELECT COUNT(*), COALESCE(SUM(octet_length(title) + octet_length(COALESCE(content, ''))), 0)
+		 FROM tasks WHERE user_id = $1`
+
+// lockTaskQuota serialize the task writes of one user until tx ends and return
+// the usage as of now. Every write that adds tasks or text takes the lock
+// first, so the usage can't change under the caller before commit. Without
+// quotas there is nothing to serialize and the usage is nil.
+func lockTaskQuota(ctx context.Context, tx pgx.Tx, userID string, quota models.TaskQuota) (*models.TaskUsage, error) {
+	if !quota.Limited() {
+		return nil, nil
+	}
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext('task_quota:' || $1::text))`, userID); err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("user_id", userID).
+			Msg("failed to lock task quota")
+		return nil, apperror.NewInternalError("Failed to check quota", err)
+	}
+
+	usage := new(models.TaskUsage)
+	if err := tx.QueryRow(ctx, taskUsageSQL, userID).Scan(&usage.Tasks, &usage.ContentBytes); err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("user_id", userID).
+			Msg("failed to query task usage")
+		return nil, apperror.NewInternalError("Failed to check quota", err)
+	}
+	return usage, nil
+}
+
+// checkTaskQuota fail when adding tasks and bytes to the locked usage breaks a quota
+func checkTaskQuota(ctx context.Context, userID string, quota models.TaskQuota, usage *models.TaskUsage, addTasks int, addBytes int64) error {
+	if usage == nil {
+		return nil
+	}
+	if msg := quota.Exceeded(usage, addTasks, addBytes); msg != "" {
+		zerolog.Ctx(ctx).Warn().
+			Str("user_id", userID).
+			Int("tasks", usage.Tasks).
+			Int64("content_bytes", usage.ContentBytes).
+			Int("adding_tasks", addTasks).
+			Int64("adding_bytes", addBytes).
+			Msg("quota exceeded")
+		return apperror.NewQuotaExceededError(msg)
+	}
+	return nil
+}
+
+// checkTaskQuotaGrowth compare the usage at the end of tx with the usage
+// returned by lockTaskQuota, for writes whose growth isn't known up front
+func checkTaskQuotaGrowth(ctx context.Context, tx pgx.Tx, userID string, quota models.TaskQuota, before *models.TaskUsage) error {
+	_, err := checkTaskQuotaStep(ctx, tx, userID, quota, before)
+	return err
+}
+
+// checkTaskQuotaStep like checkTaskQuotaGrowth, also returns the usage now so
+// the next step of a batch is compared against it
+func checkTaskQuotaStep(ctx context.Context, tx pgx.Tx, userID string, quota models.TaskQuota, before *models.TaskUsage) (*models.TaskUsage, error) {
+	if before == nil {
+		return nil, nil
+	}
+	after := new(models.TaskUsage)
+	if err := tx.QueryRow(ctx, taskUsageSQL, userID).Scan(&after.Tasks, &after.ContentBytes); err != nil {
+		return nil, apperror.NewInternalError("Failed to check quota", err)
+	}
+	if err := checkTaskQuota(ctx, userID, quota, before, after.Tasks-before.Tasks, after.ContentBytes-before.ContentBytes); err != nil {
+		return nil, err
+	}
+	return after, nil
+}
+
+// lockTaskQuotaForUpdate lock the quota of the task's owner and check the
+// growth of replacing its text with title and content
+func lockTaskQuotaForUpdate(ctx context.Context, tx pgx.Tx, id string, quota models.TaskQuota, title string, content string) error {
+	if !quota.Limited() {
+		return nil
+	}
+
+	// the owner never changes, its lock must be taken before the row's
+	var userID string
+	err := tx.QueryRow(ctx, `SELECT user_id FROM tasks WHERE id = $1`, id).Scan(&userID)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return apperror.NewNotFoundError("task not found")
+	}
+	if err != nil {
+		return apperror.NewInternalError("Failed to check quota", err)
+	}
+
+	usage, err := lockTaskQuota(ctx, tx, userID, quota)
+	if err != nil {
+		return err
+	}
+	var size int64
+	err = tx.QueryRow(ctx, `SELECT octet_length(title) + octet_length(COALESCE(content, '')) FROM tasks WHERE id = $1`, id).Scan(&size)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return apperror.NewNotFoundError("task not found")
+	}
+	if err != nil {
+		return apperror.NewInternalError("Failed to check quota", err)
+	}
+	return checkTaskQuota(ctx, userID, quota, usage, 0, models.TaskContentBytes(title, content)-size)
+}
@@ -5,6 +5,8 @@ import (
 	"errors"
 
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
 	"github.com/suryansh74/task-management-api-project/internal/apperror"
 	"github.com/suryansh74/task-management-api-project/internal/logger"
 	"github.com/suryansh74/task-management-api-project/internal/models"
@@ -12,25 +14,27 @@ import (
 )
 
 type taskRepository struct {
-	db *pgx.Conn
+	db *pgxpool.Pool
+	// enforced by every write that adds tasks or text, see lockTaskQuota
+	quota models.TaskQuota
 }
 
-func NewTaskRepository(db *pgx.Conn) ports.TaskRepository {
+func NewTaskRepository(db *pgxpool.Pool, quota models.TaskQuota) ports.TaskRepository {
 	logger.Log.Info().Msg("initializing task repository")
-	return &taskRepository{db: db}
+	return &taskRepository{db: db, quota: quota}
 }
 
 // GetAllTasks get all tasks
 // =========================================================================
 func (tr *taskRepository) GetAllTasks(ctx context.Context, userID string) ([]*models.Task, error) {
-	logger.Log.Debug().
+	zerolog.Ctx(ctx).Debug().
 		Str("user_id", userID).
 		Msg("fetching all tasks for user")
 
 	var tasks []*models.Task
-	rows, err := tr.db.Query(context.Background(), "select id, title, content, created_at, updated_at from tasks where user_id = $1", userID)
+	rows, err := tr.db.Query(context.Background(), "select id, title, content, status, due_at, series_id, created_at, updated_at, checklist_total, checklist_done, labels from tasks where user_id = $1", userID)
 	if err != nil {
-		logger.Log.Error().
+		zerolog.Ctx(ctx).Error().
 			Err(err).
 			Str("user_id", userID).
 			Msg("failed to query tasks")
@@ -40,9 +44,9 @@ func (tr *taskRepository) GetAllTasks(ctx context.Context, userID string) ([]*mo
 
 	for rows.Next() {
 		var task models.Task
-		err := rows.Scan(&task.ID, &task.Title, &task.Content, &task.CreatedAt, &task.UpdatedAt)
+		err := rows.Scan(&task.ID, &task.Title, &task.Content, &task.Status, &task.DueAt, &task.SeriesID, &task.CreatedAt, &task.UpdatedAt, &task.ChecklistTotal, &task.ChecklistDone, &task.Labels)
 		if err != nil {
-			logger.Log.Error().
+			zerolog.Ctx(ctx).Error().
 				Err(err).
 				Str("user_id", userID).
 				Msg("failed to scan task row")
@@ -51,33 +55,91 @@ func (tr *taskRepository) GetAllTasks(ctx context.Context, userID string) ([]*mo
 		tasks = append(tasks, &task)
 	}
 
-	logger.Log.Info().
+	zerolog.Ctx(ctx).Info().
 		Str("user_id", userID).
 		Int("task_count", len(tasks)).
 		Msg("successfully fetched all tasks for user")
 	return tasks, nil
 }
 
+// GetTaskUsage count tasks and stored bytes of the user for quotas
+// =========================================================================
+func (tr *taskRepository) GetTaskUsage(ctx context.Context, userID string) (*models.TaskUsage, error) {
+	usage := new(models.TaskUsage)
+	err := tr.db.QueryRow(ctx, taskUsageSQL, userID).Scan(&usage.Tasks, &usage.ContentBytes)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("user_id", userID).
+			Msg("failed to query task usage")
+		return nil, apperror.NewInternalError("Failed to check quota", err)
+	}
+	return usage, nil
+}
+
+// GetTasksWithDueDate get all tasks of the user that have a due date
+// =========================================================================
+func (tr *taskRepository) GetTasksWithDueDate(ctx context.Context, userID string) ([]*models.Task, error) {
+	rows, err := tr.db.Query(ctx,
+		`SELECT id, title, content, status, due_at, series_id, created_at, updated_at, user_id,
+		        checklist_total, checklist_done, labels
+		 FROM tasks
+		 WHERE user_id = $1 AND due_at IS NOT NULL
+		 ORDER BY due_at, id`,
+		userID,
+	)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("user_id", userID).
+			Msg("failed to query tasks with due date")
+		return nil, err
+	}
+	return scanTasks(rows)
+}
+
 // CreateTask create a task
 // =========================================================================
 func (tr *taskRepository) CreateTask(ctx context.Context, task *models.Task) (string, error) {
-	logger.Log.Debug().
+	zerolog.Ctx(ctx).Debug().
 		Str("title", task.Title).
 		Str("user_id", task.UserID).
 		Msg("creating new task")
 
 	var id string
-	err := tr.db.QueryRow(context.Background(), "insert into tasks(title, content, user_id) values($1,$2,$3) returning id", task.Title, task.Content, task.UserID).Scan(&id)
+	status := task.Status
+	if status == "" {
+		status = models.TaskStatusTodo
+	}
+
+	tx, err := tr.db.Begin(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback(ctx)
+
+	usage, err := lockTaskQuota(ctx, tx, task.UserID, tr.quota)
+	if err != nil {
+		return "", err
+	}
+	if err := checkTaskQuota(ctx, task.UserID, tr.quota, usage, 1, models.TaskContentBytes(task.Title, task.Content)); err != nil {
+		return "", err
+	}
+
+	err = tx.QueryRow(ctx, "insert into tasks(title, content, user_id, status, due_at, series_id, labels) values($1,$2,$3,$4,$5,$6,COALESCE($7::text[], '{}')) returning id", task.Title, task.Content, task.UserID, status, task.DueAt, task.SeriesID, task.Labels).Scan(&id)
 	if err != nil {
-		logger.Log.Error().
+		zerolog.Ctx(ctx).Error().
 			Err(err).
 			Str("title", task.Title).
 			Str("user_id", task.UserID).
 			Msg("failed to create task")
 		return "", err
 	}
+	if err := tx.Commit(ctx); err != nil {
+		return "", err
+	}
 
-	logger.Log.Info().
+	zerolog.Ctx(ctx).Info().
 		Str("task_id", id).
 		Str("title", task.Title).
 		Str("user_id", task.UserID).
@@ -88,39 +150,46 @@ func (tr *taskRepository) CreateTask(ctx context.Context, task *models.Task) (st
 // Get task by id
 // =========================================================================
 func (tr *taskRepository) GetTaskByID(ctx context.Context, id string) (*models.Task, error) {
-	logger.Log.Debug().
+	zerolog.Ctx(ctx).Debug().
 		Str("task_id", id).
 		Msg("fetching task by id")
 
 	task := new(models.Task)
 
 	err := tr.db.QueryRow(ctx,
-		`SELECT id, title, content, created_at, updated_at, user_id
+		`SELECT id, title, content, status, due_at, series_id, created_at, updated_at, user_id,
+		        checklist_total, checklist_done, labels
 		 FROM tasks WHERE id = $1`,
 		id,
 	).Scan(
 		&task.ID,
 		&task.Title,
 		&task.Content,
+		&task.Status,
+		&task.DueAt,
+		&task.SeriesID,
 		&task.CreatedAt,
 		&task.UpdatedAt,
 		&task.UserID,
+		&task.ChecklistTotal,
+		&task.ChecklistDone,
+		&task.Labels,
 	)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			logger.Log.Warn().
+			zerolog.Ctx(ctx).Warn().
 				Str("task_id", id).
 				Msg("task not found")
 			return nil, apperror.NewNotFoundError("task not found")
 		}
-		logger.Log.Error().
+		zerolog.Ctx(ctx).Error().
 			Err(err).
 			Str("task_id", id).
 			Msg("failed to fetch task")
 		return nil, err
 	}
 
-	logger.Log.Info().
+	zerolog.Ctx(ctx).Info().
 		Str("task_id", task.ID).
 		Str("title", task.Title).
 		Str("user_id", task.UserID).
@@ -131,21 +200,38 @@ func (tr *taskRepository) GetTaskByID(ctx context.Context, id string) (*models.T
 // Update task by id
 // =========================================================================
 func (tr *taskRepository) UpdateTaskByID(ctx context.Context, id string, task *models.Task) error {
-	logger.Log.Debug().
+	zerolog.Ctx(ctx).Debug().
 		Str("task_id", id).
 		Str("title", task.Title).
 		Msg("updating task")
 
-	cmd, err := tr.db.Exec(ctx,
+	tx, err := tr.db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := lockTaskQuotaForUpdate(ctx, tx, id, tr.quota, task.Title, task.Content); err != nil {
+		return err
+	}
+
+	cmd, err := tx.Exec(ctx,
 		`UPDATE tasks
-		 SET title = $1, content = $2, updated_at = NOW()
-		 WHERE id = $3`,
+		 SET title = $1, content = $2,
+		     status = COALESCE(NULLIF($3, ''), status),
+		     due_at = COALESCE($4, due_at),
+		     labels = COALESCE($5, labels),
+		     updated_at = NOW()
+		 WHERE id = $6`,
 		task.Title,
 		task.Content,
+		task.Status,
+		task.DueAt,
+		task.Labels,
 		id,
 	)
 	if err != nil {
-		logger.Log.Error().
+		zerolog.Ctx(ctx).Error().
 			Err(err).
 			Str("task_id", id).
 			Msg("failed to update task")
@@ -153,13 +239,16 @@ func (tr *taskRepository) UpdateTaskByID(ctx context.Context, id string, task *m
 	}
 
 	if cmd.RowsAffected() == 0 {
-		logger.Log.Warn().
+		zerolog.Ctx(ctx).Warn().
 			Str("task_id", id).
 			Msg("task not found for update")
 		return apperror.NewNotFoundError("task not found")
 	}
+	if err := tx.Commit(ctx); err != nil {
+		return err
+	}
 
-	logger.Log.Info().
+	zerolog.Ctx(ctx).Info().
 		Str("task_id", id).
 		Str("title", task.Title).
 		Int64("rows_affected", cmd.RowsAffected()).
@@ -170,7 +259,7 @@ func (tr *taskRepository) UpdateTaskByID(ctx context.Context, id string, task *m
 // Delete task by id
 // =========================================================================
 func (tr *taskRepository) DeleteTaskByID(ctx context.Context, id string) error {
-	logger.Log.Debug().
+	zerolog.Ctx(ctx).Debug().
 		Str("task_id", id).
 		Msg("deleting task")
 
@@ -179,7 +268,7 @@ func (tr *taskRepository) DeleteTaskByID(ctx context.Context, id string) error {
 		id,
 	)
 	if err != nil {
-		logger.Log.Error().
+		zerolog.Ctx(ctx).Error().
 			Err(err).
 			Str("task_id", id).
 			Msg("failed to delete task")
@@ -187,13 +276,13 @@ func (tr *taskRepository) DeleteTaskByID(ctx context.Context, id string) error {
 	}
 
 	if cmd.RowsAffected() == 0 {
-		logger.Log.Warn().
+		zerolog.Ctx(ctx).Warn().
 			Str("task_id", id).
 			Msg("task not found for deletion")
 		return apperror.NewNotFoundError("task not found")
 	}
 
-	logger.Log.Info().
+	zerolog.Ctx(ctx).Info().
 		Str("task_id", id).
 		Int64("rows_affected", cmd.RowsAffected()).
 		Msg("task deleted successfully")
@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rs/zerolog"
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+)
+
+// ApplyTaskBatch run many task writes in one transaction
+// =========================================================================
+func (tr *taskRepository) ApplyTaskBatch(ctx context.Context, ops []*models.TaskBatchOp, atomic bool) ([]error, error) {
+	zerolog.Ctx(ctx).Debug().
+		Int("op_count", len(ops)).
+		Bool("atomic", atomic).
+		Msg("applying task batch")
+
+	tx, err := tr.db.Begin(ctx)
+	if err != nil {
+		return nil, apperror.NewInternalError("Failed to apply batch", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// a batch belongs to one user; an atomic batch's growth is checked once
+	// all ops ran, otherwise after each op so only the op over quota fails
+	var userID string
+	if len(ops) > 0 {
+		userID = ops[0].UserID
+	}
+	usage, err := lockTaskQuota(ctx, tx, userID, tr.quota)
+	if err != nil {
+		return nil, err
+	}
+
+	errs := make([]error, len(ops))
+	failed := 0
+	for i, op := range ops {
+		// each op gets a savepoint so a failure doesn't abort the whole tx
+		sp, err := tx.Begin(ctx)
+		if err != nil {
+			return nil, apperror.NewInternalError("Failed to apply batch", err)
+		}
+
+		err = applyTaskBatchOp(ctx, sp, op)
+		if err == nil && !atomic {
+			var after *models.TaskUsage
+			if after, err = checkTaskQuotaStep(ctx, sp, userID, tr.quota, usage); err == nil {
+				usage = after
+			}
+		}
+		if err != nil {
+			sp.Rollback(ctx)
+			errs[i] = err
+			failed++
+			zerolog.Ctx(ctx).Warn().
+				Err(err).
+				Int("index", i).
+				Str("op", op.Op).
+				Str("task_id", op.ID).
+				Msg("batch operation failed")
+			if atomic {
+				return errs, nil
+			}
+			continue
+		}
+		if err := sp.Commit(ctx); err != nil {
+			return nil, apperror.NewInternalError("Failed to apply batch", err)
+		}
+	}
+
+	if atomic {
+		if err := checkTaskQuotaGrowth(ctx, tx, userID, tr.quota, usage); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Int("op_count", len(ops)).
+			Msg("failed to commit task batch")
+		return nil, apperror.NewInternalError("Failed to apply batch", err)
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Int("op_count", len(ops)).
+		Int("failed", failed).
+		Bool("atomic", atomic).
+		Msg("task batch applied successfully")
+	return errs, nil
+}
+
+func applyTaskBatchOp(ctx context.Context, tx pgx.Tx, op *models.TaskBatchOp) error {
+	var (
+		cmd pgconn.CommandTag
+		err error
+	)
+
+	switch op.Op {
+	case models.BatchOpCreate:
+		status := op.Status
+		if status == "" {
+			status = models.TaskStatusTodo
+		}
+		err = tx.QueryRow(ctx,
+			`INSERT INTO tasks (title, content, user_id, status, due_at) VALUES ($1, $2, $3, $4, $5) RETURNING id`,
+			op.Title,
+			op.Content,
+			op.UserID,
+			status,
+			op.DueAt,
+		).Scan(&op.ID)
+		cmd = pgconn.NewCommandTag("INSERT 0 1")
+	case models.BatchOpUpdate:
+		cmd, err = tx.Exec(ctx,
+			`UPDATE tasks
+			 SET title = $1, content = $2,
+			     status = COALESCE(NULLIF($3, ''), status),
+			     due_at = COALESCE($4, due_at),
+			     updated_at = NOW()
+			 WHERE id = $5`,
+			op.Title,
+			op.Content,
+			op.Status,
+			op.DueAt,
+			op.ID,
+		)
+	case models.BatchOpSetStatus:
+		cmd, err = tx.Exec(ctx,
+			`UPDATE tasks SET status = $1, updated_at = NOW() WHERE id = $2`,
+			op.Status,
+			op.ID,
+		)
+	case models.BatchOpDelete:
+		cmd, err = tx.Exec(ctx, `DELETE FROM tasks WHERE id = $1`, op.ID)
+	default:
+		return apperror.NewBadRequestError(fmt.Sprintf("unknown operation %q", op.Op))
+	}
+
+	if err != nil {
+		return apperror.NewInternalError("Failed to apply operation", err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return apperror.NewNotFoundError("task not found")
+	}
+	return nil
+}
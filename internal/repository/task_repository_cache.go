@@ -3,27 +3,39 @@ package repository
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"time"
 
 	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
 	"github.com/suryansh74/task-management-api-project/internal/logger"
 	"github.com/suryansh74/task-management-api-project/internal/models"
 	"github.com/suryansh74/task-management-api-project/internal/ports"
 )
 
+// notFoundMarker value of a key whose task does not exist
+const notFoundMarker = "!notfound"
+
 type taskCacheRepository struct {
-	redisClient *redis.Client
+	redisClient  *redis.Client
+	redisAppName string
+	// expiry of the list generation keys, derived from the list expiration
+	generationTTL time.Duration
 }
 
-func NewTaskCacheRepository(redisClient *redis.Client) ports.TaskCacheRepository {
+func NewTaskCacheRepository(redisClient *redis.Client, redisAppName string, listExpiration time.Duration) ports.TaskCacheRepository {
 	logger.Log.Info().Msg("initializing task cache repository")
-	return &taskCacheRepository{redisClient: redisClient}
+	return &taskCacheRepository{
+		redisClient:   redisClient,
+		redisAppName:  redisAppName,
+		generationTTL: taskListGenerationTTL(listExpiration),
+	}
 }
 
 // SetTask set task
 // =========================================================================
 func (s *taskCacheRepository) SetTask(ctx context.Context, task *models.Task, key string, exp time.Duration) error {
-	logger.Log.Debug().
+	zerolog.Ctx(ctx).Debug().
 		Str("cache_key", key).
 		Str("task_id", task.ID).
 		Dur("expiration", exp).
@@ -31,7 +43,7 @@ func (s *taskCacheRepository) SetTask(ctx context.Context, task *models.Task, ke
 
 	bytes, err := json.Marshal(task)
 	if err != nil {
-		logger.Log.Error().
+		zerolog.Ctx(ctx).Error().
 			Err(err).
 			Str("cache_key", key).
 			Str("task_id", task.ID).
@@ -41,7 +53,7 @@ func (s *taskCacheRepository) SetTask(ctx context.Context, task *models.Task, ke
 
 	err = s.redisClient.Set(ctx, key, bytes, exp).Err()
 	if err != nil {
-		logger.Log.Error().
+		zerolog.Ctx(ctx).Error().
 			Err(err).
 			Str("cache_key", key).
 			Str("task_id", task.ID).
@@ -49,7 +61,7 @@ func (s *taskCacheRepository) SetTask(ctx context.Context, task *models.Task, ke
 		return err
 	}
 
-	logger.Log.Info().
+	zerolog.Ctx(ctx).Info().
 		Str("cache_key", key).
 		Str("task_id", task.ID).
 		Dur("expiration", exp).
@@ -58,57 +70,125 @@ func (s *taskCacheRepository) SetTask(ctx context.Context, task *models.Task, ke
 }
 
 func (s *taskCacheRepository) GetTaskByID(ctx context.Context, key string) (*models.Task, error) {
-	logger.Log.Debug().
+	entry, err := s.GetTaskEntry(ctx, key)
+	if err != nil || entry == nil {
+		return nil, err // cache miss
+	}
+	return entry.Task, nil // nil for a task cached as missing
+}
+
+// GetTaskEntry cached task or not-found marker plus its remaining TTL, one round trip
+// =========================================================================
+func (s *taskCacheRepository) GetTaskEntry(ctx context.Context, key string) (*models.TaskCacheEntry, error) {
+	zerolog.Ctx(ctx).Debug().
 		Str("cache_key", key).
 		Msg("retrieving task from cache")
 
-	val, err := s.redisClient.Get(ctx, key).Result()
-	if err == redis.Nil {
-		logger.Log.Debug().
+	pipe := s.redisClient.Pipeline()
+	get := pipe.Get(ctx, key)
+	ttl := pipe.PTTL(ctx, key)
+	_, err := pipe.Exec(ctx)
+	if errors.Is(get.Err(), redis.Nil) {
+		zerolog.Ctx(ctx).Debug().
 			Str("cache_key", key).
 			Msg("cache miss: task not found in cache")
 		return nil, nil // cache miss
 	}
 	if err != nil {
-		logger.Log.Error().
+		zerolog.Ctx(ctx).Error().
 			Err(err).
 			Str("cache_key", key).
 			Msg("failed to get task from cache")
 		return nil, err
 	}
 
+	entry := &models.TaskCacheEntry{TTL: ttl.Val()}
+	if get.Val() == notFoundMarker {
+		zerolog.Ctx(ctx).Debug().
+			Str("cache_key", key).
+			Msg("cache hit: task cached as not found")
+		entry.NotFound = true
+		return entry, nil
+	}
+
 	var task models.Task
-	if err := json.Unmarshal([]byte(val), &task); err != nil {
-		logger.Log.Error().
+	if err := json.Unmarshal([]byte(get.Val()), &task); err != nil {
+		zerolog.Ctx(ctx).Error().
 			Err(err).
 			Str("cache_key", key).
 			Msg("failed to unmarshal cached task")
 		return nil, err
 	}
+	entry.Task = &task
 
-	logger.Log.Info().
+	zerolog.Ctx(ctx).Info().
 		Str("cache_key", key).
 		Str("task_id", task.ID).
 		Msg("cache hit: task retrieved successfully")
-	return &task, nil
+	return entry, nil
+}
+
+// SetNotFound cache the absence of a task, never valid task JSON
+// =========================================================================
+func (s *taskCacheRepository) SetNotFound(ctx context.Context, key string, exp time.Duration) error {
+	err := s.redisClient.Set(ctx, key, notFoundMarker, exp).Err()
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("cache_key", key).
+			Msg("failed to cache missing task")
+		return err
+	}
+
+	zerolog.Ctx(ctx).Debug().
+		Str("cache_key", key).
+		Dur("expiration", exp).
+		Msg("missing task cached")
+	return nil
 }
 
 func (s *taskCacheRepository) DeleteTaskByID(ctx context.Context, key string) error {
-	logger.Log.Debug().
+	zerolog.Ctx(ctx).Debug().
 		Str("cache_key", key).
 		Msg("deleting task from cache")
 
 	err := s.redisClient.Unlink(ctx, key).Err()
 	if err != nil {
-		logger.Log.Error().
+		zerolog.Ctx(ctx).Error().
 			Err(err).
 			Str("cache_key", key).
 			Msg("failed to delete task from cache")
 		return err
 	}
 
-	logger.Log.Info().
+	zerolog.Ctx(ctx).Info().
 		Str("cache_key", key).
 		Msg("task removed from cache successfully")
 	return nil
 }
+
+// DeleteTasks unlink many cached tasks with one command
+// =========================================================================
+func (s *taskCacheRepository) DeleteTasks(ctx context.Context, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	zerolog.Ctx(ctx).Debug().
+		Int("key_count", len(keys)).
+		Msg("deleting tasks from cache")
+
+	err := s.redisClient.Unlink(ctx, keys...).Err()
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Int("key_count", len(keys)).
+			Msg("failed to delete tasks from cache")
+		return err
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Int("key_count", len(keys)).
+		Msg("tasks removed from cache successfully")
+	return nil
+}
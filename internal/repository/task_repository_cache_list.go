@@ -0,0 +1,142 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+)
+
+// minTaskListGenerationTTL lower bound of a generation key's TTL, which must
+// outlive any cached list so the key never expires while lists cached under it
+// are still readable
+const minTaskListGenerationTTL = 24 * time.Hour
+
+// taskListGenerationTTL at least twice the list expiration
+func taskListGenerationTTL(listExpiration time.Duration) time.Duration {
+	return max(minTaskListGenerationTTL, 2*listExpiration)
+}
+
+// getTaskListScript reads the user's generation and the list cached under it
+// in one round trip.
+//
+// KEYS[1] generation key, ARGV[1] list key prefix, ARGV[2] query
+// returns {generation, list or nil}
+var getTaskListScript = redis.NewScript(`
+local gen = redis.call('GET', KEYS[1]) or '0'
+return {gen, redis.call('GET', ARGV[1] .. gen .. ':' .. ARGV[2])}
+`)
+
+func (s *taskCacheRepository) taskListGenerationKey(userID string) string {
+	return fmt.Sprintf("%s:cache:tasks_gen:%s", s.redisAppName, userID)
+}
+
+// taskListKeyPrefix lists are keyed <prefix><generation>:<query>
+func (s *taskCacheRepository) taskListKeyPrefix(userID string) string {
+	return fmt.Sprintf("%s:cache:tasks:%s:", s.redisAppName, userID)
+}
+
+// GetTaskList cached list under the user's current generation
+// =========================================================================
+func (s *taskCacheRepository) GetTaskList(ctx context.Context, userID string, query string) (*models.TaskPage, int64, error) {
+	reply, err := getTaskListScript.Run(ctx, s.redisClient,
+		[]string{s.taskListGenerationKey(userID)},
+		s.taskListKeyPrefix(userID),
+		query,
+	).Slice()
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("user_id", userID).
+			Msg("failed to get task list from cache")
+		return nil, 0, err
+	}
+
+	if len(reply) != 2 {
+		return nil, 0, fmt.Errorf("unexpected task list reply of length %d", len(reply))
+	}
+	generation, err := strconv.ParseInt(fmt.Sprint(reply[0]), 10, 64)
+	if err != nil {
+		return nil, 0, err
+	}
+	// a missing list comes back as a nil element
+	cached, ok := reply[1].(string)
+	if !ok {
+		zerolog.Ctx(ctx).Debug().
+			Str("user_id", userID).
+			Int64("generation", generation).
+			Str("query", query).
+			Msg("cache miss: task list not found in cache")
+		return nil, generation, nil
+	}
+
+	var page models.TaskPage
+	if err := json.Unmarshal([]byte(cached), &page); err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("user_id", userID).
+			Msg("failed to unmarshal cached task list")
+		return nil, generation, err
+	}
+
+	zerolog.Ctx(ctx).Debug().
+		Str("user_id", userID).
+		Int64("generation", generation).
+		Str("query", query).
+		Int("task_count", len(page.Tasks)).
+		Msg("cache hit: task list retrieved successfully")
+	return &page, generation, nil
+}
+
+// SetTaskList cache a list under the generation it was read at
+// =========================================================================
+func (s *taskCacheRepository) SetTaskList(ctx context.Context, userID string, query string, generation int64, page *models.TaskPage, exp time.Duration) error {
+	bytes, err := json.Marshal(page)
+	if err != nil {
+		return err
+	}
+
+	key := s.taskListKeyPrefix(userID) + strconv.FormatInt(generation, 10) + ":" + query
+	if err := s.redisClient.Set(ctx, key, bytes, exp).Err(); err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("cache_key", key).
+			Msg("failed to set task list in cache")
+		return err
+	}
+
+	zerolog.Ctx(ctx).Debug().
+		Str("cache_key", key).
+		Int("task_count", len(page.Tasks)).
+		Dur("expiration", exp).
+		Msg("task list cached successfully")
+	return nil
+}
+
+// InvalidateTaskLists bump the user's list generation
+// =========================================================================
+func (s *taskCacheRepository) InvalidateTaskLists(ctx context.Context, userID string) error {
+	key := s.taskListGenerationKey(userID)
+	_, err := s.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Incr(ctx, key)
+		pipe.Expire(ctx, key, s.generationTTL)
+		return nil
+	})
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("user_id", userID).
+			Msg("failed to invalidate task lists")
+		return err
+	}
+
+	zerolog.Ctx(ctx).Debug().
+		Str("user_id", userID).
+		Msg("task lists invalidated")
+	return nil
+}
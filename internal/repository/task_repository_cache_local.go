@@ -0,0 +1,236 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	lru "github.com/hashicorp/golang-lru/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+	"github.com/suryansh74/task-management-api-project/internal/logger"
+	"github.com/suryansh74/task-management-api-project/internal/metrics"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+)
+
+type localTaskCache struct {
+	next        ports.TaskCacheRepository
+	redisClient *redis.Client
+	channel     string
+	ttl         time.Duration
+	entries     *lru.Cache[string, localTaskEntry]
+
+	// bumped by every invalidation, a Redis read that raced one is not kept
+	epoch atomic.Uint64
+}
+
+type localTaskEntry struct {
+	task      *models.Task // nil when cached as not found
+	expiresAt time.Time
+	// expiry of the Redis key, zero when it has none, reported as the entry TTL
+	sharedExpiresAt time.Time
+}
+
+// NewLocalTaskCache bounded LRU of at most size tasks, each kept for at most ttl,
+// in front of next. Deletes are published on <app>:cache:invalidate.
+// =========================================================================
+func NewLocalTaskCache(next ports.TaskCacheRepository, redisClient *redis.Client, redisAppName string, size int, ttl time.Duration) ports.LocalTaskCache {
+	logger.Log.Info().
+		Int("size", size).
+		Dur("ttl", ttl).
+		Msg("initializing local task cache")
+	entries, err := lru.New[string, localTaskEntry](size)
+	if err != nil {
+		logger.Log.Fatal().Err(err).Msg("invalid local task cache size")
+	}
+	return &localTaskCache{
+		next:        next,
+		redisClient: redisClient,
+		channel:     redisAppName + ":cache:invalidate",
+		ttl:         ttl,
+		entries:     entries,
+	}
+}
+
+// SetTask the local copy is dropped, the next read picks up the new value
+// =========================================================================
+func (c *localTaskCache) SetTask(ctx context.Context, task *models.Task, key string, exp time.Duration) error {
+	c.entries.Remove(key)
+	return c.next.SetTask(ctx, task, key, exp)
+}
+
+func (c *localTaskCache) GetTaskByID(ctx context.Context, key string) (*models.Task, error) {
+	entry, err := c.GetTaskEntry(ctx, key)
+	if err != nil || entry == nil {
+		return nil, err
+	}
+	return entry.Task, nil
+}
+
+// GetTaskEntry local copy if still fresh, else the shared cache
+// =========================================================================
+func (c *localTaskCache) GetTaskEntry(ctx context.Context, key string) (*models.TaskCacheEntry, error) {
+	if local, ok := c.entries.Get(key); ok {
+		if time.Now().Before(local.expiresAt) {
+			metrics.CacheHits.WithLabelValues("task_local").Inc()
+			return local.entry(), nil
+		}
+		c.entries.Remove(key)
+	}
+	metrics.CacheMisses.WithLabelValues("task_local").Inc()
+
+	epoch := c.epoch.Load()
+	entry, err := c.next.GetTaskEntry(ctx, key)
+	metrics.ObserveCacheLookup("task_redis", entry != nil, err)
+	if err != nil || entry == nil {
+		return entry, err
+	}
+	if c.epoch.Load() == epoch {
+		c.store(key, entry)
+	}
+	return entry, nil
+}
+
+// SetNotFound the local copy is dropped, the next read picks up the marker
+// =========================================================================
+func (c *localTaskCache) SetNotFound(ctx context.Context, key string, exp time.Duration) error {
+	c.entries.Remove(key)
+	return c.next.SetNotFound(ctx, key, exp)
+}
+
+// DeleteTaskByID delete from Redis, then evict on every replica
+// =========================================================================
+func (c *localTaskCache) DeleteTaskByID(ctx context.Context, key string) error {
+	err := c.next.DeleteTaskByID(ctx, key)
+	c.invalidate(ctx, []string{key})
+	return err
+}
+
+// DeleteTasks delete from Redis, then evict on every replica with one message
+// =========================================================================
+func (c *localTaskCache) DeleteTasks(ctx context.Context, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	err := c.next.DeleteTasks(ctx, keys)
+	c.invalidate(ctx, keys)
+	return err
+}
+
+// GetTaskList lists are only cached in Redis, the generation counter is shared
+// by all replicas
+// =========================================================================
+func (c *localTaskCache) GetTaskList(ctx context.Context, userID string, query string) (*models.TaskPage, int64, error) {
+	return c.next.GetTaskList(ctx, userID, query)
+}
+
+// SetTaskList passes through to Redis
+// =========================================================================
+func (c *localTaskCache) SetTaskList(ctx context.Context, userID string, query string, generation int64, page *models.TaskPage, exp time.Duration) error {
+	return c.next.SetTaskList(ctx, userID, query, generation, page, exp)
+}
+
+// InvalidateTaskLists passes through to Redis
+// =========================================================================
+func (c *localTaskCache) InvalidateTaskLists(ctx context.Context, userID string) error {
+	return c.next.InvalidateTaskLists(ctx, userID)
+}
+
+// Listen evict what other replicas invalidate. Messages published while the
+// subscription is down are lost, so the whole local tier is dropped on every
+// (re)subscribe.
+// =========================================================================
+func (c *localTaskCache) Listen(ctx context.Context) {
+	zerolog.Ctx(ctx).Info().
+		Str("channel", c.channel).
+		Msg("local task cache invalidation listener started")
+
+	pubsub := c.redisClient.Subscribe(ctx, c.channel)
+	// Receive does not watch ctx, closing the subscription unblocks it
+	stop := context.AfterFunc(ctx, func() { pubsub.Close() })
+	defer stop()
+
+	for {
+		msg, err := pubsub.Receive(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				zerolog.Ctx(ctx).Info().Msg("local task cache invalidation listener stopped")
+				return
+			}
+			c.purge()
+			zerolog.Ctx(ctx).Warn().
+				Err(err).
+				Msg("cache invalidation subscription failed, local task cache dropped")
+			select {
+			case <-ctx.Done():
+			case <-time.After(time.Second):
+			}
+			continue
+		}
+
+		switch msg := msg.(type) {
+		case *redis.Subscription:
+			c.purge()
+		case *redis.Message:
+			for _, key := range strings.Split(msg.Payload, "\n") {
+				c.evict(key)
+			}
+		}
+	}
+}
+
+// invalidate evict here right away, and everywhere through the channel
+func (c *localTaskCache) invalidate(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		c.evict(key)
+	}
+	if err := c.redisClient.Publish(ctx, c.channel, strings.Join(keys, "\n")).Err(); err != nil {
+		metrics.CacheErrors.WithLabelValues("task_local", "publish").Inc()
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Int("key_count", len(keys)).
+			Msg("failed to publish cache invalidation")
+	}
+}
+
+func (c *localTaskCache) evict(key string) {
+	c.epoch.Add(1)
+	c.entries.Remove(key)
+}
+
+func (c *localTaskCache) purge() {
+	c.epoch.Add(1)
+	c.entries.Purge()
+}
+
+// store keep a copy until the local TTL or the Redis expiry, whichever is first
+func (c *localTaskCache) store(key string, entry *models.TaskCacheEntry) {
+	now := time.Now()
+	local := localTaskEntry{expiresAt: now.Add(c.ttl)}
+	if entry.TTL >= 0 {
+		local.sharedExpiresAt = now.Add(entry.TTL)
+		if local.sharedExpiresAt.Before(local.expiresAt) {
+			local.expiresAt = local.sharedExpiresAt
+		}
+	}
+	if entry.Task != nil {
+		task := *entry.Task
+		local.task = &task
+	}
+	c.entries.Add(key, local)
+}
+
+// entry a copy per caller, callers may modify what they receive
+func (e localTaskEntry) entry() *models.TaskCacheEntry {
+	entry := &models.TaskCacheEntry{NotFound: e.task == nil, TTL: -1}
+	if !e.sharedExpiresAt.IsZero() {
+		entry.TTL = time.Until(e.sharedExpiresAt)
+	}
+	if e.task != nil {
+		task := *e.task
+		entry.Task = &task
+	}
+	return entry
+}
@@ -0,0 +1,143 @@
+package repository_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	goredis "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/require"
+
+	"github.com/suryansh74/task-management-api-project/internal/models"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+	"github.com/suryansh74/task-management-api-project/internal/repository"
+)
+
+// fakeTaskCache shared tier that counts reads
+type fakeTaskCache struct {
+	entries map[string]*models.TaskCacheEntry
+	reads   int
+	onRead  func()
+}
+
+func (f *fakeTaskCache) SetTask(ctx context.Context, task *models.Task, key string, exp time.Duration) error {
+	f.entries[key] = &models.TaskCacheEntry{Task: task, TTL: exp}
+	return nil
+}
+func (f *fakeTaskCache) GetTaskByID(ctx context.Context, key string) (*models.Task, error) {
+	return nil, nil
+}
+func (f *fakeTaskCache) GetTaskEntry(ctx context.Context, key string) (*models.TaskCacheEntry, error) {
+	f.reads++
+	if f.onRead != nil {
+		f.onRead()
+	}
+	return f.entries[key], nil
+}
+func (f *fakeTaskCache) SetNotFound(ctx context.Context, key string, exp time.Duration) error {
+	f.entries[key] = &models.TaskCacheEntry{NotFound: true, TTL: exp}
+	return nil
+}
+func (f *fakeTaskCache) DeleteTaskByID(ctx context.Context, key string) error {
+	delete(f.entries, key)
+	return nil
+}
+func (f *fakeTaskCache) DeleteTasks(ctx context.Context, keys []string) error {
+	for _, key := range keys {
+		delete(f.entries, key)
+	}
+	return nil
+}
+func (f *fakeTaskCache) GetTaskList(ctx context.Context, userID string, query string) (*models.TaskPage, int64, error) {
+	return nil, 0, nil
+}
+func (f *fakeTaskCache) SetTaskList(ctx context.Context, userID string, query string, generation int64, page *models.TaskPage, exp time.Duration) error {
+	return nil
+}
+func (f *fakeTaskCache) InvalidateTaskLists(ctx context.Context, userID string) error {
+	return nil
+}
+
+// newLocalTaskCache local tier whose invalidations go nowhere, publishing just fails
+func newLocalTaskCache(t *testing.T, next ports.TaskCacheRepository, ttl time.Duration) ports.LocalTaskCache {
+	t.Helper()
+	client := goredis.NewClient(&goredis.Options{Addr: "127.0.0.1:1", MaxRetries: -1, DialTimeout: 10 * time.Millisecond})
+	t.Cleanup(func() { client.Close() })
+	return repository.NewLocalTaskCache(next, client, "test-app", 2, ttl)
+}
+
+func TestLocalTaskCache(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("serves repeated reads locally", func(t *testing.T) {
+		next := &fakeTaskCache{entries: map[string]*models.TaskCacheEntry{
+			"k1": {Task: &models.Task{ID: "t1", Title: "Hot"}, TTL: time.Minute},
+		}}
+		cache := newLocalTaskCache(t, next, time.Minute)
+
+		for range 3 {
+			entry, err := cache.GetTaskEntry(ctx, "k1")
+			require.NoError(t, err)
+			require.Equal(t, "Hot", entry.Task.Title)
+			require.Greater(t, entry.TTL, 50*time.Second)
+			entry.Task.Title = "changed by caller"
+		}
+		require.Equal(t, 1, next.reads)
+
+		// deleting evicts locally, the next read goes to the shared tier
+		require.NoError(t, cache.DeleteTaskByID(ctx, "k1"))
+		entry, err := cache.GetTaskEntry(ctx, "k1")
+		require.NoError(t, err)
+		require.Nil(t, entry)
+		require.Equal(t, 2, next.reads)
+	})
+
+	t.Run("never outlives the shared entry", func(t *testing.T) {
+		next := &fakeTaskCache{entries: map[string]*models.TaskCacheEntry{
+			"k1": {NotFound: true, TTL: 20 * time.Millisecond},
+		}}
+		cache := newLocalTaskCache(t, next, time.Minute)
+
+		entry, err := cache.GetTaskEntry(ctx, "k1")
+		require.NoError(t, err)
+		require.True(t, entry.NotFound)
+		time.Sleep(30 * time.Millisecond)
+		_, err = cache.GetTaskEntry(ctx, "k1")
+		require.NoError(t, err)
+		require.Equal(t, 2, next.reads)
+	})
+
+	t.Run("drops a read that raced an invalidation", func(t *testing.T) {
+		next := &fakeTaskCache{entries: map[string]*models.TaskCacheEntry{
+			"k1": {Task: &models.Task{ID: "t1"}, TTL: time.Minute},
+		}}
+		cache := newLocalTaskCache(t, next, time.Minute)
+		next.onRead = func() {
+			next.onRead = nil
+			// another request invalidates while this read is in flight
+			require.NoError(t, cache.DeleteTasks(ctx, []string{"other"}))
+		}
+
+		_, err := cache.GetTaskEntry(ctx, "k1")
+		require.NoError(t, err)
+		_, err = cache.GetTaskEntry(ctx, "k1")
+		require.NoError(t, err)
+		require.Equal(t, 2, next.reads)
+	})
+
+	t.Run("bounded size", func(t *testing.T) {
+		next := &fakeTaskCache{entries: map[string]*models.TaskCacheEntry{
+			"k1": {Task: &models.Task{ID: "t1"}, TTL: time.Minute},
+			"k2": {Task: &models.Task{ID: "t2"}, TTL: time.Minute},
+			"k3": {Task: &models.Task{ID: "t3"}, TTL: time.Minute},
+		}}
+		cache := newLocalTaskCache(t, next, time.Minute)
+
+		for _, key := range []string{"k1", "k2", "k3", "k1"} {
+			_, err := cache.GetTaskEntry(ctx, key)
+			require.NoError(t, err)
+		}
+		// k1 was the least recently used when k3 arrived
+		require.Equal(t, 4, next.reads)
+	})
+}
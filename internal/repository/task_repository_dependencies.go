@@ -0,0 +1,207 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/rs/zerolog"
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+)
+
+// AddDependency insert edge unless it closes a cycle
+// =========================================================================
+func (tr *taskRepository) AddDependency(ctx context.Context, userID string, taskID string, blockedByID string) error {
+	zerolog.Ctx(ctx).Debug().
+		Str("task_id", taskID).
+		Str("blocked_by_id", blockedByID).
+		Msg("adding task dependency")
+
+	tx, err := tr.db.Begin(ctx)
+	if err != nil {
+		return apperror.NewInternalError("Failed to add dependency", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// serialize graph changes per user, two concurrent inserts could otherwise
+	// each pass the cycle check and close a cycle together
+	if _, err := tx.Exec(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, "task_dependencies:"+userID); err != nil {
+		return apperror.NewInternalError("Failed to add dependency", err)
+	}
+
+	// a cycle appears iff taskID already (transitively) blocks blockedByID
+	var cycle bool
+	err = tx.QueryRow(ctx,
+		`WITH RECURSIVE chain(id) AS (
+		     SELECT blocked_by_id FROM task_dependencies WHERE task_id = $2
+		     UNION
+		     SELECT d.blocked_by_id FROM task_dependencies d JOIN chain c ON d.task_id = c.id
+		 )
+		 SELECT EXISTS (SELECT 1 FROM chain WHERE id = $1)`,
+		taskID,
+		blockedByID,
+	).Scan(&cycle)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("task_id", taskID).
+			Msg("failed to check dependency cycle")
+		return apperror.NewInternalError("Failed to add dependency", err)
+	}
+	if cycle {
+		zerolog.Ctx(ctx).Warn().
+			Str("task_id", taskID).
+			Str("blocked_by_id", blockedByID).
+			Msg("dependency rejected: would create a cycle")
+		return apperror.NewConflictError("dependency would create a cycle")
+	}
+
+	_, err = tx.Exec(ctx,
+		`INSERT INTO task_dependencies (task_id, blocked_by_id) VALUES ($1, $2)
+		 ON CONFLICT DO NOTHING`,
+		taskID,
+		blockedByID,
+	)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("task_id", taskID).
+			Msg("failed to insert dependency")
+		return apperror.NewInternalError("Failed to add dependency", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return apperror.NewInternalError("Failed to add dependency", err)
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("task_id", taskID).
+		Str("blocked_by_id", blockedByID).
+		Msg("task dependency added successfully")
+	return nil
+}
+
+// RemoveDependency delete edge
+// =========================================================================
+func (tr *taskRepository) RemoveDependency(ctx context.Context, taskID string, blockedByID string) error {
+	cmd, err := tr.db.Exec(ctx,
+		`DELETE FROM task_dependencies WHERE task_id = $1 AND blocked_by_id = $2`,
+		taskID,
+		blockedByID,
+	)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("task_id", taskID).
+			Msg("failed to remove dependency")
+		return apperror.NewInternalError("Failed to remove dependency", err)
+	}
+	if cmd.RowsAffected() == 0 {
+		return apperror.NewNotFoundError("dependency not found")
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("task_id", taskID).
+		Str("blocked_by_id", blockedByID).
+		Msg("task dependency removed successfully")
+	return nil
+}
+
+// GetBlockers tasks that block taskID
+// =========================================================================
+func (tr *taskRepository) GetBlockers(ctx context.Context, taskID string) ([]*models.Task, error) {
+	rows, err := tr.db.Query(ctx,
+		`SELECT t.id, t.title, t.content, t.status, t.due_at, t.series_id, t.created_at, t.updated_at, t.user_id,
+		        t.checklist_total, t.checklist_done, t.labels
+		 FROM task_dependencies d JOIN tasks t ON t.id = d.blocked_by_id
+		 WHERE d.task_id = $1
+		 ORDER BY t.created_at`,
+		taskID,
+	)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("task_id", taskID).
+			Msg("failed to query blockers")
+		return nil, err
+	}
+	return scanTasks(rows)
+}
+
+// GetDependents tasks blocked by taskID
+// =========================================================================
+func (tr *taskRepository) GetDependents(ctx context.Context, taskID string) ([]*models.Task, error) {
+	rows, err := tr.db.Query(ctx,
+		`SELECT t.id, t.title, t.content, t.status, t.due_at, t.series_id, t.created_at, t.updated_at, t.user_id,
+		        t.checklist_total, t.checklist_done, t.labels
+		 FROM task_dependencies d JOIN tasks t ON t.id = d.task_id
+		 WHERE d.blocked_by_id = $1
+		 ORDER BY t.created_at`,
+		taskID,
+	)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("task_id", taskID).
+			Msg("failed to query dependents")
+		return nil, err
+	}
+	return scanTasks(rows)
+}
+
+// GetDependencyEdges every edge between tasks of a user
+// =========================================================================
+func (tr *taskRepository) GetDependencyEdges(ctx context.Context, userID string) ([]*models.TaskDependency, error) {
+	rows, err := tr.db.Query(ctx,
+		`SELECT d.task_id, d.blocked_by_id, d.created_at
+		 FROM task_dependencies d JOIN tasks t ON t.id = d.task_id
+		 WHERE t.user_id = $1`,
+		userID,
+	)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("user_id", userID).
+			Msg("failed to query dependency edges")
+		return nil, err
+	}
+	defer rows.Close()
+
+	edges := []*models.TaskDependency{}
+	for rows.Next() {
+		var edge models.TaskDependency
+		if err := rows.Scan(&edge.TaskID, &edge.BlockedByID, &edge.CreatedAt); err != nil {
+			return nil, err
+		}
+		edges = append(edges, &edge)
+	}
+	return edges, rows.Err()
+}
+
+func scanTasks(rows pgx.Rows) ([]*models.Task, error) {
+	defer rows.Close()
+
+	tasks := []*models.Task{}
+	for rows.Next() {
+		var task models.Task
+		err := rows.Scan(
+			&task.ID,
+			&task.Title,
+			&task.Content,
+			&task.Status,
+			&task.DueAt,
+			&task.SeriesID,
+			&task.CreatedAt,
+			&task.UpdatedAt,
+			&task.UserID,
+			&task.ChecklistTotal,
+			&task.ChecklistDone,
+			&task.Labels,
+		)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, &task)
+	}
+	return tasks, rows.Err()
+}
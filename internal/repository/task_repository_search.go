@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+)
+
+// headlineOptions snippet settings, matches are wrapped in <mark></mark>
+const headlineOptions = `StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=20, MinWords=5, FragmentDelimiter=" … "`
+
+// SearchTasks ranked full-text search with prefix matching
+// =========================================================================
+func (tr *taskRepository) SearchTasks(ctx context.Context, query *models.TaskSearchQuery) (*models.TaskSearchResult, error) {
+	zerolog.Ctx(ctx).Debug().
+		Str("user_id", query.UserID).
+		Strs("terms", query.Terms).
+		Strs("labels", query.Labels).
+		Msg("searching tasks")
+
+	// every term must match, each as a prefix ("rep" finds "report")
+	prefixes := make([]string, 0, len(query.Terms))
+	for _, term := range query.Terms {
+		prefixes = append(prefixes, term+":*")
+	}
+
+	args := []any{query.UserID, strings.Join(prefixes, " & ")}
+	where := []string{"t.user_id = $1", "t.search_vector @@ q"}
+	filter := func(cond string, value any) {
+		args = append(args, value)
+		where = append(where, fmt.Sprintf(cond, len(args)))
+	}
+	if len(query.Statuses) > 0 {
+		filter("t.status = ANY($%d)", query.Statuses)
+	}
+	if len(query.Labels) > 0 {
+		filter("t.labels @> $%d", query.Labels)
+	}
+	if query.DueFrom != nil {
+		filter("t.due_at >= $%d", *query.DueFrom)
+	}
+	if query.DueTo != nil {
+		filter("t.due_at < $%d", *query.DueTo)
+	}
+	if query.CreatedFrom != nil {
+		filter("t.created_at >= $%d", *query.CreatedFrom)
+	}
+	if query.CreatedTo != nil {
+		filter("t.created_at < $%d", *query.CreatedTo)
+	}
+	args = append(args, query.Limit, query.Offset)
+
+	// rank and page first, ts_headline is expensive so it only runs on the page
+	sql := fmt.Sprintf(
+		`WITH hits AS (
+		     SELECT t.id, t.title, t.content, t.status, t.due_at, t.series_id, t.created_at, t.updated_at, t.user_id,
+		            t.checklist_total, t.checklist_done, t.labels,
+		            ts_rank_cd(t.search_vector, q) AS rank,
+		            COUNT(*) OVER () AS total
+		     FROM tasks t, to_tsquery('english', $2) q
+		     WHERE %s
+		     ORDER BY rank DESC, t.updated_at DESC, t.id
+		     LIMIT $%d OFFSET $%d
+		 )
+		 SELECT h.id, h.title, h.content, h.status, h.due_at, h.series_id, h.created_at, h.updated_at, h.user_id,
+		        h.checklist_total, h.checklist_done, h.labels, h.rank,
+		        ts_headline('english', h.title, q, 'StartSel=<mark>, StopSel=</mark>, HighlightAll=true'),
+		        ts_headline('english', coalesce(h.content, ''), q, '%s'),
+		        h.total
+		 FROM hits h, to_tsquery('english', $2) q
+		 ORDER BY h.rank DESC, h.updated_at DESC, h.id`,
+		strings.Join(where, " AND "),
+		len(args)-1,
+		len(args),
+		headlineOptions,
+	)
+
+	rows, err := tr.db.Query(ctx, sql, args...)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("user_id", query.UserID).
+			Msg("failed to search tasks")
+		return nil, err
+	}
+	defer rows.Close()
+
+	result := &models.TaskSearchResult{
+		Hits:   []*models.TaskSearchHit{},
+		Limit:  query.Limit,
+		Offset: query.Offset,
+	}
+	for rows.Next() {
+		var task models.Task
+		hit := &models.TaskSearchHit{Task: &task}
+		err := rows.Scan(
+			&task.ID,
+			&task.Title,
+			&task.Content,
+			&task.Status,
+			&task.DueAt,
+			&task.SeriesID,
+			&task.CreatedAt,
+			&task.UpdatedAt,
+			&task.UserID,
+			&task.ChecklistTotal,
+			&task.ChecklistDone,
+			&task.Labels,
+			&hit.Rank,
+			&hit.TitleHighlight,
+			&hit.Snippet,
+			&result.Total,
+		)
+		if err != nil {
+			zerolog.Ctx(ctx).Error().
+				Err(err).
+				Str("user_id", query.UserID).
+				Msg("failed to scan search hit")
+			return nil, err
+		}
+		result.Hits = append(result.Hits, hit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("user_id", query.UserID).
+		Int("hit_count", len(result.Hits)).
+		Int("total", result.Total).
+		Msg("task search completed")
+	return result, nil
+}
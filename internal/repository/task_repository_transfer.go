@@ -0,0 +1,191 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/rs/zerolog"
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+)
+
+// ListTasksPage keyset page of a user's tasks, oldest first
+// =========================================================================
+func (tr *taskRepository) ListTasksPage(ctx context.Context, userID string, after *models.Task, limit int) ([]*models.Task, error) {
+	args := []any{userID, limit}
+	cond := ""
+	if after != nil {
+		args = append(args, after.CreatedAt, after.ID)
+		cond = "AND (created_at, id) > ($3, $4)"
+	}
+
+	rows, err := tr.db.Query(ctx,
+		`SELECT id, title, content, status, due_at, series_id, created_at, updated_at, user_id,
+		        checklist_total, checklist_done, labels, external_id
+		 FROM tasks
+		 WHERE user_id = $1 `+cond+`
+		 ORDER BY created_at, id
+		 LIMIT $2`,
+		args...,
+	)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("user_id", userID).
+			Msg("failed to query task page")
+		return nil, err
+	}
+	defer rows.Close()
+
+	tasks := []*models.Task{}
+	for rows.Next() {
+		var task models.Task
+		err := rows.Scan(
+			&task.ID,
+			&task.Title,
+			&task.Content,
+			&task.Status,
+			&task.DueAt,
+			&task.SeriesID,
+			&task.CreatedAt,
+			&task.UpdatedAt,
+			&task.UserID,
+			&task.ChecklistTotal,
+			&task.ChecklistDone,
+			&task.Labels,
+			&task.ExternalID,
+		)
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, &task)
+	}
+	return tasks, rows.Err()
+}
+
+// GetTaskIDsByExternalIDs map external id -> task id for the ones that exist
+// =========================================================================
+func (tr *taskRepository) GetTaskIDsByExternalIDs(ctx context.Context, userID string, externalIDs []string) (map[string]string, error) {
+	ids := make(map[string]string)
+	if len(externalIDs) == 0 {
+		return ids, nil
+	}
+
+	rows, err := tr.db.Query(ctx,
+		`SELECT external_id, id FROM tasks WHERE user_id = $1 AND external_id = ANY($2)`,
+		userID,
+		externalIDs,
+	)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("user_id", userID).
+			Msg("failed to query external ids")
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var externalID, id string
+		if err := rows.Scan(&externalID, &id); err != nil {
+			return nil, err
+		}
+		ids[externalID] = id
+	}
+	return ids, rows.Err()
+}
+
+// ImportTasks insert or upsert imported tasks, one savepoint per row
+// =========================================================================
+func (tr *taskRepository) ImportTasks(ctx context.Context, tasks []*models.Task, upsert bool) ([]bool, []error, error) {
+	zerolog.Ctx(ctx).Debug().
+		Int("task_count", len(tasks)).
+		Bool("upsert", upsert).
+		Msg("importing tasks")
+
+	query := `INSERT INTO tasks (title, content, user_id, status, due_at, external_id, labels)
+	          VALUES ($1, $2, $3, COALESCE(NULLIF($4, ''), 'todo'), $5, $6, COALESCE($7::text[], '{}'))
+	          RETURNING id, true`
+	if upsert {
+		// missing status/due_at/labels keep the stored value; xmax is 0 only for fresh inserts
+		query = `INSERT INTO tasks (title, content, user_id, status, due_at, external_id, labels)
+		         VALUES ($1, $2, $3, COALESCE(NULLIF($4, ''), 'todo'), $5, $6, COALESCE($7::text[], '{}'))
+		         ON CONFLICT (user_id, external_id) WHERE external_id IS NOT NULL DO UPDATE
+		         SET title = EXCLUDED.title, content = EXCLUDED.content,
+		             status = COALESCE(NULLIF($4, ''), tasks.status),
+		             due_at = COALESCE(EXCLUDED.due_at, tasks.due_at),
+		             labels = COALESCE($7::text[], tasks.labels),
+		             updated_at = NOW()
+		         RETURNING id, (xmax = 0)`
+	}
+
+	tx, err := tr.db.Begin(ctx)
+	if err != nil {
+		return nil, nil, apperror.NewInternalError("Failed to import tasks", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// an import belongs to one user, its growth is checked once all rows ran
+	var userID string
+	if len(tasks) > 0 {
+		userID = tasks[0].UserID
+	}
+	usage, err := lockTaskQuota(ctx, tx, userID, tr.quota)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	inserted := make([]bool, len(tasks))
+	errs := make([]error, len(tasks))
+	for i, task := range tasks {
+		sp, err := tx.Begin(ctx)
+		if err != nil {
+			return nil, nil, apperror.NewInternalError("Failed to import tasks", err)
+		}
+
+		err = sp.QueryRow(ctx, query,
+			task.Title,
+			task.Content,
+			task.UserID,
+			task.Status,
+			task.DueAt,
+			task.ExternalID,
+			task.Labels,
+		).Scan(&task.ID, &inserted[i])
+		if err != nil {
+			sp.Rollback(ctx)
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+				errs[i] = apperror.NewConflictError("external_id already exists")
+			} else {
+				errs[i] = apperror.NewInternalError("Failed to import task", err)
+			}
+			zerolog.Ctx(ctx).Warn().
+				Err(err).
+				Int("index", i).
+				Msg("task import row failed")
+			continue
+		}
+		if err := sp.Commit(ctx); err != nil {
+			return nil, nil, apperror.NewInternalError("Failed to import tasks", err)
+		}
+	}
+
+	if err := checkTaskQuotaGrowth(ctx, tx, userID, tr.quota, usage); err != nil {
+		return nil, nil, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Int("task_count", len(tasks)).
+			Msg("failed to commit task import")
+		return nil, nil, apperror.NewInternalError("Failed to import tasks", err)
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Int("task_count", len(tasks)).
+		Bool("upsert", upsert).
+		Msg("tasks imported successfully")
+	return inserted, errs, nil
+}
@@ -0,0 +1,284 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/logger"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+)
+
+type taskSeriesRepository struct {
+	db *pgxpool.Pool
+	// generated occurrences count against the quotas like any other task
+	quota models.TaskQuota
+}
+
+func NewTaskSeriesRepository(db *pgxpool.Pool, quota models.TaskQuota) ports.TaskSeriesRepository {
+	logger.Log.Info().Msg("initializing task series repository")
+	return &taskSeriesRepository{db: db, quota: quota}
+}
+
+// CreateSeries create a recurring task template
+// =========================================================================
+func (sr *taskSeriesRepository) CreateSeries(ctx context.Context, series *models.TaskSeries) (string, error) {
+	zerolog.Ctx(ctx).Debug().
+		Str("user_id", series.UserID).
+		Str("rrule", series.RRule).
+		Msg("creating task series")
+
+	var id string
+	err := sr.db.QueryRow(ctx,
+		`INSERT INTO task_series (user_id, title, content, rrule, timezone, dtstart, mode)
+		 VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`,
+		series.UserID,
+		series.Title,
+		series.Content,
+		series.RRule,
+		series.Timezone,
+		series.DTStart,
+		series.Mode,
+	).Scan(&id)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("user_id", series.UserID).
+			Msg("failed to create task series")
+		return "", err
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("series_id", id).
+		Str("user_id", series.UserID).
+		Str("rrule", series.RRule).
+		Msg("task series created successfully")
+	return id, nil
+}
+
+// GetSeriesByID get series by id
+// =========================================================================
+func (sr *taskSeriesRepository) GetSeriesByID(ctx context.Context, id string) (*models.TaskSeries, error) {
+	zerolog.Ctx(ctx).Debug().
+		Str("series_id", id).
+		Msg("fetching task series by id")
+
+	series := new(models.TaskSeries)
+	err := sr.db.QueryRow(ctx,
+		`SELECT id, user_id, title, content, rrule, timezone, dtstart, mode, created_at, updated_at
+		 FROM task_series WHERE id = $1`,
+		id,
+	).Scan(
+		&series.ID,
+		&series.UserID,
+		&series.Title,
+		&series.Content,
+		&series.RRule,
+		&series.Timezone,
+		&series.DTStart,
+		&series.Mode,
+		&series.CreatedAt,
+		&series.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			zerolog.Ctx(ctx).Warn().
+				Str("series_id", id).
+				Msg("task series not found")
+			return nil, apperror.NewNotFoundError("task series not found")
+		}
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("series_id", id).
+			Msg("failed to fetch task series")
+		return nil, err
+	}
+	return series, nil
+}
+
+// UpdateSeries update template and schedule of a series
+// =========================================================================
+func (sr *taskSeriesRepository) UpdateSeries(ctx context.Context, id string, series *models.TaskSeries) error {
+	zerolog.Ctx(ctx).Debug().
+		Str("series_id", id).
+		Msg("updating task series")
+
+	cmd, err := sr.db.Exec(ctx,
+		`UPDATE task_series
+		 SET title = $1, content = $2, rrule = $3, timezone = $4, dtstart = $5, mode = $6, updated_at = NOW()
+		 WHERE id = $7`,
+		series.Title,
+		series.Content,
+		series.RRule,
+		series.Timezone,
+		series.DTStart,
+		series.Mode,
+		id,
+	)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("series_id", id).
+			Msg("failed to update task series")
+		return err
+	}
+
+	if cmd.RowsAffected() == 0 {
+		return apperror.NewNotFoundError("task series not found")
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("series_id", id).
+		Msg("task series updated successfully")
+	return nil
+}
+
+// CreateOccurrence insert next occurrence, ignoring duplicates
+// =========================================================================
+func (sr *taskSeriesRepository) CreateOccurrence(ctx context.Context, task *models.Task) (string, bool, error) {
+	zerolog.Ctx(ctx).Debug().
+		Str("user_id", task.UserID).
+		Interface("series_id", task.SeriesID).
+		Interface("due_at", task.DueAt).
+		Msg("creating task occurrence")
+
+	tx, err := sr.db.Begin(ctx)
+	if err != nil {
+		return "", false, err
+	}
+	defer tx.Rollback(ctx)
+
+	usage, err := lockTaskQuota(ctx, tx, task.UserID, sr.quota)
+	if err != nil {
+		return "", false, err
+	}
+	if err := checkTaskQuota(ctx, task.UserID, sr.quota, usage, 1, models.TaskContentBytes(task.Title, task.Content)); err != nil {
+		return "", false, err
+	}
+
+	var id string
+	err = tx.QueryRow(ctx,
+		`INSERT INTO tasks (title, content, user_id, status, due_at, series_id)
+		 VALUES ($1, $2, $3, $4, $5, $6)
+		 ON CONFLICT (series_id, due_at) WHERE series_id IS NOT NULL DO NOTHING
+		 RETURNING id`,
+		task.Title,
+		task.Content,
+		task.UserID,
+		models.TaskStatusTodo,
+		task.DueAt,
+		task.SeriesID,
+	).Scan(&id)
+	if errors.Is(err, pgx.ErrNoRows) {
+		zerolog.Ctx(ctx).Debug().
+			Interface("series_id", task.SeriesID).
+			Interface("due_at", task.DueAt).
+			Msg("occurrence already exists, skipping")
+		return "", false, nil
+	}
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Interface("series_id", task.SeriesID).
+			Msg("failed to create task occurrence")
+		return "", false, err
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return "", false, err
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("task_id", id).
+		Interface("series_id", task.SeriesID).
+		Interface("due_at", task.DueAt).
+		Msg("task occurrence created successfully")
+	return id, true, nil
+}
+
+// GetOpenOccurrences not-done occurrences due strictly after a point in time
+// =========================================================================
+func (sr *taskSeriesRepository) GetOpenOccurrences(ctx context.Context, seriesID string, after time.Time) ([]*models.Task, error) {
+	rows, err := sr.db.Query(ctx,
+		`SELECT id, user_id, title, content, status, due_at, series_id, created_at, updated_at
+		 FROM tasks
+		 WHERE series_id = $1 AND status <> $2 AND due_at > $3
+		 ORDER BY due_at`,
+		seriesID,
+		models.TaskStatusDone,
+		after,
+	)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("series_id", seriesID).
+			Msg("failed to query open occurrences")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tasks []*models.Task
+	for rows.Next() {
+		var task models.Task
+		if err := rows.Scan(&task.ID, &task.UserID, &task.Title, &task.Content, &task.Status, &task.DueAt, &task.SeriesID, &task.CreatedAt, &task.UpdatedAt); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, &task)
+	}
+	return tasks, rows.Err()
+}
+
+// GetDueScheduledSeries scheduled series whose newest occurrence is due
+// =========================================================================
+func (sr *taskSeriesRepository) GetDueScheduledSeries(ctx context.Context, now time.Time) ([]*ports.DueSeries, error) {
+	rows, err := sr.db.Query(ctx,
+		`SELECT s.id, s.user_id, s.title, s.content, s.rrule, s.timezone, s.dtstart, s.mode, s.created_at, s.updated_at,
+		        MAX(t.due_at) AS latest_due_at
+		 FROM task_series s
+		 JOIN tasks t ON t.series_id = s.id
+		 WHERE s.mode = $1
+		 GROUP BY s.id
+		 HAVING MAX(t.due_at) <= $2`,
+		models.SeriesModeScheduled,
+		now,
+	)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Msg("failed to query due scheduled series")
+		return nil, err
+	}
+	defer rows.Close()
+
+	var due []*ports.DueSeries
+	for rows.Next() {
+		var series models.TaskSeries
+		var latest time.Time
+		err := rows.Scan(
+			&series.ID,
+			&series.UserID,
+			&series.Title,
+			&series.Content,
+			&series.RRule,
+			&series.Timezone,
+			&series.DTStart,
+			&series.Mode,
+			&series.CreatedAt,
+			&series.UpdatedAt,
+			&latest,
+		)
+		if err != nil {
+			return nil, err
+		}
+		due = append(due, &ports.DueSeries{Series: &series, LatestDueAt: latest})
+	}
+
+	zerolog.Ctx(ctx).Debug().
+		Int("series_count", len(due)).
+		Msg("fetched due scheduled series")
+	return due, rows.Err()
+}
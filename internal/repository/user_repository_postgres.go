@@ -6,6 +6,8 @@ import (
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/rs/zerolog"
 	"github.com/suryansh74/task-management-api-project/internal/apperror"
 	"github.com/suryansh74/task-management-api-project/internal/logger"
 	"github.com/suryansh74/task-management-api-project/internal/models"
@@ -13,16 +15,16 @@ import (
 )
 
 type userRepository struct {
-	db *pgx.Conn
+	db *pgxpool.Pool
 }
 
-func NewUserRepository(db *pgx.Conn) ports.UserRepository {
+func NewUserRepository(db *pgxpool.Pool) ports.UserRepository {
 	logger.Log.Info().Msg("initializing user repository")
 	return &userRepository{db: db}
 }
 
 func (ur *userRepository) CreateUser(ctx context.Context, user *models.User) (string, error) {
-	logger.Log.Debug().
+	zerolog.Ctx(ctx).Debug().
 		Str("email", user.Email).
 		Str("name", user.Name).
 		Msg("creating new user")
@@ -34,13 +36,13 @@ func (ur *userRepository) CreateUser(ctx context.Context, user *models.User) (st
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
-			logger.Log.Warn().
+			zerolog.Ctx(ctx).Warn().
 				Str("email", user.Email).
 				Str("pg_error_code", pgErr.Code).
 				Msg("duplicate user registration attempt")
 			return "", apperror.NewConflictError("User with this email already exists")
 		}
-		logger.Log.Error().
+		zerolog.Ctx(ctx).Error().
 			Err(err).
 			Str("email", user.Email).
 			Str("name", user.Name).
@@ -48,7 +50,7 @@ func (ur *userRepository) CreateUser(ctx context.Context, user *models.User) (st
 		return "", apperror.NewInternalError("Failed to create user", err)
 	}
 
-	logger.Log.Info().
+	zerolog.Ctx(ctx).Info().
 		Str("user_id", id).
 		Str("email", user.Email).
 		Str("name", user.Name).
@@ -57,32 +59,125 @@ func (ur *userRepository) CreateUser(ctx context.Context, user *models.User) (st
 }
 
 func (ur *userRepository) FindByEmail(ctx context.Context, email string) (*models.User, error) {
-	logger.Log.Debug().
+	zerolog.Ctx(ctx).Debug().
 		Str("email", email).
 		Msg("finding user by email")
 
 	var user models.User
 	err := ur.db.QueryRow(ctx,
-		"SELECT id, name, email, password FROM users WHERE email = $1",
+		"SELECT id, name, email, password, email_verified_at FROM users WHERE email = $1",
 		email,
-	).Scan(&user.ID, &user.Name, &user.Email, &user.Password)
+	).Scan(&user.ID, &user.Name, &user.Email, &user.Password, &user.EmailVerifiedAt)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			logger.Log.Warn().
+			zerolog.Ctx(ctx).Warn().
 				Str("email", email).
 				Msg("user not found")
 			return nil, apperror.NewNotFoundError("User not found")
 		}
-		logger.Log.Error().
+		zerolog.Ctx(ctx).Error().
 			Err(err).
 			Str("email", email).
 			Msg("failed to find user by email")
 		return nil, apperror.NewInternalError("Failed to retrieve user", err)
 	}
 
-	logger.Log.Info().
+	zerolog.Ctx(ctx).Info().
 		Str("user_id", user.ID).
 		Str("email", email).
 		Msg("user found successfully")
 	return &user, nil
 }
+
+func (ur *userRepository) FindByID(ctx context.Context, id string) (*models.User, error) {
+	zerolog.Ctx(ctx).Debug().
+		Str("user_id", id).
+		Msg("finding user by id")
+
+	var user models.User
+	err := ur.db.QueryRow(ctx,
+		"SELECT id, name, email, password, email_verified_at FROM users WHERE id = $1",
+		id,
+	).Scan(&user.ID, &user.Name, &user.Email, &user.Password, &user.EmailVerifiedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			zerolog.Ctx(ctx).Warn().
+				Str("user_id", id).
+				Msg("user not found")
+			return nil, apperror.NewNotFoundError("User not found")
+		}
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "22P02" {
+			// not a uuid, can't match any user
+			return nil, apperror.NewNotFoundError("User not found")
+		}
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("user_id", id).
+			Msg("failed to find user by id")
+		return nil, apperror.NewInternalError("Failed to retrieve user", err)
+	}
+
+	return &user, nil
+}
+
+func (ur *userRepository) UpdatePassword(ctx context.Context, userID string, hashedPassword string) error {
+	zerolog.Ctx(ctx).Debug().
+		Str("user_id", userID).
+		Msg("updating user password")
+
+	cmd, err := ur.db.Exec(ctx,
+		"UPDATE users SET password = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1",
+		userID, hashedPassword,
+	)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("user_id", userID).
+			Msg("failed to update user password")
+		return apperror.NewInternalError("Failed to update password", err)
+	}
+	if cmd.RowsAffected() == 0 {
+		zerolog.Ctx(ctx).Warn().
+			Str("user_id", userID).
+			Msg("user not found")
+		return apperror.NewNotFoundError("User not found")
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("user_id", userID).
+		Msg("user password updated successfully")
+	return nil
+}
+
+func (ur *userRepository) MarkEmailVerified(ctx context.Context, userID string, email string) error {
+	zerolog.Ctx(ctx).Debug().
+		Str("user_id", userID).
+		Msg("marking user email verified")
+
+	// the email is matched too, a link sent to an old address verifies nothing
+	cmd, err := ur.db.Exec(ctx,
+		`UPDATE users
+		SET email_verified_at = COALESCE(email_verified_at, CURRENT_TIMESTAMP), updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND email = $2`,
+		userID, email,
+	)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("user_id", userID).
+			Msg("failed to mark user email verified")
+		return apperror.NewInternalError("Failed to verify email", err)
+	}
+	if cmd.RowsAffected() == 0 {
+		zerolog.Ctx(ctx).Warn().
+			Str("user_id", userID).
+			Msg("user not found")
+		return apperror.NewNotFoundError("User not found")
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("user_id", userID).
+		Msg("user email verified successfully")
+	return nil
+}
@@ -1,52 +1,254 @@
 package server
 
 import (
+	"context"
 	"fmt"
+	"os/signal"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/v9"
 	grpcadapter "github.com/suryansh74/task-management-api-project/internal/adapter/grpc"
 	"github.com/suryansh74/task-management-api-project/internal/config"
 	"github.com/suryansh74/task-management-api-project/internal/handler"
 	"github.com/suryansh74/task-management-api-project/internal/logger"
+	"github.com/suryansh74/task-management-api-project/internal/models"
 	"github.com/suryansh74/task-management-api-project/internal/ports"
 	"github.com/suryansh74/task-management-api-project/internal/repository"
 	"github.com/suryansh74/task-management-api-project/internal/service"
+	"github.com/suryansh74/task-management-api-project/internal/utils"
 )
 
 type server struct {
 	app            *fiber.App
 	redisClient    *redis.Client
-	postgresClient *pgx.Conn
+	postgresClient *pgxpool.Pool
 	cfg            *config.Config
+
+	idempotencyStore ports.IdempotencyStore
+	rateLimiter      ports.RateLimiter
+	rateLimits       map[string]models.RateLimitRule
+	routeRateLimits  map[string]models.RateLimitRule
+	healthService    ports.HealthService
+
+	// issues reset links in the background, drained on shutdown
+	passwordResetService ports.PasswordResetService
+
+	// what sessions with an unverified email may do, a models.EmailVerification* policy
+	emailVerificationPolicy string
+
+	// set once shutdown begins, fails the readiness probe
+	shuttingDown atomic.Bool
 }
 
-// StartServer wires repositories → services → adapters (REST + gRPC) and starts both servers.
-func StartServer(app *fiber.App, redisClient *redis.Client, postgresClient *pgx.Conn, cfg *config.Config) {
+// StartServer wires repositories → services → adapters (REST + gRPC) and runs both servers
+// until SIGINT/SIGTERM or a server failure, then shuts everything down and closes the clients.
+func StartServer(app *fiber.App, redisClient *redis.Client, postgresClient *pgxpool.Pool, cfg *config.Config) error {
 	server := &server{
 		app:            app,
 		redisClient:    redisClient,
 		postgresClient: postgresClient,
 		cfg:            cfg,
 	}
+	rateLimits, routeRateLimits, err := loadRateLimits(cfg)
+	if err != nil {
+		logger.Log.Fatal().Err(err).Msg("invalid rate limit config")
+	}
+	server.rateLimits = rateLimits
+	server.routeRateLimits = routeRateLimits
+	server.emailVerificationPolicy, err = models.ParseEmailVerificationPolicy(cfg.EmailVerificationPolicy)
+	if err != nil {
+		logger.Log.Fatal().Err(err).Msg("invalid email verification config")
+	}
+	// larger bodies and longer timeouts only for uploads, downloads and exports
+	app.Server().HeaderReceived = server.requestConfig
+
+	taskQuota := models.TaskQuota{
+		MaxTasks:        cfg.QuotaMaxTasks,
+		MaxContentBytes: cfg.QuotaMaxContentBytes,
+	}
 
 	// Initialize repositories (driven adapters)
 	var userRepo ports.UserRepository = repository.NewUserRepository(postgresClient)
-	var sessionRepo ports.SessionRepository = repository.NewSessionRepository(redisClient)
-	var taskRepo ports.TaskRepository = repository.NewTaskRepository(postgresClient)
-	var taskCacheRepo ports.TaskCacheRepository = repository.NewTaskCacheRepository(redisClient)
+	var sessionRepo ports.SessionRepository = repository.NewSessionRepository(redisClient, cfg.RedisAppName)
+	var taskRepo ports.TaskRepository = repository.NewTaskRepository(postgresClient, taskQuota)
+	var taskCacheRepo ports.TaskCacheRepository = repository.NewTaskCacheRepository(redisClient, cfg.RedisAppName, cfg.CacheExpiration)
+	var localTaskCache ports.LocalTaskCache
+	if cfg.CacheLocalSize > 0 {
+		localTaskCache = repository.NewLocalTaskCache(taskCacheRepo, redisClient, cfg.RedisAppName, cfg.CacheLocalSize, cfg.CacheLocalTTL)
+		taskCacheRepo = localTaskCache
+	}
+	var taskSeriesRepo ports.TaskSeriesRepository = repository.NewTaskSeriesRepository(postgresClient, taskQuota)
+	var attachmentRepo ports.AttachmentRepository = repository.NewAttachmentRepository(postgresClient)
+	var reminderRepo ports.ReminderRepository = repository.NewReminderRepository(postgresClient)
+	var reminderQueue ports.ReminderQueue = repository.NewReminderQueue(redisClient, cfg.RedisAppName)
+	var checklistRepo ports.ChecklistRepository = repository.NewChecklistRepository(postgresClient)
+	var prefsRepo ports.NotificationPreferencesRepository = repository.NewNotificationPreferencesRepository(postgresClient)
+	var calendarFeedRepo ports.CalendarFeedRepository = repository.NewCalendarFeedRepository(postgresClient)
+	var mailQueue ports.MailQueue = repository.NewMailQueue(redisClient, cfg.RedisAppName, cfg.MailClaimTimeout)
+	server.idempotencyStore = repository.NewIdempotencyStore(redisClient, cfg.RedisAppName)
+	server.rateLimiter = repository.NewRateLimiter(redisClient, cfg.RedisAppName)
+	var passwordResetStore ports.PasswordResetStore = repository.NewPasswordResetStore(redisClient, cfg.RedisAppName)
+	var mfaRepo ports.MFARepository = repository.NewMFARepository(postgresClient)
+	var mfaChallengeStore ports.MFAChallengeStore = repository.NewMFAChallengeStore(redisClient, cfg.RedisAppName)
+	var loginAttemptStore ports.LoginAttemptStore = repository.NewLoginAttemptStore(redisClient, cfg.RedisAppName)
+	var auditLog ports.AuditLog = repository.NewLogAuditLog()
+	var healthRepo ports.HealthRepository = repository.NewHealthRepository(postgresClient, redisClient)
+	if cfg.BlobSigningKey == "" {
+		// links signed with a per-process key break on restart and across replicas
+		key, err := utils.GenerateRandomID(32)
+		if err != nil {
+			logger.Log.Fatal().Err(err).Msg("unable to generate blob signing key")
+		}
+		cfg.BlobSigningKey = key
+		logger.Log.Warn().Msg("BLOB_SIGNING_KEY not set, using an ephemeral key")
+	}
+	if cfg.EmailVerificationSigningKey == "" {
+		// links signed with a per-process key break on restart and across replicas
+		key, err := utils.GenerateRandomID(32)
+		if err != nil {
+			logger.Log.Fatal().Err(err).Msg("unable to generate email verification signing key")
+		}
+		cfg.EmailVerificationSigningKey = key
+		logger.Log.Warn().Msg("EMAIL_VERIFICATION_SIGNING_KEY not set, using an ephemeral key")
+	}
+	if cfg.MFAEncryptionKey == "" {
+		// TOTP secrets enrolled under a per-process key are unreadable after a restart,
+		// those users can only log in with a recovery code
+		key, err := utils.GenerateRandomID(32)
+		if err != nil {
+			logger.Log.Fatal().Err(err).Msg("unable to generate mfa encryption key")
+		}
+		cfg.MFAEncryptionKey = key
+		logger.Log.Warn().Msg("MFA_ENCRYPTION_KEY not set, using an ephemeral key")
+	}
+	blobStore, err := newBlobStore(cfg)
+	if err != nil {
+		logger.Log.Fatal().Err(err).Str("blob_store", cfg.BlobStore).Msg("blob store init failed")
+	}
 
 	// Initialize services (application core)
-	var userService ports.UserService = service.NewUserService(userRepo)
+	server.healthService = service.NewHealthService(healthRepo, cfg.HealthCheckTimeout)
+	loginProtection := models.LoginProtection{
+		MaxFailures:      cfg.LoginMaxFailures,
+		Window:           cfg.LoginFailureWindow,
+		Lockout:          cfg.LoginLockoutDuration,
+		BaseDelay:        cfg.LoginBaseDelay,
+		MaxDelay:         cfg.LoginMaxDelay,
+		StuffingAccounts: cfg.LoginStuffingAccounts,
+		StuffingBlock:    cfg.LoginStuffingBlock,
+		DistributedIPs:   cfg.LoginDistributedIPs,
+	}
+	var userService ports.UserService = service.NewUserService(userRepo, mfaRepo, loginAttemptStore, auditLog, loginProtection)
 	var sessionService ports.SessionService = service.NewSessionService(sessionRepo, cfg.SessionExpiration, cfg.RedisAppName)
-	var taskService ports.TaskService = service.NewTaskService(taskRepo, taskCacheRepo, cfg.RedisAppName, cfg.CacheExpiration)
+	var quotaService ports.TaskQuotaService = service.NewTaskQuotaService(taskRepo, taskQuota)
+	var taskService ports.TaskService = service.NewTaskService(taskRepo, taskCacheRepo, cfg.RedisAppName, models.TaskCacheOptions{
+		Expiration:         cfg.CacheExpiration,
+		NegativeExpiration: cfg.CacheNegativeExpiration,
+		Jitter:             cfg.CacheTTLJitter,
+		EarlyRefreshBeta:   cfg.CacheEarlyRefreshBeta,
+	})
+	// every adapter gets the recurrence-aware service so completing an occurrence works everywhere
+	var recurrenceService ports.RecurrenceService = service.NewRecurrenceService(taskService, taskSeriesRepo, taskCacheRepo)
+	taskService = recurrenceService
+	var attachmentService ports.AttachmentService = service.NewAttachmentService(
+		attachmentRepo,
+		blobStore,
+		taskService,
+		cfg.AttachmentMaxSize,
+		cfg.AttachmentAllowedTypes,
+		cfg.BlobURLExpiration,
+		cfg.BlobSigningKey,
+	)
+	var emailService ports.EmailService = service.NewEmailService(newMailer(cfg), mailQueue, prefsRepo, cfg.MailMaxAttempts, cfg.MailRetryBackoff)
+	var dependencyService ports.TaskDependencyService = service.NewTaskDependencyService(taskRepo, taskService)
+	var batchService ports.TaskBatchService = service.NewTaskBatchService(taskRepo, recurrenceService, taskCacheRepo, quotaService, cfg.RedisAppName)
+	var calendarService ports.CalendarFeedService = service.NewCalendarFeedService(calendarFeedRepo, taskRepo, cfg.PublicBaseURL)
+	var transferService ports.TaskTransferService = service.NewTaskTransferService(taskRepo, recurrenceService, taskCacheRepo, quotaService, cfg.RedisAppName)
+	var checklistService ports.ChecklistService = service.NewChecklistService(checklistRepo, taskService, taskCacheRepo, cfg.RedisAppName)
+	if cfg.PasswordResetURL == "" {
+		cfg.PasswordResetURL = strings.TrimRight(cfg.PublicBaseURL, "/") + "/password/reset"
+	}
+	var passwordResetService ports.PasswordResetService = service.NewPasswordResetService(
+		userRepo,
+		passwordResetStore,
+		sessionService,
+		emailService,
+		cfg.PasswordResetURL,
+		cfg.PasswordResetTTL,
+	)
+	if cfg.EmailVerificationURL == "" {
+		cfg.EmailVerificationURL = strings.TrimRight(cfg.PublicBaseURL, "/") + "/email/verify"
+	}
+	var verificationService ports.EmailVerificationService = service.NewEmailVerificationService(
+		userRepo,
+		sessionService,
+		emailService,
+		cfg.EmailVerificationSigningKey,
+		cfg.EmailVerificationURL,
+		cfg.EmailVerificationTTL,
+	)
+	var mfaService ports.MFAService = service.NewMFAService(
+		userRepo,
+		mfaRepo,
+		mfaChallengeStore,
+		loginAttemptStore,
+		auditLog,
+		loginProtection,
+		cfg.MFAEncryptionKey,
+		cfg.MFAIssuer,
+		cfg.MFAChallengeTTL,
+		cfg.MFAMaxAttempts,
+	)
+	var preferencesService ports.NotificationPreferencesService = service.NewNotificationPreferencesService(prefsRepo)
+	var reminderService ports.ReminderService = service.NewReminderService(
+		reminderRepo,
+		reminderQueue,
+		taskService,
+		[]ports.Notifier{
+			repository.NewLogNotifier(),
+			repository.NewWebhookNotifier(cfg.ReminderWebhookSecret, cfg.ReminderWebhookTimeout),
+			service.NewEmailNotifier(emailService),
+		},
+		cfg.ReminderLookahead,
+		cfg.ReminderBatchSize,
+	)
 
 	// Initialize HTTP handlers (driving adapters – REST)
-	var userHandler ports.UserHandler = handler.NewUserHandler(userService, sessionService, cfg.SessionExpiration, cfg.RedisAppName)
-	var taskHandler ports.TaskHandler = handler.NewTaskHandler(taskService, cfg.RedisAppName, cfg.SessionExpiration)
+	var userHandler ports.UserHandler = handler.NewUserHandler(userService, sessionService, verificationService, mfaService, cfg.SessionExpiration, cfg.MFAChallengeTTL, cfg.RedisAppName)
+	var taskHandler ports.TaskHandler = handler.NewTaskHandler(taskService, recurrenceService, cfg.RedisAppName, cfg.SessionExpiration)
+	var attachmentHandler ports.AttachmentHandler = handler.NewAttachmentHandler(attachmentService)
+	var reminderHandler ports.ReminderHandler = handler.NewReminderHandler(reminderService)
+	var notificationHandler ports.NotificationHandler = handler.NewNotificationHandler(preferencesService)
+	var dependencyHandler ports.TaskDependencyHandler = handler.NewTaskDependencyHandler(dependencyService)
+	var checklistHandler ports.ChecklistHandler = handler.NewChecklistHandler(checklistService)
+	var batchHandler ports.TaskBatchHandler = handler.NewTaskBatchHandler(batchService)
+	var transferHandler ports.TaskTransferHandler = handler.NewTaskTransferHandler(transferService)
+	var calendarHandler ports.CalendarFeedHandler = handler.NewCalendarFeedHandler(calendarService)
+	server.passwordResetService = passwordResetService
+	var passwordHandler ports.PasswordHandler = handler.NewPasswordHandler(passwordResetService)
+	var verificationHandler ports.EmailVerificationHandler = handler.NewEmailVerificationHandler(verificationService)
+	var mfaHandler ports.MFAHandler = handler.NewMFAHandler(mfaService)
+
+	server.setupRoutes(userHandler, taskHandler, attachmentHandler, reminderHandler, notificationHandler, dependencyHandler, checklistHandler, batchHandler, transferHandler, calendarHandler, passwordHandler, verificationHandler, mfaHandler)
 
-	server.setupRoutes(userHandler, taskHandler)
+	// Background jobs, stopped after the servers have drained
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	var jobs sync.WaitGroup
+	jobs.Go(func() { service.StartRecurrenceScheduler(jobsCtx, recurrenceService, cfg.RecurrenceInterval) })
+	jobs.Go(func() { service.StartReminderScheduler(jobsCtx, reminderService, cfg.ReminderInterval) })
+	jobs.Go(func() { service.StartMailWorker(jobsCtx, emailService, cfg.MailWorkerInterval) })
+	jobs.Go(func() { service.StartSessionMetrics(jobsCtx, sessionService, cfg.MetricsInterval) })
+	jobs.Go(func() { service.StartBlobSweeper(jobsCtx, attachmentService, cfg.AttachmentSweepInterval) })
+	if localTaskCache != nil {
+		jobs.Go(func() { localTaskCache.Listen(jobsCtx) })
+	}
 
 	// Initialize gRPC server (driving adapter – gRPC)
 	// Shares the same taskService instance as REST
@@ -55,19 +257,151 @@ func StartServer(app *fiber.App, redisClient *redis.Client, postgresClient *pgx.
 		grpcPort = "50051"
 	}
 	grpcAddr := fmt.Sprintf("%s:%s", cfg.ServerHost, grpcPort)
-	grpcServer := grpcadapter.NewServer(grpcAddr, taskService)
+	grpcMethodRateLimits, err := models.ParseMethodRateLimits(cfg.RateLimitGRPCMethods)
+	if err != nil {
+		logger.Log.Fatal().Err(err).Msg("invalid gRPC rate limit config")
+	}
+	grpcServer := grpcadapter.NewServer(grpcAddr, taskService, batchService,
+		grpcadapter.RequestIDInterceptor(),
+		grpcadapter.MetricsInterceptor(),
+		grpcadapter.RateLimitInterceptor(server.rateLimiter, server.rateLimits["grpc"], grpcMethodRateLimits, cfg.RateLimitFailOpen),
+		grpcadapter.IdempotencyInterceptor(server.idempotencyStore, cfg.IdempotencyTTL, cfg.IdempotencyLockTTL),
+	)
+	jobs.Go(func() {
+		service.StartHealthMonitor(jobsCtx, server.healthService, cfg.HealthCheckInterval, grpcServer.SetServing)
+	})
 
-	// Start gRPC in background
+	// Start both servers; either one failing shuts the process down
+	serveErr := make(chan error, 2)
 	go func() {
 		if err := grpcServer.Start(); err != nil {
-			logger.Log.Fatal().Err(err).Msg("gRPC server failed")
+			serveErr <- fmt.Errorf("gRPC server failed: %w", err)
 		}
 	}()
-
-	// Start REST (blocks)
 	addr := fmt.Sprintf("%s:%s", cfg.ServerHost, cfg.ServerPort)
-	logger.Log.Info().Msg("REST server starting on " + addr)
-	if err := app.Listen(addr); err != nil {
-		logger.Log.Fatal().Err(err).Msg("REST server failed")
+	go func() {
+		logger.Log.Info().Msg("REST server starting on " + addr)
+		if err := app.Listen(addr); err != nil {
+			serveErr <- fmt.Errorf("REST server failed: %w", err)
+		}
+	}()
+
+	signalCtx, stopSignals := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stopSignals()
+
+	select {
+	case <-signalCtx.Done():
+		logger.Log.Info().Msg("shutdown signal received")
+		err = nil
+	case err = <-serveErr:
+		logger.Log.Error().Err(err).Msg("server failed, shutting down")
+	}
+	// a second signal terminates immediately
+	stopSignals()
+
+	server.shutdown(grpcServer, stopJobs, &jobs)
+	return err
+}
+
+// shutdown fails readiness, drains REST and gRPC within SHUTDOWN_TIMEOUT,
+// stops the background jobs and reset link issues and closes Redis, then Postgres
+// ==================================================
+func (s *server) shutdown(grpcServer *grpcadapter.Server, stopJobs context.CancelFunc, jobs *sync.WaitGroup) {
+	s.shuttingDown.Store(true)
+	grpcServer.StopServing()
+	logger.Log.Info().
+		Dur("delay", s.cfg.ShutdownDelay).
+		Dur("timeout", s.cfg.ShutdownTimeout).
+		Msg("shutting down")
+
+	// keep serving while load balancers notice the failing readiness probe
+	time.Sleep(s.cfg.ShutdownDelay)
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.cfg.ShutdownTimeout)
+	defer cancel()
+
+	var servers sync.WaitGroup
+	servers.Go(func() {
+		if err := s.app.ShutdownWithContext(ctx); err != nil {
+			logger.Log.Error().Err(err).Msg("REST drain incomplete")
+		}
+		logger.Log.Info().Msg("REST server stopped")
+	})
+	servers.Go(func() {
+		grpcServer.Shutdown(ctx)
+		logger.Log.Info().Msg("gRPC server stopped")
+	})
+	servers.Wait()
+
+	// jobs finish their current run and requested reset links get queued;
+	// both use the clients closed below
+	stopJobs()
+	done := make(chan struct{})
+	go func() {
+		jobs.Wait()
+		s.passwordResetService.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		logger.Log.Warn().Msg("background jobs did not stop before the drain timeout")
+	}
+
+	if err := s.redisClient.Close(); err != nil {
+		logger.Log.Error().Err(err).Msg("failed to close Redis")
+	}
+	// waits for connections still checked out, jobs and handlers are done by now
+	s.postgresClient.Close()
+	logger.Log.Info().Msg("shutdown complete")
+}
+
+// newBlobStore picks the attachment storage backend from config
+// ==================================================
+func newBlobStore(cfg *config.Config) (ports.BlobStore, error) {
+	switch cfg.BlobStore {
+	case "local":
+		return repository.NewLocalBlobStore(cfg.BlobLocalDir, cfg.PublicBaseURL, cfg.BlobSigningKey)
+	case "s3":
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		return repository.NewS3BlobStore(ctx, cfg.S3Endpoint, cfg.S3Region, cfg.S3Bucket, cfg.S3AccessKey, cfg.S3SecretKey, cfg.S3UseSSL)
+	default:
+		return nil, fmt.Errorf("unknown BLOB_STORE %q (want local or s3)", cfg.BlobStore)
+	}
+}
+
+// newMailer SMTP when configured, otherwise emails are only logged
+// ==================================================
+func newMailer(cfg *config.Config) ports.Mailer {
+	if cfg.SMTPHost == "" {
+		return repository.NewLogMailer()
+	}
+	return repository.NewSMTPMailer(cfg.SMTPHost, cfg.SMTPPort, cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPFrom, cfg.SMTPTimeout)
+}
+
+// loadRateLimits named limits used by the routes and the per-route overrides
+// ==================================================
+func loadRateLimits(cfg *config.Config) (map[string]models.RateLimitRule, map[string]models.RateLimitRule, error) {
+	named := map[string]string{
+		"public":       cfg.RateLimitPublic,
+		"task":         cfg.RateLimitTask,
+		"grpc":         cfg.RateLimitGRPC,
+		"password":     cfg.RateLimitPassword,
+		"verification": cfg.RateLimitVerification,
+	}
+	limits := make(map[string]models.RateLimitRule, len(named))
+	for name, spec := range named {
+		rule, err := models.ParseRateLimitRule(spec)
+		if err != nil {
+			return nil, nil, err
+		}
+		limits[name] = rule
+	}
+
+	routes, err := models.ParseRouteRateLimits(cfg.RateLimitRoutes)
+	if err != nil {
+		return nil, nil, err
 	}
+	return limits, routes, nil
 }
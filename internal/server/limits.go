@@ -0,0 +1,39 @@
+package server
+
+import (
+	"strings"
+
+	"github.com/valyala/fasthttp"
+)
+
+// multipartOverhead headroom for multipart boundaries and headers on top of
+// the attachment limit
+const multipartOverhead = 1 << 20
+
+// requestConfig per-request limits on top of the fiber.Config defaults: only
+// attachment uploads may send bodies up to ATTACHMENT_MAX_SIZE and get
+// TRANSFER_TIMEOUT to do so, signed downloads and exports get it to stream
+// their response; everything else keeps the global body limit and timeouts
+// ==================================================
+func (s *server) requestConfig(header *fasthttp.RequestHeader) fasthttp.RequestConfig {
+	path, _, _ := strings.Cut(string(header.RequestURI()), "?")
+	path = strings.ToLower(strings.TrimSuffix(path, "/"))
+	method := string(header.Method())
+
+	switch {
+	case method == fasthttp.MethodPost && isAttachmentUpload(path):
+		return fasthttp.RequestConfig{
+			MaxRequestBodySize: int(s.cfg.AttachmentMaxSize) + multipartOverhead,
+			ReadTimeout:        s.cfg.TransferTimeout,
+		}
+	case method == fasthttp.MethodGet && (path == "/tasks/export" || strings.HasPrefix(path, "/files/")):
+		return fasthttp.RequestConfig{WriteTimeout: s.cfg.TransferTimeout}
+	}
+	return fasthttp.RequestConfig{}
+}
+
+// isAttachmentUpload path matches /tasks/:id/attachments
+func isAttachmentUpload(path string) bool {
+	parts := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	return len(parts) == 3 && parts[0] == "tasks" && parts[1] != "" && parts[2] == "attachments"
+}
@@ -1,14 +1,27 @@
 package server
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"fmt"
-	"time"
+	"math"
+	"strconv"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/rs/zerolog"
 	"github.com/suryansh74/task-management-api-project/internal/apperror"
 	"github.com/suryansh74/task-management-api-project/internal/http/response"
 	"github.com/suryansh74/task-management-api-project/internal/logger"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+	"github.com/suryansh74/task-management-api-project/internal/utils"
+)
+
+const (
+	idempotencyKeyHeader     = "Idempotency-Key"
+	idempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
 )
 
 // ErrorHandler is the global Fiber error handler
@@ -26,11 +39,23 @@ func ErrorHandler() fiber.ErrorHandler {
 	}
 }
 
+// RequestID accepts the caller's X-Request-ID or generates one, echoes it in
+// the response and scopes a logger carrying it to the request context
+// ==================================================
+func RequestID() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		requestID := utils.RequestID(c.Get(fiber.HeaderXRequestID))
+		c.Set(fiber.HeaderXRequestID, requestID)
+		c.SetUserContext(logger.NewRequestContext(c.UserContext(), requestID))
+		return c.Next()
+	}
+}
+
 // RequestLogger logs incoming requests
 // ==================================================
 func RequestLogger() fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		logger.Log.Info().
+		zerolog.Ctx(c.UserContext()).Info().
 			Str("method", c.Method()).
 			Str("path", c.Path()).
 			Str("ip", c.IP()).
@@ -40,9 +65,21 @@ func RequestLogger() fiber.Handler {
 	}
 }
 
-// AuthMiddleware checks if incoming req have cookie with valid session user id
+// AuthMiddleware checks if incoming req have cookie with valid session user id,
+// unverified emails are limited by EMAIL_VERIFICATION_POLICY
 // ==================================================
 func (s *server) AuthMiddleware(c *fiber.Ctx) error {
+	return s.authenticate(c, true)
+}
+
+// UnverifiedAuthMiddleware like AuthMiddleware for the routes an unverified
+// account always needs, e.g. logging out or asking for a new link
+// ==================================================
+func (s *server) UnverifiedAuthMiddleware(c *fiber.Ctx) error {
+	return s.authenticate(c, false)
+}
+
+func (s *server) authenticate(c *fiber.Ctx, enforceVerification bool) error {
 	reqCtx := c.UserContext()
 	sessionID := c.Cookies("session_id")
 	if sessionID == "" {
@@ -51,17 +88,41 @@ func (s *server) AuthMiddleware(c *fiber.Ctx) error {
 		})
 	}
 
-	userID, err := s.redisClient.HGet(reqCtx, sessionID, "user_id").Result()
+	fields, err := s.redisClient.HMGet(reqCtx, sessionID, "user_id", "email_verified").Result()
+	userID, _ := fieldOrNil(fields, 0).(string)
 	if err != nil || userID == "" {
 		return c.Status(fiber.StatusUnauthorized).JSON(&fiber.Map{
 			"error": "invalid session",
 		})
 	}
+	// sessions created before verification existed have no flag, their users were grandfathered
+	verified := fieldOrNil(fields, 1)
+	emailVerified := verified == nil || verified == "1"
 
 	c.Locals("user_id", userID)
+	c.Locals("email_verified", emailVerified)
+	c.SetUserContext(logger.WithField(reqCtx, "user_id", userID))
+
+	if enforceVerification && !emailVerified {
+		switch s.emailVerificationPolicy {
+		case models.EmailVerificationBlock:
+			return apperror.NewEmailNotVerifiedError("verify your email address to continue")
+		case models.EmailVerificationReadOnly:
+			if c.Method() != fiber.MethodGet && c.Method() != fiber.MethodHead {
+				return apperror.NewEmailNotVerifiedError("verify your email address to make changes")
+			}
+		}
+	}
 	return c.Next()
 }
 
+func fieldOrNil(fields []any, i int) any {
+	if i < len(fields) {
+		return fields[i]
+	}
+	return nil
+}
+
 func (s *server) GuestMiddleware(c *fiber.Ctx) error {
 	reqCtx := c.UserContext()
 	sessionID := c.Cookies("session_id")
@@ -77,35 +138,122 @@ func (s *server) GuestMiddleware(c *fiber.Ctx) error {
 	return apperror.NewForbiddenError("already logged in")
 }
 
-// RedisRateLimiter
+// RateLimiter sliding window limit per user when logged in, per IP otherwise;
+// a RATE_LIMIT_ROUTES entry for the matched route replaces rule
 // ==================================================
-func (s *server) RedisRateLimiter(
-	prefix string,
-	limit int,
-	window time.Duration,
-	keyFunc func(ctx *fiber.Ctx) string,
-) fiber.Handler {
+func (s *server) RateLimiter(name string, rule models.RateLimitRule) fiber.Handler {
 	return func(ctx *fiber.Ctx) error {
-		// incr count
-		reqCtx := ctx.UserContext()
-		key := fmt.Sprintf("%s:%s:%s", s.cfg.RedisAppName, prefix, keyFunc(ctx))
-		count, err := s.redisClient.Incr(reqCtx, key).Result()
-		if err != nil {
-			panic(err)
+		bucket, limit := name, rule
+		// escaped colons (/tasks\\:batch) are matched as written in URLs
+		route := ctx.Method() + " " + strings.ReplaceAll(ctx.Route().Path, `\`, "")
+		if override, ok := s.routeRateLimits[route]; ok {
+			bucket, limit = route, override
 		}
+		// every API route is limited, this is the first handler that knows the matched route
+		ctx.SetUserContext(logger.WithField(ctx.UserContext(), "route", route))
 
-		// if count == 1 set expiry
-		if count == 1 {
-			s.redisClient.Expire(reqCtx, key, window)
+		subject := "ip:" + ctx.IP()
+		if userID, ok := ctx.Locals("user_id").(string); ok && userID != "" {
+			subject = "user:" + userID
 		}
-		// if count > limit block req other wise next
-		if count > int64(limit) {
-			ttl, _ := s.redisClient.TTL(reqCtx, key).Result()
-			return ctx.Status(fiber.StatusTooManyRequests).JSON(&fiber.Map{
-				"error":       "too many requests",
-				"retry_after": ttl.Seconds(),
-			})
+
+		result, err := s.rateLimiter.Allow(ctx.UserContext(), bucket+":"+subject, limit)
+		if err != nil {
+			zerolog.Ctx(ctx.UserContext()).Error().
+				Err(err).
+				Str("bucket", bucket).
+				Bool("fail_open", s.cfg.RateLimitFailOpen).
+				Msg("rate limiter unavailable")
+			if s.cfg.RateLimitFailOpen {
+				return ctx.Next()
+			}
+			return apperror.NewServiceUnavailableError("rate limiter unavailable")
+		}
+
+		// IETF RateLimit header fields, reset and retry are whole seconds
+		reset := int(math.Ceil(result.Reset.Seconds()))
+		ctx.Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+		ctx.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+		ctx.Set("RateLimit-Reset", strconv.Itoa(reset))
+		ctx.Set("RateLimit-Policy", fmt.Sprintf("%d;w=%d", limit.Limit, int(limit.Window.Seconds())))
+
+		if !result.Allowed {
+			ctx.Set(fiber.HeaderRetryAfter, strconv.Itoa(reset))
+			return apperror.NewTooManyRequestsError("too many requests")
 		}
 		return ctx.Next()
 	}
 }
+
+// IdempotencyMiddleware replays the stored response of a request retried with
+// the same Idempotency-Key; must run after AuthMiddleware, keys are per user
+// ==================================================
+func (s *server) IdempotencyMiddleware(c *fiber.Ctx) error {
+	key := c.Get(idempotencyKeyHeader)
+	if key == "" {
+		return c.Next()
+	}
+	if len(key) > maxIdempotencyKeyLength {
+		return apperror.NewBadRequestError(fmt.Sprintf("%s must be at most %d characters", idempotencyKeyHeader, maxIdempotencyKeyLength))
+	}
+	userID, ok := c.Locals("user_id").(string)
+	if !ok || userID == "" {
+		return apperror.NewUnauthorizedError("invalid auth context")
+	}
+
+	reqCtx := c.UserContext()
+	storeKey := "rest:" + userID + ":" + key
+	sum := sha256.New()
+	sum.Write([]byte(c.Method() + " " + c.Path() + "\n"))
+	sum.Write(c.Body())
+	fingerprint := hex.EncodeToString(sum.Sum(nil))
+
+	record, claimed, err := s.idempotencyStore.Begin(reqCtx, storeKey, fingerprint, s.cfg.IdempotencyLockTTL)
+	if err != nil {
+		return apperror.NewInternalError("Failed to check idempotency key", err)
+	}
+	if !claimed {
+		switch {
+		case record.Fingerprint != fingerprint:
+			return apperror.NewUnprocessableEntityError(idempotencyKeyHeader + " was already used with a different request")
+		case record.State == models.IdempotencyStateInFlight:
+			return apperror.NewConflictError("a request with this " + idempotencyKeyHeader + " is still in progress")
+		}
+
+		zerolog.Ctx(c.UserContext()).Debug().
+			Str("user_id", userID).
+			Str("path", c.Path()).
+			Msg("replaying idempotent response")
+		c.Set(idempotentReplayedHeader, "true")
+		c.Set(fiber.HeaderContentType, record.ContentType)
+		return c.Status(record.StatusCode).Send(record.Body)
+	}
+
+	// run the handler and render errors now, so the final response can be stored
+	if err := c.Next(); err != nil {
+		if err := c.App().Config().ErrorHandler(c, err); err != nil {
+			s.idempotencyStore.Release(reqCtx, storeKey)
+			return err
+		}
+	}
+
+	status := c.Response().StatusCode()
+	// server errors may be transient, let the client retry for real
+	if status >= fiber.StatusInternalServerError {
+		if err := s.idempotencyStore.Release(reqCtx, storeKey); err != nil {
+			zerolog.Ctx(c.UserContext()).Error().Err(err).Msg("failed to release idempotency key")
+		}
+		return nil
+	}
+
+	err = s.idempotencyStore.Complete(reqCtx, storeKey, &models.IdempotencyRecord{
+		Fingerprint: fingerprint,
+		StatusCode:  status,
+		ContentType: string(c.Response().Header.ContentType()),
+		Body:        append([]byte(nil), c.Response().Body()...),
+	}, s.cfg.IdempotencyTTL)
+	if err != nil {
+		zerolog.Ctx(c.UserContext()).Error().Err(err).Msg("failed to store idempotent response")
+	}
+	return nil
+}
@@ -1,44 +1,125 @@
 package server
 
 import (
-	"time"
-
 	"github.com/gofiber/fiber/v2"
+	"github.com/suryansh74/task-management-api-project/internal/models"
 	"github.com/suryansh74/task-management-api-project/internal/ports"
 )
 
 // setupRoutes serves all http routes
 // ==================================================
 
-func (s *server) setupRoutes(userHandler ports.UserHandler, taskHandler ports.TaskHandler) {
-	publicLimiter := s.RedisRateLimiter("public", 10, time.Minute, func(c *fiber.Ctx) string {
-		return c.IP()
-	})
-	taskLimiter := s.RedisRateLimiter("task", 100, time.Minute, func(c *fiber.Ctx) string {
-		return c.IP()
-	})
+func (s *server) setupRoutes(userHandler ports.UserHandler, taskHandler ports.TaskHandler, attachmentHandler ports.AttachmentHandler, reminderHandler ports.ReminderHandler, notificationHandler ports.NotificationHandler, dependencyHandler ports.TaskDependencyHandler, checklistHandler ports.ChecklistHandler, batchHandler ports.TaskBatchHandler, transferHandler ports.TaskTransferHandler, calendarHandler ports.CalendarFeedHandler, passwordHandler ports.PasswordHandler, verificationHandler ports.EmailVerificationHandler, mfaHandler ports.MFAHandler) {
+	publicLimiter := s.RateLimiter("public", s.rateLimits["public"])
+	taskLimiter := s.RateLimiter("task", s.rateLimits["task"])
+	passwordLimiter := s.RateLimiter("password", s.rateLimits["password"])
+	verificationLimiter := s.RateLimiter("verification", s.rateLimits["verification"])
 
 	// Health must NOT be rate-limited — k8s probes hit this every few seconds
-	s.app.Get("/check_health", s.checkHealth)
+	s.app.Get("/check_health", s.checkLive)
+	s.app.Get("/livez", s.checkLive)
+	s.app.Get("/readyz", s.checkReady)
 
 	// Guest-only routes (must NOT be logged in)
 	s.app.Post("/register", publicLimiter, s.GuestMiddleware, userHandler.Register)
 	s.app.Post("/login", publicLimiter, s.GuestMiddleware, userHandler.Login)
+	// second step for accounts with 2FA, carries the mfa_challenge cookie from /login
+	s.app.Post("/login/mfa", publicLimiter, s.GuestMiddleware, userHandler.LoginMFA)
+
+	// Password reset works with or without a session, limited per IP
+	s.app.Post("/password/forgot", passwordLimiter, passwordHandler.ForgotPassword)
+	s.app.Post("/password/reset", passwordLimiter, passwordHandler.ResetPassword)
+
+	// The emailed link is opened wherever the mail is read, no session needed;
+	// resending is per user and allowed whatever the verification policy
+	s.app.Post("/email/verify", verificationLimiter, verificationHandler.VerifyEmail)
+	s.app.Post("/email/verify/resend", s.UnverifiedAuthMiddleware, verificationLimiter, verificationHandler.ResendVerification)
 
-	// Protected routes (must be logged in)
-	s.app.Post("/logout", publicLimiter, s.AuthMiddleware, userHandler.Logout)
+	// Protected routes (must be logged in), limited per user so auth runs first
+	// POSTs honour an Idempotency-Key header; uploads are left out because
+	// clients pick a new multipart boundary on every retry
+	s.app.Post("/logout", s.UnverifiedAuthMiddleware, publicLimiter, userHandler.Logout)
 	// tasks
-	s.app.Get("/tasks", taskLimiter, s.AuthMiddleware, taskHandler.GetTasks)
-	s.app.Post("/tasks", taskLimiter, s.AuthMiddleware, taskHandler.CreateTask)
-	s.app.Get("/tasks/:id", taskLimiter, s.AuthMiddleware, taskHandler.GetTaskByID)
-	s.app.Put("/tasks/:id", taskLimiter, s.AuthMiddleware, taskHandler.UpdateTaskByID)
-	s.app.Delete("/tasks/:id", taskLimiter, s.AuthMiddleware, taskHandler.DeleteTaskByID)
+	s.app.Get("/tasks", s.AuthMiddleware, taskLimiter, taskHandler.GetTasks)
+	s.app.Post("/tasks", s.AuthMiddleware, taskLimiter, s.IdempotencyMiddleware, taskHandler.CreateTask)
+	// escaped colon: /tasks:batch is a literal path, not a :param
+	s.app.Post("/tasks\\:batch", s.AuthMiddleware, taskLimiter, s.IdempotencyMiddleware, batchHandler.BatchTasks)
+	// static /tasks/<name> routes must be registered before /tasks/:id
+	s.app.Get("/tasks/order", s.AuthMiddleware, taskLimiter, dependencyHandler.GetTopologicalOrder)
+	s.app.Get("/tasks/search", s.AuthMiddleware, taskLimiter, taskHandler.SearchTasks)
+	s.app.Get("/tasks/export", s.AuthMiddleware, taskLimiter, transferHandler.ExportTasks)
+	s.app.Post("/tasks/import", s.AuthMiddleware, taskLimiter, s.IdempotencyMiddleware, transferHandler.ImportTasks)
+	s.app.Get("/tasks/:id", s.AuthMiddleware, taskLimiter, taskHandler.GetTaskByID)
+	s.app.Put("/tasks/:id", s.AuthMiddleware, taskLimiter, taskHandler.UpdateTaskByID)
+	s.app.Delete("/tasks/:id", s.AuthMiddleware, taskLimiter, taskHandler.DeleteTaskByID)
+	// attachments
+	s.app.Post("/tasks/:id/attachments", s.AuthMiddleware, taskLimiter, attachmentHandler.UploadAttachment)
+	s.app.Get("/tasks/:id/attachments", s.AuthMiddleware, taskLimiter, attachmentHandler.GetAttachments)
+	s.app.Get("/tasks/:id/attachments/:attachmentID", s.AuthMiddleware, taskLimiter, attachmentHandler.GetDownloadURL)
+	s.app.Delete("/tasks/:id/attachments/:attachmentID", s.AuthMiddleware, taskLimiter, attachmentHandler.DeleteAttachmentByID)
+	// reminders
+	s.app.Post("/tasks/:id/reminders", s.AuthMiddleware, taskLimiter, s.IdempotencyMiddleware, reminderHandler.CreateReminder)
+	s.app.Get("/tasks/:id/reminders", s.AuthMiddleware, taskLimiter, reminderHandler.GetReminders)
+	s.app.Delete("/tasks/:id/reminders/:reminderID", s.AuthMiddleware, taskLimiter, reminderHandler.DeleteReminderByID)
+	s.app.Get("/reminders/upcoming", s.AuthMiddleware, taskLimiter, reminderHandler.GetUpcomingReminders)
+	// dependencies
+	s.app.Post("/tasks/:id/blockers", s.AuthMiddleware, taskLimiter, s.IdempotencyMiddleware, dependencyHandler.AddBlocker)
+	s.app.Get("/tasks/:id/blockers", s.AuthMiddleware, taskLimiter, dependencyHandler.GetBlockers)
+	s.app.Delete("/tasks/:id/blockers/:blockerID", s.AuthMiddleware, taskLimiter, dependencyHandler.RemoveBlocker)
+	s.app.Get("/tasks/:id/dependents", s.AuthMiddleware, taskLimiter, dependencyHandler.GetDependents)
+	// checklist
+	s.app.Post("/tasks/:id/checklist", s.AuthMiddleware, taskLimiter, s.IdempotencyMiddleware, checklistHandler.AddItem)
+	s.app.Get("/tasks/:id/checklist", s.AuthMiddleware, taskLimiter, checklistHandler.GetItems)
+	s.app.Put("/tasks/:id/checklist/order", s.AuthMiddleware, taskLimiter, checklistHandler.ReorderItems)
+	s.app.Put("/tasks/:id/checklist/:itemID", s.AuthMiddleware, taskLimiter, checklistHandler.UpdateItem)
+	s.app.Post("/tasks/:id/checklist/:itemID/toggle", s.AuthMiddleware, taskLimiter, s.IdempotencyMiddleware, checklistHandler.ToggleItem)
+	s.app.Delete("/tasks/:id/checklist/:itemID", s.AuthMiddleware, taskLimiter, checklistHandler.DeleteItem)
+	// notification preferences
+	s.app.Get("/me/notification-preferences", s.AuthMiddleware, taskLimiter, notificationHandler.GetPreferences)
+	s.app.Put("/me/notification-preferences", s.AuthMiddleware, taskLimiter, notificationHandler.UpdatePreferences)
+	// calendar feed
+	s.app.Get("/me/calendar-feed", s.AuthMiddleware, taskLimiter, calendarHandler.GetFeed)
+	s.app.Post("/me/calendar-feed/rotate", s.AuthMiddleware, taskLimiter, calendarHandler.RotateFeedToken)
+	s.app.Delete("/me/calendar-feed", s.AuthMiddleware, taskLimiter, calendarHandler.DisableFeed)
+	// two-factor authentication
+	s.app.Post("/me/mfa/enroll", s.AuthMiddleware, taskLimiter, mfaHandler.Enroll)
+	s.app.Post("/me/mfa/confirm", s.AuthMiddleware, taskLimiter, mfaHandler.Confirm)
+	s.app.Post("/me/mfa/disable", s.AuthMiddleware, taskLimiter, mfaHandler.Disable)
+
+	// Signed download links carry their own authorization (no session cookie)
+	s.app.Get("/files/*", taskLimiter, attachmentHandler.DownloadSignedFile)
+	// Calendar apps subscribe with the secret feed URL (no session cookie)
+	s.app.Get("/calendar/:token.ics", taskLimiter, calendarHandler.ServeFeed)
 }
 
-// checkHealth
+// checkLive the process is up and serving HTTP; dependencies are left to
+// readiness so an outage does not get every pod restarted
 // ==================================================
-func (s *server) checkHealth(ctx *fiber.Ctx) error {
+func (s *server) checkLive(ctx *fiber.Ctx) error {
 	return ctx.Status(fiber.StatusOK).JSON(&fiber.Map{
-		"message": "working fine",
+		"status": models.HealthStatusOK,
 	})
 }
+
+// checkReady checks Postgres, Redis and the schema version; fails as soon as
+// shutdown starts so the pod leaves the Service endpoints before the servers
+// stop accepting connections. ?verbose lists every check
+// ==================================================
+func (s *server) checkReady(ctx *fiber.Ctx) error {
+	report := &models.HealthReport{
+		Status: models.HealthStatusFailing,
+		Checks: []*models.HealthCheck{{Name: "shutdown", Status: models.HealthStatusFailing, Error: "shutting down"}},
+	}
+	if !s.shuttingDown.Load() {
+		report = s.healthService.CheckReadiness(ctx.UserContext())
+	}
+
+	code := fiber.StatusOK
+	if !report.OK() {
+		code = fiber.StatusServiceUnavailable
+	}
+	if _, verbose := ctx.Queries()["verbose"]; !verbose {
+		report = &models.HealthReport{Status: report.Status}
+	}
+	return ctx.Status(code).JSON(report)
+}
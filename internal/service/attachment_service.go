@@ -0,0 +1,326 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/logger"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+	"github.com/suryansh74/task-management-api-project/internal/utils"
+)
+
+// sniffLen is how many bytes http.DetectContentType looks at
+const sniffLen = 512
+
+// blobSweepBatchSize caps the blobs one sweep deletes
+const blobSweepBatchSize = 500
+
+type attachmentService struct {
+	attachmentRepo ports.AttachmentRepository
+	blobStore      ports.BlobStore
+	taskService    ports.TaskService
+	maxSize        int64
+	allowedTypes   []string
+	urlExpiration  time.Duration
+	signingKey     string
+}
+
+// NewAttachmentService creates a new attachment service instance
+// =========================================================================
+func NewAttachmentService(
+	attachmentRepo ports.AttachmentRepository,
+	blobStore ports.BlobStore,
+	taskService ports.TaskService,
+	maxSize int64,
+	allowedTypes []string,
+	urlExpiration time.Duration,
+	signingKey string,
+) ports.AttachmentService {
+	logger.Log.Info().
+		Int64("max_size", maxSize).
+		Strs("allowed_types", allowedTypes).
+		Dur("url_expiration", urlExpiration).
+		Msg("initializing attachment service")
+	return &attachmentService{
+		attachmentRepo: attachmentRepo,
+		blobStore:      blobStore,
+		taskService:    taskService,
+		maxSize:        maxSize,
+		allowedTypes:   allowedTypes,
+		urlExpiration:  urlExpiration,
+		signingKey:     signingKey,
+	}
+}
+
+// UploadAttachment validates and stores a file for a task
+// =========================================================================
+func (s *attachmentService) UploadAttachment(ctx context.Context, taskID string, userID string, upload *ports.AttachmentUpload) (*models.Attachment, error) {
+	zerolog.Ctx(ctx).Debug().
+		Str("task_id", taskID).
+		Str("user_id", userID).
+		Str("filename", upload.Filename).
+		Int64("size_bytes", upload.Size).
+		Msg("uploading attachment")
+
+	// check policy (task service enforces ownership)
+	if _, err := s.taskService.GetTaskByID(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+
+	if upload.Size <= 0 {
+		return nil, apperror.NewBadRequestError("file is empty")
+	}
+	if upload.Size > s.maxSize {
+		zerolog.Ctx(ctx).Warn().
+			Str("task_id", taskID).
+			Int64("size_bytes", upload.Size).
+			Int64("max_size", s.maxSize).
+			Msg("attachment rejected: too large")
+		return nil, apperror.NewPayloadTooLargeError(fmt.Sprintf("file exceeds maximum size of %d bytes", s.maxSize))
+	}
+
+	// never trust the client's Content-Type, sniff the first bytes instead
+	body := bufio.NewReaderSize(upload.Body, sniffLen)
+	head, err := body.Peek(sniffLen)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, apperror.NewBadRequestError("unable to read file")
+	}
+	contentType, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+	if !s.isAllowedType(contentType) {
+		zerolog.Ctx(ctx).Warn().
+			Str("task_id", taskID).
+			Str("content_type", contentType).
+			Msg("attachment rejected: content type not allowed")
+		return nil, apperror.NewUnsupportedMediaTypeError(fmt.Sprintf("file type %s is not allowed", contentType))
+	}
+
+	filename := sanitizeFilename(upload.Filename)
+	key := fmt.Sprintf("attachments/%s/%s", taskID, utils.MustRandomID())
+
+	if err := s.blobStore.Put(ctx, key, io.LimitReader(body, upload.Size), upload.Size, contentType); err != nil {
+		return nil, err
+	}
+
+	attachment := &models.Attachment{
+		TaskID:      taskID,
+		UserID:      userID,
+		Filename:    filename,
+		ContentType: contentType,
+		SizeBytes:   upload.Size,
+		StorageKey:  key,
+		CreatedAt:   time.Now(),
+	}
+	id, err := s.attachmentRepo.CreateAttachment(ctx, attachment)
+	if err != nil {
+		// metadata failed, don't leave an orphaned blob behind
+		if delErr := s.blobStore.Delete(ctx, key); delErr != nil {
+			zerolog.Ctx(ctx).Error().
+				Err(delErr).
+				Str("storage_key", key).
+				Msg("failed to clean up orphaned blob")
+		}
+		return nil, err
+	}
+	attachment.ID = id
+
+	zerolog.Ctx(ctx).Info().
+		Str("attachment_id", id).
+		Str("task_id", taskID).
+		Str("user_id", userID).
+		Str("content_type", contentType).
+		Msg("attachment uploaded successfully")
+	return attachment, nil
+}
+
+// GetAttachments list attachments of a task
+// =========================================================================
+func (s *attachmentService) GetAttachments(ctx context.Context, taskID string, userID string) ([]*models.Attachment, error) {
+	if _, err := s.taskService.GetTaskByID(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+	return s.attachmentRepo.GetAttachmentsByTaskID(ctx, taskID)
+}
+
+// GetDownloadURL returns a signed, time-limited link to an attachment
+// =========================================================================
+func (s *attachmentService) GetDownloadURL(ctx context.Context, taskID string, attachmentID string, userID string) (*ports.AttachmentDownload, error) {
+	attachment, err := s.mustOwnAttachment(ctx, taskID, attachmentID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	url, err := s.blobStore.SignedURL(ctx, attachment.StorageKey, attachment.Filename, s.urlExpiration)
+	if err != nil {
+		return nil, err
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("attachment_id", attachmentID).
+		Str("user_id", userID).
+		Dur("expires_in", s.urlExpiration).
+		Msg("issued signed download url")
+	return &ports.AttachmentDownload{
+		URL:       url,
+		ExpiresAt: time.Now().Add(s.urlExpiration),
+	}, nil
+}
+
+// DeleteAttachmentByID removes metadata and blob
+// =========================================================================
+func (s *attachmentService) DeleteAttachmentByID(ctx context.Context, taskID string, attachmentID string, userID string) error {
+	attachment, err := s.mustOwnAttachment(ctx, taskID, attachmentID, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.attachmentRepo.DeleteAttachmentByID(ctx, attachmentID); err != nil {
+		return err
+	}
+
+	// metadata is gone so the blob is unreachable, a failed delete is retried
+	// by the blob sweeper
+	if err := s.blobStore.Delete(ctx, attachment.StorageKey); err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("attachment_id", attachmentID).
+			Str("storage_key", attachment.StorageKey).
+			Msg("failed to delete attachment blob")
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("attachment_id", attachmentID).
+		Str("task_id", taskID).
+		Str("user_id", userID).
+		Msg("attachment deleted successfully")
+	return nil
+}
+
+// OpenSignedFile verifies a link issued by the local blob store
+// =========================================================================
+func (s *attachmentService) OpenSignedFile(ctx context.Context, key string, filename string, expires int64, signature string) (io.ReadCloser, error) {
+	if !utils.VerifyPayload(s.signingKey, signature, key, filename, strconv.FormatInt(expires, 10)) {
+		zerolog.Ctx(ctx).Warn().
+			Str("storage_key", key).
+			Msg("rejected download with invalid signature")
+		return nil, apperror.NewForbiddenError("invalid signature")
+	}
+	if time.Now().Unix() > expires {
+		return nil, apperror.NewForbiddenError("download link expired")
+	}
+	return s.blobStore.Get(ctx, key)
+}
+
+// SweepDeletedBlobs delete the blobs of deleted attachments, kept queued until the delete succeeds
+// =========================================================================
+func (s *attachmentService) SweepDeletedBlobs(ctx context.Context) (int, error) {
+	keys, err := s.attachmentRepo.GetDeletedBlobKeys(ctx, blobSweepBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	if len(keys) == 0 {
+		return 0, nil
+	}
+
+	deleted := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if err := s.blobStore.Delete(ctx, key); err != nil {
+			zerolog.Ctx(ctx).Warn().
+				Err(err).
+				Str("storage_key", key).
+				Msg("attachment blob not deleted, retrying next sweep")
+			continue
+		}
+		deleted = append(deleted, key)
+	}
+	if len(deleted) == 0 {
+		return 0, nil
+	}
+	if err := s.attachmentRepo.ForgetDeletedBlobKeys(ctx, deleted); err != nil {
+		return 0, err
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Int("deleted", len(deleted)).
+		Int("failed", len(keys)-len(deleted)).
+		Msg("swept deleted attachment blobs")
+	return len(deleted), nil
+}
+
+// StartBlobSweeper runs SweepDeletedBlobs every interval until ctx is done
+// =========================================================================
+func StartBlobSweeper(ctx context.Context, attachmentService ports.AttachmentService, interval time.Duration) {
+	zerolog.Ctx(ctx).Info().
+		Dur("interval", interval).
+		Msg("blob sweeper started")
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			zerolog.Ctx(ctx).Info().Msg("blob sweeper stopped")
+			return
+		case <-ticker.C:
+			if _, err := attachmentService.SweepDeletedBlobs(ctx); err != nil {
+				zerolog.Ctx(ctx).Error().
+					Err(err).
+					Msg("blob sweep failed")
+			}
+		}
+	}
+}
+
+// mustOwnAttachment checks task ownership and that attachment belongs to task
+func (s *attachmentService) mustOwnAttachment(ctx context.Context, taskID, attachmentID, userID string) (*models.Attachment, error) {
+	if _, err := s.taskService.GetTaskByID(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+
+	attachment, err := s.attachmentRepo.GetAttachmentByID(ctx, attachmentID)
+	if err != nil {
+		return nil, err
+	}
+	if attachment.TaskID != taskID {
+		return nil, apperror.NewNotFoundError("attachment not found")
+	}
+	return attachment, nil
+}
+
+// isAllowedType matches exact types and "type/*" wildcards
+func (s *attachmentService) isAllowedType(contentType string) bool {
+	for _, allowed := range s.allowedTypes {
+		allowed = strings.TrimSpace(allowed)
+		if allowed == contentType {
+			return true
+		}
+		if prefix, ok := strings.CutSuffix(allowed, "/*"); ok && strings.HasPrefix(contentType, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// sanitizeFilename strips any client supplied directories
+func sanitizeFilename(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	if name == "." || name == "/" || name == "" {
+		return "file"
+	}
+	if len(name) > 255 {
+		name = name[len(name)-255:]
+	}
+	return name
+}
@@ -0,0 +1,259 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+	"github.com/suryansh74/task-management-api-project/internal/utils"
+)
+
+type mockTaskService struct {
+	ports.TaskService
+	getByIDFn func(ctx context.Context, taskID string, userID string) (*models.Task, error)
+}
+
+func (m *mockTaskService) GetTaskByID(ctx context.Context, taskID string, userID string) (*models.Task, error) {
+	if m.getByIDFn != nil {
+		return m.getByIDFn(ctx, taskID, userID)
+	}
+	return &models.Task{ID: taskID, UserID: userID}, nil
+}
+
+type mockAttachmentRepository struct {
+	createFn        func(ctx context.Context, attachment *models.Attachment) (string, error)
+	getByIDFn       func(ctx context.Context, id string) (*models.Attachment, error)
+	listFn          func(ctx context.Context, taskID string) ([]*models.Attachment, error)
+	deleteFn        func(ctx context.Context, id string) error
+	deletedBlobKeys []string
+	forgotten       []string
+}
+
+func (m *mockAttachmentRepository) CreateAttachment(ctx context.Context, attachment *models.Attachment) (string, error) {
+	if m.createFn != nil {
+		return m.createFn(ctx, attachment)
+	}
+	return "", errors.New("not implemented")
+}
+func (m *mockAttachmentRepository) GetAttachmentByID(ctx context.Context, id string) (*models.Attachment, error) {
+	if m.getByIDFn != nil {
+		return m.getByIDFn(ctx, id)
+	}
+	return nil, errors.New("not implemented")
+}
+func (m *mockAttachmentRepository) GetAttachmentsByTaskID(ctx context.Context, taskID string) ([]*models.Attachment, error) {
+	if m.listFn != nil {
+		return m.listFn(ctx, taskID)
+	}
+	return nil, errors.New("not implemented")
+}
+func (m *mockAttachmentRepository) DeleteAttachmentByID(ctx context.Context, id string) error {
+	if m.deleteFn != nil {
+		return m.deleteFn(ctx, id)
+	}
+	return errors.New("not implemented")
+}
+
+func (m *mockAttachmentRepository) GetDeletedBlobKeys(ctx context.Context, limit int) ([]string, error) {
+	return m.deletedBlobKeys, nil
+}
+func (m *mockAttachmentRepository) ForgetDeletedBlobKeys(ctx context.Context, keys []string) error {
+	m.forgotten = append(m.forgotten, keys...)
+	return nil
+}
+
+type mockBlobStore struct {
+	blobs      map[string][]byte
+	deleted    []string
+	deleteErrs map[string]error
+}
+
+func newMockBlobStore() *mockBlobStore {
+	return &mockBlobStore{blobs: map[string][]byte{}}
+}
+
+func (m *mockBlobStore) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	m.blobs[key] = b
+	return nil
+}
+func (m *mockBlobStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	b, ok := m.blobs[key]
+	if !ok {
+		return nil, apperror.NewNotFoundError("file not found")
+	}
+	return io.NopCloser(bytes.NewReader(b)), nil
+}
+func (m *mockBlobStore) Delete(ctx context.Context, key string) error {
+	if err := m.deleteErrs[key]; err != nil {
+		return err
+	}
+	m.deleted = append(m.deleted, key)
+	delete(m.blobs, key)
+	return nil
+}
+func (m *mockBlobStore) SignedURL(ctx context.Context, key string, filename string, expiry time.Duration) (string, error) {
+	return "https://blobs.example.com/" + key, nil
+}
+
+// minimal PNG header is enough for http.DetectContentType
+var pngBytes = append([]byte("\x89PNG\r\n\x1a\n"), bytes.Repeat([]byte{0}, 64)...)
+
+func newTestAttachmentService(repo ports.AttachmentRepository, blobs ports.BlobStore) ports.AttachmentService {
+	return NewAttachmentService(repo, blobs, &mockTaskService{}, 1024, []string{"image/*", "text/plain"}, time.Minute, "signing-key")
+}
+
+func TestAttachmentService_Upload_Success(t *testing.T) {
+	var saved *models.Attachment
+	repo := &mockAttachmentRepository{
+		createFn: func(ctx context.Context, attachment *models.Attachment) (string, error) {
+			saved = attachment
+			return "att-1", nil
+		},
+	}
+	blobs := newMockBlobStore()
+	svc := newTestAttachmentService(repo, blobs)
+
+	att, err := svc.UploadAttachment(context.Background(), "t1", "user-1", &ports.AttachmentUpload{
+		Filename: "../../screenshot.png",
+		Size:     int64(len(pngBytes)),
+		Body:     bytes.NewReader(pngBytes),
+	})
+	if err != nil {
+		t.Fatalf("UploadAttachment failed: %v", err)
+	}
+	if att.ID != "att-1" || saved.ContentType != "image/png" {
+		t.Errorf("unexpected attachment: %+v", saved)
+	}
+	if saved.Filename != "screenshot.png" {
+		t.Errorf("expected sanitized filename, got %s", saved.Filename)
+	}
+	if !bytes.Equal(blobs.blobs[saved.StorageKey], pngBytes) {
+		t.Error("expected blob to contain the full upload")
+	}
+}
+
+func TestAttachmentService_Upload_RejectsType(t *testing.T) {
+	svc := newTestAttachmentService(&mockAttachmentRepository{}, newMockBlobStore())
+	body := []byte("<html><body>hi</body></html>")
+
+	_, err := svc.UploadAttachment(context.Background(), "t1", "user-1", &ports.AttachmentUpload{
+		Filename: "page.png",
+		Size:     int64(len(body)),
+		Body:     bytes.NewReader(body),
+	})
+	var appErr *apperror.AppError
+	if !errors.As(err, &appErr) || appErr.Code != "UNSUPPORTED_MEDIA_TYPE" {
+		t.Errorf("expected UNSUPPORTED_MEDIA_TYPE, got %v", err)
+	}
+}
+
+func TestAttachmentService_Upload_RejectsSize(t *testing.T) {
+	svc := newTestAttachmentService(&mockAttachmentRepository{}, newMockBlobStore())
+
+	_, err := svc.UploadAttachment(context.Background(), "t1", "user-1", &ports.AttachmentUpload{
+		Filename: "big.txt",
+		Size:     4096,
+		Body:     bytes.NewReader(make([]byte, 4096)),
+	})
+	var appErr *apperror.AppError
+	if !errors.As(err, &appErr) || appErr.Code != "PAYLOAD_TOO_LARGE" {
+		t.Errorf("expected PAYLOAD_TOO_LARGE, got %v", err)
+	}
+}
+
+func TestAttachmentService_Upload_CleansUpBlobOnRepoError(t *testing.T) {
+	repo := &mockAttachmentRepository{
+		createFn: func(ctx context.Context, attachment *models.Attachment) (string, error) {
+			return "", apperror.NewInternalError("db down", nil)
+		},
+	}
+	blobs := newMockBlobStore()
+	svc := newTestAttachmentService(repo, blobs)
+
+	_, err := svc.UploadAttachment(context.Background(), "t1", "user-1", &ports.AttachmentUpload{
+		Filename: "notes.txt",
+		Size:     5,
+		Body:     bytes.NewReader([]byte("hello")),
+	})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	if len(blobs.deleted) != 1 || len(blobs.blobs) != 0 {
+		t.Error("expected orphaned blob to be deleted")
+	}
+}
+
+func TestAttachmentService_GetDownloadURL_WrongTask(t *testing.T) {
+	repo := &mockAttachmentRepository{
+		getByIDFn: func(ctx context.Context, id string) (*models.Attachment, error) {
+			return &models.Attachment{ID: id, TaskID: "other-task"}, nil
+		},
+	}
+	svc := newTestAttachmentService(repo, newMockBlobStore())
+
+	_, err := svc.GetDownloadURL(context.Background(), "t1", "att-1", "user-1")
+	var appErr *apperror.AppError
+	if !errors.As(err, &appErr) || appErr.Code != "NOT_FOUND" {
+		t.Errorf("expected NOT_FOUND, got %v", err)
+	}
+}
+
+func TestAttachmentService_OpenSignedFile(t *testing.T) {
+	blobs := newMockBlobStore()
+	blobs.blobs["attachments/t1/abc"] = []byte("hello")
+	svc := newTestAttachmentService(&mockAttachmentRepository{}, blobs)
+
+	expires := time.Now().Add(time.Minute).Unix()
+	sig := utils.SignPayload("signing-key", "attachments/t1/abc", "notes.txt", strconv.FormatInt(expires, 10))
+
+	body, err := svc.OpenSignedFile(context.Background(), "attachments/t1/abc", "notes.txt", expires, sig)
+	if err != nil {
+		t.Fatalf("OpenSignedFile failed: %v", err)
+	}
+	body.Close()
+
+	if _, err := svc.OpenSignedFile(context.Background(), "attachments/t1/abc", "other.txt", expires, sig); err == nil {
+		t.Error("expected tampered filename to be rejected")
+	}
+
+	past := time.Now().Add(-time.Minute).Unix()
+	expiredSig := utils.SignPayload("signing-key", "attachments/t1/abc", "notes.txt", strconv.FormatInt(past, 10))
+	if _, err := svc.OpenSignedFile(context.Background(), "attachments/t1/abc", "notes.txt", past, expiredSig); err == nil {
+		t.Error("expected expired link to be rejected")
+	}
+}
+
+func TestAttachmentService_SweepDeletedBlobs(t *testing.T) {
+	repo := &mockAttachmentRepository{deletedBlobKeys: []string{"attachments/t1/a", "attachments/t1/b"}}
+	blobs := newMockBlobStore()
+	blobs.blobs["attachments/t1/a"] = []byte("a")
+	blobs.blobs["attachments/t1/b"] = []byte("b")
+	blobs.deleteErrs = map[string]error{"attachments/t1/b": errors.New("store unavailable")}
+	svc := newTestAttachmentService(repo, blobs)
+
+	n, err := svc.SweepDeletedBlobs(context.Background())
+	if err != nil {
+		t.Fatalf("SweepDeletedBlobs failed: %v", err)
+	}
+	if n != 1 || len(blobs.blobs) != 1 {
+		t.Errorf("expected one blob deleted, got %d (%d left)", n, len(blobs.blobs))
+	}
+	// the failed key stays queued for the next sweep
+	if len(repo.forgotten) != 1 || repo.forgotten[0] != "attachments/t1/a" {
+		t.Errorf("expected only the deleted key to be forgotten, got %v", repo.forgotten)
+	}
+}
+
+var _ ports.AttachmentRepository = (*mockAttachmentRepository)(nil)
+var _ ports.BlobStore = (*mockBlobStore)(nil)
@@ -0,0 +1,228 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/rs/zerolog"
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/logger"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+	"github.com/suryansh74/task-management-api-project/internal/utils"
+)
+
+const (
+	// feedTokenBytes 32 random bytes, 64 hex chars in the URL
+	feedTokenBytes = 32
+	icalTimeFormat = "20060102T150405Z"
+	// icalLineLimit lines are folded after 75 octets (RFC 5545 3.1)
+	icalLineLimit = 75
+)
+
+type calendarFeedService struct {
+	feedRepo ports.CalendarFeedRepository
+	taskRepo ports.TaskRepository
+	baseURL  string
+}
+
+// NewCalendarFeedService creates a new calendar feed service instance
+// =========================================================================
+func NewCalendarFeedService(feedRepo ports.CalendarFeedRepository, taskRepo ports.TaskRepository, baseURL string) ports.CalendarFeedService {
+	logger.Log.Info().
+		Str("base_url", baseURL).
+		Msg("initializing calendar feed service")
+	return &calendarFeedService{
+		feedRepo: feedRepo,
+		taskRepo: taskRepo,
+		baseURL:  strings.TrimRight(baseURL, "/"),
+	}
+}
+
+// GetFeed whether the user has a feed, the URL itself is not recoverable
+// =========================================================================
+func (s *calendarFeedService) GetFeed(ctx context.Context, userID string) (*models.CalendarFeed, error) {
+	if userID == "" {
+		return nil, apperror.NewUnauthorizedError("not authenticated")
+	}
+	return s.feedRepo.GetFeed(ctx, userID)
+}
+
+// RotateFeedToken create the feed or replace its token
+// =========================================================================
+func (s *calendarFeedService) RotateFeedToken(ctx context.Context, userID string) (*models.CalendarFeed, error) {
+	if userID == "" {
+		return nil, apperror.NewUnauthorizedError("not authenticated")
+	}
+
+	token, err := utils.GenerateRandomID(feedTokenBytes)
+	if err != nil {
+		return nil, apperror.NewInternalError("Failed to generate feed token", err)
+	}
+
+	feed, err := s.feedRepo.SaveFeedToken(ctx, userID, hashFeedToken(token))
+	if err != nil {
+		return nil, err
+	}
+	feed.URL = fmt.Sprintf("%s/calendar/%s.ics", s.baseURL, token)
+
+	zerolog.Ctx(ctx).Info().
+		Str("user_id", userID).
+		Msg("calendar feed token rotated")
+	return feed, nil
+}
+
+// DisableFeed remove the feed, its URL stops working
+// =========================================================================
+func (s *calendarFeedService) DisableFeed(ctx context.Context, userID string) error {
+	if userID == "" {
+		return apperror.NewUnauthorizedError("not authenticated")
+	}
+	return s.feedRepo.DeleteFeed(ctx, userID)
+}
+
+// RenderFeed build the calendar of the token owner's tasks with a due date
+// =========================================================================
+func (s *calendarFeedService) RenderFeed(ctx context.Context, token string, feedType string) ([]byte, string, error) {
+	if feedType == "" {
+		feedType = models.CalendarFeedTypeEvent
+	}
+	if feedType != models.CalendarFeedTypeEvent && feedType != models.CalendarFeedTypeTodo {
+		return nil, "", apperror.NewBadRequestError("type must be event or todo")
+	}
+	// malformed tokens can't exist, skip the lookup
+	if _, err := hex.DecodeString(token); err != nil || len(token) != 2*feedTokenBytes {
+		return nil, "", apperror.NewNotFoundError("calendar feed not found")
+	}
+
+	userID, err := s.feedRepo.GetUserIDByTokenHash(ctx, hashFeedToken(token))
+	if err != nil {
+		return nil, "", err
+	}
+
+	tasks, err := s.taskRepo.GetTasksWithDueDate(ctx, userID)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("user_id", userID).
+			Msg("failed to fetch tasks for calendar feed")
+		return nil, "", err
+	}
+
+	body := renderCalendar(tasks, feedType)
+	// the body only depends on the tasks, so identical content gives the same tag
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	zerolog.Ctx(ctx).Debug().
+		Str("user_id", userID).
+		Str("type", feedType).
+		Int("task_count", len(tasks)).
+		Msg("calendar feed rendered")
+	return body, etag, nil
+}
+
+func hashFeedToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// renderCalendar RFC 5545 calendar with one VEVENT or VTODO per task
+func renderCalendar(tasks []*models.Task, feedType string) []byte {
+	var b bytes.Buffer
+	line := func(name, value string) { writeICalLine(&b, name+":"+value) }
+
+	line("BEGIN", "VCALENDAR")
+	line("VERSION", "2.0")
+	line("PRODID", "-//task-management-api//tasks//EN")
+	line("CALSCALE", "GREGORIAN")
+	line("METHOD", "PUBLISH")
+	line("X-WR-CALNAME", "Tasks")
+
+	for _, task := range tasks {
+		if task.DueAt == nil {
+			continue
+		}
+		due := task.DueAt.UTC().Format(icalTimeFormat)
+		summary := task.Title
+		if feedType == models.CalendarFeedTypeEvent && task.Status == models.TaskStatusDone {
+			summary = "✓ " + summary
+		}
+
+		component := "VEVENT"
+		if feedType == models.CalendarFeedTypeTodo {
+			component = "VTODO"
+		}
+		line("BEGIN", component)
+		line("UID", task.ID)
+		// DTSTAMP from the task, not the clock, keeps the body (and ETag) stable
+		line("DTSTAMP", task.UpdatedAt.UTC().Format(icalTimeFormat))
+		line("CREATED", task.CreatedAt.UTC().Format(icalTimeFormat))
+		line("LAST-MODIFIED", task.UpdatedAt.UTC().Format(icalTimeFormat))
+		line("SUMMARY", escapeICalText(summary))
+		if task.Content != "" {
+			line("DESCRIPTION", escapeICalText(task.Content))
+		}
+		if feedType == models.CalendarFeedTypeTodo {
+			line("DUE", due)
+			line("STATUS", icalTodoStatus(task.Status))
+			if task.Status == models.TaskStatusDone {
+				line("PERCENT-COMPLETE", "100")
+			}
+		} else {
+			line("DTSTART", due)
+			line("DTEND", due)
+			line("TRANSP", "TRANSPARENT")
+		}
+		line("END", component)
+	}
+
+	line("END", "VCALENDAR")
+	return b.Bytes()
+}
+
+func icalTodoStatus(status string) string {
+	switch status {
+	case models.TaskStatusInProgress:
+		return "IN-PROCESS"
+	case models.TaskStatusDone:
+		return "COMPLETED"
+	}
+	return "NEEDS-ACTION"
+}
+
+// escapeICalText escape a TEXT value (RFC 5545 3.3.11)
+func escapeICalText(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	).Replace(s)
+}
+
+// writeICalLine write a CRLF terminated content line, folded without
+// splitting a multi-byte character
+func writeICalLine(b *bytes.Buffer, s string) {
+	limit := icalLineLimit
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(s[cut]) {
+			cut--
+		}
+		b.WriteString(s[:cut])
+		b.WriteString("\r\n ")
+		s = s[cut:]
+		// continuation lines start with a space that counts toward the limit
+		limit = icalLineLimit - 1
+	}
+	b.WriteString(s)
+	b.WriteString("\r\n")
+}
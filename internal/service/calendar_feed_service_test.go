@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+)
+
+type mockCalendarFeedRepository struct {
+	tokens map[string]string // token hash -> user id
+}
+
+func (m *mockCalendarFeedRepository) SaveFeedToken(ctx context.Context, userID string, tokenHash string) (*models.CalendarFeed, error) {
+	for hash, owner := range m.tokens {
+		if owner == userID {
+			delete(m.tokens, hash)
+		}
+	}
+	m.tokens[tokenHash] = userID
+	now := time.Now()
+	return &models.CalendarFeed{UserID: userID, Enabled: true, CreatedAt: &now}, nil
+}
+func (m *mockCalendarFeedRepository) GetFeed(ctx context.Context, userID string) (*models.CalendarFeed, error) {
+	return nil, errors.New("not implemented")
+}
+func (m *mockCalendarFeedRepository) GetUserIDByTokenHash(ctx context.Context, tokenHash string) (string, error) {
+	if userID, ok := m.tokens[tokenHash]; ok {
+		return userID, nil
+	}
+	return "", apperror.NewNotFoundError("calendar feed not found")
+}
+func (m *mockCalendarFeedRepository) DeleteFeed(ctx context.Context, userID string) error {
+	return errors.New("not implemented")
+}
+
+func feedToken(t *testing.T, url string) string {
+	t.Helper()
+	if !strings.HasPrefix(url, "https://tasks.example.com/calendar/") || !strings.HasSuffix(url, ".ics") {
+		t.Fatalf("unexpected feed URL %q", url)
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(url, "https://tasks.example.com/calendar/"), ".ics")
+}
+
+func TestCalendarFeedService_RenderFeed(t *testing.T) {
+	due := time.Date(2026, 5, 4, 15, 30, 0, 0, time.UTC)
+	updated := time.Date(2026, 5, 1, 8, 0, 0, 0, time.UTC)
+	repo := &mockTaskRepository{
+		dueFn: func(ctx context.Context, userID string) ([]*models.Task, error) {
+			if userID != "user-1" {
+				t.Errorf("expected tasks of user-1, got %s", userID)
+			}
+			return []*models.Task{
+				{ID: "t1", Title: "Plan; review, ship", Content: `line one` + "\n" + `C:\tmp`, Status: models.TaskStatusInProgress, DueAt: &due, CreatedAt: updated, UpdatedAt: updated},
+				{ID: "t2", Title: strings.Repeat("é", 60), Status: models.TaskStatusDone, DueAt: &due, CreatedAt: updated, UpdatedAt: updated},
+			}, nil
+		},
+	}
+	svc := NewCalendarFeedService(&mockCalendarFeedRepository{tokens: map[string]string{}}, repo, "https://tasks.example.com/")
+
+	feed, err := svc.RotateFeedToken(context.Background(), "user-1")
+	if err != nil {
+		t.Fatalf("RotateFeedToken failed: %v", err)
+	}
+	token := feedToken(t, feed.URL)
+
+	body, etag, err := svc.RenderFeed(context.Background(), token, models.CalendarFeedTypeTodo)
+	if err != nil {
+		t.Fatalf("RenderFeed failed: %v", err)
+	}
+	ics := string(body)
+
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"BEGIN:VTODO\r\nUID:t1\r\n",
+		"SUMMARY:Plan\\; review\\, ship\r\n",
+		"DESCRIPTION:line one\\nC:\\\\tmp\r\n",
+		"DUE:20260504T153000Z\r\n",
+		"STATUS:IN-PROCESS\r\n",
+		"STATUS:COMPLETED\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(ics, want) {
+			t.Errorf("feed is missing %q", want)
+		}
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(ics, "\r\n"), "\r\n") {
+		if len(line) > 75 {
+			t.Errorf("line longer than 75 octets: %q", line)
+		}
+		if !strings.ContainsRune(line, ':') && !strings.HasPrefix(line, " ") {
+			t.Errorf("line is neither a property nor a continuation: %q", line)
+		}
+	}
+
+	// same tasks, same tag
+	_, again, err := svc.RenderFeed(context.Background(), token, models.CalendarFeedTypeTodo)
+	if err != nil || again != etag {
+		t.Errorf("expected a stable ETag, got %s and %s (%v)", etag, again, err)
+	}
+
+	events, _, err := svc.RenderFeed(context.Background(), token, "")
+	if err != nil {
+		t.Fatalf("RenderFeed failed: %v", err)
+	}
+	if !strings.Contains(string(events), "BEGIN:VEVENT\r\n") || !strings.Contains(string(events), "DTSTART:20260504T153000Z\r\n") {
+		t.Errorf("default feed must contain events, got %q", events)
+	}
+}
+
+func TestCalendarFeedService_RotateFeedToken(t *testing.T) {
+	repo := &mockTaskRepository{
+		dueFn: func(ctx context.Context, userID string) ([]*models.Task, error) {
+			return nil, nil
+		},
+	}
+	svc := NewCalendarFeedService(&mockCalendarFeedRepository{tokens: map[string]string{}}, repo, "https://tasks.example.com")
+
+	first, err := svc.RotateFeedToken(context.Background(), "user-1")
+	if err != nil {
+		t.Fatalf("RotateFeedToken failed: %v", err)
+	}
+	second, err := svc.RotateFeedToken(context.Background(), "user-1")
+	if err != nil {
+		t.Fatalf("RotateFeedToken failed: %v", err)
+	}
+
+	if _, _, err := svc.RenderFeed(context.Background(), feedToken(t, second.URL), ""); err != nil {
+		t.Errorf("new token must work, got %v", err)
+	}
+	for _, token := range []string{feedToken(t, first.URL), "not-a-token"} {
+		_, _, err := svc.RenderFeed(context.Background(), token, "")
+		var appErr *apperror.AppError
+		if !errors.As(err, &appErr) || appErr.Code != "NOT_FOUND" {
+			t.Errorf("token %q: expected NOT_FOUND, got %v", token, err)
+		}
+	}
+}
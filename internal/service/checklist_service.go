@@ -0,0 +1,147 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rs/zerolog"
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/logger"
+	"github.com/suryansh74/task-management-api-project/internal/metrics"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+)
+
+type checklistService struct {
+	checklistRepo ports.ChecklistRepository
+	taskService   ports.TaskService
+	taskCacheRepo ports.TaskCacheRepository
+	redisAppName  string
+}
+
+// NewChecklistService creates a new checklist service instance
+// =========================================================================
+func NewChecklistService(checklistRepo ports.ChecklistRepository, taskService ports.TaskService, taskCacheRepo ports.TaskCacheRepository, redisAppName string) ports.ChecklistService {
+	logger.Log.Info().Msg("initializing checklist service")
+	return &checklistService{
+		checklistRepo: checklistRepo,
+		taskService:   taskService,
+		taskCacheRepo: taskCacheRepo,
+		redisAppName:  redisAppName,
+	}
+}
+
+// AddItem append a checklist item to a task
+// =========================================================================
+func (s *checklistService) AddItem(ctx context.Context, taskID string, userID string, text string) (*models.ChecklistItem, error) {
+	zerolog.Ctx(ctx).Debug().
+		Str("task_id", taskID).
+		Str("user_id", userID).
+		Msg("adding checklist item")
+
+	// check policy (task service enforces ownership)
+	if _, err := s.taskService.GetTaskByID(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+
+	item := &models.ChecklistItem{TaskID: taskID, Text: text}
+	if _, err := s.checklistRepo.CreateChecklistItem(ctx, item); err != nil {
+		return nil, err
+	}
+	s.invalidateTask(ctx, taskID, userID)
+	return item, nil
+}
+
+// GetItems checklist of a task in order
+// =========================================================================
+func (s *checklistService) GetItems(ctx context.Context, taskID string, userID string) ([]*models.ChecklistItem, error) {
+	if _, err := s.taskService.GetTaskByID(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+	return s.checklistRepo.GetChecklistItemsByTaskID(ctx, taskID)
+}
+
+// UpdateItem change text and/or checked state of an item
+// =========================================================================
+func (s *checklistService) UpdateItem(ctx context.Context, taskID string, itemID string, userID string, text *string, checked *bool) (*models.ChecklistItem, error) {
+	if _, err := s.taskService.GetTaskByID(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+
+	// partial update in the repository, so concurrent edits of the other
+	// field are not overwritten with a stale copy
+	item, err := s.checklistRepo.UpdateChecklistItem(ctx, taskID, itemID, text, checked)
+	if err != nil {
+		return nil, err
+	}
+	s.invalidateTask(ctx, taskID, userID)
+	return item, nil
+}
+
+// ToggleItem flip checked state of an item
+// =========================================================================
+func (s *checklistService) ToggleItem(ctx context.Context, taskID string, itemID string, userID string) (*models.ChecklistItem, error) {
+	if _, err := s.taskService.GetTaskByID(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+
+	item, err := s.checklistRepo.ToggleChecklistItem(ctx, taskID, itemID)
+	if err != nil {
+		return nil, err
+	}
+	s.invalidateTask(ctx, taskID, userID)
+	return item, nil
+}
+
+// ReorderItems apply a new order, itemIDs must be a permutation of the checklist
+// =========================================================================
+func (s *checklistService) ReorderItems(ctx context.Context, taskID string, userID string, itemIDs []string) ([]*models.ChecklistItem, error) {
+	zerolog.Ctx(ctx).Debug().
+		Str("task_id", taskID).
+		Str("user_id", userID).
+		Int("item_count", len(itemIDs)).
+		Msg("reordering checklist")
+
+	if _, err := s.taskService.GetTaskByID(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+
+	seen := make(map[string]bool, len(itemIDs))
+	for _, id := range itemIDs {
+		if seen[id] {
+			return nil, apperror.NewBadRequestError(fmt.Sprintf("checklist item %s listed more than once", id))
+		}
+		seen[id] = true
+	}
+
+	if err := s.checklistRepo.ReorderChecklistItems(ctx, taskID, itemIDs); err != nil {
+		return nil, err
+	}
+	return s.checklistRepo.GetChecklistItemsByTaskID(ctx, taskID)
+}
+
+// DeleteItem remove an item from the checklist
+// =========================================================================
+func (s *checklistService) DeleteItem(ctx context.Context, taskID string, itemID string, userID string) error {
+	if _, err := s.taskService.GetTaskByID(ctx, taskID, userID); err != nil {
+		return err
+	}
+
+	if err := s.checklistRepo.DeleteChecklistItemByID(ctx, taskID, itemID); err != nil {
+		return err
+	}
+	s.invalidateTask(ctx, taskID, userID)
+	return nil
+}
+
+// invalidateTask drop the cached task and the owner's lists, its checklist
+// counts just changed
+func (s *checklistService) invalidateTask(ctx context.Context, taskID string, userID string) {
+	key := fmt.Sprintf("%s:cache:task:%s", s.redisAppName, taskID)
+	zerolog.Ctx(ctx).Debug().
+		Str("cache_key", key).
+		Str("task_id", taskID).
+		Msg("invalidating task cache")
+	metrics.ObserveCacheWrite("task", "delete", s.taskCacheRepo.DeleteTaskByID(ctx, key))
+	invalidateTaskLists(ctx, s.taskCacheRepo, userID)
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+)
+
+type mockChecklistRepository struct {
+	createFn  func(ctx context.Context, item *models.ChecklistItem) (string, error)
+	getByIDFn func(ctx context.Context, id string) (*models.ChecklistItem, error)
+	listFn    func(ctx context.Context, taskID string) ([]*models.ChecklistItem, error)
+	updateFn  func(ctx context.Context, taskID string, id string, text *string, checked *bool) (*models.ChecklistItem, error)
+	toggleFn  func(ctx context.Context, taskID string, id string) (*models.ChecklistItem, error)
+	reorderFn func(ctx context.Context, taskID string, itemIDs []string) error
+	deleteFn  func(ctx context.Context, taskID string, id string) error
+}
+
+var _ ports.ChecklistRepository = (*mockChecklistRepository)(nil)
+
+func (m *mockChecklistRepository) CreateChecklistItem(ctx context.Context, item *models.ChecklistItem) (string, error) {
+	if m.createFn != nil {
+		return m.createFn(ctx, item)
+	}
+	item.ID = "item-1"
+	return item.ID, nil
+}
+
+func (m *mockChecklistRepository) GetChecklistItemByID(ctx context.Context, id string) (*models.ChecklistItem, error) {
+	if m.getByIDFn != nil {
+		return m.getByIDFn(ctx, id)
+	}
+	return nil, apperror.NewNotFoundError("checklist item not found")
+}
+
+func (m *mockChecklistRepository) GetChecklistItemsByTaskID(ctx context.Context, taskID string) ([]*models.ChecklistItem, error) {
+	if m.listFn != nil {
+		return m.listFn(ctx, taskID)
+	}
+	return []*models.ChecklistItem{}, nil
+}
+
+func (m *mockChecklistRepository) UpdateChecklistItem(ctx context.Context, taskID string, id string, text *string, checked *bool) (*models.ChecklistItem, error) {
+	if m.updateFn != nil {
+		return m.updateFn(ctx, taskID, id, text, checked)
+	}
+	return &models.ChecklistItem{ID: id, TaskID: taskID}, nil
+}
+
+func (m *mockChecklistRepository) ToggleChecklistItem(ctx context.Context, taskID string, id string) (*models.ChecklistItem, error) {
+	if m.toggleFn != nil {
+		return m.toggleFn(ctx, taskID, id)
+	}
+	return &models.ChecklistItem{ID: id, TaskID: taskID, Checked: true}, nil
+}
+
+func (m *mockChecklistRepository) ReorderChecklistItems(ctx context.Context, taskID string, itemIDs []string) error {
+	if m.reorderFn != nil {
+		return m.reorderFn(ctx, taskID, itemIDs)
+	}
+	return nil
+}
+
+func (m *mockChecklistRepository) DeleteChecklistItemByID(ctx context.Context, taskID string, id string) error {
+	if m.deleteFn != nil {
+		return m.deleteFn(ctx, taskID, id)
+	}
+	return nil
+}
+
+func TestChecklistService_ToggleItem_InvalidatesTaskCache(t *testing.T) {
+	var deleted string
+	cache := &mockTaskCacheRepository{
+		deleteFn: func(ctx context.Context, key string) error {
+			deleted = key
+			return nil
+		},
+	}
+	svc := NewChecklistService(&mockChecklistRepository{}, &mockTaskService{}, cache, "app")
+
+	item, err := svc.ToggleItem(context.Background(), "t1", "i1", "user-1")
+	if err != nil {
+		t.Fatalf("ToggleItem failed: %v", err)
+	}
+	if !item.Checked {
+		t.Error("expected item to be checked")
+	}
+	if deleted != "app:cache:task:t1" {
+		t.Errorf("expected cached task to be invalidated, got %q", deleted)
+	}
+}
+
+func TestChecklistService_UpdateItem_NotOwner(t *testing.T) {
+	taskService := &mockTaskService{
+		getByIDFn: func(ctx context.Context, taskID string, userID string) (*models.Task, error) {
+			return nil, apperror.NewNotFoundError("task not found")
+		},
+	}
+	repo := &mockChecklistRepository{
+		updateFn: func(ctx context.Context, taskID string, id string, text *string, checked *bool) (*models.ChecklistItem, error) {
+			t.Fatal("item of a foreign task must not be updated")
+			return nil, nil
+		},
+	}
+	svc := NewChecklistService(repo, taskService, &mockTaskCacheRepository{}, "app")
+
+	checked := true
+	_, err := svc.UpdateItem(context.Background(), "t1", "i1", "intruder", nil, &checked)
+	var appErr *apperror.AppError
+	if !errors.As(err, &appErr) || appErr.Code != "NOT_FOUND" {
+		t.Errorf("expected NOT_FOUND, got %v", err)
+	}
+}
+
+func TestChecklistService_UpdateItem_Partial(t *testing.T) {
+	repo := &mockChecklistRepository{
+		updateFn: func(ctx context.Context, taskID string, id string, text *string, checked *bool) (*models.ChecklistItem, error) {
+			if text != nil {
+				t.Errorf("expected text to be left to the stored value, got %q", *text)
+			}
+			if checked == nil || !*checked {
+				t.Fatal("expected checked to be set")
+			}
+			return &models.ChecklistItem{ID: id, TaskID: taskID, Text: "buy milk", Checked: true}, nil
+		},
+	}
+	svc := NewChecklistService(repo, &mockTaskService{}, &mockTaskCacheRepository{}, "app")
+
+	checked := true
+	item, err := svc.UpdateItem(context.Background(), "t1", "i1", "user-1", nil, &checked)
+	if err != nil {
+		t.Fatalf("UpdateItem failed: %v", err)
+	}
+	if item.Text != "buy milk" || !item.Checked {
+		t.Errorf("expected stored text and item checked, got %+v", item)
+	}
+}
+
+func TestChecklistService_ReorderItems_RejectsDuplicates(t *testing.T) {
+	repo := &mockChecklistRepository{
+		reorderFn: func(ctx context.Context, taskID string, itemIDs []string) error {
+			t.Fatal("duplicate order must not reach the repository")
+			return nil
+		},
+	}
+	svc := NewChecklistService(repo, &mockTaskService{}, &mockTaskCacheRepository{}, "app")
+
+	_, err := svc.ReorderItems(context.Background(), "t1", "user-1", []string{"a", "b", "a"})
+	var appErr *apperror.AppError
+	if !errors.As(err, &appErr) || appErr.Code != "BAD_REQUEST" {
+		t.Errorf("expected BAD_REQUEST, got %v", err)
+	}
+}
+
+func TestChecklistService_AddItem_NotOwner(t *testing.T) {
+	taskService := &mockTaskService{
+		getByIDFn: func(ctx context.Context, taskID string, userID string) (*models.Task, error) {
+			return nil, apperror.NewForbiddenError("not allowed")
+		},
+	}
+	repo := &mockChecklistRepository{
+		createFn: func(ctx context.Context, item *models.ChecklistItem) (string, error) {
+			t.Fatal("item must not be created for a foreign task")
+			return "", nil
+		},
+	}
+	svc := NewChecklistService(repo, taskService, &mockTaskCacheRepository{}, "app")
+
+	_, err := svc.AddItem(context.Background(), "t1", "intruder", "steal")
+	var appErr *apperror.AppError
+	if !errors.As(err, &appErr) || appErr.Code != "FORBIDDEN" {
+		t.Errorf("expected FORBIDDEN, got %v", err)
+	}
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"context"
+
+	"github.com/suryansh74/task-management-api-project/internal/logger"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+)
+
+type emailNotifier struct {
+	emailService ports.EmailService
+}
+
+// NewEmailNotifier delivers reminders through the email queue, honouring
+// the owner's notification preferences
+// =========================================================================
+func NewEmailNotifier(emailService ports.EmailService) ports.Notifier {
+	logger.Log.Info().Msg("initializing email notifier")
+	return &emailNotifier{emailService: emailService}
+}
+
+func (n *emailNotifier) Channel() string {
+	return models.ReminderChannelEmail
+}
+
+// Notify queue the reminder email, to the target address or the owner's email
+// =========================================================================
+func (n *emailNotifier) Notify(ctx context.Context, reminder *models.DueReminder) error {
+	to := reminder.Target
+	if to == "" {
+		to = reminder.UserEmail
+	}
+
+	return n.emailService.Enqueue(ctx, &models.EmailMessage{
+		Template: "reminder",
+		To:       to,
+		UserID:   reminder.UserID,
+		Category: models.EmailCategoryReminders,
+		Data: map[string]any{
+			"Title": reminder.TaskTitle,
+			"DueAt": reminder.DueAt,
+		},
+	})
+}
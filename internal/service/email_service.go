@@ -0,0 +1,234 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/logger"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+	"github.com/suryansh74/task-management-api-project/internal/utils"
+)
+
+// Every email template is a set of files under templates/email:
+// <name>.subject.tmpl, <name>.txt.tmpl and an optional <name>.html.tmpl
+//
+//go:embed templates/email/*.tmpl
+var emailTemplateFS embed.FS
+
+// maxMailBackoff caps the exponential retry delay
+const maxMailBackoff = time.Hour
+
+type emailService struct {
+	mailer        ports.Mailer
+	mailQueue     ports.MailQueue
+	prefsRepo     ports.NotificationPreferencesRepository
+	textTemplates *texttemplate.Template
+	htmlTemplates *htmltemplate.Template
+	maxAttempts   int
+	backoff       time.Duration
+	batchSize     int
+	now           func() time.Time
+}
+
+// NewEmailService creates a new email service instance
+// =========================================================================
+func NewEmailService(
+	mailer ports.Mailer,
+	mailQueue ports.MailQueue,
+	prefsRepo ports.NotificationPreferencesRepository,
+	maxAttempts int,
+	backoff time.Duration,
+) ports.EmailService {
+	logger.Log.Info().
+		Int("max_attempts", maxAttempts).
+		Dur("backoff", backoff).
+		Msg("initializing email service")
+	return &emailService{
+		mailer:        mailer,
+		mailQueue:     mailQueue,
+		prefsRepo:     prefsRepo,
+		textTemplates: texttemplate.Must(texttemplate.ParseFS(emailTemplateFS, "templates/email/*.subject.tmpl", "templates/email/*.txt.tmpl")),
+		htmlTemplates: htmltemplate.Must(htmltemplate.ParseFS(emailTemplateFS, "templates/email/*.html.tmpl")),
+		maxAttempts:   maxAttempts,
+		backoff:       backoff,
+		batchSize:     50,
+		now:           time.Now,
+	}
+}
+
+// Enqueue render a templated email and queue it for the workers
+// =========================================================================
+func (s *emailService) Enqueue(ctx context.Context, msg *models.EmailMessage) error {
+	zerolog.Ctx(ctx).Debug().
+		Str("template", msg.Template).
+		Str("user_id", msg.UserID).
+		Str("category", msg.Category).
+		Msg("queueing email")
+
+	if msg.To == "" {
+		return apperror.NewBadRequestError("email recipient is required")
+	}
+
+	if msg.UserID != "" {
+		prefs, err := s.prefsRepo.GetPreferences(ctx, msg.UserID)
+		if err != nil {
+			return err
+		}
+		if !prefs.Allows(msg.Category) {
+			zerolog.Ctx(ctx).Info().
+				Str("user_id", msg.UserID).
+				Str("category", msg.Category).
+				Msg("email skipped: user opted out")
+			return nil
+		}
+	}
+
+	email, err := s.render(msg)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("template", msg.Template).
+			Msg("failed to render email")
+		return apperror.NewInternalError("unable to render email", err)
+	}
+
+	job := &models.MailJob{ID: utils.MustRandomID(), Email: *email}
+	if err := s.mailQueue.Enqueue(ctx, job, s.now()); err != nil {
+		return apperror.NewInternalError("unable to queue email", err)
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("job_id", job.ID).
+		Str("template", msg.Template).
+		Msg("email queued successfully")
+	return nil
+}
+
+// ProcessQueue send due emails, failures are retried with exponential backoff
+// =========================================================================
+func (s *emailService) ProcessQueue(ctx context.Context, now time.Time) (int, error) {
+	jobs, err := s.mailQueue.ClaimDue(ctx, now, s.batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, job := range jobs {
+		if err := s.mailer.Send(ctx, &job.Email); err != nil {
+			s.retry(ctx, job, err, now)
+			continue
+		}
+		sent++
+		// a failed ack only means the email may go out twice once its lease ends
+		s.mailQueue.Ack(ctx, job)
+	}
+
+	if sent > 0 {
+		zerolog.Ctx(ctx).Info().
+			Int("sent", sent).
+			Msg("queued emails sent")
+	}
+	return sent, nil
+}
+
+// retry reschedules a failed job or dead letters it after maxAttempts
+func (s *emailService) retry(ctx context.Context, job *models.MailJob, sendErr error, now time.Time) {
+	job.Attempts++
+	job.LastError = sendErr.Error()
+
+	if job.Attempts >= s.maxAttempts {
+		zerolog.Ctx(ctx).Error().
+			Err(sendErr).
+			Str("job_id", job.ID).
+			Int("attempts", job.Attempts).
+			Msg("email failed permanently, moving to dead letter")
+		if err := s.mailQueue.DeadLetter(ctx, job); err != nil {
+			zerolog.Ctx(ctx).Error().
+				Err(err).
+				Str("job_id", job.ID).
+				Msg("failed to dead letter email")
+		}
+		return
+	}
+
+	delay := s.backoff << (job.Attempts - 1)
+	if delay <= 0 || delay > maxMailBackoff {
+		delay = maxMailBackoff
+	}
+
+	zerolog.Ctx(ctx).Warn().
+		Err(sendErr).
+		Str("job_id", job.ID).
+		Int("attempts", job.Attempts).
+		Dur("retry_in", delay).
+		Msg("email send failed, retrying")
+	if err := s.mailQueue.Enqueue(ctx, job, now.Add(delay)); err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("job_id", job.ID).
+			Msg("failed to requeue email")
+	}
+}
+
+// render executes the subject, text and html templates of msg.Template
+func (s *emailService) render(msg *models.EmailMessage) (*models.Email, error) {
+	subjectTmpl := s.textTemplates.Lookup(msg.Template + ".subject.tmpl")
+	textTmpl := s.textTemplates.Lookup(msg.Template + ".txt.tmpl")
+	if subjectTmpl == nil || textTmpl == nil {
+		return nil, fmt.Errorf("unknown email template %q", msg.Template)
+	}
+
+	var subject, text, html bytes.Buffer
+	if err := subjectTmpl.Execute(&subject, msg.Data); err != nil {
+		return nil, err
+	}
+	if err := textTmpl.Execute(&text, msg.Data); err != nil {
+		return nil, err
+	}
+	if htmlTmpl := s.htmlTemplates.Lookup(msg.Template + ".html.tmpl"); htmlTmpl != nil {
+		if err := htmlTmpl.Execute(&html, msg.Data); err != nil {
+			return nil, err
+		}
+	}
+
+	return &models.Email{
+		To:      []string{msg.To},
+		Subject: strings.TrimSpace(subject.String()),
+		Text:    text.String(),
+		HTML:    html.String(),
+	}, nil
+}
+
+// StartMailWorker runs ProcessQueue every interval until ctx is done
+// =========================================================================
+func StartMailWorker(ctx context.Context, emailService ports.EmailService, interval time.Duration) {
+	zerolog.Ctx(ctx).Info().
+		Dur("interval", interval).
+		Msg("mail worker started")
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			zerolog.Ctx(ctx).Info().Msg("mail worker stopped")
+			return
+		case now := <-ticker.C:
+			if _, err := emailService.ProcessQueue(ctx, now); err != nil {
+				zerolog.Ctx(ctx).Error().
+					Err(err).
+					Msg("mail worker run failed")
+			}
+		}
+	}
+}
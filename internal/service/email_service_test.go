@@ -0,0 +1,208 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/suryansh74/task-management-api-project/internal/models"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+)
+
+type mockMailer struct {
+	err  error
+	sent []*models.Email
+}
+
+func (m *mockMailer) Send(ctx context.Context, email *models.Email) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.sent = append(m.sent, email)
+	return nil
+}
+
+type queuedMail struct {
+	job    *models.MailJob
+	sendAt time.Time
+}
+
+type mockMailQueue struct {
+	queued []queuedMail
+	acked  []*models.MailJob
+	dead   []*models.MailJob
+}
+
+func (m *mockMailQueue) Enqueue(ctx context.Context, job *models.MailJob, sendAt time.Time) error {
+	m.queued = append(m.queued, queuedMail{job: job, sendAt: sendAt})
+	return nil
+}
+func (m *mockMailQueue) ClaimDue(ctx context.Context, now time.Time, limit int) ([]*models.MailJob, error) {
+	var jobs []*models.MailJob
+	var rest []queuedMail
+	for _, q := range m.queued {
+		if !q.sendAt.After(now) && len(jobs) < limit {
+			jobs = append(jobs, q.job)
+			continue
+		}
+		rest = append(rest, q)
+	}
+	m.queued = rest
+	return jobs, nil
+}
+func (m *mockMailQueue) Ack(ctx context.Context, job *models.MailJob) error {
+	m.acked = append(m.acked, job)
+	return nil
+}
+func (m *mockMailQueue) DeadLetter(ctx context.Context, job *models.MailJob) error {
+	m.dead = append(m.dead, job)
+	return nil
+}
+
+type mockPreferencesRepository struct {
+	prefs *models.NotificationPreferences
+}
+
+func (m *mockPreferencesRepository) GetPreferences(ctx context.Context, userID string) (*models.NotificationPreferences, error) {
+	if m.prefs != nil {
+		return m.prefs, nil
+	}
+	return models.DefaultNotificationPreferences(userID), nil
+}
+func (m *mockPreferencesRepository) UpsertPreferences(ctx context.Context, prefs *models.NotificationPreferences) error {
+	m.prefs = prefs
+	return nil
+}
+
+func reminderMessage() *models.EmailMessage {
+	return &models.EmailMessage{
+		Template: "reminder",
+		To:       "owner@example.com",
+		UserID:   "user-1",
+		Category: models.EmailCategoryReminders,
+		Data: map[string]any{
+			"Title": "<b>Ship</b> release",
+			"DueAt": time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC),
+		},
+	}
+}
+
+func TestEmailService_Enqueue_RendersTemplates(t *testing.T) {
+	queue := &mockMailQueue{}
+	svc := NewEmailService(&mockMailer{}, queue, &mockPreferencesRepository{}, 3, time.Second)
+
+	if err := svc.Enqueue(context.Background(), reminderMessage()); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+	if len(queue.queued) != 1 {
+		t.Fatalf("expected one queued email, got %d", len(queue.queued))
+	}
+
+	email := queue.queued[0].job.Email
+	if email.Subject != "Reminder: <b>Ship</b> release" {
+		t.Errorf("unexpected subject: %q", email.Subject)
+	}
+	if !strings.Contains(email.Text, "Mon, 02 Mar 2026 09:00 UTC") {
+		t.Errorf("expected due date in text body, got %q", email.Text)
+	}
+	if !strings.Contains(email.HTML, "&lt;b&gt;Ship&lt;/b&gt;") {
+		t.Errorf("expected escaped title in html body, got %q", email.HTML)
+	}
+}
+
+func TestEmailService_Enqueue_RespectsPreferences(t *testing.T) {
+	queue := &mockMailQueue{}
+	prefs := models.DefaultNotificationPreferences("user-1")
+	prefs.Reminders = false
+	svc := NewEmailService(&mockMailer{}, queue, &mockPreferencesRepository{prefs: prefs}, 3, time.Second)
+
+	if err := svc.Enqueue(context.Background(), reminderMessage()); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+	if len(queue.queued) != 0 {
+		t.Error("expected opted-out reminder email to be skipped")
+	}
+
+	// account emails ignore preferences
+	msg := reminderMessage()
+	msg.Category = models.EmailCategoryAccount
+	prefs.EmailEnabled = false
+	if err := svc.Enqueue(context.Background(), msg); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+	if len(queue.queued) != 1 {
+		t.Error("expected account email to be queued")
+	}
+}
+
+func TestEmailService_Enqueue_UnknownTemplate(t *testing.T) {
+	svc := NewEmailService(&mockMailer{}, &mockMailQueue{}, &mockPreferencesRepository{}, 3, time.Second)
+
+	msg := reminderMessage()
+	msg.Template = "does-not-exist"
+	if err := svc.Enqueue(context.Background(), msg); err == nil {
+		t.Error("expected unknown template to fail")
+	}
+}
+
+func TestEmailService_ProcessQueue_RetriesThenDeadLetters(t *testing.T) {
+	queue := &mockMailQueue{}
+	mailer := &mockMailer{err: errors.New("connection refused")}
+	svc := NewEmailService(mailer, queue, &mockPreferencesRepository{}, 3, time.Second)
+
+	if err := svc.Enqueue(context.Background(), reminderMessage()); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+
+	now := time.Now()
+	// attempt 1 and 2 are rescheduled with growing delays
+	for attempt, wantDelay := range []time.Duration{time.Second, 2 * time.Second} {
+		now = now.Add(time.Hour)
+		if _, err := svc.ProcessQueue(context.Background(), now); err != nil {
+			t.Fatalf("ProcessQueue failed: %v", err)
+		}
+		if len(queue.queued) != 1 {
+			t.Fatalf("attempt %d: expected job to be requeued", attempt+1)
+		}
+		if got := queue.queued[0].sendAt.Sub(now); got != wantDelay {
+			t.Errorf("attempt %d: expected retry in %v, got %v", attempt+1, wantDelay, got)
+		}
+	}
+
+	now = now.Add(time.Hour)
+	if _, err := svc.ProcessQueue(context.Background(), now); err != nil {
+		t.Fatalf("ProcessQueue failed: %v", err)
+	}
+	if len(queue.queued) != 0 || len(queue.dead) != 1 {
+		t.Fatalf("expected job in dead letter after 3 attempts, queued=%d dead=%d", len(queue.queued), len(queue.dead))
+	}
+	if queue.dead[0].Attempts != 3 || queue.dead[0].LastError == "" {
+		t.Errorf("unexpected dead job: %+v", queue.dead[0])
+	}
+}
+
+func TestEmailService_ProcessQueue_Sends(t *testing.T) {
+	queue := &mockMailQueue{}
+	mailer := &mockMailer{}
+	svc := NewEmailService(mailer, queue, &mockPreferencesRepository{}, 3, time.Second)
+
+	if err := svc.Enqueue(context.Background(), reminderMessage()); err != nil {
+		t.Fatalf("Enqueue failed: %v", err)
+	}
+	sent, err := svc.ProcessQueue(context.Background(), time.Now().Add(time.Second))
+	if err != nil {
+		t.Fatalf("ProcessQueue failed: %v", err)
+	}
+	if sent != 1 || len(mailer.sent) != 1 || mailer.sent[0].To[0] != "owner@example.com" {
+		t.Errorf("expected one email to owner, got %d", sent)
+	}
+	if len(queue.acked) != 1 {
+		t.Errorf("expected the sent job to be acked, got %d acks", len(queue.acked))
+	}
+}
+
+var _ ports.Mailer = (*mockMailer)(nil)
+var _ ports.MailQueue = (*mockMailQueue)(nil)
+var _ ports.NotificationPreferencesRepository = (*mockPreferencesRepository)(nil)
@@ -0,0 +1,131 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+)
+
+var verificationLinkPattern = regexp.MustCompile(`https://app\.example\.com/verify\?token=\S+`)
+
+// newEmailVerificationTest one unverified user, user-1, whose email can be changed through the returned pointer
+func newEmailVerificationTest(t *testing.T) (*emailVerificationService, *models.User, *mockMailQueue, *mockSessionService) {
+	t.Helper()
+	user := &models.User{ID: "user-1", Name: "Alice", Email: "alice@example.com"}
+	users := &mockUserRepository{
+		findByIDFn: func(ctx context.Context, id string) (*models.User, error) {
+			if id != user.ID {
+				return nil, apperror.NewNotFoundError("User not found")
+			}
+			copied := *user
+			return &copied, nil
+		},
+		markEmailVerifiedFn: func(ctx context.Context, userID string, email string) error {
+			if userID != user.ID || email != user.Email {
+				return apperror.NewNotFoundError("User not found")
+			}
+			now := time.Now()
+			user.EmailVerifiedAt = &now
+			return nil
+		},
+	}
+	queue := &mockMailQueue{}
+	sessions := &mockSessionService{}
+	svc := NewEmailVerificationService(
+		users,
+		sessions,
+		NewEmailService(&mockMailer{}, queue, &mockPreferencesRepository{}, 3, time.Second),
+		"secret",
+		"https://app.example.com/verify",
+		time.Hour,
+	).(*emailVerificationService)
+	return svc, user, queue, sessions
+}
+
+// emailedVerificationToken token from the link of the i-th queued email
+func emailedVerificationToken(t *testing.T, queue *mockMailQueue, i int) string {
+	t.Helper()
+	link := verificationLinkPattern.FindString(queue.queued[i].job.Email.Text)
+	u, err := url.Parse(link)
+	if err != nil || link == "" {
+		t.Fatalf("no verification link in %q", queue.queued[i].job.Email.Text)
+	}
+	return u.Query().Get("token")
+}
+
+func TestEmailVerificationService_Verify(t *testing.T) {
+	svc, user, queue, sessions := newEmailVerificationTest(t)
+	ctx := context.Background()
+
+	if err := svc.SendVerification(ctx, user.ID); err != nil {
+		t.Fatalf("SendVerification failed: %v", err)
+	}
+	token := emailedVerificationToken(t, queue, 0)
+
+	if err := svc.Verify(ctx, token); err != nil {
+		t.Fatalf("Verify failed: %v", err)
+	}
+	if user.EmailVerifiedAt == nil {
+		t.Fatal("expected the email to be verified")
+	}
+	if len(sessions.verified) != 1 || sessions.verified[0] != user.ID {
+		t.Errorf("expected the user's sessions to be marked verified, got %v", sessions.verified)
+	}
+
+	// opening the link again is fine
+	if err := svc.Verify(ctx, token); err != nil {
+		t.Errorf("second Verify failed: %v", err)
+	}
+
+	// nothing left to verify
+	err := svc.SendVerification(ctx, user.ID)
+	var appErr *apperror.AppError
+	if !errors.As(err, &appErr) || appErr.Code != "CONFLICT" {
+		t.Errorf("expected conflict for a verified user, got %v", err)
+	}
+}
+
+func TestEmailVerificationService_Verify_Invalid(t *testing.T) {
+	svc, user, queue, _ := newEmailVerificationTest(t)
+	ctx := context.Background()
+
+	if err := svc.SendVerification(ctx, user.ID); err != nil {
+		t.Fatalf("SendVerification failed: %v", err)
+	}
+	token := emailedVerificationToken(t, queue, 0)
+	parts := strings.Split(token, ".")
+
+	for name, bad := range map[string]string{
+		"empty":         "",
+		"malformed":     "not-a-token",
+		"other user":    "user-2." + parts[1] + "." + parts[2],
+		"longer life":   parts[0] + ".9999999999." + parts[2],
+		"bad signature": parts[0] + "." + parts[1] + ".00",
+	} {
+		if err := svc.Verify(ctx, bad); !isBadRequest(err) {
+			t.Errorf("%s: expected bad request, got %v", name, err)
+		}
+	}
+
+	// links sent to the old address stop working once it changes
+	user.Email = "alice@new.example.com"
+	if err := svc.Verify(ctx, token); !isBadRequest(err) {
+		t.Errorf("expected bad request after an email change, got %v", err)
+	}
+	user.Email = "alice@example.com"
+
+	svc.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+	if err := svc.Verify(ctx, token); !isBadRequest(err) {
+		t.Errorf("expected bad request for an expired token, got %v", err)
+	}
+	if user.EmailVerifiedAt != nil {
+		t.Error("no invalid token should verify the email")
+	}
+}
@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/suryansh74/task-management-api-project/internal/logger"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+)
+
+// SchemaVersion version of init.sql this build needs, bump both together
+const SchemaVersion = 5
+
+type healthService struct {
+	healthRepo ports.HealthRepository
+	timeout    time.Duration
+}
+
+// NewHealthService creates a new health service instance
+// =========================================================================
+func NewHealthService(healthRepo ports.HealthRepository, timeout time.Duration) ports.HealthService {
+	logger.Log.Info().Msg("initializing health service")
+	return &healthService{
+		healthRepo: healthRepo,
+		timeout:    timeout,
+	}
+}
+
+// CheckReadiness run every check concurrently, each with its own timeout;
+// postgres checks take their own pool connection, so a timed out one is
+// replaced by the pool instead of breaking the next probe
+// =========================================================================
+func (s *healthService) CheckReadiness(ctx context.Context) *models.HealthReport {
+	checks := []struct {
+		name string
+		run  func(ctx context.Context) error
+	}{
+		{"postgres", s.healthRepo.PingPostgres},
+		{"redis", s.healthRepo.PingRedis},
+		{"migrations", s.checkSchemaVersion},
+	}
+
+	results := make([]*models.HealthCheck, len(checks))
+	var wg sync.WaitGroup
+	for i, check := range checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = s.runCheck(ctx, check.name, check.run)
+		}()
+	}
+	wg.Wait()
+
+	report := &models.HealthReport{Status: models.HealthStatusOK, Checks: results}
+	for _, result := range results {
+		if result.Status != models.HealthStatusOK {
+			report.Status = models.HealthStatusFailing
+		}
+	}
+	return report
+}
+
+func (s *healthService) runCheck(ctx context.Context, name string, run func(ctx context.Context) error) *models.HealthCheck {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := run(ctx)
+	result := &models.HealthCheck{
+		Name:       name,
+		Status:     models.HealthStatusOK,
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		zerolog.Ctx(ctx).Warn().
+			Err(err).
+			Str("check", name).
+			Msg("readiness check failed")
+		result.Status = models.HealthStatusFailing
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// checkSchemaVersion the database must be migrated at least as far as the code expects
+func (s *healthService) checkSchemaVersion(ctx context.Context) error {
+	version, err := s.healthRepo.GetSchemaVersion(ctx)
+	if err != nil {
+		return err
+	}
+	if version < SchemaVersion {
+		return fmt.Errorf("schema version %d, need %d", version, SchemaVersion)
+	}
+	return nil
+}
+
+// StartHealthMonitor reports readiness to onChange every interval, used to
+// keep the gRPC health service in step with the HTTP probe
+// =========================================================================
+func StartHealthMonitor(ctx context.Context, healthService ports.HealthService, interval time.Duration, onChange func(ok bool)) {
+	zerolog.Ctx(ctx).Info().
+		Dur("interval", interval).
+		Msg("health monitor started")
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	onChange(healthService.CheckReadiness(ctx).OK())
+	for {
+		select {
+		case <-ctx.Done():
+			zerolog.Ctx(ctx).Info().Msg("health monitor stopped")
+			return
+		case <-ticker.C:
+			onChange(healthService.CheckReadiness(ctx).OK())
+		}
+	}
+}
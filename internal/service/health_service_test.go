@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/suryansh74/task-management-api-project/internal/models"
+)
+
+type mockHealthRepository struct {
+	postgresErr   error
+	redisErr      error
+	schemaVersion int
+	delay         time.Duration
+}
+
+func (m *mockHealthRepository) PingPostgres(ctx context.Context) error {
+	select {
+	case <-time.After(m.delay):
+		return m.postgresErr
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+func (m *mockHealthRepository) PingRedis(ctx context.Context) error {
+	return m.redisErr
+}
+func (m *mockHealthRepository) GetSchemaVersion(ctx context.Context) (int, error) {
+	return m.schemaVersion, nil
+}
+
+func checkStatuses(report *models.HealthReport) map[string]string {
+	statuses := map[string]string{}
+	for _, check := range report.Checks {
+		statuses[check.Name] = check.Status
+	}
+	return statuses
+}
+
+func TestHealthService_CheckReadiness(t *testing.T) {
+	ok := NewHealthService(&mockHealthRepository{schemaVersion: SchemaVersion}, time.Second).CheckReadiness(context.Background())
+	if !ok.OK() || len(ok.Checks) != 3 {
+		t.Fatalf("expected 3 passing checks, got %+v", ok)
+	}
+	if ok.Checks[0].Name != "postgres" || ok.Checks[2].Name != "migrations" {
+		t.Errorf("expected checks in a stable order, got %+v", ok.Checks)
+	}
+
+	report := NewHealthService(&mockHealthRepository{
+		redisErr:      errors.New("connection refused"),
+		schemaVersion: SchemaVersion - 1,
+	}, time.Second).CheckReadiness(context.Background())
+	if report.OK() {
+		t.Fatal("expected readiness to fail")
+	}
+	want := map[string]string{
+		"postgres":   models.HealthStatusOK,
+		"redis":      models.HealthStatusFailing,
+		"migrations": models.HealthStatusFailing,
+	}
+	for name, status := range checkStatuses(report) {
+		if want[name] != status {
+			t.Errorf("check %s: expected %s, got %s", name, want[name], status)
+		}
+	}
+}
+
+func TestHealthService_CheckReadiness_Timeout(t *testing.T) {
+	svc := NewHealthService(&mockHealthRepository{schemaVersion: SchemaVersion, delay: time.Minute}, 50*time.Millisecond)
+
+	start := time.Now()
+	report := svc.CheckReadiness(context.Background())
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("a hanging check must time out, took %s", elapsed)
+	}
+	if report.OK() || checkStatuses(report)["postgres"] != models.HealthStatusFailing {
+		t.Errorf("expected the postgres check to fail, got %+v", report.Checks)
+	}
+	// the other checks don't wait behind the hanging one
+	if redis := report.Checks[1]; redis.Status != models.HealthStatusOK || redis.DurationMs >= 50 {
+		t.Errorf("expected the redis check to pass right away, got %+v", redis)
+	}
+}
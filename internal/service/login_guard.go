@@ -0,0 +1,122 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	"github.com/rs/zerolog"
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+)
+
+// loginGuard brute-force protection shared by both login steps, a wrong
+// password and a wrong two-factor code count against the same account
+type loginGuard struct {
+	attempts   ports.LoginAttemptStore
+	auditLog   ports.AuditLog
+	protection models.LoginProtection
+}
+
+// check refuse the attempt while the account or the IP is throttled
+func (g *loginGuard) check(ctx context.Context, account string, userID string, email string, clientIP string) error {
+	throttle, err := g.attempts.Check(ctx, account, clientIP)
+	if err != nil {
+		return err
+	}
+	if throttle == nil {
+		return nil
+	}
+	g.auditLog.Record(ctx, &models.AuditEvent{
+		Type:       models.AuditLoginThrottled,
+		UserID:     userID,
+		Email:      email,
+		IP:         clientIP,
+		Reason:     throttle.Reason,
+		RetryAfter: throttle.RetryAfter,
+	})
+	return apperror.NewLoginThrottledError("too many failed login attempts, try again later", throttle.RetryAfter)
+}
+
+// failed record the failure and audit it, the caller picks the error so the
+// response doesn't say whether the email exists
+func (g *loginGuard) failed(ctx context.Context, account string, userID string, email string, clientIP string, reason string) {
+	failure, err := g.attempts.RecordFailure(ctx, account, clientIP, g.protection)
+	if err != nil {
+		// still a failed login, only the bookkeeping is missing
+		g.auditLog.Record(ctx, &models.AuditEvent{
+			Type:   models.AuditLoginFailed,
+			UserID: userID,
+			Email:  email,
+			IP:     clientIP,
+			Reason: reason,
+		})
+		return
+	}
+
+	g.auditLog.Record(ctx, &models.AuditEvent{
+		Type:       models.AuditLoginFailed,
+		UserID:     userID,
+		Email:      email,
+		IP:         clientIP,
+		Reason:     reason,
+		Failures:   failure.Failures,
+		RetryAfter: failure.RetryAfter,
+	})
+	if failure.Locked {
+		g.auditLog.Record(ctx, &models.AuditEvent{
+			Type:       models.AuditAccountLocked,
+			UserID:     userID,
+			Email:      email,
+			IP:         clientIP,
+			Failures:   int64(g.protection.MaxFailures),
+			RetryAfter: failure.RetryAfter,
+		})
+	}
+	if failure.IPBlocked {
+		g.auditLog.Record(ctx, &models.AuditEvent{
+			Type:       models.AuditCredentialStuffing,
+			Email:      email,
+			IP:         clientIP,
+			Reason:     "ip_blocked",
+			Distinct:   failure.DistinctAccounts,
+			RetryAfter: g.protection.StuffingBlock,
+		})
+	}
+	if failure.Distributed {
+		g.auditLog.Record(ctx, &models.AuditEvent{
+			Type:     models.AuditDistributedAttack,
+			UserID:   userID,
+			Email:    email,
+			IP:       clientIP,
+			Failures: failure.Failures,
+			Distinct: failure.DistinctIPs,
+		})
+	}
+}
+
+// succeeded the login is complete, forget the account's failures
+func (g *loginGuard) succeeded(ctx context.Context, account string, userID string, email string, clientIP string) {
+	// a failure to clear the counter only costs a delay later
+	if err := g.attempts.Reset(ctx, account); err != nil {
+		zerolog.Ctx(ctx).Warn().
+			Err(err).
+			Str("user_id", userID).
+			Msg("failed to reset login attempts")
+	}
+	g.auditLog.Record(ctx, &models.AuditEvent{
+		Type:   models.AuditLoginSucceeded,
+		UserID: userID,
+		Email:  email,
+		IP:     clientIP,
+	})
+}
+
+// loginAccountKey the email normalized and hashed, unknown emails get a key too
+// and the store never holds addresses
+func loginAccountKey(email string) string {
+	sum := sha256.Sum256([]byte(strings.ToLower(strings.TrimSpace(email))))
+	return hex.EncodeToString(sum[:])
+}
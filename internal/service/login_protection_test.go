@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+	"github.com/suryansh74/task-management-api-project/internal/utils"
+)
+
+var testLoginProtection = models.LoginProtection{
+	MaxFailures:      3,
+	Window:           15 * time.Minute,
+	Lockout:          15 * time.Minute,
+	BaseDelay:        time.Second,
+	MaxDelay:         30 * time.Second,
+	StuffingAccounts: 2,
+	StuffingBlock:    time.Hour,
+	DistributedIPs:   2,
+}
+
+// mockLoginAttemptStore counts failures in memory without delays, throttle
+// overrides Check, locked accounts stay locked until Reset
+type mockLoginAttemptStore struct {
+	throttle   *models.LoginThrottle
+	failures   map[string]int64
+	locked     map[string]bool
+	ipAccounts map[string]map[string]bool
+	accountIPs map[string]map[string]bool
+	resets     []string
+}
+
+func (m *mockLoginAttemptStore) Check(ctx context.Context, account string, ip string) (*models.LoginThrottle, error) {
+	if m.throttle == nil && m.locked[account] {
+		return &models.LoginThrottle{Reason: models.LoginThrottleLocked, RetryAfter: time.Minute}, nil
+	}
+	return m.throttle, nil
+}
+func (m *mockLoginAttemptStore) RecordFailure(ctx context.Context, account string, ip string, protection models.LoginProtection) (*models.LoginFailure, error) {
+	if m.failures == nil {
+		m.failures = map[string]int64{}
+		m.locked = map[string]bool{}
+		m.ipAccounts = map[string]map[string]bool{}
+		m.accountIPs = map[string]map[string]bool{}
+	}
+	if m.ipAccounts[ip] == nil {
+		m.ipAccounts[ip] = map[string]bool{}
+	}
+	if m.accountIPs[account] == nil {
+		m.accountIPs[account] = map[string]bool{}
+	}
+	m.failures[account]++
+	m.ipAccounts[ip][account] = true
+	newIP := !m.accountIPs[account][ip]
+	m.accountIPs[account][ip] = true
+
+	failure := &models.LoginFailure{
+		Failures:         m.failures[account],
+		DistinctAccounts: int64(len(m.ipAccounts[ip])),
+		DistinctIPs:      int64(len(m.accountIPs[account])),
+	}
+	failure.Distributed = newIP && failure.DistinctIPs == int64(protection.DistributedIPs)
+	if failure.Failures >= int64(protection.MaxFailures) {
+		failure.Locked, failure.RetryAfter = true, protection.Lockout
+		m.locked[account] = true
+		delete(m.failures, account)
+	}
+	failure.IPBlocked = failure.DistinctAccounts == int64(protection.StuffingAccounts)
+	return failure, nil
+}
+func (m *mockLoginAttemptStore) Reset(ctx context.Context, account string) error {
+	m.resets = append(m.resets, account)
+	delete(m.failures, account)
+	delete(m.locked, account)
+	return nil
+}
+
+type mockAuditLog struct {
+	events []*models.AuditEvent
+}
+
+func (m *mockAuditLog) Record(ctx context.Context, event *models.AuditEvent) {
+	m.events = append(m.events, event)
+}
+
+func (m *mockAuditLog) types() []string {
+	types := make([]string, 0, len(m.events))
+	for _, event := range m.events {
+		types = append(types, event.Type)
+	}
+	return types
+}
+
+// Ensure interface compliance
+var _ ports.LoginAttemptStore = (*mockLoginAttemptStore)(nil)
+var _ ports.AuditLog = (*mockAuditLog)(nil)
+
+func newLoginProtectionTest(t *testing.T) (ports.UserService, *mockLoginAttemptStore, *mockAuditLog) {
+	t.Helper()
+	hashed, err := utils.HashedPassword("password123")
+	if err != nil {
+		t.Fatalf("hash password: %v", err)
+	}
+	users := &mockUserRepository{
+		findByEmailFn: func(ctx context.Context, email string) (*models.User, error) {
+			if email != "alice@example.com" {
+				return nil, apperror.NewNotFoundError("user not found")
+			}
+			return &models.User{ID: "user-1", Email: email, Password: hashed}, nil
+		},
+	}
+	attempts, audit := &mockLoginAttemptStore{}, &mockAuditLog{}
+	return NewUserService(users, &mockMFARepository{}, attempts, audit, testLoginProtection), attempts, audit
+}
+
+func TestUserService_Login_Throttled(t *testing.T) {
+	svc, attempts, audit := newLoginProtectionTest(t)
+	attempts.throttle = &models.LoginThrottle{Reason: models.LoginThrottleLocked, RetryAfter: 90 * time.Second}
+
+	// refused before the password is checked, even the right one
+	_, err := svc.Login(context.Background(), "alice@example.com", "password123", "203.0.113.7")
+	var appErr *apperror.AppError
+	if !errors.As(err, &appErr) || appErr.Code != "LOGIN_THROTTLED" {
+		t.Fatalf("expected LOGIN_THROTTLED, got %v", err)
+	}
+	if appErr.RetryAfter != 90*time.Second {
+		t.Errorf("expected retry after 90s, got %v", appErr.RetryAfter)
+	}
+	if len(audit.events) != 1 || audit.events[0].Type != models.AuditLoginThrottled || audit.events[0].Reason != models.LoginThrottleLocked {
+		t.Errorf("expected one login_throttled event, got %v", audit.types())
+	}
+	if len(attempts.failures) != 0 {
+		t.Error("a throttled attempt must not count as a failure")
+	}
+}
+
+func TestUserService_Login_UnknownEmailCountsAsFailure(t *testing.T) {
+	svc, attempts, audit := newLoginProtectionTest(t)
+
+	_, err := svc.Login(context.Background(), "Nobody@Example.com ", "password123", "203.0.113.7")
+	if !isUnauthorized(err) {
+		t.Fatalf("expected unauthorized, got %v", err)
+	}
+	if attempts.failures[loginAccountKey("nobody@example.com")] != 1 {
+		t.Errorf("expected the unknown email to be tracked, got %v", attempts.failures)
+	}
+	if len(audit.events) != 1 || audit.events[0].Reason != "unknown_email" || audit.events[0].UserID != "" {
+		t.Errorf("unexpected audit events %+v", audit.events)
+	}
+}
+
+func TestUserService_Login_ResetOnSuccess(t *testing.T) {
+	svc, attempts, audit := newLoginProtectionTest(t)
+	ctx := context.Background()
+
+	for i := 0; i < testLoginProtection.MaxFailures-1; i++ {
+		svc.Login(ctx, "alice@example.com", "wrongpass", "203.0.113.7")
+	}
+	if _, err := svc.Login(ctx, "alice@example.com", "password123", "203.0.113.7"); err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+	if len(attempts.resets) != 1 || attempts.resets[0] != loginAccountKey("alice@example.com") {
+		t.Errorf("expected the account to be reset, got %v", attempts.resets)
+	}
+	if last := audit.events[len(audit.events)-1]; last.Type != models.AuditLoginSucceeded {
+		t.Errorf("expected login_succeeded, got %s", last.Type)
+	}
+}
+
+func TestUserService_Login_Lockout(t *testing.T) {
+	svc, _, audit := newLoginProtectionTest(t)
+	ctx := context.Background()
+
+	for i := 0; i < testLoginProtection.MaxFailures; i++ {
+		if _, err := svc.Login(ctx, "alice@example.com", "wrongpass", "203.0.113.7"); !isUnauthorized(err) {
+			t.Fatalf("attempt %d: expected unauthorized, got %v", i+1, err)
+		}
+	}
+	want := []string{models.AuditLoginFailed, models.AuditLoginFailed, models.AuditLoginFailed, models.AuditAccountLocked}
+	if got := audit.types(); len(got) != len(want) || got[3] != want[3] {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	if audit.events[3].UserID != "user-1" {
+		t.Errorf("expected the lock to name the user, got %q", audit.events[3].UserID)
+	}
+
+	// locked, even for the right password
+	_, err := svc.Login(ctx, "alice@example.com", "password123", "203.0.113.7")
+	var appErr *apperror.AppError
+	if !errors.As(err, &appErr) || appErr.Code != "LOGIN_THROTTLED" {
+		t.Errorf("expected LOGIN_THROTTLED, got %v", err)
+	}
+}
+
+func TestUserService_Login_CredentialStuffing(t *testing.T) {
+	svc, _, audit := newLoginProtectionTest(t)
+	ctx := context.Background()
+
+	// one IP failing on StuffingAccounts different accounts
+	svc.Login(ctx, "alice@example.com", "wrongpass", "198.51.100.9")
+	svc.Login(ctx, "bob@example.com", "wrongpass", "198.51.100.9")
+
+	var stuffing *models.AuditEvent
+	for _, event := range audit.events {
+		if event.Type == models.AuditCredentialStuffing {
+			stuffing = event
+		}
+	}
+	if stuffing == nil {
+		t.Fatalf("expected a credential_stuffing event, got %v", audit.types())
+	}
+	if stuffing.IP != "198.51.100.9" || stuffing.Distinct != 2 {
+		t.Errorf("unexpected stuffing event %+v", stuffing)
+	}
+}
+
+func TestUserService_Login_DistributedAttack(t *testing.T) {
+	svc, _, audit := newLoginProtectionTest(t)
+	ctx := context.Background()
+
+	// one account failing from DistributedIPs different IPs, reported once
+	svc.Login(ctx, "alice@example.com", "wrongpass", "198.51.100.1")
+	svc.Login(ctx, "alice@example.com", "wrongpass", "198.51.100.2")
+	svc.Login(ctx, "alice@example.com", "wrongpass", "198.51.100.2")
+
+	var distributed []*models.AuditEvent
+	for _, event := range audit.events {
+		if event.Type == models.AuditDistributedAttack {
+			distributed = append(distributed, event)
+		}
+	}
+	if len(distributed) != 1 {
+		t.Fatalf("expected one distributed_attack event, got %v", audit.types())
+	}
+	if distributed[0].UserID != "user-1" || distributed[0].Distinct != 2 {
+		t.Errorf("unexpected distributed event %+v", distributed[0])
+	}
+}
+
+func TestLoginAccountKey(t *testing.T) {
+	if loginAccountKey(" Alice@Example.COM") != loginAccountKey("alice@example.com") {
+		t.Error("expected the key to ignore case and surrounding spaces")
+	}
+	if loginAccountKey("alice@example.com") == loginAccountKey("bob@example.com") {
+		t.Error("expected different emails to get different keys")
+	}
+}
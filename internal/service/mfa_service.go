@@ -0,0 +1,341 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/logger"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+	"github.com/suryansh74/task-management-api-project/internal/utils"
+)
+
+const (
+	// recoveryCodeCount codes handed out when 2FA is enabled
+	recoveryCodeCount = 10
+	// recoveryCodeBytes 5 random bytes, shown as xxxxx-xxxxx
+	recoveryCodeBytes = 5
+	// totpSkew steps accepted either side of now, covers clock drift and slow typing
+	totpSkew = 1
+	// mfaChallengeIDBytes the pending login cookie, as hard to guess as a session id
+	mfaChallengeIDBytes = 32
+)
+
+type mfaService struct {
+	userRepo       ports.UserRepository
+	mfaRepo        ports.MFARepository
+	challengeStore ports.MFAChallengeStore
+	encryptionKey  string
+	issuer         string
+	challengeTTL   time.Duration
+	maxAttempts    int
+	guard          *loginGuard
+	now            func() time.Time
+}
+
+// NewMFAService creates a new two-factor authentication service instance,
+// a login challenge is dropped after maxAttempts wrong codes and every wrong
+// code counts against the account like a wrong password
+// =========================================================================
+func NewMFAService(
+	userRepo ports.UserRepository,
+	mfaRepo ports.MFARepository,
+	challengeStore ports.MFAChallengeStore,
+	attempts ports.LoginAttemptStore,
+	auditLog ports.AuditLog,
+	protection models.LoginProtection,
+	encryptionKey string,
+	issuer string,
+	challengeTTL time.Duration,
+	maxAttempts int,
+) ports.MFAService {
+	logger.Log.Info().
+		Str("issuer", issuer).
+		Dur("challenge_ttl", challengeTTL).
+		Int("max_attempts", maxAttempts).
+		Msg("initializing mfa service")
+	return &mfaService{
+		userRepo:       userRepo,
+		mfaRepo:        mfaRepo,
+		challengeStore: challengeStore,
+		encryptionKey:  encryptionKey,
+		issuer:         issuer,
+		challengeTTL:   challengeTTL,
+		maxAttempts:    maxAttempts,
+		guard:          &loginGuard{attempts: attempts, auditLog: auditLog, protection: protection},
+		now:            time.Now,
+	}
+}
+
+// Enroll generate a secret for the authenticator app, replacing an unconfirmed one
+// =========================================================================
+func (s *mfaService) Enroll(ctx context.Context, userID string) (*models.MFAEnrollment, error) {
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := utils.GenerateTOTPSecret()
+	if err != nil {
+		return nil, apperror.NewInternalError("Failed to generate two-factor secret", err)
+	}
+	sealed, err := utils.Encrypt(s.encryptionKey, secret)
+	if err != nil {
+		return nil, apperror.NewInternalError("Failed to protect two-factor secret", err)
+	}
+	if err := s.mfaRepo.SavePendingSecret(ctx, userID, sealed); err != nil {
+		return nil, err
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("user_id", userID).
+		Msg("mfa enrollment started")
+	return &models.MFAEnrollment{
+		Secret:          secret,
+		ProvisioningURI: utils.TOTPProvisioningURI(s.issuer, user.Email, secret),
+	}, nil
+}
+
+// Confirm check a first code from the app, then enable 2FA with fresh recovery codes
+// =========================================================================
+func (s *mfaService) Confirm(ctx context.Context, userID string, code string) ([]string, error) {
+	mfa, err := s.mfaRepo.GetMFA(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if mfa.Enabled {
+		return nil, apperror.NewConflictError("two-factor authentication is already enabled")
+	}
+	if mfa.Secret == "" {
+		return nil, apperror.NewBadRequestError("start two-factor enrollment first")
+	}
+
+	secret, err := utils.Decrypt(s.encryptionKey, mfa.Secret)
+	if err != nil {
+		return nil, apperror.NewInternalError("Failed to read two-factor secret", err)
+	}
+	step, ok := utils.ValidateTOTP(secret, strings.TrimSpace(code), s.now(), totpSkew)
+	if !ok {
+		return nil, apperror.NewBadRequestError("invalid two-factor code")
+	}
+
+	codes := make([]string, recoveryCodeCount)
+	hashes := make([]string, recoveryCodeCount)
+	for i := range codes {
+		raw, err := utils.GenerateRandomID(recoveryCodeBytes)
+		if err != nil {
+			return nil, apperror.NewInternalError("Failed to generate recovery codes", err)
+		}
+		codes[i] = raw[:5] + "-" + raw[5:]
+		hashes[i] = hashRecoveryCode(codes[i])
+	}
+	// the confirming code counts as used, it can't log anyone in afterwards
+	if err := s.mfaRepo.Enable(ctx, userID, step, hashes); err != nil {
+		return nil, err
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("user_id", userID).
+		Msg("mfa enabled")
+	return codes, nil
+}
+
+// Disable turn 2FA off, proven with a current or recovery code
+// =========================================================================
+func (s *mfaService) Disable(ctx context.Context, userID string, code string, clientIP string) error {
+	mfa, err := s.mfaRepo.GetMFA(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if !mfa.Enabled {
+		return apperror.NewBadRequestError("two-factor authentication is not enabled")
+	}
+
+	user, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		return err
+	}
+	// a stolen session must not be enough to guess the code: the login account
+	// and the disable route both lock
+	account := loginAccountKey(user.Email)
+	disableAccount := mfaDisableAccountKey(userID)
+	for _, key := range []string{account, disableAccount} {
+		if err := s.guard.check(ctx, key, user.ID, user.Email, clientIP); err != nil {
+			return err
+		}
+	}
+
+	ok, err := s.checkCode(ctx, mfa, code)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		s.guard.failed(ctx, account, user.ID, user.Email, clientIP, "invalid_mfa_code")
+		s.recordDisableFailure(ctx, disableAccount, userID, clientIP)
+		return apperror.NewBadRequestError("invalid two-factor code")
+	}
+	if err := s.mfaRepo.Delete(ctx, userID); err != nil {
+		return err
+	}
+	// a failure to clear the counter only locks a later re-enrollment's disable
+	if err := s.guard.attempts.Reset(ctx, disableAccount); err != nil {
+		zerolog.Ctx(ctx).Warn().
+			Err(err).
+			Str("user_id", userID).
+			Msg("failed to reset mfa disable attempts")
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("user_id", userID).
+		Msg("mfa disabled")
+	return nil
+}
+
+// StartLogin park a password-checked login until the second factor arrives
+// =========================================================================
+func (s *mfaService) StartLogin(ctx context.Context, user *ports.UserResponse) (string, error) {
+	id, err := utils.GenerateRandomID(mfaChallengeIDBytes)
+	if err != nil {
+		return "", apperror.NewInternalError("Failed to start two-factor login", err)
+	}
+	challenge := &models.MFAChallenge{ID: id, UserID: user.ID, EmailVerified: user.EmailVerified}
+	if err := s.challengeStore.Save(ctx, challenge, s.challengeTTL); err != nil {
+		return "", err
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("user_id", user.ID).
+		Dur("ttl", s.challengeTTL).
+		Msg("mfa login challenge issued")
+	return id, nil
+}
+
+// CompleteLogin check the code for a pending login, the challenge works once
+// =========================================================================
+func (s *mfaService) CompleteLogin(ctx context.Context, challengeID string, code string, clientIP string) (*ports.UserResponse, error) {
+	expired := apperror.NewUnauthorizedError("two-factor login expired, log in again")
+
+	challenge, err := s.challengeStore.Get(ctx, challengeID)
+	if err != nil {
+		return nil, err
+	}
+	if challenge == nil {
+		return nil, expired
+	}
+
+	mfa, err := s.mfaRepo.GetMFA(ctx, challenge.UserID)
+	if err != nil {
+		return nil, err
+	}
+	// disabled since the password was checked, start over
+	if !mfa.Enabled {
+		_ = s.challengeStore.Delete(ctx, challengeID)
+		return nil, expired
+	}
+
+	user, err := s.userRepo.FindByID(ctx, challenge.UserID)
+	if err != nil {
+		return nil, err
+	}
+	// the same account key as the password step, a lock from either applies to both
+	account := loginAccountKey(user.Email)
+	if err := s.guard.check(ctx, account, user.ID, user.Email, clientIP); err != nil {
+		return nil, err
+	}
+
+	ok, err := s.checkCode(ctx, mfa, code)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		s.guard.failed(ctx, account, user.ID, user.Email, clientIP, "invalid_mfa_code")
+		failures, err := s.challengeStore.RecordFailure(ctx, challengeID)
+		if err != nil {
+			return nil, err
+		}
+		zerolog.Ctx(ctx).Warn().
+			Str("user_id", challenge.UserID).
+			Int64("failures", failures).
+			Msg("invalid mfa code")
+		if failures >= int64(s.maxAttempts) {
+			// guessing goes back through the password check
+			if err := s.challengeStore.Delete(ctx, challengeID); err != nil {
+				return nil, err
+			}
+			return nil, apperror.NewUnauthorizedError("too many invalid two-factor codes, log in again")
+		}
+		return nil, apperror.NewUnauthorizedError("invalid two-factor code")
+	}
+	if err := s.challengeStore.Delete(ctx, challengeID); err != nil {
+		return nil, err
+	}
+	s.guard.succeeded(ctx, account, user.ID, user.Email, clientIP)
+
+	zerolog.Ctx(ctx).Info().
+		Str("user_id", user.ID).
+		Msg("mfa login completed")
+	return &ports.UserResponse{
+		ID:            user.ID,
+		Name:          user.Name,
+		Email:         user.Email,
+		EmailVerified: user.EmailVerifiedAt != nil,
+		MFAEnabled:    true,
+	}, nil
+}
+
+// checkCode accept a TOTP code not used before or an unused recovery code
+func (s *mfaService) checkCode(ctx context.Context, mfa *models.UserMFA, code string) (bool, error) {
+	code = strings.TrimSpace(code)
+	if len(code) == utils.TOTPDigits {
+		secret, err := utils.Decrypt(s.encryptionKey, mfa.Secret)
+		if err != nil {
+			return false, apperror.NewInternalError("Failed to read two-factor secret", err)
+		}
+		step, ok := utils.ValidateTOTP(secret, code, s.now(), totpSkew)
+		if !ok {
+			return false, nil
+		}
+		// a code seen by someone else can't be replayed in the same window
+		return s.mfaRepo.UseStep(ctx, mfa.UserID, step)
+	}
+	return s.mfaRepo.UseRecoveryCode(ctx, mfa.UserID, hashRecoveryCode(code))
+}
+
+// recordDisableFailure count a wrong code against the disable route, it locks
+// after maxAttempts like a login challenge, without delays in between
+func (s *mfaService) recordDisableFailure(ctx context.Context, disableAccount string, userID string, clientIP string) {
+	protection := models.LoginProtection{
+		MaxFailures: s.maxAttempts,
+		Window:      s.guard.protection.Window,
+		Lockout:     s.guard.protection.Lockout,
+	}
+	failure, err := s.guard.attempts.RecordFailure(ctx, disableAccount, clientIP, protection)
+	if err != nil {
+		// the login account still counted the failure
+		return
+	}
+	zerolog.Ctx(ctx).Warn().
+		Str("user_id", userID).
+		Int64("failures", failure.Failures).
+		Bool("locked", failure.Locked).
+		Msg("invalid mfa code for disable")
+}
+
+// mfaDisableAccountKey attempt store key of the disable route, apart from the
+// hashed email keys of logins
+func mfaDisableAccountKey(userID string) string {
+	return "mfa_disable:" + userID
+}
+
+// hashRecoveryCode codes are random, a fast hash is enough; dashes, spaces
+// and case don't matter when typing them
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
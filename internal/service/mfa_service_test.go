@@ -0,0 +1,324 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+	"github.com/suryansh74/task-management-api-project/internal/utils"
+)
+
+// mockMFARepository enrollments in memory, users without one have 2FA off
+type mockMFARepository struct {
+	enrollments map[string]*models.UserMFA
+	codes       map[string]map[string]bool // user id -> code hash -> used
+}
+
+func (m *mockMFARepository) GetMFA(ctx context.Context, userID string) (*models.UserMFA, error) {
+	if mfa, ok := m.enrollments[userID]; ok {
+		copied := *mfa
+		return &copied, nil
+	}
+	return &models.UserMFA{UserID: userID}, nil
+}
+func (m *mockMFARepository) SavePendingSecret(ctx context.Context, userID string, secret string) error {
+	if mfa, ok := m.enrollments[userID]; ok && mfa.Enabled {
+		return apperror.NewConflictError("two-factor authentication is already enabled")
+	}
+	m.enrollments[userID] = &models.UserMFA{UserID: userID, Secret: secret}
+	return nil
+}
+func (m *mockMFARepository) Enable(ctx context.Context, userID string, step int64, recoveryCodeHashes []string) error {
+	mfa := m.enrollments[userID]
+	mfa.Enabled, mfa.LastUsedStep = true, step
+	m.codes[userID] = map[string]bool{}
+	for _, hash := range recoveryCodeHashes {
+		m.codes[userID][hash] = false
+	}
+	return nil
+}
+func (m *mockMFARepository) UseStep(ctx context.Context, userID string, step int64) (bool, error) {
+	mfa := m.enrollments[userID]
+	if step <= mfa.LastUsedStep {
+		return false, nil
+	}
+	mfa.LastUsedStep = step
+	return true, nil
+}
+func (m *mockMFARepository) UseRecoveryCode(ctx context.Context, userID string, codeHash string) (bool, error) {
+	used, ok := m.codes[userID][codeHash]
+	if !ok || used {
+		return false, nil
+	}
+	m.codes[userID][codeHash] = true
+	return true, nil
+}
+func (m *mockMFARepository) Delete(ctx context.Context, userID string) error {
+	delete(m.enrollments, userID)
+	delete(m.codes, userID)
+	return nil
+}
+
+// mockMFAChallengeStore challenges in memory, never expire
+type mockMFAChallengeStore struct {
+	challenges map[string]*models.MFAChallenge
+	failures   map[string]int64
+}
+
+func (m *mockMFAChallengeStore) Save(ctx context.Context, challenge *models.MFAChallenge, ttl time.Duration) error {
+	m.challenges[challenge.ID] = challenge
+	return nil
+}
+func (m *mockMFAChallengeStore) Get(ctx context.Context, id string) (*models.MFAChallenge, error) {
+	return m.challenges[id], nil
+}
+func (m *mockMFAChallengeStore) RecordFailure(ctx context.Context, id string) (int64, error) {
+	m.failures[id]++
+	return m.failures[id], nil
+}
+func (m *mockMFAChallengeStore) Delete(ctx context.Context, id string) error {
+	delete(m.challenges, id)
+	return nil
+}
+
+func newMFATest(t *testing.T) (*mfaService, *mockMFARepository, *mockMFAChallengeStore) {
+	t.Helper()
+	users := &mockUserRepository{
+		findByIDFn: func(ctx context.Context, id string) (*models.User, error) {
+			return &models.User{ID: id, Name: "Alice", Email: "alice@example.com"}, nil
+		},
+	}
+	repo := &mockMFARepository{enrollments: map[string]*models.UserMFA{}, codes: map[string]map[string]bool{}}
+	store := &mockMFAChallengeStore{challenges: map[string]*models.MFAChallenge{}, failures: map[string]int64{}}
+	svc := NewMFAService(users, repo, store, &mockLoginAttemptStore{}, &mockAuditLog{}, testLoginProtection, "key", "Task API", 5*time.Minute, 3).(*mfaService)
+	return svc, repo, store
+}
+
+// enableMFA enrolls user-1 and returns its secret and recovery codes
+func enableMFA(t *testing.T, svc *mfaService, at time.Time) (string, []string) {
+	t.Helper()
+	ctx := context.Background()
+	enrollment, err := svc.Enroll(ctx, "user-1")
+	if err != nil {
+		t.Fatalf("Enroll failed: %v", err)
+	}
+	code, _ := utils.TOTPCode(enrollment.Secret, utils.TOTPStep(at))
+	svc.now = func() time.Time { return at }
+	recovery, err := svc.Confirm(ctx, "user-1", code)
+	if err != nil {
+		t.Fatalf("Confirm failed: %v", err)
+	}
+	return enrollment.Secret, recovery
+}
+
+func isUnauthorized(err error) bool {
+	var appErr *apperror.AppError
+	return errors.As(err, &appErr) && appErr.Code == "UNAUTHORIZED"
+}
+
+func TestMFAService_Enroll(t *testing.T) {
+	svc, repo, _ := newMFATest(t)
+	ctx := context.Background()
+
+	enrollment, err := svc.Enroll(ctx, "user-1")
+	if err != nil {
+		t.Fatalf("Enroll failed: %v", err)
+	}
+	if enrollment.ProvisioningURI != utils.TOTPProvisioningURI("Task API", "alice@example.com", enrollment.Secret) {
+		t.Errorf("unexpected provisioning uri %s", enrollment.ProvisioningURI)
+	}
+	if stored := repo.enrollments["user-1"].Secret; stored == enrollment.Secret || stored == "" {
+		t.Error("expected the secret to be stored encrypted")
+	}
+
+	if _, err := svc.Confirm(ctx, "user-1", "000000"); !isBadRequest(err) {
+		t.Errorf("expected a wrong code to be rejected, got %v", err)
+	}
+	if repo.enrollments["user-1"].Enabled {
+		t.Fatal("2FA must stay off until confirmed")
+	}
+
+	_, recovery := enableMFA(t, svc, time.Now())
+	if len(recovery) != recoveryCodeCount || len(repo.codes["user-1"]) != recoveryCodeCount {
+		t.Fatalf("expected %d recovery codes, got %d", recoveryCodeCount, len(recovery))
+	}
+	if _, ok := repo.codes["user-1"][recovery[0]]; ok {
+		t.Error("recovery codes must be stored hashed")
+	}
+
+	if _, err := svc.Enroll(ctx, "user-1"); err == nil {
+		t.Error("expected enrolling again to fail while enabled")
+	}
+}
+
+func TestMFAService_CompleteLogin(t *testing.T) {
+	svc, _, store := newMFATest(t)
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	secret, recovery := enableMFA(t, svc, now)
+
+	challengeID, err := svc.StartLogin(ctx, &ports.UserResponse{ID: "user-1", EmailVerified: true})
+	if err != nil {
+		t.Fatalf("StartLogin failed: %v", err)
+	}
+
+	// the code used to confirm enrollment can't be replayed
+	confirmCode, _ := utils.TOTPCode(secret, utils.TOTPStep(now))
+	if _, err := svc.CompleteLogin(ctx, challengeID, confirmCode, "203.0.113.7"); !isUnauthorized(err) {
+		t.Fatalf("expected the replayed code to be rejected, got %v", err)
+	}
+
+	svc.now = func() time.Time { return now.Add(utils.TOTPPeriod) }
+	code, _ := utils.TOTPCode(secret, utils.TOTPStep(now)+1)
+	user, err := svc.CompleteLogin(ctx, challengeID, code, "203.0.113.7")
+	if err != nil {
+		t.Fatalf("CompleteLogin failed: %v", err)
+	}
+	if user.ID != "user-1" || !user.MFAEnabled {
+		t.Errorf("unexpected user %+v", user)
+	}
+	if _, err := svc.CompleteLogin(ctx, challengeID, code, "203.0.113.7"); !isUnauthorized(err) {
+		t.Error("expected a challenge to work once")
+	}
+
+	// recovery codes work once, whatever the formatting
+	challengeID, _ = svc.StartLogin(ctx, &ports.UserResponse{ID: "user-1"})
+	if _, err := svc.CompleteLogin(ctx, challengeID, " "+recovery[0]+" ", "203.0.113.7"); err != nil {
+		t.Fatalf("recovery code login failed: %v", err)
+	}
+	challengeID, _ = svc.StartLogin(ctx, &ports.UserResponse{ID: "user-1"})
+	if _, err := svc.CompleteLogin(ctx, challengeID, recovery[0], "203.0.113.7"); !isUnauthorized(err) {
+		t.Error("expected a used recovery code to be rejected")
+	}
+
+	// too many wrong codes drop the challenge
+	for range 2 {
+		if _, err := svc.CompleteLogin(ctx, challengeID, "000000", "203.0.113.7"); !isUnauthorized(err) {
+			t.Fatalf("expected a wrong code to be rejected, got %v", err)
+		}
+	}
+	if store.challenges[challengeID] != nil {
+		t.Error("expected the challenge to be dropped after max attempts")
+	}
+}
+
+func TestMFAService_Disable(t *testing.T) {
+	svc, repo, _ := newMFATest(t)
+	ctx := context.Background()
+	_, recovery := enableMFA(t, svc, time.Now())
+
+	if err := svc.Disable(ctx, "user-1", "wrong-code", "203.0.113.7"); !isBadRequest(err) {
+		t.Errorf("expected a wrong code to be rejected, got %v", err)
+	}
+	if err := svc.Disable(ctx, "user-1", recovery[1], "203.0.113.7"); err != nil {
+		t.Fatalf("Disable failed: %v", err)
+	}
+	if _, ok := repo.enrollments["user-1"]; ok {
+		t.Error("expected the enrollment to be deleted")
+	}
+	if err := svc.Disable(ctx, "user-1", recovery[2], "203.0.113.7"); !isBadRequest(err) {
+		t.Errorf("expected disabling twice to fail, got %v", err)
+	}
+}
+
+func TestMFAService_Disable_LocksAfterMaxAttempts(t *testing.T) {
+	svc, repo, _ := newMFATest(t)
+	attempts := svc.guard.attempts.(*mockLoginAttemptStore)
+	ctx := context.Background()
+	_, recovery := enableMFA(t, svc, time.Now())
+
+	for i := 0; i < svc.maxAttempts; i++ {
+		if err := svc.Disable(ctx, "user-1", "00000-00000", "203.0.113.7"); !isBadRequest(err) {
+			t.Fatalf("attempt %d: expected a wrong code to be rejected, got %v", i+1, err)
+		}
+	}
+	if !attempts.locked[mfaDisableAccountKey("user-1")] {
+		t.Fatal("expected wrong codes to lock disabling")
+	}
+	// testLoginProtection locks the login account after as many failures
+	if !attempts.locked[loginAccountKey("alice@example.com")] {
+		t.Error("expected wrong codes to count as failed logins")
+	}
+
+	// locked, even for the right code
+	delete(attempts.locked, loginAccountKey("alice@example.com"))
+	err := svc.Disable(ctx, "user-1", recovery[0], "203.0.113.7")
+	var appErr *apperror.AppError
+	if !errors.As(err, &appErr) || appErr.Code != "LOGIN_THROTTLED" {
+		t.Fatalf("expected LOGIN_THROTTLED, got %v", err)
+	}
+	if _, ok := repo.enrollments["user-1"]; !ok {
+		t.Error("expected 2FA to stay enabled")
+	}
+}
+
+func TestUserService_Login_MFARequired(t *testing.T) {
+	hashed, _ := utils.HashedPassword("password123")
+	users := &mockUserRepository{
+		findByEmailFn: func(ctx context.Context, email string) (*models.User, error) {
+			return &models.User{ID: "user-1", Email: email, Password: hashed}, nil
+		},
+	}
+	mfa := &mockMFARepository{enrollments: map[string]*models.UserMFA{
+		"user-1": {UserID: "user-1", Secret: "x", Enabled: true},
+	}}
+
+	attempts := &mockLoginAttemptStore{}
+	user, err := NewUserService(users, mfa, attempts, &mockAuditLog{}, testLoginProtection).
+		Login(context.Background(), "alice@example.com", "password123", "203.0.113.7")
+	if err != nil {
+		t.Fatalf("Login failed: %v", err)
+	}
+	if !user.MFAEnabled {
+		t.Error("expected login to ask for a second factor")
+	}
+	// failures are only cleared once the second factor is right too
+	if len(attempts.resets) != 0 {
+		t.Errorf("expected no reset before the second factor, got %v", attempts.resets)
+	}
+}
+
+func TestMFAService_CompleteLogin_CountsAsLoginFailure(t *testing.T) {
+	svc, _, _ := newMFATest(t)
+	attempts := svc.guard.attempts.(*mockLoginAttemptStore)
+	audit := svc.guard.auditLog.(*mockAuditLog)
+	ctx := context.Background()
+	now := time.Unix(1700000000, 0)
+	secret, _ := enableMFA(t, svc, now)
+	svc.now = func() time.Time { return now.Add(utils.TOTPPeriod) }
+	code, _ := utils.TOTPCode(secret, utils.TOTPStep(now)+1)
+
+	// a fresh challenge per wrong code, as a loop through /login would get
+	for i := 0; i < testLoginProtection.MaxFailures; i++ {
+		challengeID, _ := svc.StartLogin(ctx, &ports.UserResponse{ID: "user-1"})
+		if _, err := svc.CompleteLogin(ctx, challengeID, "000000", "203.0.113.7"); !isUnauthorized(err) {
+			t.Fatalf("attempt %d: expected unauthorized, got %v", i+1, err)
+		}
+	}
+	if !attempts.locked[loginAccountKey("alice@example.com")] {
+		t.Fatal("expected wrong codes to lock the account")
+	}
+	if got := audit.types(); got[len(got)-1] != models.AuditAccountLocked {
+		t.Errorf("expected account_locked, got %v", got)
+	}
+
+	// locked, even for the right code
+	challengeID, _ := svc.StartLogin(ctx, &ports.UserResponse{ID: "user-1"})
+	_, err := svc.CompleteLogin(ctx, challengeID, code, "203.0.113.7")
+	var appErr *apperror.AppError
+	if !errors.As(err, &appErr) || appErr.Code != "LOGIN_THROTTLED" {
+		t.Fatalf("expected LOGIN_THROTTLED, got %v", err)
+	}
+
+	delete(attempts.locked, loginAccountKey("alice@example.com"))
+	if _, err := svc.CompleteLogin(ctx, challengeID, code, "203.0.113.7"); err != nil {
+		t.Fatalf("CompleteLogin failed: %v", err)
+	}
+	if len(attempts.resets) != 1 {
+		t.Errorf("expected the account to be reset after the second factor, got %v", attempts.resets)
+	}
+}
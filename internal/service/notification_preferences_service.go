@@ -0,0 +1,49 @@
+package service
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/logger"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+)
+
+type notificationPreferencesService struct {
+	prefsRepo ports.NotificationPreferencesRepository
+}
+
+// NewNotificationPreferencesService creates a new notification preferences service instance
+// =========================================================================
+func NewNotificationPreferencesService(prefsRepo ports.NotificationPreferencesRepository) ports.NotificationPreferencesService {
+	logger.Log.Info().Msg("initializing notification preferences service")
+	return &notificationPreferencesService{prefsRepo: prefsRepo}
+}
+
+// GetPreferences get preferences of user
+// =========================================================================
+func (s *notificationPreferencesService) GetPreferences(ctx context.Context, userID string) (*models.NotificationPreferences, error) {
+	if userID == "" {
+		return nil, apperror.NewUnauthorizedError("not authenticated")
+	}
+	return s.prefsRepo.GetPreferences(ctx, userID)
+}
+
+// UpdatePreferences replace preferences of user
+// =========================================================================
+func (s *notificationPreferencesService) UpdatePreferences(ctx context.Context, prefs *models.NotificationPreferences) (*models.NotificationPreferences, error) {
+	if prefs.UserID == "" {
+		return nil, apperror.NewUnauthorizedError("not authenticated")
+	}
+
+	if err := s.prefsRepo.UpsertPreferences(ctx, prefs); err != nil {
+		return nil, err
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("user_id", prefs.UserID).
+		Bool("email_enabled", prefs.EmailEnabled).
+		Msg("notification preferences updated")
+	return prefs, nil
+}
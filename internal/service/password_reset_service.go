@@ -0,0 +1,184 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/logger"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+	"github.com/suryansh74/task-management-api-project/internal/utils"
+)
+
+// resetTokenBytes 32 random bytes, 64 hex chars in the link
+const resetTokenBytes = 32
+
+// resetIssueTimeout bounds the background lookup and email of one request
+const resetIssueTimeout = 30 * time.Second
+
+type passwordResetService struct {
+	userRepo       ports.UserRepository
+	resetStore     ports.PasswordResetStore
+	sessionService ports.SessionService
+	emailService   ports.EmailService
+	resetURL       string
+	tokenTTL       time.Duration
+	issuing        sync.WaitGroup
+}
+
+// NewPasswordResetService creates a new password reset service instance,
+// emailed links are resetURL with the token appended as ?token=
+// =========================================================================
+func NewPasswordResetService(
+	userRepo ports.UserRepository,
+	resetStore ports.PasswordResetStore,
+	sessionService ports.SessionService,
+	emailService ports.EmailService,
+	resetURL string,
+	tokenTTL time.Duration,
+) ports.PasswordResetService {
+	logger.Log.Info().
+		Str("reset_url", resetURL).
+		Dur("token_ttl", tokenTTL).
+		Msg("initializing password reset service")
+	return &passwordResetService{
+		userRepo:       userRepo,
+		resetStore:     resetStore,
+		sessionService: sessionService,
+		emailService:   emailService,
+		resetURL:       resetURL,
+		tokenTTL:       tokenTTL,
+	}
+}
+
+// ForgotPassword issue a single-use token and email it to the account
+// =========================================================================
+func (s *passwordResetService) ForgotPassword(ctx context.Context, email string) error {
+	zerolog.Ctx(ctx).Debug().
+		Str("email", email).
+		Msg("password reset requested")
+
+	// the lookup and the email happen after the response, so neither the extra
+	// work for a known address nor its failures show whether the account exists
+	issueCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), resetIssueTimeout)
+	s.issuing.Go(func() {
+		defer cancel()
+		if err := s.issueResetToken(issueCtx, email); err != nil {
+			zerolog.Ctx(issueCtx).Error().
+				Err(err).
+				Msg("failed to issue password reset token")
+		}
+	})
+	return nil
+}
+
+// issueResetToken store a token for the account of email and queue the link
+func (s *passwordResetService) issueResetToken(ctx context.Context, email string) error {
+	user, err := s.userRepo.FindByEmail(ctx, email)
+	if err != nil {
+		var appErr *apperror.AppError
+		if errors.As(err, &appErr) && appErr.Code == "NOT_FOUND" {
+			// answered like a known address, callers can't probe for accounts
+			zerolog.Ctx(ctx).Info().
+				Str("email", email).
+				Msg("password reset requested for unknown email")
+			return nil
+		}
+		return err
+	}
+
+	token, err := utils.GenerateRandomID(resetTokenBytes)
+	if err != nil {
+		return apperror.NewInternalError("Failed to generate reset token", err)
+	}
+	// only the hash is stored, a Redis dump can't be used to take over accounts
+	if err := s.resetStore.Save(ctx, user.ID, hashResetToken(token), s.tokenTTL); err != nil {
+		return err
+	}
+
+	sep := "?"
+	if strings.Contains(s.resetURL, "?") {
+		sep = "&"
+	}
+	err = s.emailService.Enqueue(ctx, &models.EmailMessage{
+		Template: "password_reset",
+		To:       user.Email,
+		UserID:   user.ID,
+		Category: models.EmailCategoryAccount,
+		Data: map[string]any{
+			"Name":      user.Name,
+			"URL":       s.resetURL + sep + "token=" + token,
+			"ExpiresIn": s.tokenTTL.String(),
+		},
+	})
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("user_id", user.ID).
+			Msg("failed to queue password reset email")
+		return err
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("user_id", user.ID).
+		Dur("token_ttl", s.tokenTTL).
+		Msg("password reset email queued")
+	return nil
+}
+
+// ResetPassword use the token once, set the password and revoke all sessions
+// =========================================================================
+func (s *passwordResetService) ResetPassword(ctx context.Context, token string, password string) error {
+	invalid := apperror.NewBadRequestError("invalid or expired reset token")
+
+	// malformed tokens can't exist, skip the lookup
+	if _, err := hex.DecodeString(token); err != nil || len(token) != 2*resetTokenBytes {
+		return invalid
+	}
+
+	userID, err := s.resetStore.Consume(ctx, hashResetToken(token))
+	if err != nil {
+		return err
+	}
+	if userID == "" {
+		zerolog.Ctx(ctx).Warn().
+			Msg("password reset with unknown or expired token")
+		return invalid
+	}
+
+	hashedPassword, err := utils.HashedPassword(password)
+	if err != nil {
+		return apperror.NewInternalError("Failed to process password", err)
+	}
+	if err := s.userRepo.UpdatePassword(ctx, userID, hashedPassword); err != nil {
+		return err
+	}
+
+	// whoever knew the old password must not stay logged in
+	if err := s.sessionService.RevokeUserSessions(ctx, userID); err != nil {
+		return err
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("user_id", userID).
+		Msg("password reset successfully")
+	return nil
+}
+
+// Wait block until background token issues are done
+// =========================================================================
+func (s *passwordResetService) Wait() {
+	s.issuing.Wait()
+}
+
+func hashResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,178 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+	"github.com/suryansh74/task-management-api-project/internal/utils"
+)
+
+// mockPasswordResetStore single-use tokens, one per user
+type mockPasswordResetStore struct {
+	tokens map[string]string // token hash -> user id
+}
+
+func (m *mockPasswordResetStore) Save(ctx context.Context, userID string, tokenHash string, ttl time.Duration) error {
+	for hash, owner := range m.tokens {
+		if owner == userID {
+			delete(m.tokens, hash)
+		}
+	}
+	m.tokens[tokenHash] = userID
+	return nil
+}
+func (m *mockPasswordResetStore) Consume(ctx context.Context, tokenHash string) (string, error) {
+	userID := m.tokens[tokenHash]
+	delete(m.tokens, tokenHash)
+	return userID, nil
+}
+
+type mockSessionService struct {
+	ports.SessionService
+	revoked  []string
+	verified []string
+}
+
+func (m *mockSessionService) RevokeUserSessions(ctx context.Context, userID string) error {
+	m.revoked = append(m.revoked, userID)
+	return nil
+}
+
+func (m *mockSessionService) MarkEmailVerified(ctx context.Context, userID string) error {
+	m.verified = append(m.verified, userID)
+	return nil
+}
+
+var resetLinkPattern = regexp.MustCompile(`https://app\.example\.com/reset\?token=[0-9a-f]+`)
+
+func newPasswordResetTest(users *mockUserRepository) (ports.PasswordResetService, *mockMailQueue, *mockSessionService) {
+	queue := &mockMailQueue{}
+	sessions := &mockSessionService{}
+	svc := NewPasswordResetService(
+		users,
+		&mockPasswordResetStore{tokens: map[string]string{}},
+		sessions,
+		NewEmailService(&mockMailer{}, queue, &mockPreferencesRepository{}, 3, time.Second),
+		"https://app.example.com/reset",
+		30*time.Minute,
+	)
+	return svc, queue, sessions
+}
+
+// forgotPassword request a reset and wait for the background issue
+func forgotPassword(t *testing.T, svc ports.PasswordResetService, email string) {
+	t.Helper()
+	if err := svc.ForgotPassword(context.Background(), email); err != nil {
+		t.Fatalf("ForgotPassword failed: %v", err)
+	}
+	svc.Wait()
+}
+
+// emailedToken token from the reset link of the i-th queued email
+func emailedToken(t *testing.T, queue *mockMailQueue, i int) string {
+	t.Helper()
+	link := resetLinkPattern.FindString(queue.queued[i].job.Email.Text)
+	u, err := url.Parse(link)
+	if err != nil || link == "" {
+		t.Fatalf("no reset link in %q", queue.queued[i].job.Email.Text)
+	}
+	return u.Query().Get("token")
+}
+
+func TestPasswordResetService_ResetPassword(t *testing.T) {
+	var stored string
+	users := &mockUserRepository{
+		findByEmailFn: func(ctx context.Context, email string) (*models.User, error) {
+			return &models.User{ID: "user-1", Name: "Alice", Email: email}, nil
+		},
+		updatePasswordFn: func(ctx context.Context, userID string, hashedPassword string) error {
+			if userID != "user-1" {
+				t.Errorf("expected password of user-1 to change, got %s", userID)
+			}
+			stored = hashedPassword
+			return nil
+		},
+	}
+	svc, queue, sessions := newPasswordResetTest(users)
+
+	// a second request replaces the first token
+	for range 2 {
+		forgotPassword(t, svc, "alice@example.com")
+	}
+	if len(queue.queued) != 2 {
+		t.Fatalf("expected two reset emails, got %d", len(queue.queued))
+	}
+	first, second := emailedToken(t, queue, 0), emailedToken(t, queue, 1)
+
+	if err := svc.ResetPassword(context.Background(), first, "new-password"); !isBadRequest(err) {
+		t.Errorf("replaced token: expected BAD_REQUEST, got %v", err)
+	}
+	if err := svc.ResetPassword(context.Background(), second, "new-password"); err != nil {
+		t.Fatalf("ResetPassword failed: %v", err)
+	}
+	if utils.CheckPassword("new-password", stored) != nil {
+		t.Error("expected the new password to be stored hashed")
+	}
+	if len(sessions.revoked) != 1 || sessions.revoked[0] != "user-1" {
+		t.Errorf("expected sessions of user-1 to be revoked, got %v", sessions.revoked)
+	}
+
+	// single use
+	if err := svc.ResetPassword(context.Background(), second, "another-password"); !isBadRequest(err) {
+		t.Errorf("reused token: expected BAD_REQUEST, got %v", err)
+	}
+}
+
+func TestPasswordResetService_ForgotPassword_UnknownEmail(t *testing.T) {
+	users := &mockUserRepository{
+		findByEmailFn: func(ctx context.Context, email string) (*models.User, error) {
+			return nil, apperror.NewNotFoundError("User not found")
+		},
+	}
+	svc, queue, _ := newPasswordResetTest(users)
+
+	forgotPassword(t, svc, "nobody@example.com")
+	if len(queue.queued) != 0 {
+		t.Error("expected no email for an unknown address")
+	}
+}
+
+func TestPasswordResetService_ForgotPassword_HidesFailures(t *testing.T) {
+	users := &mockUserRepository{
+		findByEmailFn: func(ctx context.Context, email string) (*models.User, error) {
+			return nil, apperror.NewInternalError("Failed to fetch user", errors.New("connection refused"))
+		},
+	}
+	svc, queue, _ := newPasswordResetTest(users)
+
+	// answered like any other address, the failure is only logged
+	forgotPassword(t, svc, "alice@example.com")
+	if len(queue.queued) != 0 {
+		t.Error("expected no email when the lookup fails")
+	}
+}
+
+func TestPasswordResetService_ResetPassword_InvalidToken(t *testing.T) {
+	svc, _, sessions := newPasswordResetTest(&mockUserRepository{})
+
+	for _, token := range []string{"", "not-hex", "abcd", "0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"} {
+		if err := svc.ResetPassword(context.Background(), token, "new-password"); !isBadRequest(err) {
+			t.Errorf("token %q: expected BAD_REQUEST, got %v", token, err)
+		}
+	}
+	if len(sessions.revoked) != 0 {
+		t.Error("expected no sessions to be revoked")
+	}
+}
+
+func isBadRequest(err error) bool {
+	var appErr *apperror.AppError
+	return errors.As(err, &appErr) && appErr.Code == "BAD_REQUEST"
+}
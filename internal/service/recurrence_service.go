@@ -0,0 +1,400 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/teambition/rrule-go"
+
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/logger"
+	"github.com/suryansh74/task-management-api-project/internal/metrics"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+)
+
+// recurrenceService decorates a TaskService, every plain task operation
+// goes through the wrapped service so caching and ownership stay in one place
+type recurrenceService struct {
+	ports.TaskService
+	seriesRepo    ports.TaskSeriesRepository
+	taskCacheRepo ports.TaskCacheRepository
+	now           func() time.Time
+}
+
+// NewRecurrenceService wraps taskService with recurring task support
+// =========================================================================
+func NewRecurrenceService(taskService ports.TaskService, seriesRepo ports.TaskSeriesRepository, taskCacheRepo ports.TaskCacheRepository) ports.RecurrenceService {
+	logger.Log.Info().Msg("initializing recurrence service")
+	return &recurrenceService{
+		TaskService:   taskService,
+		seriesRepo:    seriesRepo,
+		taskCacheRepo: taskCacheRepo,
+		now:           time.Now,
+	}
+}
+
+// CreateRecurringTask creates the series and its first occurrence
+// =========================================================================
+func (s *recurrenceService) CreateRecurringTask(ctx context.Context, task *models.Task, recurrence *models.Recurrence) (string, error) {
+	zerolog.Ctx(ctx).Debug().
+		Str("user_id", task.UserID).
+		Str("rrule", recurrence.RRule).
+		Str("timezone", recurrence.Timezone).
+		Msg("creating recurring task")
+
+	if task.DueAt == nil {
+		return "", apperror.NewBadRequestError("due_at is required for recurring tasks")
+	}
+
+	series := &models.TaskSeries{
+		UserID:  task.UserID,
+		Title:   task.Title,
+		Content: task.Content,
+	}
+	if err := applyRecurrence(series, recurrence, *task.DueAt); err != nil {
+		return "", err
+	}
+
+	// DTSTART itself only counts when it matches the rule (RFC 5545)
+	first, err := nextOccurrence(series, series.DTStart, true)
+	if err != nil {
+		return "", err
+	}
+	if first.IsZero() {
+		return "", apperror.NewBadRequestError("rrule produces no occurrences")
+	}
+
+	seriesID, err := s.seriesRepo.CreateSeries(ctx, series)
+	if err != nil {
+		return "", err
+	}
+
+	task.Status = models.TaskStatusTodo
+	task.DueAt = &first
+	task.SeriesID = &seriesID
+
+	id, err := s.TaskService.CreateTask(ctx, task)
+	if err != nil {
+		return "", err
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("task_id", id).
+		Str("series_id", seriesID).
+		Str("user_id", task.UserID).
+		Time("due_at", first).
+		Msg("recurring task created successfully")
+	return id, nil
+}
+
+// UpdateTaskByID edits only this occurrence, completing it may spawn the next one
+// =========================================================================
+func (s *recurrenceService) UpdateTaskByID(ctx context.Context, taskID string, userID string, task *models.Task) error {
+	current, err := s.TaskService.GetTaskByID(ctx, taskID, userID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.TaskService.UpdateTaskByID(ctx, taskID, userID, task); err != nil {
+		return err
+	}
+
+	if task.Status == models.TaskStatusDone {
+		s.OccurrenceCompleted(ctx, current)
+	}
+	return nil
+}
+
+// OccurrenceCompleted spawns the next occurrence of an on_complete series,
+// current is the task as it was before being marked done
+// =========================================================================
+func (s *recurrenceService) OccurrenceCompleted(ctx context.Context, current *models.Task) {
+	if current.SeriesID == nil || current.Status == models.TaskStatusDone {
+		return
+	}
+
+	series, err := s.seriesOf(ctx, current)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("task_id", current.ID).
+			Str("series_id", *current.SeriesID).
+			Msg("failed to load series for completed occurrence")
+		return
+	}
+	if series.Mode != models.SeriesModeOnComplete {
+		return
+	}
+
+	// the update itself succeeded, a failed generation must not turn it into an error
+	if _, err := s.generateNext(ctx, series, dueOrZero(current.DueAt)); err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("task_id", current.ID).
+			Str("series_id", series.ID).
+			Msg("failed to generate next occurrence")
+	}
+}
+
+// UpdateFutureOccurrences edits this and all following occurrences
+// =========================================================================
+func (s *recurrenceService) UpdateFutureOccurrences(ctx context.Context, taskID string, userID string, task *models.Task, recurrence *models.Recurrence) error {
+	zerolog.Ctx(ctx).Debug().
+		Str("task_id", taskID).
+		Str("user_id", userID).
+		Msg("updating all future occurrences")
+
+	current, err := s.TaskService.GetTaskByID(ctx, taskID, userID)
+	if err != nil {
+		return err
+	}
+	if current.SeriesID == nil {
+		return apperror.NewBadRequestError("task is not part of a recurring series")
+	}
+
+	series, err := s.seriesOf(ctx, current)
+	if err != nil {
+		return err
+	}
+
+	// the series is re-anchored at this occurrence, earlier ones are history
+	anchor := dueOrZero(current.DueAt)
+	if task.DueAt != nil {
+		anchor = *task.DueAt
+	}
+	if anchor.IsZero() {
+		anchor = s.now()
+	}
+
+	series.Title = task.Title
+	series.Content = task.Content
+	if recurrence == nil {
+		recurrence = &models.Recurrence{RRule: series.RRule, Timezone: series.Timezone, Mode: series.Mode}
+	}
+	if err := applyRecurrence(series, recurrence, anchor); err != nil {
+		return err
+	}
+	first, err := nextOccurrence(series, series.DTStart, true)
+	if err != nil {
+		return err
+	}
+	if first.IsZero() {
+		return apperror.NewBadRequestError("rrule produces no occurrences")
+	}
+
+	if err := s.seriesRepo.UpdateSeries(ctx, series.ID, series); err != nil {
+		return err
+	}
+
+	// later open occurrences take the new template and are re-timed in order
+	var later []*models.Task
+	if current.DueAt != nil {
+		later, err = s.seriesRepo.GetOpenOccurrences(ctx, series.ID, *current.DueAt)
+		if err != nil {
+			return err
+		}
+	}
+
+	task.DueAt = &first
+	if err := s.UpdateTaskByID(ctx, taskID, userID, task); err != nil {
+		return err
+	}
+
+	previous := first
+	for _, occurrence := range later {
+		due, err := nextOccurrence(series, previous, false)
+		if err != nil || due.IsZero() {
+			break
+		}
+		err = s.TaskService.UpdateTaskByID(ctx, occurrence.ID, userID, &models.Task{
+			Title:   series.Title,
+			Content: series.Content,
+			DueAt:   &due,
+		})
+		if err != nil {
+			zerolog.Ctx(ctx).Error().
+				Err(err).
+				Str("task_id", occurrence.ID).
+				Str("series_id", series.ID).
+				Msg("failed to update later occurrence")
+			continue
+		}
+		previous = due
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("task_id", taskID).
+		Str("series_id", series.ID).
+		Int("later_occurrences", len(later)).
+		Msg("future occurrences updated successfully")
+	return nil
+}
+
+// GenerateScheduledOccurrences creates next occurrences for due scheduled series
+// =========================================================================
+func (s *recurrenceService) GenerateScheduledOccurrences(ctx context.Context, now time.Time) (int, error) {
+	due, err := s.seriesRepo.GetDueScheduledSeries(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	for _, d := range due {
+		ok, err := s.generateNext(ctx, d.Series, d.LatestDueAt)
+		if err != nil {
+			zerolog.Ctx(ctx).Error().
+				Err(err).
+				Str("series_id", d.Series.ID).
+				Msg("failed to generate scheduled occurrence")
+			continue
+		}
+		if ok {
+			created++
+		}
+	}
+
+	if created > 0 {
+		zerolog.Ctx(ctx).Info().
+			Int("created", created).
+			Msg("scheduled occurrences generated")
+	}
+	return created, nil
+}
+
+// generateNext creates the occurrence after max(after, now), skipping missed ones
+func (s *recurrenceService) generateNext(ctx context.Context, series *models.TaskSeries, after time.Time) (bool, error) {
+	if now := s.now(); now.After(after) {
+		after = now
+	}
+
+	next, err := nextOccurrence(series, after, false)
+	if err != nil {
+		return false, err
+	}
+	if next.IsZero() {
+		zerolog.Ctx(ctx).Info().
+			Str("series_id", series.ID).
+			Msg("series has no further occurrences")
+		return false, nil
+	}
+
+	seriesID := series.ID
+	_, created, err := s.seriesRepo.CreateOccurrence(ctx, &models.Task{
+		UserID:   series.UserID,
+		Title:    series.Title,
+		Content:  series.Content,
+		DueAt:    &next,
+		SeriesID: &seriesID,
+	})
+	if created {
+		metrics.TasksCreated.WithLabelValues("recurrence").Inc()
+		// occurrences are inserted directly, not through the wrapped service
+		invalidateTaskLists(ctx, s.taskCacheRepo, series.UserID)
+	}
+	return created, err
+}
+
+// StartRecurrenceScheduler runs GenerateScheduledOccurrences every interval until ctx is done.
+// Safe on every replica: the (series_id, due_at) unique index drops duplicates.
+// =========================================================================
+func StartRecurrenceScheduler(ctx context.Context, recurrenceService ports.RecurrenceService, interval time.Duration) {
+	zerolog.Ctx(ctx).Info().
+		Dur("interval", interval).
+		Msg("recurrence scheduler started")
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			zerolog.Ctx(ctx).Info().Msg("recurrence scheduler stopped")
+			return
+		case now := <-ticker.C:
+			if _, err := recurrenceService.GenerateScheduledOccurrences(ctx, now); err != nil {
+				zerolog.Ctx(ctx).Error().
+					Err(err).
+					Msg("recurrence scheduler run failed")
+			}
+		}
+	}
+}
+
+// seriesOf load the series of an occurrence, a series of another user is
+// treated as missing
+func (s *recurrenceService) seriesOf(ctx context.Context, task *models.Task) (*models.TaskSeries, error) {
+	series, err := s.seriesRepo.GetSeriesByID(ctx, *task.SeriesID)
+	if err != nil {
+		return nil, err
+	}
+	if series.UserID != task.UserID {
+		zerolog.Ctx(ctx).Warn().
+			Str("task_id", task.ID).
+			Str("series_id", series.ID).
+			Msg("task points at a series of another user")
+		return nil, apperror.NewNotFoundError("task series not found")
+	}
+	return series, nil
+}
+
+// applyRecurrence validates recurrence and copies it onto series anchored at dtstart
+func applyRecurrence(series *models.TaskSeries, recurrence *models.Recurrence, dtstart time.Time) error {
+	rule := strings.TrimSpace(recurrence.RRule)
+	rule = strings.TrimPrefix(rule, "RRULE:")
+	if strings.ContainsAny(rule, "\r\n") {
+		// DTSTART always comes from the task's due date
+		return apperror.NewBadRequestError("rrule must be a single RRULE line")
+	}
+
+	timezone := recurrence.Timezone
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return apperror.NewBadRequestError(fmt.Sprintf("unknown timezone %q", timezone))
+	}
+
+	mode := recurrence.Mode
+	if mode == "" {
+		mode = models.SeriesModeOnComplete
+	}
+
+	series.RRule = rule
+	series.Timezone = timezone
+	series.Mode = mode
+	series.DTStart = dtstart.In(loc)
+	return nil
+}
+
+// nextOccurrence evaluates the rule in the series' timezone so wall-clock
+// times survive DST changes (09:00 stays 09:00 local)
+func nextOccurrence(series *models.TaskSeries, after time.Time, inclusive bool) (time.Time, error) {
+	loc, err := time.LoadLocation(series.Timezone)
+	if err != nil {
+		return time.Time{}, apperror.NewBadRequestError(fmt.Sprintf("unknown timezone %q", series.Timezone))
+	}
+
+	option, err := rrule.StrToROptionInLocation(series.RRule, loc)
+	if err != nil {
+		return time.Time{}, apperror.NewBadRequestError(fmt.Sprintf("invalid rrule: %v", err))
+	}
+	option.Dtstart = series.DTStart.In(loc)
+
+	rule, err := rrule.NewRRule(*option)
+	if err != nil {
+		return time.Time{}, apperror.NewBadRequestError(fmt.Sprintf("invalid rrule: %v", err))
+	}
+	return rule.After(after.In(loc), inclusive), nil
+}
+
+func dueOrZero(due *time.Time) time.Time {
+	if due == nil {
+		return time.Time{}
+	}
+	return *due
+}
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+)
+
+type mockTaskSeriesRepository struct {
+	series      map[string]*models.TaskSeries
+	occurrences []*models.Task
+	due         []*ports.DueSeries
+}
+
+func newMockTaskSeriesRepository() *mockTaskSeriesRepository {
+	return &mockTaskSeriesRepository{series: map[string]*models.TaskSeries{}}
+}
+
+func (m *mockTaskSeriesRepository) CreateSeries(ctx context.Context, series *models.TaskSeries) (string, error) {
+	series.ID = "series-1"
+	m.series[series.ID] = series
+	return series.ID, nil
+}
+func (m *mockTaskSeriesRepository) GetSeriesByID(ctx context.Context, id string) (*models.TaskSeries, error) {
+	if s, ok := m.series[id]; ok {
+		return s, nil
+	}
+	return nil, apperror.NewNotFoundError("task series not found")
+}
+func (m *mockTaskSeriesRepository) UpdateSeries(ctx context.Context, id string, series *models.TaskSeries) error {
+	m.series[id] = series
+	return nil
+}
+func (m *mockTaskSeriesRepository) CreateOccurrence(ctx context.Context, task *models.Task) (string, bool, error) {
+	m.occurrences = append(m.occurrences, task)
+	return "occurrence-1", true, nil
+}
+func (m *mockTaskSeriesRepository) GetOpenOccurrences(ctx context.Context, seriesID string, after time.Time) ([]*models.Task, error) {
+	return nil, nil
+}
+func (m *mockTaskSeriesRepository) GetDueScheduledSeries(ctx context.Context, now time.Time) ([]*ports.DueSeries, error) {
+	return m.due, nil
+}
+
+func TestNextOccurrence_KeepsWallClockAcrossDST(t *testing.T) {
+	loc, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("tzdata not available: %v", err)
+	}
+	// US DST starts 2026-03-08, the Monday after must still be 09:00 local
+	series := &models.TaskSeries{
+		RRule:    "FREQ=WEEKLY;BYDAY=MO",
+		Timezone: "America/New_York",
+		DTStart:  time.Date(2026, 3, 2, 9, 0, 0, 0, loc),
+	}
+
+	next, err := nextOccurrence(series, series.DTStart, false)
+	if err != nil {
+		t.Fatalf("nextOccurrence failed: %v", err)
+	}
+	local := next.In(loc)
+	if local.Day() != 9 || local.Hour() != 9 {
+		t.Errorf("expected 2026-03-09 09:00 local, got %v", local)
+	}
+	if next.UTC().Hour() != 13 {
+		t.Errorf("expected 13:00 UTC after DST change, got %v", next.UTC())
+	}
+}
+
+func TestRecurrenceService_CreateRecurringTask_InvalidRule(t *testing.T) {
+	svc := NewRecurrenceService(NewTaskService(&mockTaskRepository{}, &mockTaskCacheRepository{}, "app", models.TaskCacheOptions{Expiration: time.Minute}), newMockTaskSeriesRepository(), &mockTaskCacheRepository{})
+	due := time.Now().Add(time.Hour)
+
+	_, err := svc.CreateRecurringTask(context.Background(), &models.Task{UserID: "user-1", Title: "Ops", DueAt: &due}, &models.Recurrence{RRule: "FREQ=SOMETIMES"})
+	var appErr *apperror.AppError
+	if !errors.As(err, &appErr) || appErr.Code != "BAD_REQUEST" {
+		t.Errorf("expected BAD_REQUEST, got %v", err)
+	}
+}
+
+func TestRecurrenceService_CreateRecurringTask(t *testing.T) {
+	var created *models.Task
+	repo := &mockTaskRepository{
+		createFn: func(ctx context.Context, task *models.Task) (string, error) {
+			created = task
+			return "task-1", nil
+		},
+	}
+	seriesRepo := newMockTaskSeriesRepository()
+	svc := NewRecurrenceService(NewTaskService(repo, &mockTaskCacheRepository{}, "app", models.TaskCacheOptions{Expiration: time.Minute}), seriesRepo, &mockTaskCacheRepository{})
+
+	// a Wednesday start with a Monday rule moves the first occurrence forward
+	due := time.Date(2026, 1, 7, 9, 0, 0, 0, time.UTC)
+	id, err := svc.CreateRecurringTask(context.Background(), &models.Task{UserID: "user-1", Title: "Ops", DueAt: &due}, &models.Recurrence{RRule: "RRULE:FREQ=WEEKLY;BYDAY=MO"})
+	if err != nil {
+		t.Fatalf("CreateRecurringTask failed: %v", err)
+	}
+	if id != "task-1" || created.SeriesID == nil || *created.SeriesID != "series-1" {
+		t.Fatalf("unexpected created task: %+v", created)
+	}
+	if want := time.Date(2026, 1, 12, 9, 0, 0, 0, time.UTC); !created.DueAt.Equal(want) {
+		t.Errorf("expected first occurrence %v, got %v", want, created.DueAt)
+	}
+	if seriesRepo.series["series-1"].Mode != models.SeriesModeOnComplete {
+		t.Error("expected default mode on_complete")
+	}
+}
+
+func TestRecurrenceService_CompletingOccurrenceCreatesNext(t *testing.T) {
+	seriesID := "series-1"
+	due := time.Now().Add(time.Hour).Truncate(time.Second).UTC()
+	repo := &mockTaskRepository{
+		getByIDFn: func(ctx context.Context, id string) (*models.Task, error) {
+			return &models.Task{ID: id, UserID: "user-1", Status: models.TaskStatusTodo, DueAt: &due, SeriesID: &seriesID}, nil
+		},
+		updateFn: func(ctx context.Context, id string, task *models.Task) error {
+			return nil
+		},
+	}
+	seriesRepo := newMockTaskSeriesRepository()
+	seriesRepo.series[seriesID] = &models.TaskSeries{
+		ID:       seriesID,
+		UserID:   "user-1",
+		Title:    "Daily check",
+		RRule:    "FREQ=DAILY",
+		Timezone: "UTC",
+		DTStart:  due,
+		Mode:     models.SeriesModeOnComplete,
+	}
+	svc := NewRecurrenceService(NewTaskService(repo, &mockTaskCacheRepository{}, "app", models.TaskCacheOptions{Expiration: time.Minute}), seriesRepo, &mockTaskCacheRepository{})
+
+	err := svc.UpdateTaskByID(context.Background(), "t1", "user-1", &models.Task{Title: "Daily check", Status: models.TaskStatusDone})
+	if err != nil {
+		t.Fatalf("UpdateTaskByID failed: %v", err)
+	}
+	if len(seriesRepo.occurrences) != 1 {
+		t.Fatalf("expected one new occurrence, got %d", len(seriesRepo.occurrences))
+	}
+	if next := seriesRepo.occurrences[0].DueAt; !next.Equal(due.Add(24 * time.Hour)) {
+		t.Errorf("expected next due %v, got %v", due.Add(24*time.Hour), next)
+	}
+}
+
+func TestRecurrenceService_CompletingOccurrence_IgnoresForeignSeries(t *testing.T) {
+	seriesID := "series-1"
+	due := time.Now().Add(time.Hour).Truncate(time.Second).UTC()
+	repo := &mockTaskRepository{
+		getByIDFn: func(ctx context.Context, id string) (*models.Task, error) {
+			// a task of user-2 pointing at the series of user-1
+			return &models.Task{ID: id, UserID: "user-2", Status: models.TaskStatusTodo, DueAt: &due, SeriesID: &seriesID}, nil
+		},
+		updateFn: func(ctx context.Context, id string, task *models.Task) error {
+			return nil
+		},
+	}
+	seriesRepo := newMockTaskSeriesRepository()
+	seriesRepo.series[seriesID] = &models.TaskSeries{
+		ID:       seriesID,
+		UserID:   "user-1",
+		Title:    "Daily check",
+		RRule:    "FREQ=DAILY",
+		Timezone: "UTC",
+		DTStart:  due,
+		Mode:     models.SeriesModeOnComplete,
+	}
+	svc := NewRecurrenceService(NewTaskService(repo, &mockTaskCacheRepository{}, "app", models.TaskCacheOptions{Expiration: time.Minute}), seriesRepo, &mockTaskCacheRepository{})
+
+	err := svc.UpdateTaskByID(context.Background(), "t1", "user-2", &models.Task{Title: "Daily check", Status: models.TaskStatusDone})
+	if err != nil {
+		t.Fatalf("UpdateTaskByID failed: %v", err)
+	}
+	if len(seriesRepo.occurrences) != 0 {
+		t.Errorf("expected no occurrence in the other user's series, got %d", len(seriesRepo.occurrences))
+	}
+
+	err = svc.UpdateFutureOccurrences(context.Background(), "t1", "user-2", &models.Task{Title: "Hijacked"}, nil)
+	if !errors.As(err, new(*apperror.AppError)) || seriesRepo.series[seriesID].Title != "Daily check" {
+		t.Errorf("expected the foreign series to stay untouched, got %v", err)
+	}
+}
+
+func TestRecurrenceService_GenerateScheduledOccurrences_SkipsMissed(t *testing.T) {
+	seriesRepo := newMockTaskSeriesRepository()
+	start := time.Now().Add(-72 * time.Hour).Truncate(time.Second).UTC()
+	seriesRepo.due = []*ports.DueSeries{{
+		Series: &models.TaskSeries{
+			ID:       "series-1",
+			UserID:   "user-1",
+			Title:    "Daily check",
+			RRule:    "FREQ=DAILY",
+			Timezone: "UTC",
+			DTStart:  start,
+			Mode:     models.SeriesModeScheduled,
+		},
+		LatestDueAt: start,
+	}}
+	svc := NewRecurrenceService(NewTaskService(&mockTaskRepository{}, &mockTaskCacheRepository{}, "app", models.TaskCacheOptions{Expiration: time.Minute}), seriesRepo, &mockTaskCacheRepository{})
+
+	created, err := svc.GenerateScheduledOccurrences(context.Background(), time.Now())
+	if err != nil {
+		t.Fatalf("GenerateScheduledOccurrences failed: %v", err)
+	}
+	if created != 1 {
+		t.Fatalf("expected 1 created, got %d", created)
+	}
+	if next := seriesRepo.occurrences[0].DueAt; !next.After(time.Now()) {
+		t.Errorf("expected next occurrence in the future, got %v", next)
+	}
+}
+
+var _ ports.TaskSeriesRepository = (*mockTaskSeriesRepository)(nil)
@@ -0,0 +1,294 @@
+package service
+
+import (
+	"context"
+	"net/netip"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/logger"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+	"github.com/suryansh74/task-management-api-project/internal/utils"
+)
+
+// reminderGrace is how late a missed reminder (e.g. during downtime) is still delivered
+const reminderGrace = time.Hour
+
+type reminderService struct {
+	reminderRepo  ports.ReminderRepository
+	reminderQueue ports.ReminderQueue
+	taskService   ports.TaskService
+	notifiers     map[string]ports.Notifier
+	lookahead     time.Duration
+	batchSize     int
+	now           func() time.Time
+}
+
+// NewReminderService creates a new reminder service instance
+// =========================================================================
+func NewReminderService(
+	reminderRepo ports.ReminderRepository,
+	reminderQueue ports.ReminderQueue,
+	taskService ports.TaskService,
+	notifiers []ports.Notifier,
+	lookahead time.Duration,
+	batchSize int,
+) ports.ReminderService {
+	byChannel := make(map[string]ports.Notifier, len(notifiers))
+	channels := make([]string, 0, len(notifiers))
+	for _, n := range notifiers {
+		byChannel[n.Channel()] = n
+		channels = append(channels, n.Channel())
+	}
+
+	logger.Log.Info().
+		Strs("channels", channels).
+		Dur("lookahead", lookahead).
+		Int("batch_size", batchSize).
+		Msg("initializing reminder service")
+	return &reminderService{
+		reminderRepo:  reminderRepo,
+		reminderQueue: reminderQueue,
+		taskService:   taskService,
+		notifiers:     byChannel,
+		lookahead:     lookahead,
+		batchSize:     batchSize,
+		now:           time.Now,
+	}
+}
+
+// CreateReminder adds a reminder rule to a task
+// =========================================================================
+func (s *reminderService) CreateReminder(ctx context.Context, taskID string, userID string, reminder *models.Reminder) (*models.Reminder, error) {
+	zerolog.Ctx(ctx).Debug().
+		Str("task_id", taskID).
+		Str("user_id", userID).
+		Int64("offset_seconds", reminder.OffsetSeconds).
+		Str("channel", reminder.Channel).
+		Msg("creating reminder")
+
+	// check policy (task service enforces ownership)
+	task, err := s.taskService.GetTaskByID(ctx, taskID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if reminder.OffsetSeconds < 0 {
+		return nil, apperror.NewBadRequestError("reminder must fire before the due date")
+	}
+	if _, ok := s.notifiers[reminder.Channel]; !ok {
+		return nil, apperror.NewBadRequestError("reminder channel " + reminder.Channel + " is not available")
+	}
+	if reminder.Channel == models.ReminderChannelWebhook {
+		u, err := url.Parse(reminder.Target)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return nil, apperror.NewBadRequestError("webhook reminders need an http(s) target url")
+		}
+		// early answer for obvious internal targets, the notifier checks every resolved address
+		if !isPublicWebhookHost(u.Hostname()) {
+			return nil, apperror.NewBadRequestError("webhook reminders need a public target host")
+		}
+	}
+
+	reminder.TaskID = taskID
+	reminder.UserID = userID
+	id, err := s.reminderRepo.CreateReminder(ctx, reminder)
+	if err != nil {
+		return nil, err
+	}
+	reminder.ID = id
+
+	// reminders firing before the next scheduler pass are queued right away
+	if task.DueAt != nil {
+		fireAt := task.DueAt.Add(-time.Duration(reminder.OffsetSeconds) * time.Second)
+		if fireAt.Before(s.now().Add(s.lookahead)) {
+			if err := s.reminderQueue.Schedule(ctx, id, fireAt); err != nil {
+				zerolog.Ctx(ctx).Warn().
+					Err(err).
+					Str("reminder_id", id).
+					Msg("failed to queue reminder, scheduler will retry")
+			}
+		}
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("reminder_id", id).
+		Str("task_id", taskID).
+		Str("user_id", userID).
+		Msg("reminder created successfully")
+	return reminder, nil
+}
+
+// GetReminders list reminder rules of a task
+// =========================================================================
+func (s *reminderService) GetReminders(ctx context.Context, taskID string, userID string) ([]*models.Reminder, error) {
+	if _, err := s.taskService.GetTaskByID(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+	return s.reminderRepo.GetRemindersByTaskID(ctx, taskID)
+}
+
+// DeleteReminderByID removes a reminder rule, queued entries for it are dropped on claim
+// =========================================================================
+func (s *reminderService) DeleteReminderByID(ctx context.Context, taskID string, reminderID string, userID string) error {
+	if _, err := s.taskService.GetTaskByID(ctx, taskID, userID); err != nil {
+		return err
+	}
+
+	reminder, err := s.reminderRepo.GetReminderByID(ctx, reminderID)
+	if err != nil {
+		return err
+	}
+	if reminder.TaskID != taskID {
+		return apperror.NewNotFoundError("reminder not found")
+	}
+
+	if err := s.reminderRepo.DeleteReminderByID(ctx, reminderID); err != nil {
+		return err
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("reminder_id", reminderID).
+		Str("task_id", taskID).
+		Str("user_id", userID).
+		Msg("reminder deleted successfully")
+	return nil
+}
+
+// GetUpcomingReminders next reminders across all tasks of a user
+// =========================================================================
+func (s *reminderService) GetUpcomingReminders(ctx context.Context, userID string, limit int) ([]*models.DueReminder, error) {
+	if userID == "" {
+		return nil, apperror.NewUnauthorizedError("not authenticated")
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	return s.reminderRepo.GetUpcomingReminders(ctx, userID, s.now(), limit)
+}
+
+// ProcessDueReminders queues reminders firing soon and delivers the due ones
+// =========================================================================
+func (s *reminderService) ProcessDueReminders(ctx context.Context, now time.Time) (int, error) {
+	// 1. queue everything firing before the next pass, ZADD NX makes this idempotent across replicas
+	pending, err := s.reminderRepo.GetPendingReminders(ctx, now.Add(-reminderGrace), now.Add(s.lookahead), s.batchSize)
+	if err != nil {
+		return 0, err
+	}
+	for _, r := range pending {
+		if err := s.reminderQueue.Schedule(ctx, r.ReminderID, r.FireAt); err != nil {
+			return 0, err
+		}
+	}
+
+	// 2. claim what is due, each entry goes to exactly one replica
+	jobs, err := s.reminderQueue.ClaimDue(ctx, now, s.batchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	delivered := 0
+	for _, job := range jobs {
+		if s.deliver(ctx, job) {
+			delivered++
+		}
+	}
+
+	if delivered > 0 {
+		zerolog.Ctx(ctx).Info().
+			Int("delivered", delivered).
+			Msg("reminders delivered")
+	}
+	return delivered, nil
+}
+
+// deliver re-checks a claimed job against the database and sends it once
+func (s *reminderService) deliver(ctx context.Context, job ports.ReminderJob) bool {
+	reminder, err := s.reminderRepo.GetDueReminder(ctx, job.ReminderID)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("reminder_id", job.ReminderID).
+			Msg("failed to resolve claimed reminder")
+		return false
+	}
+	// deleted, task done or due date moved since the job was queued
+	if reminder == nil || reminder.FireAt.Unix() != job.FireAt.Unix() {
+		zerolog.Ctx(ctx).Debug().
+			Str("reminder_id", job.ReminderID).
+			Msg("dropping stale reminder job")
+		return false
+	}
+
+	notifier, ok := s.notifiers[reminder.Channel]
+	if !ok {
+		zerolog.Ctx(ctx).Warn().
+			Str("reminder_id", reminder.ReminderID).
+			Str("channel", reminder.Channel).
+			Msg("no notifier configured for reminder channel")
+		return false
+	}
+
+	fired, err := s.reminderRepo.MarkReminderFired(ctx, reminder.ReminderID, reminder.FireAt)
+	if err != nil || !fired {
+		return false
+	}
+
+	if err := notifier.Notify(ctx, reminder); err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("reminder_id", reminder.ReminderID).
+			Str("channel", reminder.Channel).
+			Msg("reminder delivery failed, will retry")
+		// un-mark so the next pass queues it again (until reminderGrace runs out)
+		if err := s.reminderRepo.ClearReminderFired(ctx, reminder.ReminderID); err != nil {
+			zerolog.Ctx(ctx).Error().
+				Err(err).
+				Str("reminder_id", reminder.ReminderID).
+				Msg("failed to release reminder for retry")
+		}
+		return false
+	}
+	return true
+}
+
+// StartReminderScheduler runs ProcessDueReminders every interval until ctx is done
+// =========================================================================
+func StartReminderScheduler(ctx context.Context, reminderService ports.ReminderService, interval time.Duration) {
+	zerolog.Ctx(ctx).Info().
+		Dur("interval", interval).
+		Msg("reminder scheduler started")
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			zerolog.Ctx(ctx).Info().Msg("reminder scheduler stopped")
+			return
+		case now := <-ticker.C:
+			if _, err := reminderService.ProcessDueReminders(ctx, now); err != nil {
+				zerolog.Ctx(ctx).Error().
+					Err(err).
+					Msg("reminder scheduler run failed")
+			}
+		}
+	}
+}
+
+// isPublicWebhookHost false for localhost names and literal internal IPs
+func isPublicWebhookHost(host string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return false
+	}
+	if ip, err := netip.ParseAddr(host); err == nil {
+		return utils.IsPublicIP(ip)
+	}
+	return true
+}
@@ -0,0 +1,241 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+)
+
+type mockReminderRepository struct {
+	createFn     func(ctx context.Context, reminder *models.Reminder) (string, error)
+	getDueFn     func(ctx context.Context, id string) (*models.DueReminder, error)
+	pendingFn    func(ctx context.Context, from, to time.Time, limit int) ([]*models.DueReminder, error)
+	fired        map[string]time.Time
+	clearedCount int
+}
+
+func newMockReminderRepository() *mockReminderRepository {
+	return &mockReminderRepository{fired: map[string]time.Time{}}
+}
+
+func (m *mockReminderRepository) CreateReminder(ctx context.Context, reminder *models.Reminder) (string, error) {
+	if m.createFn != nil {
+		return m.createFn(ctx, reminder)
+	}
+	return "rem-1", nil
+}
+func (m *mockReminderRepository) GetReminderByID(ctx context.Context, id string) (*models.Reminder, error) {
+	return nil, errors.New("not implemented")
+}
+func (m *mockReminderRepository) GetRemindersByTaskID(ctx context.Context, taskID string) ([]*models.Reminder, error) {
+	return nil, errors.New("not implemented")
+}
+func (m *mockReminderRepository) DeleteReminderByID(ctx context.Context, id string) error {
+	return errors.New("not implemented")
+}
+func (m *mockReminderRepository) GetDueReminder(ctx context.Context, id string) (*models.DueReminder, error) {
+	if m.getDueFn != nil {
+		return m.getDueFn(ctx, id)
+	}
+	return nil, nil
+}
+func (m *mockReminderRepository) GetPendingReminders(ctx context.Context, from, to time.Time, limit int) ([]*models.DueReminder, error) {
+	if m.pendingFn != nil {
+		return m.pendingFn(ctx, from, to, limit)
+	}
+	return nil, nil
+}
+func (m *mockReminderRepository) GetUpcomingReminders(ctx context.Context, userID string, now time.Time, limit int) ([]*models.DueReminder, error) {
+	return nil, nil
+}
+func (m *mockReminderRepository) MarkReminderFired(ctx context.Context, id string, fireAt time.Time) (bool, error) {
+	if prev, ok := m.fired[id]; ok && prev.Equal(fireAt) {
+		return false, nil
+	}
+	m.fired[id] = fireAt
+	return true, nil
+}
+func (m *mockReminderRepository) ClearReminderFired(ctx context.Context, id string) error {
+	delete(m.fired, id)
+	m.clearedCount++
+	return nil
+}
+
+// mockReminderQueue behaves like the sorted set: NX adds, claims remove
+type mockReminderQueue struct {
+	entries map[ports.ReminderJob]bool
+}
+
+func newMockReminderQueue() *mockReminderQueue {
+	return &mockReminderQueue{entries: map[ports.ReminderJob]bool{}}
+}
+
+func (m *mockReminderQueue) Schedule(ctx context.Context, reminderID string, fireAt time.Time) error {
+	m.entries[ports.ReminderJob{ReminderID: reminderID, FireAt: time.Unix(fireAt.Unix(), 0)}] = true
+	return nil
+}
+func (m *mockReminderQueue) ClaimDue(ctx context.Context, now time.Time, limit int) ([]ports.ReminderJob, error) {
+	var jobs []ports.ReminderJob
+	for job := range m.entries {
+		if !job.FireAt.After(now) && len(jobs) < limit {
+			jobs = append(jobs, job)
+			delete(m.entries, job)
+		}
+	}
+	return jobs, nil
+}
+
+type mockNotifier struct {
+	channel string
+	err     error
+	sent    []*models.DueReminder
+}
+
+func (m *mockNotifier) Channel() string { return m.channel }
+func (m *mockNotifier) Notify(ctx context.Context, reminder *models.DueReminder) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.sent = append(m.sent, reminder)
+	return nil
+}
+
+func newTestReminderService(repo ports.ReminderRepository, queue ports.ReminderQueue, notifiers ...ports.Notifier) ports.ReminderService {
+	return NewReminderService(repo, queue, &mockTaskService{}, notifiers, 2*time.Minute, 100)
+}
+
+func TestReminderService_CreateReminder_Validation(t *testing.T) {
+	svc := newTestReminderService(newMockReminderRepository(), newMockReminderQueue(), &mockNotifier{channel: models.ReminderChannelWebhook})
+
+	tests := []struct {
+		name     string
+		reminder *models.Reminder
+	}{
+		{"unavailable channel", &models.Reminder{OffsetSeconds: 3600, Channel: models.ReminderChannelEmail}},
+		{"webhook without target", &models.Reminder{OffsetSeconds: 3600, Channel: models.ReminderChannelWebhook}},
+		{"webhook with non http target", &models.Reminder{OffsetSeconds: 3600, Channel: models.ReminderChannelWebhook, Target: "file:///etc/passwd"}},
+		{"webhook to metadata service", &models.Reminder{OffsetSeconds: 3600, Channel: models.ReminderChannelWebhook, Target: "http://169.254.169.254/latest/meta-data"}},
+		{"webhook to localhost", &models.Reminder{OffsetSeconds: 3600, Channel: models.ReminderChannelWebhook, Target: "http://localhost:6379/"}},
+		{"webhook to loopback ipv6", &models.Reminder{OffsetSeconds: 3600, Channel: models.ReminderChannelWebhook, Target: "http://[::1]:5432/"}},
+		{"negative offset", &models.Reminder{OffsetSeconds: -1, Channel: models.ReminderChannelWebhook, Target: "https://example.com/hook"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.CreateReminder(context.Background(), "t1", "user-1", tt.reminder)
+			var appErr *apperror.AppError
+			if !errors.As(err, &appErr) || appErr.Code != "BAD_REQUEST" {
+				t.Errorf("expected BAD_REQUEST, got %v", err)
+			}
+		})
+	}
+}
+
+func TestReminderService_CreateReminder_QueuesImminent(t *testing.T) {
+	due := time.Now().Add(30 * time.Minute)
+	tasks := &mockTaskService{
+		getByIDFn: func(ctx context.Context, taskID string, userID string) (*models.Task, error) {
+			return &models.Task{ID: taskID, UserID: userID, DueAt: &due}, nil
+		},
+	}
+	queue := newMockReminderQueue()
+	svc := NewReminderService(newMockReminderRepository(), queue, tasks, []ports.Notifier{&mockNotifier{channel: models.ReminderChannelLog}}, 2*time.Minute, 100)
+
+	reminder, err := svc.CreateReminder(context.Background(), "t1", "user-1", &models.Reminder{OffsetSeconds: 1800, Channel: models.ReminderChannelLog})
+	if err != nil {
+		t.Fatalf("CreateReminder failed: %v", err)
+	}
+	if reminder.ID != "rem-1" || reminder.TaskID != "t1" || reminder.UserID != "user-1" {
+		t.Errorf("unexpected reminder: %+v", reminder)
+	}
+	if len(queue.entries) != 1 {
+		t.Errorf("expected reminder firing now to be queued, got %d entries", len(queue.entries))
+	}
+}
+
+func TestReminderService_ProcessDueReminders_DeliversOnce(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	due := &models.DueReminder{
+		ReminderID: "rem-1",
+		TaskID:     "t1",
+		Channel:    models.ReminderChannelLog,
+		DueAt:      now.Add(time.Hour),
+		FireAt:     now.Add(-time.Second),
+	}
+	repo := newMockReminderRepository()
+	repo.pendingFn = func(ctx context.Context, from, to time.Time, limit int) ([]*models.DueReminder, error) {
+		if _, fired := repo.fired[due.ReminderID]; fired {
+			return nil, nil
+		}
+		return []*models.DueReminder{due}, nil
+	}
+	repo.getDueFn = func(ctx context.Context, id string) (*models.DueReminder, error) {
+		return due, nil
+	}
+	notifier := &mockNotifier{channel: models.ReminderChannelLog}
+	queue := newMockReminderQueue()
+	svc := newTestReminderService(repo, queue, notifier)
+
+	// two passes (e.g. two replicas) must not deliver twice
+	for i := 0; i < 2; i++ {
+		if _, err := svc.ProcessDueReminders(context.Background(), now); err != nil {
+			t.Fatalf("ProcessDueReminders failed: %v", err)
+		}
+	}
+	if len(notifier.sent) != 1 {
+		t.Errorf("expected exactly one delivery, got %d", len(notifier.sent))
+	}
+}
+
+func TestReminderService_ProcessDueReminders_DropsStaleJob(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	repo := newMockReminderRepository()
+	// the due date moved after the job was queued
+	repo.getDueFn = func(ctx context.Context, id string) (*models.DueReminder, error) {
+		return &models.DueReminder{ReminderID: id, Channel: models.ReminderChannelLog, FireAt: now.Add(time.Hour)}, nil
+	}
+	notifier := &mockNotifier{channel: models.ReminderChannelLog}
+	queue := newMockReminderQueue()
+	queue.Schedule(context.Background(), "rem-1", now.Add(-time.Minute))
+	svc := newTestReminderService(repo, queue, notifier)
+
+	delivered, err := svc.ProcessDueReminders(context.Background(), now)
+	if err != nil {
+		t.Fatalf("ProcessDueReminders failed: %v", err)
+	}
+	if delivered != 0 || len(notifier.sent) != 0 {
+		t.Errorf("expected stale job to be dropped, delivered %d", delivered)
+	}
+}
+
+func TestReminderService_ProcessDueReminders_ReleasesOnFailure(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	due := &models.DueReminder{ReminderID: "rem-1", Channel: models.ReminderChannelWebhook, FireAt: now}
+	repo := newMockReminderRepository()
+	repo.getDueFn = func(ctx context.Context, id string) (*models.DueReminder, error) {
+		return due, nil
+	}
+	queue := newMockReminderQueue()
+	queue.Schedule(context.Background(), "rem-1", now)
+	svc := newTestReminderService(repo, queue, &mockNotifier{channel: models.ReminderChannelWebhook, err: errors.New("connection refused")})
+
+	delivered, err := svc.ProcessDueReminders(context.Background(), now)
+	if err != nil {
+		t.Fatalf("ProcessDueReminders failed: %v", err)
+	}
+	if delivered != 0 {
+		t.Errorf("expected no delivery, got %d", delivered)
+	}
+	if _, fired := repo.fired["rem-1"]; fired || repo.clearedCount != 1 {
+		t.Error("expected failed reminder to be released for retry")
+	}
+}
+
+var _ ports.ReminderRepository = (*mockReminderRepository)(nil)
+var _ ports.ReminderQueue = (*mockReminderQueue)(nil)
+var _ ports.Notifier = (*mockNotifier)(nil)
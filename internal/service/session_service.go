@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/rs/zerolog"
 	"github.com/suryansh74/task-management-api-project/internal/logger"
+	"github.com/suryansh74/task-management-api-project/internal/metrics"
 	"github.com/suryansh74/task-management-api-project/internal/models"
 	"github.com/suryansh74/task-management-api-project/internal/ports"
 	"github.com/suryansh74/task-management-api-project/internal/utils"
@@ -33,24 +35,25 @@ func NewSessionService(sessionRepo ports.SessionRepository, sessionExpiration ti
 
 // CreateSession it sets new session
 // =========================================================================
-func (s *sessionService) CreateSession(ctx context.Context, userID string) (string, error) {
-	logger.Log.Debug().
+func (s *sessionService) CreateSession(ctx context.Context, userID string, emailVerified bool) (string, error) {
+	zerolog.Ctx(ctx).Debug().
 		Str("user_id", userID).
+		Bool("email_verified", emailVerified).
 		Msg("creating new session for user")
 
 	// create random id
 	id := utils.MustRandomID()
 	sessionID := fmt.Sprintf("%s:sessions:%s", s.redisAppName, id)
 
-	logger.Log.Debug().
+	zerolog.Ctx(ctx).Debug().
 		Str("user_id", userID).
 		Str("session_id", sessionID).
 		Str("random_id", id).
 		Msg("generated session id")
 
-	err := s.sessionRepo.Create(ctx, &models.Session{ID: sessionID, UserID: userID}, s.sessionExpiration)
+	err := s.sessionRepo.Create(ctx, &models.Session{ID: sessionID, UserID: userID, EmailVerified: emailVerified}, s.sessionExpiration)
 	if err != nil {
-		logger.Log.Error().
+		zerolog.Ctx(ctx).Error().
 			Err(err).
 			Str("user_id", userID).
 			Str("session_id", sessionID).
@@ -58,7 +61,7 @@ func (s *sessionService) CreateSession(ctx context.Context, userID string) (stri
 		return "", err
 	}
 
-	logger.Log.Info().
+	zerolog.Ctx(ctx).Info().
 		Str("user_id", userID).
 		Str("session_id", sessionID).
 		Dur("expiration", s.sessionExpiration).
@@ -69,21 +72,95 @@ func (s *sessionService) CreateSession(ctx context.Context, userID string) (stri
 // Delete it unlink user session
 // =========================================================================
 func (s *sessionService) Logout(ctx context.Context, sessionID string) error {
-	logger.Log.Debug().
+	zerolog.Ctx(ctx).Debug().
 		Str("session_id", sessionID).
 		Msg("logging out user session")
 
 	err := s.sessionRepo.Delete(ctx, sessionID)
 	if err != nil {
-		logger.Log.Error().
+		zerolog.Ctx(ctx).Error().
 			Err(err).
 			Str("session_id", sessionID).
 			Msg("failed to logout user session")
 		return err
 	}
 
-	logger.Log.Info().
+	zerolog.Ctx(ctx).Info().
 		Str("session_id", sessionID).
 		Msg("user logged out successfully")
 	return nil
 }
+
+// RevokeUserSessions delete every session of the user
+// =========================================================================
+func (s *sessionService) RevokeUserSessions(ctx context.Context, userID string) error {
+	zerolog.Ctx(ctx).Debug().
+		Str("user_id", userID).
+		Msg("revoking all user sessions")
+
+	deleted, err := s.sessionRepo.DeleteByUserID(ctx, userID)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("user_id", userID).
+			Msg("failed to revoke user sessions")
+		return err
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("user_id", userID).
+		Int64("revoked", deleted).
+		Msg("user sessions revoked successfully")
+	return nil
+}
+
+// MarkEmailVerified flag the user's open sessions as verified
+// =========================================================================
+func (s *sessionService) MarkEmailVerified(ctx context.Context, userID string) error {
+	updated, err := s.sessionRepo.MarkEmailVerified(ctx, userID)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("user_id", userID).
+			Msg("failed to mark user sessions email verified")
+		return err
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("user_id", userID).
+		Int64("sessions", updated).
+		Msg("user sessions marked email verified")
+	return nil
+}
+
+// CountActiveSessions sessions neither expired nor logged out, across all replicas
+// =========================================================================
+func (s *sessionService) CountActiveSessions(ctx context.Context) (int64, error) {
+	return s.sessionRepo.CountActive(ctx)
+}
+
+// StartSessionMetrics refreshes the active sessions gauge every interval until ctx is done.
+// =========================================================================
+func StartSessionMetrics(ctx context.Context, sessionService ports.SessionService, interval time.Duration) {
+	refresh := func() {
+		count, err := sessionService.CountActiveSessions(ctx)
+		if err != nil {
+			zerolog.Ctx(ctx).Warn().Err(err).Msg("active sessions gauge not refreshed")
+			return
+		}
+		metrics.ActiveSessions.Set(float64(count))
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	refresh()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			refresh()
+		}
+	}
+}
@@ -0,0 +1,275 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"unicode/utf8"
+
+	"github.com/rs/zerolog"
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/logger"
+	"github.com/suryansh74/task-management-api-project/internal/metrics"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+)
+
+// maxBatchSize upper bound of operations in one batch
+const maxBatchSize = 100
+
+// maxContentLength matches the validate tag of models.Task.Content, the
+// REST DTOs are not the only way in
+const maxContentLength = 500
+
+// codeAborted marks ops of a failed atomic batch that were not applied
+const codeAborted = "ABORTED"
+
+type taskBatchService struct {
+	taskRepo          ports.TaskRepository
+	recurrenceService ports.RecurrenceService
+	taskCacheRepo     ports.TaskCacheRepository
+	quotaService      ports.TaskQuotaService
+	redisAppName      string
+}
+
+// NewTaskBatchService creates a new task batch service instance
+// =========================================================================
+func NewTaskBatchService(taskRepo ports.TaskRepository, recurrenceService ports.RecurrenceService, taskCacheRepo ports.TaskCacheRepository, quotaService ports.TaskQuotaService, redisAppName string) ports.TaskBatchService {
+	logger.Log.Info().
+		Int("max_batch_size", maxBatchSize).
+		Msg("initializing task batch service")
+	return &taskBatchService{
+		taskRepo:          taskRepo,
+		recurrenceService: recurrenceService,
+		taskCacheRepo:     taskCacheRepo,
+		quotaService:      quotaService,
+		redisAppName:      redisAppName,
+	}
+}
+
+// BatchTasks check every op, apply the valid ones and report per op
+// =========================================================================
+func (s *taskBatchService) BatchTasks(ctx context.Context, userID string, ops []*models.TaskBatchOp, atomic bool) (*models.TaskBatchResult, error) {
+	zerolog.Ctx(ctx).Debug().
+		Str("user_id", userID).
+		Int("op_count", len(ops)).
+		Bool("atomic", atomic).
+		Msg("processing task batch")
+
+	if userID == "" {
+		return nil, apperror.NewUnauthorizedError("not authenticated")
+	}
+	if len(ops) == 0 {
+		return nil, apperror.NewBadRequestError("batch must contain at least one operation")
+	}
+	if len(ops) > maxBatchSize {
+		return nil, apperror.NewBadRequestError(fmt.Sprintf("batch can contain at most %d operations", maxBatchSize))
+	}
+
+	errs := make([]error, len(ops))
+	previous := make([]*models.Task, len(ops))
+	// in atomic mode tasks completed earlier in the batch no longer block
+	completing := make(map[string]bool)
+	for i, op := range ops {
+		op.UserID = userID
+		previous[i], errs[i] = s.prepare(ctx, op, completing)
+		if errs[i] == nil && atomic && completesTask(op, previous[i]) {
+			completing[op.ID] = true
+		}
+	}
+
+	result := &models.TaskBatchResult{Atomic: atomic}
+	if atomic && hasError(errs) {
+		return s.finish(ctx, result, ops, errs, false), nil
+	}
+
+	// only ops that passed the checks reach the database
+	var valid []*models.TaskBatchOp
+	var validIndex []int
+	for i, op := range ops {
+		if errs[i] == nil {
+			valid = append(valid, op)
+			validIndex = append(validIndex, i)
+		}
+	}
+
+	// quotas are checked for an atomic batch as a whole, otherwise op by op so
+	// only the ops over quota fail
+	addTasks, addBytes := 0, int64(0)
+	var allowed []*models.TaskBatchOp
+	var allowedIndex []int
+	for j, op := range valid {
+		opTasks, opBytes := 0, int64(0)
+		switch op.Op {
+		case models.BatchOpCreate:
+			opTasks = 1
+			opBytes = models.TaskContentBytes(op.Title, op.Content)
+		case models.BatchOpUpdate:
+			current := previous[validIndex[j]]
+			opBytes = models.TaskContentBytes(op.Title, op.Content) - models.TaskContentBytes(current.Title, current.Content)
+		}
+		if !atomic && (opTasks > 0 || opBytes > 0) {
+			if err := s.quotaService.CheckQuota(ctx, userID, addTasks+opTasks, addBytes+opBytes); err != nil {
+				errs[validIndex[j]] = err
+				continue
+			}
+		}
+		addTasks += opTasks
+		addBytes += opBytes
+		allowed = append(allowed, op)
+		allowedIndex = append(allowedIndex, validIndex[j])
+	}
+	if atomic {
+		if err := s.quotaService.CheckQuota(ctx, userID, addTasks, addBytes); err != nil {
+			return nil, err
+		}
+	}
+	valid, validIndex = allowed, allowedIndex
+
+	if len(valid) > 0 {
+		applyErrs, err := s.taskRepo.ApplyTaskBatch(ctx, valid, atomic)
+		if err != nil {
+			zerolog.Ctx(ctx).Error().
+				Err(err).
+				Str("user_id", userID).
+				Msg("failed to apply task batch")
+			return nil, err
+		}
+		for j, err := range applyErrs {
+			errs[validIndex[j]] = err
+		}
+		if atomic && hasError(errs) {
+			return s.finish(ctx, result, ops, errs, false), nil
+		}
+	}
+
+	// one round trip for every invalidated task, then the side effects
+	keys := make([]string, 0, len(ops))
+	for i, op := range ops {
+		if errs[i] != nil || op.Op == models.BatchOpCreate {
+			continue
+		}
+		keys = append(keys, fmt.Sprintf("%s:cache:task:%s", s.redisAppName, op.ID))
+		if completesTask(op, previous[i]) {
+			s.recurrenceService.OccurrenceCompleted(ctx, previous[i])
+		}
+	}
+	metrics.ObserveCacheWrite("task", "delete", s.taskCacheRepo.DeleteTasks(ctx, keys))
+	invalidateTaskLists(ctx, s.taskCacheRepo, userID)
+
+	return s.finish(ctx, result, ops, errs, true), nil
+}
+
+// prepare validate one op and check ownership, returns the task before the op
+func (s *taskBatchService) prepare(ctx context.Context, op *models.TaskBatchOp, completing map[string]bool) (*models.Task, error) {
+	if op.Status != "" && op.Status != models.TaskStatusTodo && op.Status != models.TaskStatusInProgress && op.Status != models.TaskStatusDone {
+		return nil, apperror.NewBadRequestError("status must be todo, in_progress or done")
+	}
+
+	if (op.Op == models.BatchOpCreate || op.Op == models.BatchOpUpdate) && utf8.RuneCountInString(op.Content) > maxContentLength {
+		return nil, apperror.NewBadRequestError(fmt.Sprintf("content must be at most %d characters", maxContentLength))
+	}
+
+	switch op.Op {
+	case models.BatchOpCreate:
+		if len(op.Title) < 2 || len(op.Title) > 100 {
+			return nil, apperror.NewBadRequestError("title must be between 2 and 100 characters")
+		}
+		return nil, nil
+	case models.BatchOpUpdate:
+		if len(op.Title) < 2 || len(op.Title) > 100 {
+			return nil, apperror.NewBadRequestError("title must be between 2 and 100 characters")
+		}
+	case models.BatchOpSetStatus:
+		if op.Status == "" {
+			return nil, apperror.NewBadRequestError("status is required")
+		}
+	case models.BatchOpDelete:
+	default:
+		return nil, apperror.NewBadRequestError(fmt.Sprintf("unknown operation %q", op.Op))
+	}
+
+	if op.ID == "" {
+		return nil, apperror.NewBadRequestError("id is required")
+	}
+
+	// check policy (task service enforces ownership)
+	current, err := s.recurrenceService.GetTaskByID(ctx, op.ID, op.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if completesTask(op, current) {
+		blockers, err := s.taskRepo.GetBlockers(ctx, op.ID)
+		if err != nil {
+			return nil, err
+		}
+		open := 0
+		for _, blocker := range blockers {
+			if blocker.Status != models.TaskStatusDone && !completing[blocker.ID] {
+				open++
+			}
+		}
+		if open > 0 {
+			return nil, apperror.NewConflictError(fmt.Sprintf("task is blocked by %d open task(s)", open))
+		}
+	}
+	return current, nil
+}
+
+// finish fill per op results and counters
+func (s *taskBatchService) finish(ctx context.Context, result *models.TaskBatchResult, ops []*models.TaskBatchOp, errs []error, committed bool) *models.TaskBatchResult {
+	result.Committed = committed
+	result.Results = make([]*models.TaskBatchItemResult, len(ops))
+	for i, op := range ops {
+		item := &models.TaskBatchItemResult{Index: i, Op: op.Op, ID: op.ID}
+		switch {
+		case errs[i] != nil:
+			var appErr *apperror.AppError
+			if errors.As(errs[i], &appErr) {
+				item.Code, item.Error = appErr.Code, appErr.Message
+			} else {
+				item.Code, item.Error = "INTERNAL_ERROR", "internal error"
+			}
+			result.Failed++
+		case !committed:
+			item.Code, item.Error = codeAborted, "not applied, another operation of the atomic batch failed"
+			if op.Op == models.BatchOpCreate {
+				item.ID = ""
+			}
+			result.Failed++
+		default:
+			item.OK = true
+			result.Succeeded++
+			if op.Op == models.BatchOpCreate {
+				metrics.TasksCreated.WithLabelValues("batch").Inc()
+			}
+		}
+		result.Results[i] = item
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Bool("atomic", result.Atomic).
+		Bool("committed", committed).
+		Int("succeeded", result.Succeeded).
+		Int("failed", result.Failed).
+		Msg("task batch processed")
+	return result
+}
+
+// completesTask reports whether op moves a not yet done task to done
+func completesTask(op *models.TaskBatchOp, current *models.Task) bool {
+	if current == nil || current.Status == models.TaskStatusDone {
+		return false
+	}
+	return (op.Op == models.BatchOpUpdate || op.Op == models.BatchOpSetStatus) && op.Status == models.TaskStatusDone
+}
+
+func hasError(errs []error) bool {
+	for _, err := range errs {
+		if err != nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,209 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+)
+
+type mockRecurrenceService struct {
+	ports.RecurrenceService
+	getByIDFn   func(ctx context.Context, taskID string, userID string) (*models.Task, error)
+	completedFn func(ctx context.Context, current *models.Task)
+}
+
+func (m *mockRecurrenceService) GetTaskByID(ctx context.Context, taskID string, userID string) (*models.Task, error) {
+	if m.getByIDFn != nil {
+		return m.getByIDFn(ctx, taskID, userID)
+	}
+	return &models.Task{ID: taskID, UserID: userID, Status: models.TaskStatusTodo}, nil
+}
+
+func (m *mockRecurrenceService) OccurrenceCompleted(ctx context.Context, current *models.Task) {
+	if m.completedFn != nil {
+		m.completedFn(ctx, current)
+	}
+}
+
+func TestTaskBatchService_PartialFailure(t *testing.T) {
+	var applied []*models.TaskBatchOp
+	repo := &mockTaskRepository{
+		batchFn: func(ctx context.Context, ops []*models.TaskBatchOp, atomic bool) ([]error, error) {
+			applied = ops
+			ops[0].ID = "new-task"
+			return make([]error, len(ops)), nil
+		},
+	}
+	tasks := &mockRecurrenceService{
+		getByIDFn: func(ctx context.Context, taskID string, userID string) (*models.Task, error) {
+			if taskID == "foreign" {
+				return nil, apperror.NewForbiddenError("not allowed")
+			}
+			return &models.Task{ID: taskID, UserID: userID}, nil
+		},
+	}
+	var invalidated [][]string
+	cache := &mockTaskCacheRepository{
+		deleteManyFn: func(ctx context.Context, keys []string) error {
+			invalidated = append(invalidated, keys)
+			return nil
+		},
+	}
+	svc := NewTaskBatchService(repo, tasks, cache, NewTaskQuotaService(repo, models.TaskQuota{}), "app")
+
+	result, err := svc.BatchTasks(context.Background(), "user-1", []*models.TaskBatchOp{
+		{Op: models.BatchOpCreate, Title: "New one"},
+		{Op: models.BatchOpUpdate, ID: "foreign", Title: "Hijack"},
+		{Op: models.BatchOpDelete, ID: "mine"},
+	}, false)
+	if err != nil {
+		t.Fatalf("BatchTasks failed: %v", err)
+	}
+
+	if len(applied) != 2 {
+		t.Fatalf("expected 2 ops to reach the repository, got %d", len(applied))
+	}
+	if !result.Committed || result.Succeeded != 2 || result.Failed != 1 {
+		t.Errorf("unexpected summary %+v", result)
+	}
+	if result.Results[0].ID != "new-task" || !result.Results[0].OK {
+		t.Errorf("unexpected create result %+v", result.Results[0])
+	}
+	if result.Results[1].OK || result.Results[1].Code != "FORBIDDEN" {
+		t.Errorf("unexpected update result %+v", result.Results[1])
+	}
+	if !reflect.DeepEqual(invalidated, [][]string{{"app:cache:task:mine"}}) {
+		t.Errorf("expected one batched invalidation, got %v", invalidated)
+	}
+}
+
+func TestTaskBatchService_AtomicAbortsEverything(t *testing.T) {
+	repo := &mockTaskRepository{
+		batchFn: func(ctx context.Context, ops []*models.TaskBatchOp, atomic bool) ([]error, error) {
+			t.Fatal("atomic batch with an invalid op must not reach the repository")
+			return nil, nil
+		},
+	}
+	svc := NewTaskBatchService(repo, &mockRecurrenceService{}, &mockTaskCacheRepository{}, NewTaskQuotaService(repo, models.TaskQuota{}), "app")
+
+	result, err := svc.BatchTasks(context.Background(), "user-1", []*models.TaskBatchOp{
+		{Op: models.BatchOpDelete, ID: "t1"},
+		{Op: models.BatchOpSetStatus, ID: "t2", Status: "archived"},
+	}, true)
+	if err != nil {
+		t.Fatalf("BatchTasks failed: %v", err)
+	}
+
+	if result.Committed || result.Succeeded != 0 || result.Failed != 2 {
+		t.Errorf("unexpected summary %+v", result)
+	}
+	if result.Results[0].Code != codeAborted {
+		t.Errorf("expected valid op to be aborted, got %+v", result.Results[0])
+	}
+	if result.Results[1].Code != "BAD_REQUEST" {
+		t.Errorf("expected BAD_REQUEST, got %+v", result.Results[1])
+	}
+}
+
+func TestTaskBatchService_AtomicCompletesBlockerFirst(t *testing.T) {
+	repo := &mockTaskRepository{
+		blockersFn: func(ctx context.Context, taskID string) ([]*models.Task, error) {
+			if taskID == "deploy" {
+				return []*models.Task{{ID: "build", Status: models.TaskStatusTodo}}, nil
+			}
+			return nil, nil
+		},
+	}
+	var completed []string
+	tasks := &mockRecurrenceService{
+		completedFn: func(ctx context.Context, current *models.Task) {
+			completed = append(completed, current.ID)
+		},
+	}
+	svc := NewTaskBatchService(repo, tasks, &mockTaskCacheRepository{}, NewTaskQuotaService(repo, models.TaskQuota{}), "app")
+
+	ops := []*models.TaskBatchOp{
+		{Op: models.BatchOpSetStatus, ID: "build", Status: models.TaskStatusDone},
+		{Op: models.BatchOpSetStatus, ID: "deploy", Status: models.TaskStatusDone},
+	}
+	result, err := svc.BatchTasks(context.Background(), "user-1", ops, true)
+	if err != nil {
+		t.Fatalf("BatchTasks failed: %v", err)
+	}
+	if !result.Committed || result.Succeeded != 2 {
+		t.Errorf("expected both ops to commit, got %+v", result)
+	}
+	if !reflect.DeepEqual(completed, []string{"build", "deploy"}) {
+		t.Errorf("expected completion hooks for both tasks, got %v", completed)
+	}
+
+	// without atomic mode the blocker may still fail, so deploy stays blocked
+	result, err = svc.BatchTasks(context.Background(), "user-1", ops, false)
+	if err != nil {
+		t.Fatalf("BatchTasks failed: %v", err)
+	}
+	if result.Results[1].Code != "CONFLICT" {
+		t.Errorf("expected CONFLICT for blocked task, got %+v", result.Results[1])
+	}
+}
+
+func TestTaskBatchService_QuotaFailsOnlyOpsOverQuota(t *testing.T) {
+	var applied []*models.TaskBatchOp
+	repo := &mockTaskRepository{
+		usageFn: func(ctx context.Context, userID string) (*models.TaskUsage, error) {
+			return &models.TaskUsage{Tasks: 1}, nil
+		},
+		batchFn: func(ctx context.Context, ops []*models.TaskBatchOp, atomic bool) ([]error, error) {
+			applied = ops
+			return make([]error, len(ops)), nil
+		},
+	}
+	svc := NewTaskBatchService(repo, &mockRecurrenceService{}, &mockTaskCacheRepository{}, NewTaskQuotaService(repo, models.TaskQuota{MaxTasks: 2}), "app")
+
+	result, err := svc.BatchTasks(context.Background(), "user-1", []*models.TaskBatchOp{
+		{Op: models.BatchOpCreate, Title: "Fits"},
+		{Op: models.BatchOpCreate, Title: "Over quota"},
+		{Op: models.BatchOpDelete, ID: "t1"},
+	}, false)
+	if err != nil {
+		t.Fatalf("BatchTasks failed: %v", err)
+	}
+	if len(applied) != 2 || applied[0].Title != "Fits" {
+		t.Fatalf("expected the create within quota and the delete to be applied, got %d ops", len(applied))
+	}
+	if result.Succeeded != 2 || result.Results[1].Code != "QUOTA_EXCEEDED" {
+		t.Errorf("expected only the second create to fail with QUOTA_EXCEEDED, got %+v", result.Results[1])
+	}
+}
+
+func TestTaskBatchService_RejectsLongContent(t *testing.T) {
+	svc := NewTaskBatchService(&mockTaskRepository{}, &mockRecurrenceService{}, &mockTaskCacheRepository{}, NewTaskQuotaService(&mockTaskRepository{}, models.TaskQuota{}), "app")
+
+	result, err := svc.BatchTasks(context.Background(), "user-1", []*models.TaskBatchOp{
+		{Op: models.BatchOpCreate, Title: "Essay", Content: strings.Repeat("é", maxContentLength+1)},
+		{Op: models.BatchOpCreate, Title: "Short", Content: strings.Repeat("é", maxContentLength)},
+	}, false)
+	if err != nil {
+		t.Fatalf("BatchTasks failed: %v", err)
+	}
+	if result.Results[0].Code != "BAD_REQUEST" || !result.Results[1].OK {
+		t.Errorf("expected only the long content to be rejected, got %+v / %+v", result.Results[0], result.Results[1])
+	}
+}
+
+func TestTaskBatchService_Limits(t *testing.T) {
+	svc := NewTaskBatchService(&mockTaskRepository{}, &mockRecurrenceService{}, &mockTaskCacheRepository{}, NewTaskQuotaService(&mockTaskRepository{}, models.TaskQuota{}), "app")
+
+	if _, err := svc.BatchTasks(context.Background(), "user-1", nil, false); err == nil {
+		t.Error("expected error for empty batch")
+	}
+	ops := make([]*models.TaskBatchOp, maxBatchSize+1)
+	if _, err := svc.BatchTasks(context.Background(), "user-1", ops, false); err == nil {
+		t.Error("expected error for oversized batch")
+	}
+}
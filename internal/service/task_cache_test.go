@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+)
+
+func TestTaskService_GetTaskByID_CoalescesMisses(t *testing.T) {
+	var reads atomic.Int32
+	release := make(chan struct{})
+	repo := &mockTaskRepository{
+		getByIDFn: func(ctx context.Context, id string) (*models.Task, error) {
+			reads.Add(1)
+			<-release
+			return &models.Task{ID: id, UserID: "user-1", Title: "Hot"}, nil
+		},
+	}
+	svc := NewTaskService(repo, &mockTaskCacheRepository{}, "app", models.TaskCacheOptions{Expiration: time.Minute})
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for range 20 {
+		wg.Go(func() {
+			task, err := svc.GetTaskByID(context.Background(), "t1", "user-1")
+			if err == nil && task.Title != "Hot" {
+				err = errors.New("unexpected task " + task.Title)
+			}
+			errs <- err
+		})
+	}
+	// let every caller reach the shared read before it returns
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("GetTaskByID failed: %v", err)
+		}
+	}
+	if n := reads.Load(); n != 1 {
+		t.Errorf("expected 1 database read for 20 concurrent misses, got %d", n)
+	}
+}
+
+func TestTaskService_GetTaskByID_NegativeCache(t *testing.T) {
+	var reads int
+	repo := &mockTaskRepository{
+		getByIDFn: func(ctx context.Context, id string) (*models.Task, error) {
+			reads++
+			return nil, apperror.NewNotFoundError("task not found")
+		},
+	}
+	var cachedMissing bool
+	cache := &mockTaskCacheRepository{
+		entryFn: func(ctx context.Context, key string) (*models.TaskCacheEntry, error) {
+			if cachedMissing {
+				return &models.TaskCacheEntry{NotFound: true, TTL: 30 * time.Second}, nil
+			}
+			return nil, nil
+		},
+		notFoundFn: func(ctx context.Context, key string, exp time.Duration) error {
+			if exp != 30*time.Second {
+				t.Errorf("expected negative TTL 30s, got %v", exp)
+			}
+			cachedMissing = true
+			return nil
+		},
+	}
+	svc := NewTaskService(repo, cache, "app", models.TaskCacheOptions{Expiration: time.Minute, NegativeExpiration: 30 * time.Second})
+
+	for range 2 {
+		_, err := svc.GetTaskByID(context.Background(), "missing", "user-1")
+		var appErr *apperror.AppError
+		if !errors.As(err, &appErr) || appErr.Code != "NOT_FOUND" {
+			t.Fatalf("expected NOT_FOUND, got %v", err)
+		}
+	}
+	if reads != 1 {
+		t.Errorf("expected the second lookup to be served from the negative cache, got %d reads", reads)
+	}
+}
+
+func TestTaskService_CacheTTLJitter(t *testing.T) {
+	svc := &taskService{cache: models.TaskCacheOptions{Expiration: 10 * time.Minute, Jitter: 0.1}}
+
+	distinct := map[time.Duration]bool{}
+	for range 100 {
+		ttl := svc.cacheTTL()
+		if ttl < 9*time.Minute || ttl > 11*time.Minute {
+			t.Fatalf("ttl %v outside ±10%% of 10m", ttl)
+		}
+		distinct[ttl] = true
+	}
+	if len(distinct) < 2 {
+		t.Error("expected jittered TTLs to differ")
+	}
+
+	svc.cache.Jitter = 0
+	if ttl := svc.cacheTTL(); ttl != 10*time.Minute {
+		t.Errorf("expected exact TTL without jitter, got %v", ttl)
+	}
+}
+
+func TestTaskService_RefreshEarly(t *testing.T) {
+	svc := &taskService{cache: models.TaskCacheOptions{EarlyRefreshBeta: 1}}
+	svc.loadCost.Store(int64(time.Second))
+
+	// a reload costing far more than the time left is (almost) always refreshed
+	refreshed := 0
+	for range 100 {
+		if svc.refreshEarly(time.Millisecond) {
+			refreshed++
+		}
+	}
+	if refreshed < 90 {
+		t.Errorf("expected nearly every lookup to refresh, got %d/100", refreshed)
+	}
+	// with plenty of TTL left it practically never is
+	for range 100 {
+		if svc.refreshEarly(time.Hour) {
+			t.Fatal("unexpected early refresh with an hour left")
+		}
+	}
+
+	svc.cache.EarlyRefreshBeta = 0
+	if svc.refreshEarly(time.Millisecond) {
+		t.Error("early refresh must be off when beta is 0")
+	}
+}
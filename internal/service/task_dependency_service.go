@@ -0,0 +1,181 @@
+package service
+
+import (
+	"container/heap"
+	"context"
+
+	"github.com/rs/zerolog"
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/logger"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+)
+
+type taskDependencyService struct {
+	taskRepo    ports.TaskRepository
+	taskService ports.TaskService
+}
+
+// NewTaskDependencyService creates a new task dependency service instance
+// =========================================================================
+func NewTaskDependencyService(taskRepo ports.TaskRepository, taskService ports.TaskService) ports.TaskDependencyService {
+	logger.Log.Info().Msg("initializing task dependency service")
+	return &taskDependencyService{
+		taskRepo:    taskRepo,
+		taskService: taskService,
+	}
+}
+
+// AddBlocker mark taskID as blocked by blockerID
+// =========================================================================
+func (s *taskDependencyService) AddBlocker(ctx context.Context, taskID string, blockerID string, userID string) error {
+	zerolog.Ctx(ctx).Debug().
+		Str("task_id", taskID).
+		Str("blocker_id", blockerID).
+		Str("user_id", userID).
+		Msg("adding blocker")
+
+	if taskID == blockerID {
+		return apperror.NewBadRequestError("a task cannot block itself")
+	}
+
+	// check policy, the user must own both ends of the edge
+	if _, err := s.taskService.GetTaskByID(ctx, taskID, userID); err != nil {
+		return err
+	}
+	if _, err := s.taskService.GetTaskByID(ctx, blockerID, userID); err != nil {
+		return err
+	}
+
+	return s.taskRepo.AddDependency(ctx, userID, taskID, blockerID)
+}
+
+// RemoveBlocker unblock taskID from blockerID
+// =========================================================================
+func (s *taskDependencyService) RemoveBlocker(ctx context.Context, taskID string, blockerID string, userID string) error {
+	if _, err := s.taskService.GetTaskByID(ctx, taskID, userID); err != nil {
+		return err
+	}
+	return s.taskRepo.RemoveDependency(ctx, taskID, blockerID)
+}
+
+// GetBlockers tasks blocking taskID
+// =========================================================================
+func (s *taskDependencyService) GetBlockers(ctx context.Context, taskID string, userID string) ([]*models.Task, error) {
+	if _, err := s.taskService.GetTaskByID(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+	return s.taskRepo.GetBlockers(ctx, taskID)
+}
+
+// GetDependents tasks blocked by taskID
+// =========================================================================
+func (s *taskDependencyService) GetDependents(ctx context.Context, taskID string, userID string) ([]*models.Task, error) {
+	if _, err := s.taskService.GetTaskByID(ctx, taskID, userID); err != nil {
+		return nil, err
+	}
+	return s.taskRepo.GetDependents(ctx, taskID)
+}
+
+// GetTopologicalOrder Kahn's algorithm over the user's tasks; among tasks that
+// are ready at the same time the earliest due (then oldest) comes first
+// =========================================================================
+func (s *taskDependencyService) GetTopologicalOrder(ctx context.Context, userID string) ([]*models.Task, error) {
+	if userID == "" {
+		return nil, apperror.NewUnauthorizedError("not authenticated")
+	}
+
+	tasks, err := s.taskRepo.GetAllTasks(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	edges, err := s.taskRepo.GetDependencyEdges(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	ordered, err := topologicalOrder(tasks, edges)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("user_id", userID).
+			Msg("dependency graph is not acyclic")
+		return nil, err
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("user_id", userID).
+		Int("task_count", len(ordered)).
+		Int("edge_count", len(edges)).
+		Msg("topological order computed")
+	return ordered, nil
+}
+
+// topologicalOrder sorts tasks so every blocker precedes its dependents
+func topologicalOrder(tasks []*models.Task, edges []*models.TaskDependency) ([]*models.Task, error) {
+	byID := make(map[string]*models.Task, len(tasks))
+	for _, t := range tasks {
+		byID[t.ID] = t
+	}
+
+	indegree := make(map[string]int, len(tasks))
+	dependents := make(map[string][]string)
+	for _, e := range edges {
+		if byID[e.TaskID] == nil || byID[e.BlockedByID] == nil {
+			continue
+		}
+		indegree[e.TaskID]++
+		dependents[e.BlockedByID] = append(dependents[e.BlockedByID], e.TaskID)
+	}
+
+	ready := &taskHeap{}
+	for _, t := range tasks {
+		if indegree[t.ID] == 0 {
+			heap.Push(ready, t)
+		}
+	}
+
+	ordered := make([]*models.Task, 0, len(tasks))
+	for ready.Len() > 0 {
+		t := heap.Pop(ready).(*models.Task)
+		ordered = append(ordered, t)
+		for _, id := range dependents[t.ID] {
+			indegree[id]--
+			if indegree[id] == 0 {
+				heap.Push(ready, byID[id])
+			}
+		}
+	}
+
+	if len(ordered) != len(tasks) {
+		return nil, apperror.NewConflictError("task dependencies contain a cycle")
+	}
+	return ordered, nil
+}
+
+// taskHeap orders ready tasks by due date (undated last), then creation time
+type taskHeap []*models.Task
+
+func (h taskHeap) Len() int { return len(h) }
+func (h taskHeap) Less(i, j int) bool {
+	a, b := h[i], h[j]
+	switch {
+	case a.DueAt != nil && b.DueAt == nil:
+		return true
+	case a.DueAt == nil && b.DueAt != nil:
+		return false
+	case a.DueAt != nil && !a.DueAt.Equal(*b.DueAt):
+		return a.DueAt.Before(*b.DueAt)
+	case !a.CreatedAt.Equal(b.CreatedAt):
+		return a.CreatedAt.Before(b.CreatedAt)
+	}
+	return a.ID < b.ID
+}
+func (h taskHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h *taskHeap) Push(x any)   { *h = append(*h, x.(*models.Task)) }
+func (h *taskHeap) Pop() any {
+	old := *h
+	t := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return t
+}
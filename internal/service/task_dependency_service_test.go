@@ -0,0 +1,120 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+)
+
+func dependencyTasks() []*models.Task {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	soon := base.Add(24 * time.Hour)
+	later := base.Add(48 * time.Hour)
+	return []*models.Task{
+		{ID: "deploy", CreatedAt: base},
+		{ID: "test", CreatedAt: base.Add(time.Minute), DueAt: &later},
+		{ID: "build", CreatedAt: base.Add(2 * time.Minute)},
+		{ID: "docs", CreatedAt: base.Add(3 * time.Minute), DueAt: &soon},
+	}
+}
+
+func TestTaskDependencyService_GetTopologicalOrder(t *testing.T) {
+	repo := &mockTaskRepository{
+		getAllFn: func(ctx context.Context, userID string) ([]*models.Task, error) {
+			return dependencyTasks(), nil
+		},
+		edgesFn: func(ctx context.Context, userID string) ([]*models.TaskDependency, error) {
+			return []*models.TaskDependency{
+				{TaskID: "test", BlockedByID: "build"},
+				{TaskID: "deploy", BlockedByID: "test"},
+				{TaskID: "deploy", BlockedByID: "docs"},
+			}, nil
+		},
+	}
+	svc := NewTaskDependencyService(repo, &mockTaskService{})
+
+	ordered, err := svc.GetTopologicalOrder(context.Background(), "user-1")
+	if err != nil {
+		t.Fatalf("GetTopologicalOrder failed: %v", err)
+	}
+
+	// docs is due first so it wins the tie with build, deploy waits for everything
+	want := []string{"docs", "build", "test", "deploy"}
+	if len(ordered) != len(want) {
+		t.Fatalf("expected %d tasks, got %d", len(want), len(ordered))
+	}
+	for i, id := range want {
+		if ordered[i].ID != id {
+			t.Errorf("position %d: expected %s, got %s", i, id, ordered[i].ID)
+		}
+	}
+}
+
+func TestTaskDependencyService_GetTopologicalOrder_Cycle(t *testing.T) {
+	repo := &mockTaskRepository{
+		getAllFn: func(ctx context.Context, userID string) ([]*models.Task, error) {
+			return dependencyTasks(), nil
+		},
+		edgesFn: func(ctx context.Context, userID string) ([]*models.TaskDependency, error) {
+			return []*models.TaskDependency{
+				{TaskID: "test", BlockedByID: "build"},
+				{TaskID: "build", BlockedByID: "test"},
+			}, nil
+		},
+	}
+	svc := NewTaskDependencyService(repo, &mockTaskService{})
+
+	_, err := svc.GetTopologicalOrder(context.Background(), "user-1")
+	var appErr *apperror.AppError
+	if !errors.As(err, &appErr) || appErr.Code != "CONFLICT" {
+		t.Errorf("expected CONFLICT, got %v", err)
+	}
+}
+
+func TestTaskDependencyService_AddBlocker_Self(t *testing.T) {
+	svc := NewTaskDependencyService(&mockTaskRepository{}, &mockTaskService{})
+
+	err := svc.AddBlocker(context.Background(), "t1", "t1", "user-1")
+	var appErr *apperror.AppError
+	if !errors.As(err, &appErr) || appErr.Code != "BAD_REQUEST" {
+		t.Errorf("expected BAD_REQUEST, got %v", err)
+	}
+}
+
+func TestTaskService_UpdateTaskByID_BlockedCannotComplete(t *testing.T) {
+	updated := false
+	repo := &mockTaskRepository{
+		getByIDFn: func(ctx context.Context, id string) (*models.Task, error) {
+			return &models.Task{ID: id, UserID: "user-1", Status: models.TaskStatusTodo}, nil
+		},
+		updateFn: func(ctx context.Context, id string, task *models.Task) error {
+			updated = true
+			return nil
+		},
+		blockersFn: func(ctx context.Context, taskID string) ([]*models.Task, error) {
+			return []*models.Task{
+				{ID: "b1", Status: models.TaskStatusDone},
+				{ID: "b2", Status: models.TaskStatusInProgress},
+			}, nil
+		},
+	}
+	svc := NewTaskService(repo, &mockTaskCacheRepository{}, "app", models.TaskCacheOptions{Expiration: time.Minute})
+
+	err := svc.UpdateTaskByID(context.Background(), "t1", "user-1", &models.Task{Title: "Ship", Status: models.TaskStatusDone})
+	var appErr *apperror.AppError
+	if !errors.As(err, &appErr) || appErr.Code != "CONFLICT" {
+		t.Fatalf("expected CONFLICT, got %v", err)
+	}
+	if updated {
+		t.Error("blocked task must not be updated")
+	}
+
+	// other edits of a blocked task are still fine
+	if err := svc.UpdateTaskByID(context.Background(), "t1", "user-1", &models.Task{Title: "Ship it"}); err != nil {
+		t.Errorf("expected non-completing update to succeed, got %v", err)
+	}
+}
@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/metrics"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+)
+
+// list paging limits
+const (
+	defaultListLimit = 20
+	maxListLimit     = 100
+)
+
+// ListTasks one page of the user's tasks, oldest first
+// =========================================================================
+func (s *taskService) ListTasks(ctx context.Context, query *models.TaskListQuery) (*models.TaskPage, error) {
+	zerolog.Ctx(ctx).Debug().
+		Str("user_id", query.UserID).
+		Int("limit", query.Limit).
+		Bool("has_cursor", query.Cursor != "").
+		Msg("listing tasks")
+
+	if query.UserID == "" {
+		return nil, apperror.NewUnauthorizedError("not authenticated")
+	}
+	if query.Limit <= 0 {
+		query.Limit = defaultListLimit
+	}
+	if query.Limit > maxListLimit {
+		query.Limit = maxListLimit
+	}
+	after, err := decodeTaskCursor(query.Cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	// the cursor is a position, not a snapshot, so a page keyed by it stays
+	// valid until the next write bumps the generation
+	listKey := fmt.Sprintf("page:%d:%s", query.Limit, query.Cursor)
+	page, err := s.cachedTaskList(ctx, query.UserID, listKey, func() (*models.TaskPage, error) {
+		// one extra row tells whether there is a next page
+		tasks, err := s.taskRepo.ListTasksPage(ctx, query.UserID, after, query.Limit+1)
+		if err != nil {
+			return nil, err
+		}
+		page := &models.TaskPage{Tasks: tasks, Limit: query.Limit}
+		if len(tasks) > query.Limit {
+			page.Tasks = tasks[:query.Limit]
+			page.NextCursor = encodeTaskCursor(page.Tasks[query.Limit-1])
+		}
+		return page, nil
+	})
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("user_id", query.UserID).
+			Msg("failed to list tasks")
+		return nil, err
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("user_id", query.UserID).
+		Int("task_count", len(page.Tasks)).
+		Bool("has_more", page.NextCursor != "").
+		Msg("tasks listed successfully")
+	return page, nil
+}
+
+// cachedTaskList serve listKey from the user's current list generation, load
+// and cache it on a miss. A list read while a write bumps the generation is
+// cached under the old one and never served.
+func (s *taskService) cachedTaskList(ctx context.Context, userID string, listKey string, load func() (*models.TaskPage, error)) (*models.TaskPage, error) {
+	page, generation, err := s.taskCacheRepo.GetTaskList(ctx, userID, listKey)
+	metrics.ObserveCacheLookup("task_list", page != nil, err)
+	if page != nil {
+		return page, nil
+	}
+
+	page, loadErr := load()
+	if loadErr != nil {
+		return nil, loadErr
+	}
+	// without the generation the list could land under a stale one
+	if err == nil {
+		metrics.ObserveCacheWrite("task_list", "set",
+			s.taskCacheRepo.SetTaskList(ctx, userID, listKey, generation, page, s.cacheTTL()))
+	}
+	return page, nil
+}
+
+// invalidateTaskLists drop every cached list of the user, any write to one of
+// their tasks can move it between pages
+func invalidateTaskLists(ctx context.Context, taskCacheRepo ports.TaskCacheRepository, userID string) {
+	zerolog.Ctx(ctx).Debug().
+		Str("user_id", userID).
+		Msg("invalidating task lists")
+	metrics.ObserveCacheWrite("task_list", "invalidate", taskCacheRepo.InvalidateTaskLists(ctx, userID))
+}
+
+// encodeTaskCursor opaque position after task in (created_at, id) order
+func encodeTaskCursor(task *models.Task) string {
+	raw := task.CreatedAt.UTC().Format(time.RFC3339Nano) + "," + task.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// decodeTaskCursor nil for the first page
+func decodeTaskCursor(cursor string) (*models.Task, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, apperror.NewBadRequestError("invalid cursor")
+	}
+	createdAt, id, ok := strings.Cut(string(raw), ",")
+	if !ok || id == "" {
+		return nil, apperror.NewBadRequestError("invalid cursor")
+	}
+	t, err := time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return nil, apperror.NewBadRequestError("invalid cursor")
+	}
+	return &models.Task{ID: id, CreatedAt: t}, nil
+}
@@ -0,0 +1,159 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+)
+
+// listCache generation-keyed lists in memory, like the Redis implementation
+func listCache() *mockTaskCacheRepository {
+	generations := map[string]int64{}
+	lists := map[string]*models.TaskPage{}
+	listKey := func(userID string, generation int64, query string) string {
+		return fmt.Sprintf("%s:%d:%s", userID, generation, query)
+	}
+	return &mockTaskCacheRepository{
+		getListFn: func(ctx context.Context, userID string, query string) (*models.TaskPage, int64, error) {
+			generation := generations[userID]
+			return lists[listKey(userID, generation, query)], generation, nil
+		},
+		setListFn: func(ctx context.Context, userID string, query string, generation int64, page *models.TaskPage, exp time.Duration) error {
+			lists[listKey(userID, generation, query)] = page
+			return nil
+		},
+		invalidateListFn: func(ctx context.Context, userID string) error {
+			generations[userID]++
+			return nil
+		},
+	}
+}
+
+func TestTaskService_ListTasks_Paging(t *testing.T) {
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	all := make([]*models.Task, 5)
+	for i := range all {
+		all[i] = &models.Task{ID: fmt.Sprintf("t%d", i), UserID: "user-1", CreatedAt: base.Add(time.Duration(i) * time.Minute)}
+	}
+	repo := &mockTaskRepository{
+		listPageFn: func(ctx context.Context, userID string, after *models.Task, limit int) ([]*models.Task, error) {
+			start := 0
+			if after != nil {
+				for start < len(all) && !all[start].CreatedAt.After(after.CreatedAt) {
+					start++
+				}
+			}
+			return all[start:min(start+limit, len(all))], nil
+		},
+	}
+	svc := NewTaskService(repo, &mockTaskCacheRepository{}, "app", models.TaskCacheOptions{Expiration: time.Minute})
+
+	var ids []string
+	cursor := ""
+	for pages := 0; ; pages++ {
+		if pages == 3 {
+			t.Fatal("expected paging to end after 3 pages")
+		}
+		page, err := svc.ListTasks(context.Background(), &models.TaskListQuery{UserID: "user-1", Limit: 2, Cursor: cursor})
+		if err != nil {
+			t.Fatalf("ListTasks failed: %v", err)
+		}
+		for _, task := range page.Tasks {
+			ids = append(ids, task.ID)
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		cursor = page.NextCursor
+	}
+	if fmt.Sprint(ids) != "[t0 t1 t2 t3 t4]" {
+		t.Errorf("expected every task once in order, got %v", ids)
+	}
+}
+
+func TestTaskService_ListTasks_Cached(t *testing.T) {
+	loads := 0
+	repo := &mockTaskRepository{
+		listPageFn: func(ctx context.Context, userID string, after *models.Task, limit int) ([]*models.Task, error) {
+			loads++
+			return []*models.Task{{ID: "t1", UserID: userID}}, nil
+		},
+		createFn: func(ctx context.Context, task *models.Task) (string, error) {
+			return "t2", nil
+		},
+	}
+	svc := NewTaskService(repo, listCache(), "app", models.TaskCacheOptions{Expiration: time.Minute})
+	query := func(userID string) *models.TaskListQuery {
+		return &models.TaskListQuery{UserID: userID, Limit: 10}
+	}
+
+	for range 2 {
+		if _, err := svc.ListTasks(context.Background(), query("user-1")); err != nil {
+			t.Fatalf("ListTasks failed: %v", err)
+		}
+	}
+	if loads != 1 {
+		t.Fatalf("expected the second read to hit the cache, got %d loads", loads)
+	}
+
+	// another user's list is cached separately
+	if _, err := svc.ListTasks(context.Background(), query("user-2")); err != nil {
+		t.Fatalf("ListTasks failed: %v", err)
+	}
+	if loads != 2 {
+		t.Fatalf("expected a separate list per user, got %d loads", loads)
+	}
+
+	// a write orphans every list of the writer only
+	if _, err := svc.CreateTask(context.Background(), &models.Task{UserID: "user-1", Title: "New"}); err != nil {
+		t.Fatalf("CreateTask failed: %v", err)
+	}
+	svc.ListTasks(context.Background(), query("user-1"))
+	svc.ListTasks(context.Background(), query("user-2"))
+	if loads != 3 {
+		t.Errorf("expected only user-1 to reload after the write, got %d loads", loads)
+	}
+}
+
+func TestTaskService_ListTasks_CacheDown(t *testing.T) {
+	cache := &mockTaskCacheRepository{
+		getListFn: func(ctx context.Context, userID string, query string) (*models.TaskPage, int64, error) {
+			return nil, 0, errors.New("redis down")
+		},
+		setListFn: func(ctx context.Context, userID string, query string, generation int64, page *models.TaskPage, exp time.Duration) error {
+			t.Error("a list must not be cached without its generation")
+			return nil
+		},
+	}
+	repo := &mockTaskRepository{
+		listPageFn: func(ctx context.Context, userID string, after *models.Task, limit int) ([]*models.Task, error) {
+			return []*models.Task{{ID: "t1"}}, nil
+		},
+	}
+	svc := NewTaskService(repo, cache, "app", models.TaskCacheOptions{Expiration: time.Minute})
+
+	page, err := svc.ListTasks(context.Background(), &models.TaskListQuery{UserID: "user-1"})
+	if err != nil {
+		t.Fatalf("expected Postgres fallback, got %v", err)
+	}
+	if len(page.Tasks) != 1 || page.Limit != defaultListLimit {
+		t.Errorf("unexpected page %+v", page)
+	}
+}
+
+func TestTaskService_ListTasks_InvalidCursor(t *testing.T) {
+	svc := NewTaskService(&mockTaskRepository{}, &mockTaskCacheRepository{}, "app", models.TaskCacheOptions{Expiration: time.Minute})
+
+	for _, cursor := range []string{"%%%", "bm8tY29tbWE", "eWVzdGVyZGF5LHQx"} {
+		_, err := svc.ListTasks(context.Background(), &models.TaskListQuery{UserID: "user-1", Cursor: cursor})
+		var appErr *apperror.AppError
+		if !errors.As(err, &appErr) || appErr.Code != "BAD_REQUEST" {
+			t.Errorf("cursor %q: expected BAD_REQUEST, got %v", cursor, err)
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/logger"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+)
+
+type taskQuotaService struct {
+	taskRepo ports.TaskRepository
+	quota    models.TaskQuota
+}
+
+// NewTaskQuotaService creates a new task quota service instance
+// =========================================================================
+func NewTaskQuotaService(taskRepo ports.TaskRepository, quota models.TaskQuota) ports.TaskQuotaService {
+	logger.Log.Info().
+		Int("max_tasks", quota.MaxTasks).
+		Int64("max_content_bytes", quota.MaxContentBytes).
+		Msg("initializing task quota service")
+	return &taskQuotaService{
+		taskRepo: taskRepo,
+		quota:    quota,
+	}
+}
+
+// GetUsage current usage of the user
+// =========================================================================
+func (s *taskQuotaService) GetUsage(ctx context.Context, userID string) (*models.TaskUsage, error) {
+	return s.taskRepo.GetTaskUsage(ctx, userID)
+}
+
+// CheckQuota compare usage plus the additions against the quotas
+// =========================================================================
+func (s *taskQuotaService) CheckQuota(ctx context.Context, userID string, addTasks int, addBytes int64) error {
+	checkTasks := s.quota.MaxTasks > 0 && addTasks > 0
+	checkBytes := s.quota.MaxContentBytes > 0 && addBytes > 0
+	if !checkTasks && !checkBytes {
+		return nil
+	}
+
+	usage, err := s.taskRepo.GetTaskUsage(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	if msg := s.quota.Exceeded(usage, addTasks, addBytes); msg != "" {
+		zerolog.Ctx(ctx).Warn().
+			Str("user_id", userID).
+			Int("tasks", usage.Tasks).
+			Int64("content_bytes", usage.ContentBytes).
+			Int("adding_tasks", addTasks).
+			Int64("adding_bytes", addBytes).
+			Msg("quota exceeded")
+		return apperror.NewQuotaExceededError(msg)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+)
+
+func quotaRepo(tasks int, contentBytes int64) *mockTaskRepository {
+	return &mockTaskRepository{
+		usageFn: func(ctx context.Context, userID string) (*models.TaskUsage, error) {
+			return &models.TaskUsage{Tasks: tasks, ContentBytes: contentBytes}, nil
+		},
+	}
+}
+
+func requireQuotaExceeded(t *testing.T, err error) {
+	t.Helper()
+	var appErr *apperror.AppError
+	if !errors.As(err, &appErr) || appErr.Code != "QUOTA_EXCEEDED" {
+		t.Fatalf("expected QUOTA_EXCEEDED, got %v", err)
+	}
+}
+
+func TestTaskQuotaService_CheckQuota(t *testing.T) {
+	svc := NewTaskQuotaService(quotaRepo(9, 900), models.TaskQuota{MaxTasks: 10, MaxContentBytes: 1000})
+	ctx := context.Background()
+
+	if err := svc.CheckQuota(ctx, "user-1", 1, 100); err != nil {
+		t.Errorf("filling the quota exactly must pass, got %v", err)
+	}
+	requireQuotaExceeded(t, svc.CheckQuota(ctx, "user-1", 2, 0))
+	requireQuotaExceeded(t, svc.CheckQuota(ctx, "user-1", 0, 101))
+
+	// shrinking never fails, even above the quota
+	over := NewTaskQuotaService(quotaRepo(20, 5000), models.TaskQuota{MaxTasks: 10, MaxContentBytes: 1000})
+	if err := over.CheckQuota(ctx, "user-1", 0, -50); err != nil {
+		t.Errorf("expected shrinking to pass, got %v", err)
+	}
+
+	unlimited := NewTaskQuotaService(quotaRepo(1<<20, 1<<40), models.TaskQuota{})
+	if err := unlimited.CheckQuota(ctx, "user-1", 1, 1<<20); err != nil {
+		t.Errorf("zero quotas must be unlimited, got %v", err)
+	}
+}
+
+func TestTaskBatchService_AtomicQuotaExceeded(t *testing.T) {
+	repo := quotaRepo(9, 0)
+	repo.batchFn = func(ctx context.Context, ops []*models.TaskBatchOp, atomic bool) ([]error, error) {
+		t.Error("batch over quota must not be applied")
+		return nil, errors.New("unexpected write")
+	}
+	svc := NewTaskBatchService(repo, &mockRecurrenceService{}, &mockTaskCacheRepository{}, NewTaskQuotaService(repo, models.TaskQuota{MaxTasks: 10}), "app")
+
+	_, err := svc.BatchTasks(context.Background(), "user-1", []*models.TaskBatchOp{
+		{Op: models.BatchOpCreate, Title: "One"},
+		{Op: models.BatchOpCreate, Title: "Two"},
+	}, true)
+	requireQuotaExceeded(t, err)
+}
@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+)
+
+func TestSearchTerms(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"Quarterly Report", []string{"quarterly", "report"}},
+		{"rep & !draft | (x:*)", []string{"rep", "draft", "x"}},
+		{"  café déjà-vu  ", []string{"café", "déjà", "vu"}},
+		{"report report", []string{"report"}},
+		{"&|!():*", []string{}},
+	}
+	for _, tt := range tests {
+		if got := searchTerms(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("searchTerms(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestTaskService_SearchTasks(t *testing.T) {
+	var got *models.TaskSearchQuery
+	repo := &mockTaskRepository{
+		searchFn: func(ctx context.Context, query *models.TaskSearchQuery) (*models.TaskSearchResult, error) {
+			got = query
+			return &models.TaskSearchResult{Hits: []*models.TaskSearchHit{}, Limit: query.Limit}, nil
+		},
+	}
+	svc := NewTaskService(repo, &mockTaskCacheRepository{}, "app", models.TaskCacheOptions{Expiration: time.Minute})
+
+	_, err := svc.SearchTasks(context.Background(), &models.TaskSearchQuery{UserID: "user-1", Query: "Ship it!", Labels: []string{" Work", "urgent", "work"}, Limit: 1000, Offset: -5})
+	if err != nil {
+		t.Fatalf("SearchTasks failed: %v", err)
+	}
+	if !reflect.DeepEqual(got.Terms, []string{"ship", "it"}) {
+		t.Errorf("unexpected terms %v", got.Terms)
+	}
+	if !reflect.DeepEqual(got.Labels, []string{"work", "urgent"}) {
+		t.Errorf("unexpected labels %v", got.Labels)
+	}
+	if got.Limit != maxSearchLimit || got.Offset != 0 {
+		t.Errorf("expected limit %d offset 0, got %d %d", maxSearchLimit, got.Limit, got.Offset)
+	}
+}
+
+func TestTaskService_SearchTasks_EmptyQuery(t *testing.T) {
+	svc := NewTaskService(&mockTaskRepository{}, &mockTaskCacheRepository{}, "app", models.TaskCacheOptions{Expiration: time.Minute})
+
+	_, err := svc.SearchTasks(context.Background(), &models.TaskSearchQuery{UserID: "user-1", Query: " *:& "})
+	var appErr *apperror.AppError
+	if !errors.As(err, &appErr) || appErr.Code != "BAD_REQUEST" {
+		t.Errorf("expected BAD_REQUEST, got %v", err)
+	}
+}
+
+func TestNormalizeLabels(t *testing.T) {
+	if labels, err := normalizeLabels(nil); labels != nil || err != nil {
+		t.Errorf("nil labels must stay nil, got %v %v", labels, err)
+	}
+	if labels, err := normalizeLabels([]string{}); labels == nil || len(labels) != 0 || err != nil {
+		t.Errorf("empty labels must stay empty so they clear the task's labels, got %v %v", labels, err)
+	}
+	if labels, _ := normalizeLabels([]string{" Home ", "", "home", "ERRANDS"}); !reflect.DeepEqual(labels, []string{"home", "errands"}) {
+		t.Errorf("unexpected labels %v", labels)
+	}
+
+	tooMany := make([]string, maxTaskLabels+1)
+	for i := range tooMany {
+		tooMany[i] = string(rune('a' + i))
+	}
+	for _, labels := range [][]string{tooMany, {"a,b"}, {strings.Repeat("x", maxLabelLength+1)}} {
+		_, err := normalizeLabels(labels)
+		var appErr *apperror.AppError
+		if !errors.As(err, &appErr) || appErr.Code != "BAD_REQUEST" {
+			t.Errorf("normalizeLabels(%q): expected BAD_REQUEST, got %v", labels, err)
+		}
+	}
+}
@@ -2,79 +2,123 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
+	"math/rand/v2"
+	"strings"
+	"sync/atomic"
 	"time"
+	"unicode"
 
+	"github.com/rs/zerolog"
 	"github.com/suryansh74/task-management-api-project/internal/apperror"
 	"github.com/suryansh74/task-management-api-project/internal/logger"
+	"github.com/suryansh74/task-management-api-project/internal/metrics"
 	"github.com/suryansh74/task-management-api-project/internal/models"
 	"github.com/suryansh74/task-management-api-project/internal/ports"
+	"golang.org/x/sync/singleflight"
+)
+
+// search paging and query limits
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 100
+	maxSearchTerms     = 10
+)
+
+// label limits, a comma would clash with the labels=a,b search filter
+const (
+	maxTaskLabels  = 10
+	maxLabelLength = 30
 )
 
 type taskService struct {
-	taskRepo        ports.TaskRepository
-	taskCacheRepo   ports.TaskCacheRepository
-	redisAppName    string
-	cacheExpiration time.Duration
+	taskRepo      ports.TaskRepository
+	taskCacheRepo ports.TaskCacheRepository
+	redisAppName  string
+	cache         models.TaskCacheOptions
+
+	// concurrent misses of one key share a single Postgres read
+	loads singleflight.Group
+	// duration of the latest Postgres read, the recompute cost for early refresh
+	loadCost atomic.Int64
 }
 
 // NewTaskService creates a new user session service instance
 // =========================================================================
-func NewTaskService(taskRepo ports.TaskRepository, taskCacheRepo ports.TaskCacheRepository, redisAppName string, cacheExpiration time.Duration) ports.TaskService {
+func NewTaskService(taskRepo ports.TaskRepository, taskCacheRepo ports.TaskCacheRepository, redisAppName string, cache models.TaskCacheOptions) ports.TaskService {
 	logger.Log.Info().
 		Str("redis_app_name", redisAppName).
-		Dur("cache_expiration", cacheExpiration).
+		Dur("cache_expiration", cache.Expiration).
+		Dur("cache_negative_expiration", cache.NegativeExpiration).
+		Float64("cache_ttl_jitter", cache.Jitter).
+		Float64("cache_early_refresh_beta", cache.EarlyRefreshBeta).
 		Msg("initializing task service")
 	return &taskService{
-		taskRepo:        taskRepo,
-		taskCacheRepo:   taskCacheRepo,
-		redisAppName:    redisAppName,
-		cacheExpiration: cacheExpiration,
+		taskRepo:      taskRepo,
+		taskCacheRepo: taskCacheRepo,
+		redisAppName:  redisAppName,
+		cache:         cache,
 	}
 }
 
 // GetTasks get all tasks
 // =========================================================================
 func (s *taskService) GetTasks(ctx context.Context, userID string) ([]*models.Task, error) {
-	logger.Log.Debug().
+	zerolog.Ctx(ctx).Debug().
 		Str("user_id", userID).
 		Msg("fetching all tasks for user")
 
-	tasks, err := s.taskRepo.GetAllTasks(ctx, userID)
+	page, err := s.cachedTaskList(ctx, userID, "all", func() (*models.TaskPage, error) {
+		tasks, err := s.taskRepo.GetAllTasks(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		return &models.TaskPage{Tasks: tasks}, nil
+	})
 	if err != nil {
-		logger.Log.Error().
+		zerolog.Ctx(ctx).Error().
 			Err(err).
 			Str("user_id", userID).
 			Msg("failed to fetch tasks")
 		return nil, err
 	}
 
-	logger.Log.Info().
+	zerolog.Ctx(ctx).Info().
 		Str("user_id", userID).
-		Int("task_count", len(tasks)).
+		Int("task_count", len(page.Tasks)).
 		Msg("tasks fetched successfully")
-	return tasks, nil
+	return page.Tasks, nil
 }
 
 // CreateTask get all tasks
 // =========================================================================
 func (s *taskService) CreateTask(ctx context.Context, task *models.Task) (string, error) {
-	logger.Log.Debug().
+	zerolog.Ctx(ctx).Debug().
 		Str("user_id", task.UserID).
 		Str("title", task.Title).
 		Msg("creating new task")
 
+	labels, err := normalizeLabels(task.Labels)
+	if err != nil {
+		return "", err
+	}
+	task.Labels = labels
+
 	id, err := s.taskRepo.CreateTask(ctx, task)
 	if err != nil {
-		logger.Log.Error().
+		zerolog.Ctx(ctx).Error().
 			Err(err).
 			Str("user_id", task.UserID).
 			Str("title", task.Title).
 			Msg("failed to create task")
 		return "", err
 	}
+	metrics.TasksCreated.WithLabelValues("single").Inc()
+	invalidateTaskLists(ctx, s.taskCacheRepo, task.UserID)
 
-	logger.Log.Info().
+	zerolog.Ctx(ctx).Info().
 		Str("task_id", id).
 		Str("user_id", task.UserID).
 		Str("title", task.Title).
@@ -85,15 +129,15 @@ func (s *taskService) CreateTask(ctx context.Context, task *models.Task) (string
 // GetTaskByID get tasks
 // =========================================================================
 func (s *taskService) GetTaskByID(ctx context.Context, taskID string, userID string) (*models.Task, error) {
-	logger.Log.Debug().
+	zerolog.Ctx(ctx).Debug().
 		Str("task_id", taskID).
 		Str("user_id", userID).
 		Msg("fetching task by id")
 
-	// check policy
-	_, err := s.mustBeOwner(ctx, userID, taskID)
+	// check policy, the owner check already loads the task through the cache
+	task, err := s.mustBeOwner(ctx, userID, taskID)
 	if err != nil {
-		logger.Log.Warn().
+		zerolog.Ctx(ctx).Warn().
 			Err(err).
 			Str("task_id", taskID).
 			Str("user_id", userID).
@@ -101,65 +145,26 @@ func (s *taskService) GetTaskByID(ctx context.Context, taskID string, userID str
 		return nil, err
 	}
 
-	// first get from cache
-	key := fmt.Sprintf("%s:cache:task:%s", s.redisAppName, taskID)
-	logger.Log.Debug().
-		Str("cache_key", key).
-		Str("task_id", taskID).
-		Msg("checking cache for task")
-
-	task, _ := s.taskCacheRepo.GetTaskByID(ctx, key)
-	if task != nil {
-		logger.Log.Info().
-			Str("task_id", taskID).
-			Str("user_id", userID).
-			Str("cache_key", key).
-			Msg("task retrieved from cache")
-		return task, nil
-	}
-
-	// if not exist then from db
-	logger.Log.Debug().
-		Str("task_id", taskID).
-		Msg("cache miss, fetching from database")
-
-	task, err = s.taskRepo.GetTaskByID(ctx, taskID)
-	if err != nil {
-		logger.Log.Error().
-			Err(err).
-			Str("task_id", taskID).
-			Str("user_id", userID).
-			Msg("failed to fetch task from database")
-		return nil, err
-	}
-
-	// set in cache
-	logger.Log.Debug().
-		Str("cache_key", key).
-		Str("task_id", taskID).
-		Msg("caching task for future requests")
-	s.taskCacheRepo.SetTask(ctx, task, key, s.cacheExpiration)
-
-	logger.Log.Info().
+	zerolog.Ctx(ctx).Info().
 		Str("task_id", taskID).
 		Str("user_id", userID).
-		Msg("task retrieved successfully from database")
+		Msg("task retrieved successfully")
 	return task, nil
 }
 
 // UpdateTaskByID update tasks
 // =========================================================================
 func (s *taskService) UpdateTaskByID(ctx context.Context, taskID string, userID string, task *models.Task) error {
-	logger.Log.Debug().
+	zerolog.Ctx(ctx).Debug().
 		Str("task_id", taskID).
 		Str("user_id", userID).
 		Str("title", task.Title).
 		Msg("updating task")
 
 	// check policy
-	_, err := s.mustBeOwner(ctx, userID, taskID)
+	current, err := s.mustBeOwner(ctx, userID, taskID)
 	if err != nil {
-		logger.Log.Warn().
+		zerolog.Ctx(ctx).Warn().
 			Err(err).
 			Str("task_id", taskID).
 			Str("user_id", userID).
@@ -167,10 +172,21 @@ func (s *taskService) UpdateTaskByID(ctx context.Context, taskID string, userID
 		return err
 	}
 
+	// a task can't be completed while anything blocking it is still open
+	if task.Status == models.TaskStatusDone && current.Status != models.TaskStatusDone {
+		if err := s.mustHaveNoOpenBlockers(ctx, taskID); err != nil {
+			return err
+		}
+	}
+
+	if task.Labels, err = normalizeLabels(task.Labels); err != nil {
+		return err
+	}
+
 	key := fmt.Sprintf("%s:cache:task:%s", s.redisAppName, taskID)
 	err = s.taskRepo.UpdateTaskByID(ctx, taskID, task)
 	if err != nil {
-		logger.Log.Error().
+		zerolog.Ctx(ctx).Error().
 			Err(err).
 			Str("task_id", taskID).
 			Str("user_id", userID).
@@ -179,13 +195,14 @@ func (s *taskService) UpdateTaskByID(ctx context.Context, taskID string, userID
 	}
 
 	// invalidate cache
-	logger.Log.Debug().
+	zerolog.Ctx(ctx).Debug().
 		Str("cache_key", key).
 		Str("task_id", taskID).
 		Msg("invalidating task cache")
-	s.taskCacheRepo.DeleteTaskByID(ctx, key)
+	metrics.ObserveCacheWrite("task", "delete", s.taskCacheRepo.DeleteTaskByID(ctx, key))
+	invalidateTaskLists(ctx, s.taskCacheRepo, userID)
 
-	logger.Log.Info().
+	zerolog.Ctx(ctx).Info().
 		Str("task_id", taskID).
 		Str("user_id", userID).
 		Str("title", task.Title).
@@ -196,7 +213,7 @@ func (s *taskService) UpdateTaskByID(ctx context.Context, taskID string, userID
 // DeleteTaskByID delete tasks
 // =========================================================================
 func (s *taskService) DeleteTaskByID(ctx context.Context, taskID string, userID string) error {
-	logger.Log.Debug().
+	zerolog.Ctx(ctx).Debug().
 		Str("task_id", taskID).
 		Str("user_id", userID).
 		Msg("deleting task")
@@ -204,7 +221,7 @@ func (s *taskService) DeleteTaskByID(ctx context.Context, taskID string, userID
 	// check policy
 	_, err := s.mustBeOwner(ctx, userID, taskID)
 	if err != nil {
-		logger.Log.Warn().
+		zerolog.Ctx(ctx).Warn().
 			Err(err).
 			Str("task_id", taskID).
 			Str("user_id", userID).
@@ -215,7 +232,7 @@ func (s *taskService) DeleteTaskByID(ctx context.Context, taskID string, userID
 	key := fmt.Sprintf("%s:cache:task:%s", s.redisAppName, taskID)
 	err = s.taskRepo.DeleteTaskByID(ctx, taskID)
 	if err != nil {
-		logger.Log.Error().
+		zerolog.Ctx(ctx).Error().
 			Err(err).
 			Str("task_id", taskID).
 			Str("user_id", userID).
@@ -223,32 +240,129 @@ func (s *taskService) DeleteTaskByID(ctx context.Context, taskID string, userID
 		return err
 	}
 
-	logger.Log.Debug().
+	zerolog.Ctx(ctx).Debug().
 		Str("cache_key", key).
 		Str("task_id", taskID).
 		Msg("removing task from cache")
-	s.taskCacheRepo.DeleteTaskByID(ctx, key)
+	metrics.ObserveCacheWrite("task", "delete", s.taskCacheRepo.DeleteTaskByID(ctx, key))
+	invalidateTaskLists(ctx, s.taskCacheRepo, userID)
 
-	logger.Log.Info().
+	zerolog.Ctx(ctx).Info().
 		Str("task_id", taskID).
 		Str("user_id", userID).
 		Msg("task deleted successfully")
 	return nil
 }
 
+// SearchTasks full-text search over the user's tasks
+// =========================================================================
+func (s *taskService) SearchTasks(ctx context.Context, query *models.TaskSearchQuery) (*models.TaskSearchResult, error) {
+	zerolog.Ctx(ctx).Debug().
+		Str("user_id", query.UserID).
+		Str("query", query.Query).
+		Msg("searching tasks")
+
+	if query.UserID == "" {
+		return nil, apperror.NewUnauthorizedError("not authenticated")
+	}
+
+	query.Terms = searchTerms(query.Query)
+	if len(query.Terms) == 0 {
+		return nil, apperror.NewBadRequestError("search query must contain at least one word")
+	}
+	if query.Limit <= 0 {
+		query.Limit = defaultSearchLimit
+	}
+	if query.Limit > maxSearchLimit {
+		query.Limit = maxSearchLimit
+	}
+	if query.Offset < 0 {
+		query.Offset = 0
+	}
+	labels, err := normalizeLabels(query.Labels)
+	if err != nil {
+		return nil, err
+	}
+	query.Labels = labels
+
+	result, err := s.taskRepo.SearchTasks(ctx, query)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("user_id", query.UserID).
+			Msg("failed to search tasks")
+		return nil, err
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("user_id", query.UserID).
+		Int("hit_count", len(result.Hits)).
+		Int("total", result.Total).
+		Msg("tasks searched successfully")
+	return result, nil
+}
+
+// normalizeLabels trim, lowercase and dedupe labels, nil stays nil so an
+// update without labels keeps the stored ones
+func normalizeLabels(labels []string) ([]string, error) {
+	if labels == nil {
+		return nil, nil
+	}
+
+	seen := make(map[string]bool, len(labels))
+	normalized := make([]string, 0, len(labels))
+	for _, label := range labels {
+		label = strings.ToLower(strings.TrimSpace(label))
+		if label == "" || seen[label] {
+			continue
+		}
+		if len(label) > maxLabelLength || strings.Contains(label, ",") {
+			return nil, apperror.NewBadRequestError(fmt.Sprintf("labels must be at most %d characters and can't contain commas", maxLabelLength))
+		}
+		seen[label] = true
+		normalized = append(normalized, label)
+	}
+	if len(normalized) > maxTaskLabels {
+		return nil, apperror.NewBadRequestError(fmt.Sprintf("a task can have at most %d labels", maxTaskLabels))
+	}
+	return normalized, nil
+}
+
+// searchTerms split free text into lowercase words, tsquery operators and
+// punctuation are dropped so user input can never change the query shape
+func searchTerms(q string) []string {
+	words := strings.FieldsFunc(strings.ToLower(q), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	seen := make(map[string]bool, len(words))
+	terms := make([]string, 0, len(words))
+	for _, w := range words {
+		if seen[w] {
+			continue
+		}
+		seen[w] = true
+		terms = append(terms, w)
+		if len(terms) == maxSearchTerms {
+			break
+		}
+	}
+	return terms
+}
+
 // mustBeOwner helper function to check ownership
 // =========================================================================
 func (s *taskService) mustBeOwner(
 	ctx context.Context,
 	userID, taskID string,
 ) (*models.Task, error) {
-	logger.Log.Debug().
+	zerolog.Ctx(ctx).Debug().
 		Str("user_id", userID).
 		Str("task_id", taskID).
 		Msg("validating task ownership")
 
 	if userID == "" {
-		logger.Log.Warn().
+		zerolog.Ctx(ctx).Warn().
 			Str("task_id", taskID).
 			Msg("unauthenticated access attempt")
 		return nil, apperror.NewUnauthorizedError("not authenticated")
@@ -256,7 +370,7 @@ func (s *taskService) mustBeOwner(
 
 	task, err := s.getTaskByIDHelper(ctx, taskID)
 	if err != nil {
-		logger.Log.Warn().
+		zerolog.Ctx(ctx).Warn().
 			Err(err).
 			Str("user_id", userID).
 			Str("task_id", taskID).
@@ -265,7 +379,7 @@ func (s *taskService) mustBeOwner(
 	}
 
 	if task.UserID != userID {
-		logger.Log.Warn().
+		zerolog.Ctx(ctx).Warn().
 			Str("user_id", userID).
 			Str("task_id", taskID).
 			Str("task_owner_id", task.UserID).
@@ -273,54 +387,137 @@ func (s *taskService) mustBeOwner(
 		return nil, apperror.NewForbiddenError("not allowed")
 	}
 
-	logger.Log.Debug().
+	zerolog.Ctx(ctx).Debug().
 		Str("user_id", userID).
 		Str("task_id", taskID).
 		Msg("ownership validation successful")
 	return task, nil
 }
 
+// mustHaveNoOpenBlockers helper function to stop completing blocked tasks
+// =========================================================================
+func (s *taskService) mustHaveNoOpenBlockers(ctx context.Context, taskID string) error {
+	blockers, err := s.taskRepo.GetBlockers(ctx, taskID)
+	if err != nil {
+		zerolog.Ctx(ctx).Error().
+			Err(err).
+			Str("task_id", taskID).
+			Msg("failed to fetch blockers")
+		return err
+	}
+
+	open := 0
+	for _, blocker := range blockers {
+		if blocker.Status != models.TaskStatusDone {
+			open++
+		}
+	}
+	if open > 0 {
+		zerolog.Ctx(ctx).Warn().
+			Str("task_id", taskID).
+			Int("open_blockers", open).
+			Msg("task completion rejected: blocked by open tasks")
+		return apperror.NewConflictError(fmt.Sprintf("task is blocked by %d open task(s)", open))
+	}
+	return nil
+}
+
 // getTaskByIDHelper helper function to get task by id without checking ownership
+// read-through: cache (including cached misses), else one coalesced Postgres read per key
 // =========================================================================
 func (s *taskService) getTaskByIDHelper(ctx context.Context, id string) (*models.Task, error) {
-	logger.Log.Debug().
+	zerolog.Ctx(ctx).Debug().
 		Str("task_id", id).
 		Msg("fetching task by id (helper)")
 
-	// first get from cache
 	key := fmt.Sprintf("%s:cache:task:%s", s.redisAppName, id)
-	task, _ := s.taskCacheRepo.GetTaskByID(ctx, key)
-	if task != nil {
-		logger.Log.Debug().
+	entry, err := s.taskCacheRepo.GetTaskEntry(ctx, key)
+	metrics.ObserveCacheLookup("task", entry != nil, err)
+	if entry != nil {
+		if entry.NotFound {
+			return nil, apperror.NewNotFoundError("task not found")
+		}
+		if !s.refreshEarly(entry.TTL) {
+			zerolog.Ctx(ctx).Debug().
+				Str("task_id", id).
+				Str("cache_key", key).
+				Msg("task found in cache (helper)")
+			return entry.Task, nil
+		}
+		zerolog.Ctx(ctx).Debug().
+			Str("task_id", id).
+			Dur("ttl", entry.TTL).
+			Msg("refreshing cached task early (helper)")
+	} else {
+		zerolog.Ctx(ctx).Debug().
 			Str("task_id", id).
-			Str("cache_key", key).
-			Msg("task found in cache (helper)")
-		return task, nil
+			Msg("cache miss, fetching from database (helper)")
+	}
+
+	// the shared read must not fail for everyone when its first caller goes away
+	loadCtx := context.WithoutCancel(ctx)
+	loaded, err, shared := s.loads.Do(key, func() (any, error) {
+		return s.loadTask(loadCtx, id, key)
+	})
+	if err != nil {
+		if entry != nil && entry.Task != nil {
+			// early refresh failed, the cached copy is still valid
+			return entry.Task, nil
+		}
+		return nil, err
 	}
 
-	// if not exist then from db
-	logger.Log.Debug().
+	// each caller gets its own copy, callers may modify what they receive
+	task := *loaded.(*models.Task)
+	zerolog.Ctx(ctx).Debug().
 		Str("task_id", id).
-		Msg("cache miss, fetching from database (helper)")
+		Bool("shared", shared).
+		Msg("task retrieved successfully (helper)")
+	return &task, nil
+}
 
+// loadTask read the task from Postgres and cache the result, a missing task
+// is cached for the short negative TTL
+func (s *taskService) loadTask(ctx context.Context, id string, key string) (*models.Task, error) {
+	start := time.Now()
 	task, err := s.taskRepo.GetTaskByID(ctx, id)
+	s.loadCost.Store(int64(time.Since(start)))
 	if err != nil {
-		logger.Log.Error().
+		var appErr *apperror.AppError
+		if errors.As(err, &appErr) && appErr.Code == "NOT_FOUND" && s.cache.NegativeExpiration > 0 {
+			metrics.ObserveCacheWrite("task", "set", s.taskCacheRepo.SetNotFound(ctx, key, s.cache.NegativeExpiration))
+		}
+		zerolog.Ctx(ctx).Error().
 			Err(err).
 			Str("task_id", id).
 			Msg("failed to fetch task from database (helper)")
 		return nil, err
 	}
 
-	// set in cache
-	logger.Log.Debug().
+	zerolog.Ctx(ctx).Debug().
 		Str("cache_key", key).
 		Str("task_id", id).
 		Msg("caching task (helper)")
-	s.taskCacheRepo.SetTask(ctx, task, key, s.cacheExpiration)
-
-	logger.Log.Debug().
-		Str("task_id", id).
-		Msg("task retrieved successfully (helper)")
+	metrics.ObserveCacheWrite("task", "set", s.taskCacheRepo.SetTask(ctx, task, key, s.cacheTTL()))
 	return task, nil
 }
+
+// cacheTTL expiration spread by ±Jitter so tasks cached together don't expire together
+func (s *taskService) cacheTTL() time.Duration {
+	if s.cache.Jitter <= 0 {
+		return s.cache.Expiration
+	}
+	spread := float64(s.cache.Expiration) * s.cache.Jitter
+	return s.cache.Expiration + time.Duration((rand.Float64()*2-1)*spread)
+}
+
+// refreshEarly XFetch: reload before expiry with a probability that grows as the
+// TTL runs out and with the cost of a reload, so one caller refreshes a hot key
+// while everyone else is still served from cache
+func (s *taskService) refreshEarly(ttl time.Duration) bool {
+	if s.cache.EarlyRefreshBeta <= 0 || ttl < 0 {
+		return false
+	}
+	cost := float64(s.loadCost.Load())
+	return -cost*s.cache.EarlyRefreshBeta*math.Log(rand.Float64()) >= float64(ttl)
+}
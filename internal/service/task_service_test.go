@@ -22,6 +22,15 @@ type mockTaskRepository struct {
 	createFn     func(ctx context.Context, task *models.Task) (string, error)
 	updateFn     func(ctx context.Context, id string, task *models.Task) error
 	deleteFn     func(ctx context.Context, id string) error
+	blockersFn   func(ctx context.Context, taskID string) ([]*models.Task, error)
+	edgesFn      func(ctx context.Context, userID string) ([]*models.TaskDependency, error)
+	searchFn     func(ctx context.Context, query *models.TaskSearchQuery) (*models.TaskSearchResult, error)
+	batchFn      func(ctx context.Context, ops []*models.TaskBatchOp, atomic bool) ([]error, error)
+	listPageFn   func(ctx context.Context, userID string, after *models.Task, limit int) ([]*models.Task, error)
+	externalIDsFn func(ctx context.Context, userID string, externalIDs []string) (map[string]string, error)
+	importFn     func(ctx context.Context, tasks []*models.Task, upsert bool) ([]bool, []error, error)
+	dueFn        func(ctx context.Context, userID string) ([]*models.Task, error)
+	usageFn      func(ctx context.Context, userID string) (*models.TaskUsage, error)
 }
 
 func (m *mockTaskRepository) GetAllTasks(ctx context.Context, userID string) ([]*models.Task, error) {
@@ -54,11 +63,80 @@ func (m *mockTaskRepository) DeleteTaskByID(ctx context.Context, id string) erro
 	}
 	return errors.New("not implemented")
 }
+func (m *mockTaskRepository) SearchTasks(ctx context.Context, query *models.TaskSearchQuery) (*models.TaskSearchResult, error) {
+	if m.searchFn != nil {
+		return m.searchFn(ctx, query)
+	}
+	return nil, errors.New("not implemented")
+}
+func (m *mockTaskRepository) ApplyTaskBatch(ctx context.Context, ops []*models.TaskBatchOp, atomic bool) ([]error, error) {
+	if m.batchFn != nil {
+		return m.batchFn(ctx, ops, atomic)
+	}
+	return make([]error, len(ops)), nil
+}
+func (m *mockTaskRepository) GetTaskUsage(ctx context.Context, userID string) (*models.TaskUsage, error) {
+	if m.usageFn != nil {
+		return m.usageFn(ctx, userID)
+	}
+	return &models.TaskUsage{}, nil
+}
+func (m *mockTaskRepository) GetTasksWithDueDate(ctx context.Context, userID string) ([]*models.Task, error) {
+	if m.dueFn != nil {
+		return m.dueFn(ctx, userID)
+	}
+	return nil, errors.New("not implemented")
+}
+func (m *mockTaskRepository) ListTasksPage(ctx context.Context, userID string, after *models.Task, limit int) ([]*models.Task, error) {
+	if m.listPageFn != nil {
+		return m.listPageFn(ctx, userID, after, limit)
+	}
+	return nil, errors.New("not implemented")
+}
+func (m *mockTaskRepository) GetTaskIDsByExternalIDs(ctx context.Context, userID string, externalIDs []string) (map[string]string, error) {
+	if m.externalIDsFn != nil {
+		return m.externalIDsFn(ctx, userID, externalIDs)
+	}
+	return map[string]string{}, nil
+}
+func (m *mockTaskRepository) ImportTasks(ctx context.Context, tasks []*models.Task, upsert bool) ([]bool, []error, error) {
+	if m.importFn != nil {
+		return m.importFn(ctx, tasks, upsert)
+	}
+	return nil, nil, errors.New("not implemented")
+}
+func (m *mockTaskRepository) AddDependency(ctx context.Context, userID string, taskID string, blockedByID string) error {
+	return errors.New("not implemented")
+}
+func (m *mockTaskRepository) RemoveDependency(ctx context.Context, taskID string, blockedByID string) error {
+	return errors.New("not implemented")
+}
+func (m *mockTaskRepository) GetBlockers(ctx context.Context, taskID string) ([]*models.Task, error) {
+	if m.blockersFn != nil {
+		return m.blockersFn(ctx, taskID)
+	}
+	return nil, nil
+}
+func (m *mockTaskRepository) GetDependents(ctx context.Context, taskID string) ([]*models.Task, error) {
+	return nil, errors.New("not implemented")
+}
+func (m *mockTaskRepository) GetDependencyEdges(ctx context.Context, userID string) ([]*models.TaskDependency, error) {
+	if m.edgesFn != nil {
+		return m.edgesFn(ctx, userID)
+	}
+	return nil, nil
+}
 
 type mockTaskCacheRepository struct {
 	getFn    func(ctx context.Context, key string) (*models.Task, error)
 	setFn    func(ctx context.Context, task *models.Task, key string, exp time.Duration) error
 	deleteFn func(ctx context.Context, key string) error
+	deleteManyFn func(ctx context.Context, keys []string) error
+	entryFn    func(ctx context.Context, key string) (*models.TaskCacheEntry, error)
+	notFoundFn func(ctx context.Context, key string, exp time.Duration) error
+	getListFn        func(ctx context.Context, userID string, query string) (*models.TaskPage, int64, error)
+	setListFn        func(ctx context.Context, userID string, query string, generation int64, page *models.TaskPage, exp time.Duration) error
+	invalidateListFn func(ctx context.Context, userID string) error
 }
 
 func (m *mockTaskCacheRepository) GetTaskByID(ctx context.Context, key string) (*models.Task, error) {
@@ -67,6 +145,22 @@ func (m *mockTaskCacheRepository) GetTaskByID(ctx context.Context, key string) (
 	}
 	return nil, nil
 }
+func (m *mockTaskCacheRepository) GetTaskEntry(ctx context.Context, key string) (*models.TaskCacheEntry, error) {
+	if m.entryFn != nil {
+		return m.entryFn(ctx, key)
+	}
+	task, err := m.GetTaskByID(ctx, key)
+	if task == nil || err != nil {
+		return nil, err
+	}
+	return &models.TaskCacheEntry{Task: task, TTL: time.Hour}, nil
+}
+func (m *mockTaskCacheRepository) SetNotFound(ctx context.Context, key string, exp time.Duration) error {
+	if m.notFoundFn != nil {
+		return m.notFoundFn(ctx, key, exp)
+	}
+	return nil
+}
 func (m *mockTaskCacheRepository) SetTask(ctx context.Context, task *models.Task, key string, exp time.Duration) error {
 	if m.setFn != nil {
 		return m.setFn(ctx, task, key, exp)
@@ -79,6 +173,30 @@ func (m *mockTaskCacheRepository) DeleteTaskByID(ctx context.Context, key string
 	}
 	return nil
 }
+func (m *mockTaskCacheRepository) DeleteTasks(ctx context.Context, keys []string) error {
+	if m.deleteManyFn != nil {
+		return m.deleteManyFn(ctx, keys)
+	}
+	return nil
+}
+func (m *mockTaskCacheRepository) GetTaskList(ctx context.Context, userID string, query string) (*models.TaskPage, int64, error) {
+	if m.getListFn != nil {
+		return m.getListFn(ctx, userID, query)
+	}
+	return nil, 0, nil
+}
+func (m *mockTaskCacheRepository) SetTaskList(ctx context.Context, userID string, query string, generation int64, page *models.TaskPage, exp time.Duration) error {
+	if m.setListFn != nil {
+		return m.setListFn(ctx, userID, query, generation, page, exp)
+	}
+	return nil
+}
+func (m *mockTaskCacheRepository) InvalidateTaskLists(ctx context.Context, userID string) error {
+	if m.invalidateListFn != nil {
+		return m.invalidateListFn(ctx, userID)
+	}
+	return nil
+}
 
 func TestTaskService_CreateTask(t *testing.T) {
 	repo := &mockTaskRepository{
@@ -87,7 +205,7 @@ func TestTaskService_CreateTask(t *testing.T) {
 		},
 	}
 	cache := &mockTaskCacheRepository{}
-	svc := NewTaskService(repo, cache, "app", 10*time.Minute)
+	svc := NewTaskService(repo, cache, "app", models.TaskCacheOptions{Expiration: 10*time.Minute})
 
 	id, err := svc.CreateTask(context.Background(), &models.Task{
 		UserID:  "user-1",
@@ -113,7 +231,7 @@ func TestTaskService_GetTasks(t *testing.T) {
 		},
 	}
 	cache := &mockTaskCacheRepository{}
-	svc := NewTaskService(repo, cache, "app", 10*time.Minute)
+	svc := NewTaskService(repo, cache, "app", models.TaskCacheOptions{Expiration: 10*time.Minute})
 
 	tasks, err := svc.GetTasks(context.Background(), "user-1")
 	if err != nil {
@@ -137,7 +255,7 @@ func TestTaskService_GetTaskByID_FromCache(t *testing.T) {
 			return cachedTask, nil
 		},
 	}
-	svc := NewTaskService(repo, cache, "app", 10*time.Minute)
+	svc := NewTaskService(repo, cache, "app", models.TaskCacheOptions{Expiration: 10*time.Minute})
 
 	task, err := svc.GetTaskByID(context.Background(), "t1", "user-1")
 	if err != nil {
@@ -165,7 +283,7 @@ func TestTaskService_GetTaskByID_CacheMiss(t *testing.T) {
 			return nil
 		},
 	}
-	svc := NewTaskService(repo, cache, "app", 10*time.Minute)
+	svc := NewTaskService(repo, cache, "app", models.TaskCacheOptions{Expiration: 10*time.Minute})
 
 	task, err := svc.GetTaskByID(context.Background(), "t1", "user-1")
 	if err != nil {
@@ -190,7 +308,7 @@ func TestTaskService_GetTaskByID_NotOwner(t *testing.T) {
 			return nil, nil
 		},
 	}
-	svc := NewTaskService(repo, cache, "app", 10*time.Minute)
+	svc := NewTaskService(repo, cache, "app", models.TaskCacheOptions{Expiration: 10*time.Minute})
 
 	_, err := svc.GetTaskByID(context.Background(), "t1", "user-1")
 	if err == nil {
@@ -221,7 +339,7 @@ func TestTaskService_UpdateTaskByID_Success(t *testing.T) {
 			return nil
 		},
 	}
-	svc := NewTaskService(repo, cache, "app", 10*time.Minute)
+	svc := NewTaskService(repo, cache, "app", models.TaskCacheOptions{Expiration: 10*time.Minute})
 
 	err := svc.UpdateTaskByID(context.Background(), "t1", "user-1", &models.Task{Title: "Updated"})
 	if err != nil {
@@ -251,7 +369,7 @@ func TestTaskService_DeleteTaskByID_Success(t *testing.T) {
 			return nil
 		},
 	}
-	svc := NewTaskService(repo, cache, "app", 10*time.Minute)
+	svc := NewTaskService(repo, cache, "app", models.TaskCacheOptions{Expiration: 10*time.Minute})
 
 	err := svc.DeleteTaskByID(context.Background(), "t1", "user-1")
 	if err != nil {
@@ -273,7 +391,7 @@ func TestTaskService_DeleteTaskByID_NotFound(t *testing.T) {
 			return nil, nil
 		},
 	}
-	svc := NewTaskService(repo, cache, "app", 10*time.Minute)
+	svc := NewTaskService(repo, cache, "app", models.TaskCacheOptions{Expiration: 10*time.Minute})
 
 	err := svc.DeleteTaskByID(context.Background(), "missing", "user-1")
 	if err == nil {
@@ -0,0 +1,589 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/logger"
+	"github.com/suryansh74/task-management-api-project/internal/metrics"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+	"github.com/suryansh74/task-management-api-project/internal/ports"
+	"github.com/suryansh74/task-management-api-project/internal/validator"
+)
+
+const (
+	// exportPageSize rows fetched per query while streaming an export, the
+	// connection is released between pages
+	exportPageSize = 500
+	// maxImportRows upper bound of rows in one import
+	maxImportRows = 1000
+)
+
+// csvColumns header of exported CSV files, import accepts any subset in any order
+var csvColumns = []string{"id", "external_id", "title", "content", "status", "due_at", "labels", "created_at", "updated_at"}
+
+type taskTransferService struct {
+	taskRepo          ports.TaskRepository
+	recurrenceService ports.RecurrenceService
+	taskCacheRepo     ports.TaskCacheRepository
+	quotaService      ports.TaskQuotaService
+	redisAppName      string
+}
+
+// NewTaskTransferService creates a new task import/export service instance
+// =========================================================================
+func NewTaskTransferService(taskRepo ports.TaskRepository, recurrenceService ports.RecurrenceService, taskCacheRepo ports.TaskCacheRepository, quotaService ports.TaskQuotaService, redisAppName string) ports.TaskTransferService {
+	logger.Log.Info().
+		Int("export_page_size", exportPageSize).
+		Int("max_import_rows", maxImportRows).
+		Msg("initializing task transfer service")
+	return &taskTransferService{
+		taskRepo:          taskRepo,
+		recurrenceService: recurrenceService,
+		taskCacheRepo:     taskCacheRepo,
+		quotaService:      quotaService,
+		redisAppName:      redisAppName,
+	}
+}
+
+// ExportTasks write all tasks of the user in the requested format
+// =========================================================================
+func (s *taskTransferService) ExportTasks(ctx context.Context, userID string, format string, w io.Writer) error {
+	zerolog.Ctx(ctx).Debug().
+		Str("user_id", userID).
+		Str("format", format).
+		Msg("exporting tasks")
+
+	enc, err := newRecordEncoder(format, w)
+	if err != nil {
+		return err
+	}
+
+	count := 0
+	var after *models.Task
+	for {
+		page, err := s.taskRepo.ListTasksPage(ctx, userID, after, exportPageSize)
+		if err != nil {
+			zerolog.Ctx(ctx).Error().
+				Err(err).
+				Str("user_id", userID).
+				Int("exported", count).
+				Msg("failed to fetch tasks for export")
+			return err
+		}
+		for _, task := range page {
+			if err := enc.Encode(toTaskRecord(task)); err != nil {
+				return err
+			}
+		}
+		count += len(page)
+		if len(page) < exportPageSize {
+			break
+		}
+		after = page[len(page)-1]
+	}
+
+	if err := enc.Close(); err != nil {
+		return err
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("user_id", userID).
+		Str("format", format).
+		Int("task_count", count).
+		Msg("tasks exported successfully")
+	return nil
+}
+
+// ImportTasks validate every row, then create or upsert the valid ones
+// =========================================================================
+func (s *taskTransferService) ImportTasks(ctx context.Context, userID string, r io.Reader, opts models.TaskImportOptions) (*models.TaskImportResult, error) {
+	zerolog.Ctx(ctx).Debug().
+		Str("user_id", userID).
+		Str("format", opts.Format).
+		Str("mode", opts.Mode).
+		Bool("dry_run", opts.DryRun).
+		Msg("importing tasks")
+
+	if userID == "" {
+		return nil, apperror.NewUnauthorizedError("not authenticated")
+	}
+	if opts.Mode == "" {
+		opts.Mode = models.ImportModeCreate
+	}
+	if opts.Mode != models.ImportModeCreate && opts.Mode != models.ImportModeUpsert {
+		return nil, apperror.NewBadRequestError("mode must be create or upsert")
+	}
+
+	records, rowErrs, err := decodeRecords(opts.Format, r)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.TaskImportResult{
+		DryRun: opts.DryRun,
+		Mode:   opts.Mode,
+		Total:  len(records),
+		Errors: []*models.TaskImportRowError{},
+	}
+	upsert := opts.Mode == models.ImportModeUpsert
+
+	// field validation and duplicate external ids inside the file
+	tasks := make([]*models.Task, len(records))
+	seen := make(map[string]int)
+	var externalIDs []string
+	for i, rec := range records {
+		if rowErrs[i] != nil {
+			continue
+		}
+		if fieldErrors := validator.ValidateStruct(rec); len(fieldErrors) > 0 {
+			rowErrs[i] = fieldErrors
+			continue
+		}
+		labels, err := normalizeLabels(rec.Labels)
+		if err != nil {
+			rowErrs[i] = map[string]string{"Labels": errorMessage(err)}
+			continue
+		}
+		rec.Labels = labels
+		if upsert && rec.ExternalID == "" {
+			rowErrs[i] = map[string]string{"ExternalID": "This field is required in upsert mode"}
+			continue
+		}
+		if rec.ExternalID != "" {
+			if first, dup := seen[rec.ExternalID]; dup {
+				rowErrs[i] = map[string]string{"ExternalID": fmt.Sprintf("Duplicate of row %d", first+1)}
+				continue
+			}
+			seen[rec.ExternalID] = i
+			externalIDs = append(externalIDs, rec.ExternalID)
+		}
+		tasks[i] = fromTaskRecord(rec, userID)
+	}
+
+	existing, err := s.taskRepo.GetTaskIDsByExternalIDs(ctx, userID, externalIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var valid []*models.Task
+	var validIndex []int
+	for i, task := range tasks {
+		if task == nil {
+			continue
+		}
+		if task.ExternalID != nil && !upsert && existing[*task.ExternalID] != "" {
+			rowErrs[i] = map[string]string{"ExternalID": "A task with this external_id already exists"}
+			continue
+		}
+		valid = append(valid, task)
+		validIndex = append(validIndex, i)
+	}
+
+	// upserting status done completes a stored task, the same rules as single
+	// and batch updates apply; previous holds the task before the import
+	var previous []*models.Task
+	if upsert {
+		var kept []*models.Task
+		var keptIndex []int
+		// blockers completed earlier in the file no longer block
+		completing := make(map[string]bool)
+		for j, task := range valid {
+			current, err := s.completedByImport(ctx, task, existing[*task.ExternalID], completing)
+			if err != nil {
+				var appErr *apperror.AppError
+				if !errors.As(err, &appErr) || appErr.StatusCode >= 500 {
+					return nil, err
+				}
+				rowErrs[validIndex[j]] = map[string]string{"Status": appErr.Message}
+				continue
+			}
+			if current != nil {
+				completing[current.ID] = true
+			}
+			kept = append(kept, task)
+			keptIndex = append(keptIndex, validIndex[j])
+			previous = append(previous, current)
+		}
+		valid, validIndex = kept, keptIndex
+	}
+
+	// upserted rows count in full, their current size is not loaded
+	addTasks, addBytes := 0, int64(0)
+	for _, task := range valid {
+		if task.ExternalID == nil || existing[*task.ExternalID] == "" {
+			addTasks++
+		}
+		addBytes += models.TaskContentBytes(task.Title, task.Content)
+	}
+	if err := s.quotaService.CheckQuota(ctx, userID, addTasks, addBytes); err != nil {
+		return nil, err
+	}
+
+	if opts.DryRun {
+		for _, task := range valid {
+			if task.ExternalID != nil && existing[*task.ExternalID] != "" {
+				result.Updated++
+			} else {
+				result.Created++
+			}
+		}
+		return s.finishImport(ctx, result, userID, rowErrs), nil
+	}
+
+	if len(valid) > 0 {
+		inserted, errs, err := s.taskRepo.ImportTasks(ctx, valid, upsert)
+		if err != nil {
+			zerolog.Ctx(ctx).Error().
+				Err(err).
+				Str("user_id", userID).
+				Msg("failed to import tasks")
+			return nil, err
+		}
+
+		keys := make([]string, 0, len(valid))
+		for j, task := range valid {
+			if errs[j] != nil {
+				rowErrs[validIndex[j]] = map[string]string{"row": errorMessage(errs[j])}
+				continue
+			}
+			if inserted[j] {
+				result.Created++
+			} else {
+				result.Updated++
+				keys = append(keys, fmt.Sprintf("%s:cache:task:%s", s.redisAppName, task.ID))
+				if previous != nil && previous[j] != nil {
+					s.recurrenceService.OccurrenceCompleted(ctx, previous[j])
+				}
+			}
+		}
+		metrics.ObserveCacheWrite("task", "delete", s.taskCacheRepo.DeleteTasks(ctx, keys))
+		invalidateTaskLists(ctx, s.taskCacheRepo, userID)
+		metrics.TasksCreated.WithLabelValues("import").Add(float64(result.Created))
+	}
+
+	return s.finishImport(ctx, result, userID, rowErrs), nil
+}
+
+// completedByImport the stored task when an upserted row marks it done, nil
+// when the row creates a task or doesn't complete one; blocked tasks can't be
+// completed, like in taskBatchService.prepare
+func (s *taskTransferService) completedByImport(ctx context.Context, task *models.Task, existingID string, completing map[string]bool) (*models.Task, error) {
+	if task.Status != models.TaskStatusDone || existingID == "" {
+		return nil, nil
+	}
+
+	current, err := s.recurrenceService.GetTaskByID(ctx, existingID, task.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if current.Status == models.TaskStatusDone {
+		return nil, nil
+	}
+
+	blockers, err := s.taskRepo.GetBlockers(ctx, current.ID)
+	if err != nil {
+		return nil, err
+	}
+	open := 0
+	for _, blocker := range blockers {
+		if blocker.Status != models.TaskStatusDone && !completing[blocker.ID] {
+			open++
+		}
+	}
+	if open > 0 {
+		return nil, apperror.NewConflictError(fmt.Sprintf("task is blocked by %d open task(s)", open))
+	}
+	return current, nil
+}
+
+// finishImport collect row errors in row order
+func (s *taskTransferService) finishImport(ctx context.Context, result *models.TaskImportResult, userID string, rowErrs []map[string]string) *models.TaskImportResult {
+	for i, fieldErrors := range rowErrs {
+		if fieldErrors != nil {
+			result.Errors = append(result.Errors, &models.TaskImportRowError{Row: i + 1, Errors: fieldErrors})
+		}
+	}
+	result.Failed = len(result.Errors)
+
+	zerolog.Ctx(ctx).Info().
+		Str("user_id", userID).
+		Bool("dry_run", result.DryRun).
+		Int("total", result.Total).
+		Int("created", result.Created).
+		Int("updated", result.Updated).
+		Int("failed", result.Failed).
+		Msg("task import processed")
+	return result
+}
+
+func toTaskRecord(task *models.Task) *models.TaskRecord {
+	rec := &models.TaskRecord{
+		ID:        task.ID,
+		Title:     task.Title,
+		Content:   task.Content,
+		Status:    task.Status,
+		Labels:    task.Labels,
+		CreatedAt: task.CreatedAt.UTC().Format(time.RFC3339),
+		UpdatedAt: task.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+	if task.ExternalID != nil {
+		rec.ExternalID = *task.ExternalID
+	}
+	if task.DueAt != nil {
+		rec.DueAt = task.DueAt.UTC().Format(time.RFC3339)
+	}
+	if rec.Labels == nil {
+		rec.Labels = []string{}
+	}
+	return rec
+}
+
+// fromTaskRecord map a validated record, ids and timestamps of the source are ignored
+func fromTaskRecord(rec *models.TaskRecord, userID string) *models.Task {
+	task := &models.Task{
+		UserID:  userID,
+		Title:   rec.Title,
+		Content: rec.Content,
+		Status:  rec.Status,
+		Labels:  rec.Labels,
+	}
+	if rec.ExternalID != "" {
+		externalID := rec.ExternalID
+		task.ExternalID = &externalID
+	}
+	if rec.DueAt != "" {
+		// already checked by the datetime validation
+		if due, err := time.Parse(time.RFC3339, rec.DueAt); err == nil {
+			task.DueAt = &due
+		}
+	}
+	return task
+}
+
+func errorMessage(err error) string {
+	var appErr *apperror.AppError
+	if errors.As(err, &appErr) {
+		return appErr.Message
+	}
+	return "internal error"
+}
+
+// recordEncoder writes records one at a time in an export format
+type recordEncoder interface {
+	Encode(rec *models.TaskRecord) error
+	Close() error
+}
+
+func newRecordEncoder(format string, w io.Writer) (recordEncoder, error) {
+	switch format {
+	case models.TransferFormatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(csvColumns); err != nil {
+			return nil, err
+		}
+		return &csvRecordEncoder{w: cw}, nil
+	case models.TransferFormatJSON:
+		return &jsonRecordEncoder{w: w}, nil
+	case models.TransferFormatNDJSON:
+		return &ndjsonRecordEncoder{enc: json.NewEncoder(w)}, nil
+	}
+	return nil, apperror.NewBadRequestError("format must be csv, json or ndjson")
+}
+
+type csvRecordEncoder struct {
+	w *csv.Writer
+}
+
+func (e *csvRecordEncoder) Encode(rec *models.TaskRecord) error {
+	return e.w.Write([]string{rec.ID, escapeCSVCell(rec.ExternalID), escapeCSVCell(rec.Title), escapeCSVCell(rec.Content), rec.Status, rec.DueAt, escapeCSVCell(strings.Join(rec.Labels, ",")), rec.CreatedAt, rec.UpdatedAt})
+}
+
+// csvFormulaPrefixes start a cell that spreadsheets evaluate as a formula
+const csvFormulaPrefixes = "=+-@\t\r"
+
+// escapeCSVCell prefix a cell a spreadsheet would run as a formula with a quote;
+// a cell that already looks escaped, e.g. a literal '=x, gets one more so the
+// import only drops the quote the export added
+func escapeCSVCell(value string) string {
+	if value != "" && (strings.ContainsRune(csvFormulaPrefixes, rune(value[0])) || isEscapedCSVCell(value)) {
+		return "'" + value
+	}
+	return value
+}
+
+// unescapeCSVCell undo escapeCSVCell so an exported file imports unchanged
+func unescapeCSVCell(value string) string {
+	if isEscapedCSVCell(value) {
+		return value[1:]
+	}
+	return value
+}
+
+// isEscapedCSVCell quotes followed by a formula prefix, the shape escapeCSVCell
+// produces; other cells starting with a quote are plain text
+func isEscapedCSVCell(value string) bool {
+	rest := strings.TrimLeft(value, "'")
+	return len(rest) < len(value) && rest != "" && strings.ContainsRune(csvFormulaPrefixes, rune(rest[0]))
+}
+
+func (e *csvRecordEncoder) Close() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+// jsonRecordEncoder writes a JSON array element by element
+type jsonRecordEncoder struct {
+	w     io.Writer
+	count int
+}
+
+func (e *jsonRecordEncoder) Encode(rec *models.TaskRecord) error {
+	sep := ",\n"
+	if e.count == 0 {
+		sep = "[\n"
+	}
+	e.count++
+
+	b, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+	if _, err := io.WriteString(e.w, sep); err != nil {
+		return err
+	}
+	_, err = e.w.Write(b)
+	return err
+}
+
+func (e *jsonRecordEncoder) Close() error {
+	end := "\n]\n"
+	if e.count == 0 {
+		end = "[]\n"
+	}
+	_, err := io.WriteString(e.w, end)
+	return err
+}
+
+type ndjsonRecordEncoder struct {
+	enc *json.Encoder
+}
+
+func (e *ndjsonRecordEncoder) Encode(rec *models.TaskRecord) error { return e.enc.Encode(rec) }
+func (e *ndjsonRecordEncoder) Close() error                        { return nil }
+
+// decodeRecords parse an import body; rows that can't be parsed get an entry
+// in rowErrs, a body that can't be parsed at all is an error
+func decodeRecords(format string, r io.Reader) ([]*models.TaskRecord, []map[string]string, error) {
+	var records []*models.TaskRecord
+	var rowErrs []map[string]string
+	add := func(rec *models.TaskRecord, rowErr map[string]string) error {
+		if len(records) == maxImportRows {
+			return apperror.NewPayloadTooLargeError(fmt.Sprintf("import can contain at most %d rows", maxImportRows))
+		}
+		records = append(records, rec)
+		rowErrs = append(rowErrs, rowErr)
+		return nil
+	}
+
+	switch format {
+	case models.TransferFormatCSV:
+		cr := csv.NewReader(r)
+		cr.FieldsPerRecord = -1
+		header, err := cr.Read()
+		if err == io.EOF {
+			return records, rowErrs, nil
+		}
+		if err != nil {
+			return nil, nil, apperror.NewBadRequestError("invalid CSV header")
+		}
+		columns := make(map[string]int, len(header))
+		for i, name := range header {
+			columns[strings.ToLower(strings.TrimSpace(name))] = i
+		}
+		if _, ok := columns["title"]; !ok {
+			return nil, nil, apperror.NewBadRequestError("CSV header must contain a title column")
+		}
+		for {
+			row, err := cr.Read()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return nil, nil, apperror.NewBadRequestError(fmt.Sprintf("invalid CSV: %v", err))
+			}
+			field := func(name string) string {
+				if i, ok := columns[name]; ok && i < len(row) {
+					return strings.TrimSpace(row[i])
+				}
+				return ""
+			}
+			rec := &models.TaskRecord{
+				ExternalID: unescapeCSVCell(field("external_id")),
+				Title:      unescapeCSVCell(field("title")),
+				Content:    unescapeCSVCell(field("content")),
+				Status:     field("status"),
+				DueAt:      field("due_at"),
+			}
+			// labels are comma-joined, an empty cell clears them and a
+			// missing column keeps them
+			if _, ok := columns["labels"]; ok {
+				rec.Labels = strings.Split(unescapeCSVCell(field("labels")), ",")
+			}
+			if err := add(rec, nil); err != nil {
+				return nil, nil, err
+			}
+		}
+	case models.TransferFormatJSON:
+		dec := json.NewDecoder(r)
+		if tok, err := dec.Token(); err != nil || tok != json.Delim('[') {
+			return nil, nil, apperror.NewBadRequestError("JSON import must be an array of tasks")
+		}
+		for dec.More() {
+			var raw json.RawMessage
+			if err := dec.Decode(&raw); err != nil {
+				return nil, nil, apperror.NewBadRequestError("invalid JSON")
+			}
+			var rec models.TaskRecord
+			var rowErr map[string]string
+			if err := json.Unmarshal(raw, &rec); err != nil {
+				rowErr = map[string]string{"row": "Invalid task object"}
+			}
+			if err := add(&rec, rowErr); err != nil {
+				return nil, nil, err
+			}
+		}
+	case models.TransferFormatNDJSON:
+		sc := bufio.NewScanner(r)
+		sc.Buffer(make([]byte, 0, 64*1024), 1<<20)
+		for sc.Scan() {
+			line := strings.TrimSpace(sc.Text())
+			if line == "" {
+				continue
+			}
+			var rec models.TaskRecord
+			var rowErr map[string]string
+			if err := json.Unmarshal([]byte(line), &rec); err != nil {
+				rowErr = map[string]string{"row": "Invalid JSON line"}
+			}
+			if err := add(&rec, rowErr); err != nil {
+				return nil, nil, err
+			}
+		}
+		if err := sc.Err(); err != nil {
+			return nil, nil, apperror.NewBadRequestError("invalid NDJSON")
+		}
+	default:
+		return nil, nil, apperror.NewBadRequestError("format must be csv, json or ndjson")
+	}
+	return records, rowErrs, nil
+}
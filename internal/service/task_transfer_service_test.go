@@ -0,0 +1,290 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/suryansh74/task-management-api-project/internal/apperror"
+	"github.com/suryansh74/task-management-api-project/internal/models"
+)
+
+func TestTaskTransferService_ExportTasks_CSV(t *testing.T) {
+	due := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+	externalID := "JIRA-1"
+	var afters []*models.Task
+	repo := &mockTaskRepository{
+		listPageFn: func(ctx context.Context, userID string, after *models.Task, limit int) ([]*models.Task, error) {
+			afters = append(afters, after)
+			if after != nil {
+				return nil, nil
+			}
+			return []*models.Task{
+				{ID: "t1", ExternalID: &externalID, Title: "Ship, then rest", Status: models.TaskStatusTodo, DueAt: &due, Labels: []string{"work", "urgent"}},
+				{ID: "t2", Title: "Write docs", Content: "line one\nline two", Status: models.TaskStatusDone},
+			}, nil
+		},
+	}
+	svc := NewTaskTransferService(repo, &mockRecurrenceService{}, &mockTaskCacheRepository{}, NewTaskQuotaService(repo, models.TaskQuota{}), "app")
+
+	var buf bytes.Buffer
+	if err := svc.ExportTasks(context.Background(), "user-1", models.TransferFormatCSV, &buf); err != nil {
+		t.Fatalf("ExportTasks failed: %v", err)
+	}
+	if len(afters) != 1 {
+		t.Errorf("a short page must end the export, got %d queries", len(afters))
+	}
+
+	// an exported file imports back as the same tasks
+	var imported []*models.Task
+	repo.importFn = func(ctx context.Context, tasks []*models.Task, upsert bool) ([]bool, []error, error) {
+		imported = tasks
+		return []bool{true, true}, make([]error, len(tasks)), nil
+	}
+	result, err := svc.ImportTasks(context.Background(), "user-1", &buf, models.TaskImportOptions{Format: models.TransferFormatCSV})
+	if err != nil {
+		t.Fatalf("ImportTasks failed: %v", err)
+	}
+	if result.Created != 2 || result.Failed != 0 {
+		t.Fatalf("expected 2 created, got %+v", result)
+	}
+	if imported[0].Title != "Ship, then rest" || *imported[0].ExternalID != "JIRA-1" || !imported[0].DueAt.Equal(due) {
+		t.Errorf("first task did not round trip: %+v", imported[0])
+	}
+	if strings.Join(imported[0].Labels, ",") != "work,urgent" {
+		t.Errorf("labels did not round trip: %q", imported[0].Labels)
+	}
+	if imported[1].Content != "line one\nline two" || imported[1].ExternalID != nil || imported[1].UserID != "user-1" {
+		t.Errorf("second task did not round trip: %+v", imported[1])
+	}
+	// an empty labels cell clears the labels instead of keeping them
+	if imported[1].Labels == nil || len(imported[1].Labels) != 0 {
+		t.Errorf("expected empty labels for the second task, got %#v", imported[1].Labels)
+	}
+}
+
+func TestTaskTransferService_ExportTasks_CSVFormulas(t *testing.T) {
+	repo := &mockTaskRepository{
+		listPageFn: func(ctx context.Context, userID string, after *models.Task, limit int) ([]*models.Task, error) {
+			if after != nil {
+				return nil, nil
+			}
+			return []*models.Task{
+				{ID: "t1", Title: "=HYPERLINK(\"http://evil.example\")", Content: "@SUM(A1)", Status: models.TaskStatusTodo},
+				{ID: "t2", Title: "-1 day", Content: "'quoted", Status: models.TaskStatusTodo},
+				{ID: "t3", Title: "'=literal", Content: "''+1", Status: models.TaskStatusTodo},
+			}, nil
+		},
+	}
+	svc := NewTaskTransferService(repo, &mockRecurrenceService{}, &mockTaskCacheRepository{}, NewTaskQuotaService(repo, models.TaskQuota{}), "app")
+
+	var buf bytes.Buffer
+	if err := svc.ExportTasks(context.Background(), "user-1", models.TransferFormatCSV, &buf); err != nil {
+		t.Fatalf("ExportTasks failed: %v", err)
+	}
+	rows, err := csv.NewReader(bytes.NewReader(buf.Bytes())).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+	if rows[1][2] != "'=HYPERLINK(\"http://evil.example\")" || rows[1][3] != "'@SUM(A1)" || rows[2][2] != "'-1 day" {
+		t.Errorf("expected formula cells to be quoted, got %q", rows[1:])
+	}
+	if rows[2][3] != "'quoted" {
+		t.Errorf("a plain cell must be left alone, got %q", rows[2][3])
+	}
+	if rows[3][2] != "''=literal" || rows[3][3] != "'''+1" {
+		t.Errorf("a cell that looks escaped must get one more quote, got %q", rows[3])
+	}
+
+	// the quote is dropped again on import
+	var imported []*models.Task
+	repo.importFn = func(ctx context.Context, tasks []*models.Task, upsert bool) ([]bool, []error, error) {
+		imported = tasks
+		return []bool{true, true, true}, make([]error, len(tasks)), nil
+	}
+	if _, err := svc.ImportTasks(context.Background(), "user-1", &buf, models.TaskImportOptions{Format: models.TransferFormatCSV}); err != nil {
+		t.Fatalf("ImportTasks failed: %v", err)
+	}
+	if imported[0].Title != "=HYPERLINK(\"http://evil.example\")" || imported[0].Content != "@SUM(A1)" || imported[1].Title != "-1 day" || imported[1].Content != "'quoted" {
+		t.Errorf("formula cells did not round trip: %+v %+v", imported[0], imported[1])
+	}
+	if imported[2].Title != "'=literal" || imported[2].Content != "''+1" {
+		t.Errorf("a literal quote must survive the round trip, got %q %q", imported[2].Title, imported[2].Content)
+	}
+}
+
+func TestTaskTransferService_ExportTasks_JSONEmpty(t *testing.T) {
+	repo := &mockTaskRepository{
+		listPageFn: func(ctx context.Context, userID string, after *models.Task, limit int) ([]*models.Task, error) {
+			return nil, nil
+		},
+	}
+	svc := NewTaskTransferService(repo, &mockRecurrenceService{}, &mockTaskCacheRepository{}, NewTaskQuotaService(repo, models.TaskQuota{}), "app")
+
+	var buf bytes.Buffer
+	if err := svc.ExportTasks(context.Background(), "user-1", models.TransferFormatJSON, &buf); err != nil {
+		t.Fatalf("ExportTasks failed: %v", err)
+	}
+	var records []*models.TaskRecord
+	if err := json.Unmarshal(buf.Bytes(), &records); err != nil || len(records) != 0 {
+		t.Errorf("expected an empty JSON array, got %q", buf.String())
+	}
+}
+
+func TestTaskTransferService_ImportTasks_RowErrors(t *testing.T) {
+	repo := &mockTaskRepository{
+		externalIDsFn: func(ctx context.Context, userID string, externalIDs []string) (map[string]string, error) {
+			return map[string]string{"GH-7": "existing-id"}, nil
+		},
+		importFn: func(ctx context.Context, tasks []*models.Task, upsert bool) ([]bool, []error, error) {
+			if len(tasks) != 1 || tasks[0].Title != "Valid task" {
+				t.Errorf("only the valid row must reach the repository, got %d", len(tasks))
+			}
+			if strings.Join(tasks[0].Labels, ",") != "work" {
+				t.Errorf("expected normalized labels, got %q", tasks[0].Labels)
+			}
+			return []bool{true}, []error{nil}, nil
+		},
+	}
+	svc := NewTaskTransferService(repo, &mockRecurrenceService{}, &mockTaskCacheRepository{}, NewTaskQuotaService(repo, models.TaskQuota{}), "app")
+
+	body := strings.Join([]string{
+		`{"title":"Valid task","external_id":"GH-1","labels":[" Work ","work"]}`,
+		`{"title":"x"}`,
+		`not json`,
+		`{"title":"Bad status","status":"later"}`,
+		`{"title":"Duplicate","external_id":"GH-1"}`,
+		`{"title":"Already there","external_id":"GH-7"}`,
+		`{"title":"Bad labels","labels":["a,b"]}`,
+	}, "\n")
+	result, err := svc.ImportTasks(context.Background(), "user-1", strings.NewReader(body), models.TaskImportOptions{Format: models.TransferFormatNDJSON})
+	if err != nil {
+		t.Fatalf("ImportTasks failed: %v", err)
+	}
+	if result.Total != 7 || result.Created != 1 || result.Failed != 6 {
+		t.Fatalf("unexpected counts: %+v", result)
+	}
+
+	want := map[int]string{2: "Title", 3: "row", 4: "Status", 5: "ExternalID", 6: "ExternalID", 7: "Labels"}
+	for _, rowErr := range result.Errors {
+		field, ok := want[rowErr.Row]
+		if !ok {
+			t.Errorf("unexpected error for row %d: %v", rowErr.Row, rowErr.Errors)
+			continue
+		}
+		if rowErr.Errors[field] == "" {
+			t.Errorf("row %d: expected error on %s, got %v", rowErr.Row, field, rowErr.Errors)
+		}
+	}
+}
+
+func TestTaskTransferService_ImportTasks_DryRunUpsert(t *testing.T) {
+	repo := &mockTaskRepository{
+		externalIDsFn: func(ctx context.Context, userID string, externalIDs []string) (map[string]string, error) {
+			return map[string]string{"GH-1": "existing-id"}, nil
+		},
+		importFn: func(ctx context.Context, tasks []*models.Task, upsert bool) ([]bool, []error, error) {
+			t.Error("dry run must not write")
+			return nil, nil, errors.New("unexpected write")
+		},
+	}
+	svc := NewTaskTransferService(repo, &mockRecurrenceService{}, &mockTaskCacheRepository{}, NewTaskQuotaService(repo, models.TaskQuota{}), "app")
+
+	body := `[
+		{"title":"Existing task","external_id":"GH-1"},
+		{"title":"New task","external_id":"GH-2"},
+		{"title":"No external id"}
+	]`
+	result, err := svc.ImportTasks(context.Background(), "user-1", strings.NewReader(body), models.TaskImportOptions{
+		Format: models.TransferFormatJSON,
+		Mode:   models.ImportModeUpsert,
+		DryRun: true,
+	})
+	if err != nil {
+		t.Fatalf("ImportTasks failed: %v", err)
+	}
+	if !result.DryRun || result.Updated != 1 || result.Created != 1 || result.Failed != 1 {
+		t.Fatalf("unexpected counts: %+v", result)
+	}
+	if result.Errors[0].Row != 3 || result.Errors[0].Errors["ExternalID"] == "" {
+		t.Errorf("upsert without external_id must fail, got %+v", result.Errors[0])
+	}
+}
+
+func TestTaskTransferService_ImportTasks_UpsertCompletion(t *testing.T) {
+	seriesID := "series-1"
+	repo := &mockTaskRepository{
+		externalIDsFn: func(ctx context.Context, userID string, externalIDs []string) (map[string]string, error) {
+			return map[string]string{"GH-1": "blocker", "GH-2": "blocked", "GH-3": "recurring", "GH-4": "still-blocked"}, nil
+		},
+		blockersFn: func(ctx context.Context, taskID string) ([]*models.Task, error) {
+			switch taskID {
+			case "blocked":
+				return []*models.Task{{ID: "blocker", Status: models.TaskStatusTodo}}, nil
+			case "still-blocked":
+				return []*models.Task{{ID: "elsewhere", Status: models.TaskStatusInProgress}}, nil
+			}
+			return nil, nil
+		},
+		importFn: func(ctx context.Context, tasks []*models.Task, upsert bool) ([]bool, []error, error) {
+			for _, task := range tasks {
+				task.ID = map[string]string{"GH-1": "blocker", "GH-2": "blocked", "GH-3": "recurring"}[*task.ExternalID]
+			}
+			return make([]bool, len(tasks)), make([]error, len(tasks)), nil
+		},
+	}
+	var completed []string
+	recurrence := &mockRecurrenceService{
+		getByIDFn: func(ctx context.Context, taskID string, userID string) (*models.Task, error) {
+			task := &models.Task{ID: taskID, UserID: userID, Status: models.TaskStatusTodo}
+			if taskID == "recurring" {
+				task.SeriesID = &seriesID
+			}
+			return task, nil
+		},
+		completedFn: func(ctx context.Context, current *models.Task) {
+			completed = append(completed, current.ID)
+		},
+	}
+	svc := NewTaskTransferService(repo, recurrence, &mockTaskCacheRepository{}, NewTaskQuotaService(repo, models.TaskQuota{}), "app")
+
+	// the blocker is completed first in the file, so it no longer blocks GH-2
+	body := strings.Join([]string{
+		`{"title":"Blocker","external_id":"GH-1","status":"done"}`,
+		`{"title":"Blocked","external_id":"GH-2","status":"done"}`,
+		`{"title":"Recurring","external_id":"GH-3","status":"done"}`,
+		`{"title":"Still blocked","external_id":"GH-4","status":"done"}`,
+	}, "\n")
+	result, err := svc.ImportTasks(context.Background(), "user-1", strings.NewReader(body), models.TaskImportOptions{
+		Format: models.TransferFormatNDJSON,
+		Mode:   models.ImportModeUpsert,
+	})
+	if err != nil {
+		t.Fatalf("ImportTasks failed: %v", err)
+	}
+	if result.Updated != 3 || result.Failed != 1 {
+		t.Fatalf("unexpected counts: %+v", result)
+	}
+	if result.Errors[0].Row != 4 || result.Errors[0].Errors["Status"] == "" {
+		t.Errorf("expected the blocked task to be rejected, got %+v", result.Errors[0])
+	}
+	if strings.Join(completed, ",") != "blocker,blocked,recurring" {
+		t.Errorf("expected every completed task to reach the recurrence service, got %v", completed)
+	}
+}
+
+func TestTaskTransferService_ImportTasks_TooManyRows(t *testing.T) {
+	svc := NewTaskTransferService(&mockTaskRepository{}, &mockRecurrenceService{}, &mockTaskCacheRepository{}, NewTaskQuotaService(&mockTaskRepository{}, models.TaskQuota{}), "app")
+
+	body := strings.Repeat(`{"title":"Task"}`+"\n", maxImportRows+1)
+	_, err := svc.ImportTasks(context.Background(), "user-1", strings.NewReader(body), models.TaskImportOptions{Format: models.TransferFormatNDJSON})
+	var appErr *apperror.AppError
+	if !errors.As(err, &appErr) || appErr.StatusCode != 413 {
+		t.Errorf("expected payload too large, got %v", err)
+	}
+}
@@ -2,7 +2,10 @@ package service
 
 import (
 	"context"
+	"errors"
+	"sync"
 
+	"github.com/rs/zerolog"
 	"github.com/suryansh74/task-management-api-project/internal/apperror"
 	"github.com/suryansh74/task-management-api-project/internal/logger"
 	"github.com/suryansh74/task-management-api-project/internal/models"
@@ -12,39 +15,53 @@ import (
 
 type userService struct {
 	userRepo ports.UserRepository
+	mfaRepo  ports.MFARepository
+	guard    *loginGuard
 }
 
+// dummyPasswordHash compared against for unknown emails so they take as long
+// as a wrong password and can't be told apart by timing
+var dummyPasswordHash = sync.OnceValue(func() string {
+	hash, err := utils.HashedPassword("dummy password for unknown accounts")
+	if err != nil {
+		panic(err)
+	}
+	return hash
+})
+
 // NewUserService creates a new user service instance
-func NewUserService(userRepo ports.UserRepository) ports.UserService {
+func NewUserService(userRepo ports.UserRepository, mfaRepo ports.MFARepository, attempts ports.LoginAttemptStore, auditLog ports.AuditLog, protection models.LoginProtection) ports.UserService {
 	logger.Log.Info().Msg("initializing user service")
 	return &userService{
 		userRepo: userRepo,
+		mfaRepo:  mfaRepo,
+		guard:    &loginGuard{attempts: attempts, auditLog: auditLog, protection: protection},
 	}
 }
 
 // Register creates a new user account
 // =========================================================================
 func (s *userService) Register(ctx context.Context, name, email, password string) (*ports.UserResponse, error) {
-	logger.Log.Debug().
+	zerolog.Ctx(ctx).Debug().
 		Str("email", email).
 		Str("name", name).
 		Msg("registering new user")
 
 	// Hash password
-	logger.Log.Debug().
+	zerolog.Ctx(ctx).Debug().
 		Str("email", email).
 		Msg("hashing user password")
 
 	hashedPassword, err := utils.HashedPassword(password)
 	if err != nil {
-		logger.Log.Error().
+		zerolog.Ctx(ctx).Error().
 			Err(err).
 			Str("email", email).
 			Msg("failed to hash password")
 		return nil, apperror.NewInternalError("Failed to process password", err)
 	}
 
-	logger.Log.Debug().
+	zerolog.Ctx(ctx).Debug().
 		Str("email", email).
 		Msg("password hashed successfully")
 
@@ -58,14 +75,14 @@ func (s *userService) Register(ctx context.Context, name, email, password string
 	// NOTE: no need to check if user already exists repo check itself while creating new user
 
 	// Save to repository
-	logger.Log.Debug().
+	zerolog.Ctx(ctx).Debug().
 		Str("email", email).
 		Str("name", name).
 		Msg("saving user to repository")
 
 	userID, err := s.userRepo.CreateUser(ctx, user)
 	if err != nil {
-		logger.Log.Error().
+		zerolog.Ctx(ctx).Error().
 			Err(err).
 			Str("email", email).
 			Str("name", name).
@@ -73,7 +90,7 @@ func (s *userService) Register(ctx context.Context, name, email, password string
 		return nil, err // Repository already returns AppError
 	}
 
-	logger.Log.Info().
+	zerolog.Ctx(ctx).Info().
 		Str("user_id", userID).
 		Str("email", email).
 		Str("name", name).
@@ -88,26 +105,43 @@ func (s *userService) Register(ctx context.Context, name, email, password string
 
 // Login retrieves user information by email
 // =========================================================================
-func (s *userService) Login(ctx context.Context, email string, password string) (*ports.UserResponse, error) {
-	logger.Log.Debug().
+func (s *userService) Login(ctx context.Context, email string, password string, clientIP string) (*ports.UserResponse, error) {
+	zerolog.Ctx(ctx).Debug().
 		Str("email", email).
 		Msg("attempting user login")
 
+	// locked or delayed accounts are refused before the password is looked at
+	account := loginAccountKey(email)
+	if err := s.guard.check(ctx, account, "", email, clientIP); err != nil {
+		return nil, err
+	}
+
 	// Find user by email
-	logger.Log.Debug().
+	zerolog.Ctx(ctx).Debug().
 		Str("email", email).
 		Msg("finding user by email")
 
 	user, err := s.userRepo.FindByEmail(ctx, email)
 	if err != nil {
-		logger.Log.Warn().
-			Err(err).
+		var appErr *apperror.AppError
+		if !errors.As(err, &appErr) || appErr.Code != "NOT_FOUND" {
+			zerolog.Ctx(ctx).Error().
+				Err(err).
+				Str("email", email).
+				Msg("failed to find user")
+			return nil, err // Repository already returns AppError
+		}
+
+		// same work and same answer as a wrong password
+		_ = utils.CheckPassword(password, dummyPasswordHash())
+		zerolog.Ctx(ctx).Warn().
 			Str("email", email).
-			Msg("user not found or repository error")
-		return nil, err // Repository already returns AppError
+			Msg("login attempt for unknown email")
+		s.guard.failed(ctx, account, "", email, clientIP, "unknown_email")
+		return nil, apperror.NewUnauthorizedError("invalid email or password")
 	}
 
-	logger.Log.Debug().
+	zerolog.Ctx(ctx).Debug().
 		Str("user_id", user.ID).
 		Str("email", email).
 		Msg("user found, verifying password")
@@ -115,21 +149,37 @@ func (s *userService) Login(ctx context.Context, email string, password string)
 	// check hash matches
 	err = utils.CheckPassword(password, user.Password)
 	if err != nil {
-		logger.Log.Warn().
+		zerolog.Ctx(ctx).Warn().
 			Str("user_id", user.ID).
 			Str("email", email).
 			Msg("invalid password attempt")
+		s.guard.failed(ctx, account, user.ID, email, clientIP, "invalid_password")
 		return nil, apperror.NewUnauthorizedError("invalid email or password")
 	}
 
-	logger.Log.Info().
+	// with 2FA on the password is only the first step, the caller asks for a code
+	mfa, err := s.mfaRepo.GetMFA(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// with 2FA the failures stay until the code is right too, or every new
+	// challenge would reset the count and codes could be guessed forever
+	if !mfa.Enabled {
+		s.guard.succeeded(ctx, account, user.ID, email, clientIP)
+	}
+
+	zerolog.Ctx(ctx).Info().
 		Str("user_id", user.ID).
 		Str("email", email).
+		Bool("mfa_enabled", mfa.Enabled).
 		Msg("user logged in successfully")
 
 	return &ports.UserResponse{
-		ID:    user.ID,
-		Name:  user.Name,
-		Email: user.Email,
+		ID:            user.ID,
+		Name:          user.Name,
+		Email:         user.Email,
+		EmailVerified: user.EmailVerifiedAt != nil,
+		MFAEnabled:    mfa.Enabled,
 	}, nil
 }
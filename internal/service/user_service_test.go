@@ -20,6 +20,9 @@ func init() {
 type mockUserRepository struct {
 	createUserFn func(ctx context.Context, user *models.User) (string, error)
 	findByEmailFn func(ctx context.Context, email string) (*models.User, error)
+	updatePasswordFn func(ctx context.Context, userID string, hashedPassword string) error
+	findByIDFn func(ctx context.Context, id string) (*models.User, error)
+	markEmailVerifiedFn func(ctx context.Context, userID string, email string) error
 }
 
 func (m *mockUserRepository) CreateUser(ctx context.Context, user *models.User) (string, error) {
@@ -36,6 +39,27 @@ func (m *mockUserRepository) FindByEmail(ctx context.Context, email string) (*mo
 	return nil, errors.New("not implemented")
 }
 
+func (m *mockUserRepository) UpdatePassword(ctx context.Context, userID string, hashedPassword string) error {
+	if m.updatePasswordFn != nil {
+		return m.updatePasswordFn(ctx, userID, hashedPassword)
+	}
+	return errors.New("not implemented")
+}
+
+func (m *mockUserRepository) FindByID(ctx context.Context, id string) (*models.User, error) {
+	if m.findByIDFn != nil {
+		return m.findByIDFn(ctx, id)
+	}
+	return nil, errors.New("not implemented")
+}
+
+func (m *mockUserRepository) MarkEmailVerified(ctx context.Context, userID string, email string) error {
+	if m.markEmailVerifiedFn != nil {
+		return m.markEmailVerifiedFn(ctx, userID, email)
+	}
+	return errors.New("not implemented")
+}
+
 func TestUserService_Register_Success(t *testing.T) {
 	repo := &mockUserRepository{
 		createUserFn: func(ctx context.Context, user *models.User) (string, error) {
@@ -48,7 +72,7 @@ func TestUserService_Register_Success(t *testing.T) {
 			return "user-id-1", nil
 		},
 	}
-	svc := NewUserService(repo)
+	svc := NewUserService(repo, &mockMFARepository{}, &mockLoginAttemptStore{}, &mockAuditLog{}, testLoginProtection)
 
 	resp, err := svc.Register(context.Background(), "Alice", "alice@example.com", "password123")
 	if err != nil {
@@ -65,7 +89,7 @@ func TestUserService_Register_RepoError(t *testing.T) {
 			return "", apperror.NewConflictError("email already exists")
 		},
 	}
-	svc := NewUserService(repo)
+	svc := NewUserService(repo, &mockMFARepository{}, &mockLoginAttemptStore{}, &mockAuditLog{}, testLoginProtection)
 
 	_, err := svc.Register(context.Background(), "Alice", "alice@example.com", "password123")
 	if err == nil {
@@ -89,9 +113,9 @@ func TestUserService_Login_Success(t *testing.T) {
 			}, nil
 		},
 	}
-	svc := NewUserService(repo)
+	svc := NewUserService(repo, &mockMFARepository{}, &mockLoginAttemptStore{}, &mockAuditLog{}, testLoginProtection)
 
-	resp, err := svc.Login(context.Background(), "alice@example.com", "password123")
+	resp, err := svc.Login(context.Background(), "alice@example.com", "password123", "203.0.113.7")
 	if err != nil {
 		t.Fatalf("Login failed: %v", err)
 	}
@@ -112,9 +136,9 @@ func TestUserService_Login_WrongPassword(t *testing.T) {
 			}, nil
 		},
 	}
-	svc := NewUserService(repo)
+	svc := NewUserService(repo, &mockMFARepository{}, &mockLoginAttemptStore{}, &mockAuditLog{}, testLoginProtection)
 
-	_, err := svc.Login(context.Background(), "alice@example.com", "wrongpass")
+	_, err := svc.Login(context.Background(), "alice@example.com", "wrongpass", "203.0.113.7")
 	if err == nil {
 		t.Fatal("expected error for wrong password")
 	}
@@ -130,12 +154,17 @@ func TestUserService_Login_UserNotFound(t *testing.T) {
 			return nil, apperror.NewNotFoundError("user not found")
 		},
 	}
-	svc := NewUserService(repo)
+	svc := NewUserService(repo, &mockMFARepository{}, &mockLoginAttemptStore{}, &mockAuditLog{}, testLoginProtection)
 
-	_, err := svc.Login(context.Background(), "nobody@example.com", "password123")
+	_, err := svc.Login(context.Background(), "nobody@example.com", "password123", "203.0.113.7")
 	if err == nil {
 		t.Fatal("expected error")
 	}
+	// unknown emails look exactly like a wrong password
+	var appErr *apperror.AppError
+	if !errors.As(err, &appErr) || appErr.Code != "UNAUTHORIZED" {
+		t.Errorf("expected unauthorized, got %v", err)
+	}
 }
 
 // Ensure interface compliance
@@ -0,0 +1,23 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestEncrypt_RoundTrip(t *testing.T) {
+	sealed, err := Encrypt("key", "JBSWY3DPEHPK3PXP")
+	if err != nil {
+		t.Fatalf("Encrypt failed: %v", err)
+	}
+	if strings.Contains(sealed, "JBSWY3DPEHPK3PXP") {
+		t.Fatal("plaintext visible in ciphertext")
+	}
+	opened, err := Decrypt("key", sealed)
+	if err != nil || opened != "JBSWY3DPEHPK3PXP" {
+		t.Fatalf("Decrypt = %q, %v", opened, err)
+	}
+	if _, err := Decrypt("other-key", sealed); err == nil {
+		t.Error("expected a different key to fail")
+	}
+}
@@ -0,0 +1,46 @@
+package utils
+
+import (
+	"errors"
+	"net/netip"
+	"testing"
+)
+
+func TestIsPublicIP(t *testing.T) {
+	for _, tc := range []struct {
+		ip     string
+		public bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1:248:1893:25c8:1946", true},
+		{"127.0.0.1", false},
+		{"::1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"fe80::1", false},
+		{"fd00::1", false},
+		{"0.0.0.0", false},
+		{"::", false},
+		{"100.64.0.1", false},
+		{"224.0.0.1", false},
+		{"::ffff:127.0.0.1", false},
+		{"64:ff9b::a00:1", false},
+	} {
+		if got := IsPublicIP(netip.MustParseAddr(tc.ip)); got != tc.public {
+			t.Errorf("IsPublicIP(%s) = %v, want %v", tc.ip, got, tc.public)
+		}
+	}
+}
+
+func TestPublicDialControl(t *testing.T) {
+	if err := PublicDialControl("tcp4", "93.184.216.34:443", nil); err != nil {
+		t.Errorf("expected a public address to pass, got %v", err)
+	}
+	for _, address := range []string{"127.0.0.1:6379", "[::1]:5432", "169.254.169.254:80", "not-an-address"} {
+		if err := PublicDialControl("tcp", address, nil); !errors.Is(err, ErrNonPublicAddress) {
+			t.Errorf("%s: expected ErrNonPublicAddress, got %v", address, err)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package utils
+
+// maxRequestIDLength longest caller supplied request id that is kept
+const maxRequestIDLength = 128
+
+// RequestID keeps a caller supplied request id when it is short and made of
+// [A-Za-z0-9._:-] (safe to log and echo), otherwise returns a new random one
+func RequestID(id string) string {
+	if id == "" || len(id) > maxRequestIDLength {
+		return MustRandomID()
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '.', r == '_', r == ':', r == '-':
+		default:
+			return MustRandomID()
+		}
+	}
+	return id
+}
@@ -0,0 +1,21 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRequestID(t *testing.T) {
+	for _, id := range []string{"abc-123", "7f3c.9a:01_x", strings.Repeat("a", 128)} {
+		if got := RequestID(id); got != id {
+			t.Errorf("expected %q to be kept, got %q", id, got)
+		}
+	}
+
+	for _, id := range []string{"", "has space", "line\nbreak", `{"json":1}`, strings.Repeat("a", 129)} {
+		got := RequestID(id)
+		if got == id || len(got) != 16 {
+			t.Errorf("expected a generated id for %q, got %q", id, got)
+		}
+	}
+}
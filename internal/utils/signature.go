@@ -0,0 +1,21 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+)
+
+// SignPayload returns the hex HMAC-SHA256 of parts joined by "|"
+func SignPayload(secret string, parts ...string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strings.Join(parts, "|")))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyPayload checks signature against parts in constant time
+func VerifyPayload(secret string, signature string, parts ...string) bool {
+	expected := SignPayload(secret, parts...)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
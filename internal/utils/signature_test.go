@@ -0,0 +1,23 @@
+package utils
+
+import "testing"
+
+func TestSignPayload_RoundTrip(t *testing.T) {
+	sig := SignPayload("secret", "attachments/t1/abc", "1700000000")
+	if sig == "" {
+		t.Fatal("expected non-empty signature")
+	}
+	if !VerifyPayload("secret", sig, "attachments/t1/abc", "1700000000") {
+		t.Fatal("VerifyPayload should accept its own signature")
+	}
+}
+
+func TestVerifyPayload_Tampered(t *testing.T) {
+	sig := SignPayload("secret", "attachments/t1/abc", "1700000000")
+	if VerifyPayload("secret", sig, "attachments/t1/abc", "1800000000") {
+		t.Fatal("VerifyPayload should reject a modified payload")
+	}
+	if VerifyPayload("other-secret", sig, "attachments/t1/abc", "1700000000") {
+		t.Fatal("VerifyPayload should reject a different secret")
+	}
+}
@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"encoding/base32"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rfc6238Secret the SHA1 key from the RFC 6238 test vectors
+var rfc6238Secret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func TestTOTPCode_RFC6238(t *testing.T) {
+	// the RFC lists 8 digit codes, ours are their last 6 digits
+	for unix, want := range map[int64]string{
+		59:          "287082",
+		1111111109:  "081804",
+		1111111111:  "050471",
+		1234567890:  "005924",
+		2000000000:  "279037",
+		20000000000: "353130",
+	} {
+		got, err := TOTPCode(rfc6238Secret, TOTPStep(time.Unix(unix, 0)))
+		if err != nil {
+			t.Fatalf("TOTPCode failed: %v", err)
+		}
+		if got != want {
+			t.Errorf("time %d: got %s, want %s", unix, got, want)
+		}
+	}
+}
+
+func TestValidateTOTP(t *testing.T) {
+	now := time.Unix(1234567890, 0)
+	previous, _ := TOTPCode(rfc6238Secret, TOTPStep(now)-1)
+
+	step, ok := ValidateTOTP(rfc6238Secret, previous, now, 1)
+	if !ok || step != TOTPStep(now)-1 {
+		t.Errorf("expected the previous step to match, got %d %v", step, ok)
+	}
+	if _, ok := ValidateTOTP(rfc6238Secret, previous, now, 0); ok {
+		t.Error("expected no match without skew")
+	}
+	if _, ok := ValidateTOTP(rfc6238Secret, "000000", now, 1); ok {
+		t.Error("expected a wrong code to be rejected")
+	}
+}
+
+func TestTOTPProvisioningURI(t *testing.T) {
+	uri := TOTPProvisioningURI("Task API", "alice@example.com", "ABC")
+	if !strings.HasPrefix(uri, "otpauth://totp/Task%20API:alice@example.com?") {
+		t.Errorf("unexpected label in %s", uri)
+	}
+	if !strings.Contains(uri, "secret=ABC") || !strings.Contains(uri, "issuer=Task+API") {
+		t.Errorf("missing parameters in %s", uri)
+	}
+}
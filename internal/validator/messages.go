@@ -11,6 +11,8 @@ func MsgForTag(fe validator.FieldError) string {
 	switch fe.Tag() {
 	case "required":
 		return "This field is required"
+	case "required_if", "required_unless":
+		return "This field is required here"
 	case "email":
 		return "Invalid email format"
 	case "url":
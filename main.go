@@ -5,6 +5,7 @@ import (
 	"strconv"
 	"time"
 
+	"github.com/gofiber/contrib/otelfiber"
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/adaptor"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
@@ -14,6 +15,7 @@ import (
 	"github.com/suryansh74/task-management-api-project/internal/logger"
 	"github.com/suryansh74/task-management-api-project/internal/metrics"
 	"github.com/suryansh74/task-management-api-project/internal/server"
+	"github.com/suryansh74/task-management-api-project/internal/telemetry"
 )
 
 func main() {
@@ -25,8 +27,17 @@ func main() {
 		logger.Log.Fatal().Err(err).Msg("Cannot load config")
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
+	// before the clients so their instrumentation picks up the provider
+	shutdownTracing, err := telemetry.InitTracing(
+		context.Background(),
+		cfg.OTLPEndpoint,
+		cfg.OTelServiceName,
+		cfg.OTLPInsecure,
+		cfg.TraceSampleRatio,
+	)
+	if err != nil {
+		logger.Log.Fatal().Err(err).Msg("Cannot init tracing")
+	}
 
 	postgresClient := clients.PostgresClient(
 		cfg.DBUser,
@@ -34,8 +45,8 @@ func main() {
 		cfg.DBHost,
 		cfg.DBPort,
 		cfg.DBName,
+		cfg.DBMaxConns,
 	)
-	defer postgresClient.Close(ctx)
 	logger.Log.Info().Msg("PostgreSQL connected")
 
 	redisDB, err := strconv.Atoi(cfg.RedisDB)
@@ -48,19 +59,43 @@ func main() {
 		cfg.RedisPassword,
 		redisDB,
 	)
-	defer redisClient.Close()
 	logger.Log.Info().Msg("Redis connected")
 
 	app := fiber.New(fiber.Config{
 		ReadTimeout:  5 * time.Second,
 		WriteTimeout: 5 * time.Second,
 		ErrorHandler: server.ErrorHandler(),
+		// c.IP() feeds rate limits and login protection, only trust the header from known proxies
+		ProxyHeader:             cfg.ProxyHeader,
+		EnableTrustedProxyCheck: true,
+		TrustedProxies:          cfg.TrustedProxies,
+		EnableIPValidation:      true,
 	})
 
-	// Observability
+	// Observability, the span is started first so request logs carry its trace id
+	app.Use(otelfiber.Middleware(otelfiber.WithNext(func(c *fiber.Ctx) bool {
+		switch c.Path() {
+		case "/metrics", "/livez", "/readyz", "/check_health":
+			return true
+		}
+		return false
+	})))
+	app.Use(server.RequestID())
 	app.Use(server.RequestLogger())
 	app.Use(metrics.Middleware())
 	app.Get("/metrics", adaptor.HTTPHandler(promhttp.Handler()))
 
-	server.StartServer(app, redisClient, postgresClient, &cfg)
+	// closes both clients once the servers have drained
+	serverErr := server.StartServer(app, redisClient, postgresClient, &cfg)
+
+	flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdownTracing(flushCtx); err != nil {
+		logger.Log.Error().Err(err).Msg("Failed to flush traces")
+	}
+
+	if serverErr != nil {
+		logger.Log.Fatal().Err(serverErr).Msg("Application stopped")
+	}
+	logger.Log.Info().Msg("Application stopped")
 }